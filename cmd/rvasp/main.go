@@ -2,9 +2,14 @@ package main
 
 import (
 	"context"
+	"crypto/rsa"
+	"crypto/tls"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -12,9 +17,15 @@ import (
 	"github.com/trisacrypto/testnet/pkg/rvasp"
 	"github.com/trisacrypto/testnet/pkg/rvasp/config"
 	"github.com/trisacrypto/testnet/pkg/rvasp/db"
+	"github.com/trisacrypto/testnet/pkg/rvasp/inspect"
+	"github.com/trisacrypto/testnet/pkg/rvasp/loadtest"
 	pb "github.com/trisacrypto/testnet/pkg/rvasp/pb/v1"
+	"github.com/trisacrypto/testnet/pkg/rvasp/scenario"
+	protocol "github.com/trisacrypto/trisa/pkg/trisa/api/v1beta1"
+	"github.com/trisacrypto/trisa/pkg/trust"
 	"github.com/urfave/cli"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/encoding/protojson"
@@ -32,7 +43,23 @@ func main() {
 	app.Name = "rvasp"
 	app.Version = pkg.Version()
 	app.Usage = "a gRPC based directory service for TRISA identity lookups"
-	app.Flags = []cli.Flag{}
+	app.Flags = []cli.Flag{
+		cli.StringFlag{
+			Name:   "token",
+			Usage:  "the api key or jwt used to authenticate client requests",
+			EnvVar: "RVASP_CLIENT_TOKEN",
+		},
+		cli.BoolFlag{
+			Name:   "tls",
+			Usage:  "connect to the rVASP over TLS",
+			EnvVar: "RVASP_CLIENT_TLS",
+		},
+		cli.StringFlag{
+			Name:   "ca-cert",
+			Usage:  "the CA certificate to verify the rVASP with (implies --tls, defaults to the system pool)",
+			EnvVar: "RVASP_CLIENT_CA_CERT",
+		},
+	}
 	app.Commands = []cli.Command{
 		{
 			Name:     "serve",
@@ -68,6 +95,11 @@ func main() {
 					Value:  time.Duration(time.Minute * 2),
 					EnvVar: "RVASP_ASYNC_INTERVAL",
 				},
+				cli.StringFlag{
+					Name:   "T, tenants",
+					Usage:  "serve multiple rvasps in one process from a tenants config file",
+					EnvVar: "RVASP_TENANTS_PATH",
+				},
 			},
 		},
 		{
@@ -112,6 +144,159 @@ func main() {
 				},
 			},
 		},
+		{
+			Name:     "migrate",
+			Usage:    "manage the versioned schema migrations of the database",
+			Category: "server",
+			Subcommands: []cli.Command{
+				{
+					Name:   "up",
+					Usage:  "apply pending migrations",
+					Action: migrateUp,
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:   "d, db",
+							Usage:  "the dsn of the postgres database to connect to",
+							EnvVar: "RVASP_DATABASE_DSN",
+						},
+						cli.UintFlag{
+							Name:  "t, to",
+							Usage: "the version to migrate to (defaults to the latest version)",
+						},
+					},
+				},
+				{
+					Name:   "down",
+					Usage:  "roll back the most recently applied migrations",
+					Action: migrateDown,
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:   "d, db",
+							Usage:  "the dsn of the postgres database to connect to",
+							EnvVar: "RVASP_DATABASE_DSN",
+						},
+						cli.IntFlag{
+							Name:  "n, steps",
+							Usage: "the number of migrations to roll back",
+							Value: 1,
+						},
+					},
+				},
+				{
+					Name:   "status",
+					Usage:  "show the migrations that have been applied",
+					Action: migrateStatus,
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:   "d, db",
+							Usage:  "the dsn of the postgres database to connect to",
+							EnvVar: "RVASP_DATABASE_DSN",
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:     "export",
+			Usage:    "export the database to JSON fixtures",
+			Category: "server",
+			Action:   export,
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:   "d, db",
+					Usage:  "the dsn of the postgres database to connect to",
+					EnvVar: "RVASP_DATABASE_DSN",
+				},
+				cli.StringFlag{
+					Name:  "o, out",
+					Usage: "the directory to write the exported fixtures to",
+				},
+				cli.StringFlag{
+					Name:  "v, vasp",
+					Usage: "only export the wallets and transactions of this VASP (alice, bob, etc.)",
+				},
+				cli.BoolFlag{
+					Name:  "a, anonymize",
+					Usage: "replace account identities with generated ones",
+				},
+			},
+		},
+		{
+			Name:     "fixtures",
+			Usage:    "manage the JSON fixtures used to initialize the database",
+			Category: "server",
+			Subcommands: []cli.Command{
+				{
+					Name:   "generate",
+					Usage:  "generate synthetic wallet fixtures for a VASP",
+					Action: generateFixtures,
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "v, vasp",
+							Usage: "the common name or short name of the VASP (alice, bob, etc.)",
+						},
+						cli.IntFlag{
+							Name:  "n, accounts",
+							Usage: "the number of accounts to generate",
+							Value: 100,
+						},
+						cli.Int64Flag{
+							Name:  "s, seed",
+							Usage: "the random seed used to generate the fixtures",
+							Value: 42,
+						},
+						cli.StringFlag{
+							Name:  "p, policies",
+							Usage: "weighted policy pairs, e.g. SendPartial:SyncRepair=3,SendError:AsyncReject=1",
+						},
+						cli.StringFlag{
+							Name:  "D, domain",
+							Usage: "the email domain of the generated accounts (defaults to the VASP's existing domain)",
+						},
+						cli.BoolFlag{
+							Name:  "m, merge",
+							Usage: "include the existing wallet fixtures in the output",
+						},
+						cli.StringFlag{
+							Name:   "f, fixtures",
+							Usage:  "the path to the fixtures directory to read VASPs and wallets from",
+							Value:  filepath.Join("pkg", "rvasp", "fixtures"),
+							EnvVar: "RVASP_FIXTURES_PATH",
+						},
+						cli.StringFlag{
+							Name:  "o, out",
+							Usage: "the directory to write the generated fixtures to",
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:     "token",
+			Usage:    "sign a jwt that may access the specified accounts",
+			Category: "server",
+			Action:   signToken,
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:   "s, secret",
+					Usage:  "the secret used to sign the jwt",
+					EnvVar: "RVASP_AUTH_JWT_SECRET",
+				},
+				cli.StringFlag{
+					Name:  "u, subject",
+					Usage: "the subject of the jwt, e.g. the name of the tester",
+				},
+				cli.StringSliceFlag{
+					Name:  "a, account",
+					Usage: "an email or wallet address the jwt may access, or * for all accounts",
+				},
+				cli.DurationFlag{
+					Name:  "e, expires",
+					Usage: "the duration the jwt is valid for, 0 for no expiration",
+					Value: 24 * time.Hour,
+				},
+			},
+		},
 		{
 			Name:     "account",
 			Usage:    "get the account status and current transactions",
@@ -133,6 +318,102 @@ func main() {
 					Name:  "T, no-transactions",
 					Usage: "don't include any transactions in the response",
 				},
+				cli.BoolFlag{
+					Name:  "H, history",
+					Usage: "include the state transition history of each transaction",
+				},
+			},
+		},
+		{
+			Name:      "history",
+			Usage:     "get the state transition history of a transaction",
+			Category:  "client",
+			Action:    history,
+			ArgsUsage: "envelope_id",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:   "e, endpoint",
+					Usage:  "the address and port to connect to the server on",
+					Value:  "localhost:4434",
+					EnvVar: "RVASP_ADDR",
+				},
+			},
+		},
+		{
+			Name:     "webhooks",
+			Usage:    "manage the webhooks notified of transaction state changes",
+			Category: "client",
+			Subcommands: []cli.Command{
+				{
+					Name:      "register",
+					Usage:     "register a webhook and print its signing secret",
+					Action:    registerWebhook,
+					ArgsUsage: "url",
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:   "e, endpoint",
+							Usage:  "the address and port to connect to the server on",
+							Value:  "localhost:4434",
+							EnvVar: "RVASP_ADDR",
+						},
+						cli.StringFlag{
+							Name:  "a, account",
+							Usage: "only notify the webhook of the transactions of the account",
+						},
+						cli.StringFlag{
+							Name:  "s, secret",
+							Usage: "the secret used to sign events (generated if not specified)",
+						},
+					},
+				},
+				{
+					Name:   "list",
+					Usage:  "list the registered webhooks",
+					Action: listWebhooks,
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:   "e, endpoint",
+							Usage:  "the address and port to connect to the server on",
+							Value:  "localhost:4434",
+							EnvVar: "RVASP_ADDR",
+						},
+					},
+				},
+				{
+					Name:      "delete",
+					Usage:     "delete a registered webhook",
+					Action:    deleteWebhook,
+					ArgsUsage: "id",
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:   "e, endpoint",
+							Usage:  "the address and port to connect to the server on",
+							Value:  "localhost:4434",
+							EnvVar: "RVASP_ADDR",
+						},
+					},
+				},
+				{
+					Name:   "deliveries",
+					Usage:  "show the delivery log of the webhooks",
+					Action: webhookDeliveries,
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:   "e, endpoint",
+							Usage:  "the address and port to connect to the server on",
+							Value:  "localhost:4434",
+							EnvVar: "RVASP_ADDR",
+						},
+						cli.Uint64Flag{
+							Name:  "w, webhook",
+							Usage: "only show the deliveries to the webhook with the id",
+						},
+						cli.StringFlag{
+							Name:  "i, envelope",
+							Usage: "only show the deliveries for the transaction with the envelope id",
+						},
+					},
+				},
 			},
 		},
 		{
@@ -216,6 +497,268 @@ func main() {
 				},
 			},
 		},
+		{
+			Name:     "loadtest",
+			Usage:    "measure the transfer throughput and latency of an rVASP",
+			Category: "client",
+			Action:   runLoadTest,
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:   "e, endpoint",
+					Usage:  "the address and port to connect to the server on",
+					Value:  "localhost:4434",
+					EnvVar: "RVASP_ADDR",
+				},
+				cli.IntFlag{
+					Name:  "c, concurrency",
+					Usage: "the number of workers sending transfers concurrently",
+					Value: loadtest.DefaultConcurrency,
+				},
+				cli.DurationFlag{
+					Name:  "d, duration",
+					Usage: "how long to send transfers for",
+					Value: loadtest.DefaultDuration,
+				},
+				cli.StringSliceFlag{
+					Name:  "a, accounts",
+					Usage: "the originator accounts to send transfers from (email or wallet address)",
+				},
+				cli.StringSliceFlag{
+					Name:  "b, beneficiaries",
+					Usage: "the beneficiaries to send transfers to (email or wallet address)",
+				},
+				cli.StringFlag{
+					Name:  "A, assets",
+					Usage: "weighted asset types of the transfers, e.g. Bitcoin=3,Ethereum=1",
+					Value: loadtest.DefaultAssetType,
+				},
+				cli.Float64Flag{
+					Name:  "m, amount",
+					Usage: "the amount of each transfer",
+					Value: loadtest.DefaultAmount,
+				},
+				cli.DurationFlag{
+					Name:  "t, timeout",
+					Usage: "the timeout of each transfer request",
+					Value: loadtest.DefaultTimeout,
+				},
+				cli.IntFlag{
+					Name:  "s, streams",
+					Usage: "the number of live updates streams to hold open during the test",
+				},
+				cli.Int64Flag{
+					Name:  "S, seed",
+					Usage: "the random seed used to select accounts and assets",
+					Value: 42,
+				},
+			},
+		},
+		{
+			Name:     "scenario",
+			Usage:    "execute declarative transfer scenarios against running rVASPs",
+			Category: "client",
+			Subcommands: []cli.Command{
+				{
+					Name:      "run",
+					Usage:     "run the scenarios in the specified YAML files",
+					ArgsUsage: "scenario.yaml [scenario.yaml ...]",
+					Action:    runScenarios,
+					Flags: []cli.Flag{
+						cli.StringSliceFlag{
+							Name:  "e, endpoint",
+							Usage: "override a scenario endpoint, e.g. alice=localhost:5434",
+						},
+						cli.StringFlag{
+							Name:  "j, junit",
+							Usage: "write the results as JUnit XML to the specified path",
+						},
+						cli.DurationFlag{
+							Name:  "p, poll",
+							Usage: "the interval to poll transaction states while waiting",
+							Value: scenario.DefaultPollInterval,
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:     "conformance",
+			Usage:    "run the TRISA conformance checks against a remote TRISA node",
+			Category: "client",
+			Action:   runConformance,
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "t, target",
+					Usage: "the address and port of the remote TRISA node",
+				},
+				cli.StringFlag{
+					Name:  "n, common-name",
+					Usage: "the common name of the remote TRISA node certificates",
+				},
+				cli.StringFlag{
+					Name:   "c, certs",
+					Usage:  "the path to the local mTLS certificates and private key",
+					EnvVar: "RVASP_CERT_PATH",
+				},
+				cli.StringFlag{
+					Name:   "C, chain",
+					Usage:  "the path to the trust chain used to verify the remote node",
+					EnvVar: "RVASP_TRUST_CHAIN_PATH",
+				},
+				cli.StringFlag{
+					Name:   "f, fixtures",
+					Usage:  "the path to the fixtures with the originator identities",
+					EnvVar: "RVASP_FIXTURES_PATH",
+				},
+				cli.StringFlag{
+					Name:   "v, vasp",
+					Usage:  "the common name or short name of the local rVASP in the fixtures",
+					EnvVar: "RVASP_NAME",
+				},
+				cli.StringFlag{
+					Name:  "b, beneficiary",
+					Usage: "a wallet address at the remote node that accepts transfers",
+				},
+				cli.StringFlag{
+					Name:  "B, async-beneficiary",
+					Usage: "a wallet address at the remote node that responds with pending messages",
+				},
+				cli.StringFlag{
+					Name:  "a, asset-type",
+					Usage: "the type of virtual asset in the transfers",
+					Value: "Bitcoin",
+				},
+				cli.DurationFlag{
+					Name:  "T, timeout",
+					Usage: "the timeout of each check",
+					Value: 30 * time.Second,
+				},
+				cli.StringFlag{
+					Name:  "o, out",
+					Usage: "write the report as JSON to the specified path",
+				},
+				cli.StringFlag{
+					Name:  "H, html",
+					Usage: "write the report as HTML to the specified path",
+				},
+			},
+		},
+		{
+			Name:     "replay",
+			Usage:    "resend recorded secure envelopes to a TRISA node",
+			Category: "client",
+			Action:   replay,
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:   "d, db",
+					Usage:  "the dsn of the postgres database with the recorded envelopes",
+					EnvVar: "RVASP_DATABASE_DSN",
+				},
+				cli.StringFlag{
+					Name:   "v, vasp",
+					Usage:  "the common name or short name of the rVASP that recorded the envelopes",
+					EnvVar: "RVASP_NAME",
+				},
+				cli.StringFlag{
+					Name:  "e, envelope",
+					Usage: "only replay the envelopes with this envelope ID",
+				},
+				cli.StringFlag{
+					Name:  "t, target",
+					Usage: "the address and port of the TRISA node to resend the envelopes to",
+				},
+				cli.StringFlag{
+					Name:  "n, common-name",
+					Usage: "the common name of the TRISA node certificates",
+				},
+				cli.StringFlag{
+					Name:   "c, certs",
+					Usage:  "the path to the local mTLS certificates and private key",
+					EnvVar: "RVASP_CERT_PATH",
+				},
+				cli.StringFlag{
+					Name:   "C, chain",
+					Usage:  "the path to the trust chain used to verify the TRISA node",
+					EnvVar: "RVASP_TRUST_CHAIN_PATH",
+				},
+				cli.BoolFlag{
+					Name:  "i, inbound",
+					Usage: "feed the recorded inbound envelopes to the rVASP instead of resending the outbound envelopes to the peer",
+				},
+				cli.DurationFlag{
+					Name:  "T, timeout",
+					Usage: "the timeout of each transfer",
+					Value: 30 * time.Second,
+				},
+			},
+		},
+		{
+			Name:     "envelope",
+			Usage:    "open, seal and check TRISA secure envelopes offline",
+			Category: "client",
+			Subcommands: []cli.Command{
+				{
+					Name:      "open",
+					Usage:     "decrypt a secure envelope and print the identity and transaction payloads",
+					ArgsUsage: "envelope.json",
+					Action:    openEnvelope,
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:   "k, key",
+							Usage:  "the PEM file with the private key of the recipient",
+							EnvVar: "RVASP_CERT_PATH",
+						},
+					},
+				},
+				{
+					Name:   "seal",
+					Usage:  "create a secure envelope from JSON identity and transaction payloads",
+					Action: sealEnvelope,
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "k, key",
+							Usage: "the PEM file with the certificate or public key of the recipient",
+						},
+						cli.StringFlag{
+							Name:  "i, identity",
+							Usage: "the JSON file with the IVMS101 identity payload",
+						},
+						cli.StringFlag{
+							Name:  "t, transaction",
+							Usage: "the JSON file with the generic transaction payload",
+						},
+						cli.BoolFlag{
+							Name:  "p, pending",
+							Usage: "the transaction file contains a pending message",
+						},
+						cli.StringFlag{
+							Name:  "id",
+							Usage: "the envelope ID (a random ID is used by default)",
+						},
+						cli.StringFlag{
+							Name:  "o, out",
+							Usage: "write the envelope to the specified path instead of stdout",
+						},
+						cli.BoolFlag{
+							Name:  "b, binary",
+							Usage: "write the envelope as binary protocol buffers instead of JSON",
+						},
+					},
+				},
+				{
+					Name:      "check",
+					Usage:     "validate the algorithms, HMAC and rejection of a secure envelope",
+					ArgsUsage: "envelope.json",
+					Action:    checkEnvelope,
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "k, key",
+							Usage: "the PEM file with the private key of the recipient to verify the HMAC",
+						},
+					},
+				},
+			},
+		},
 	}
 
 	app.Run(os.Args)
@@ -248,6 +791,28 @@ func serve(c *cli.Context) (err error) {
 		conf.AsyncInterval = interval
 	}
 
+	if tenants := c.String("tenants"); tenants != "" {
+		conf.TenantsPath = tenants
+	}
+
+	// Host multiple rVASPs if a tenants config is specified
+	if conf.TenantsPath != "" {
+		var confs []*config.Config
+		if confs, err = config.LoadTenants(conf.TenantsPath, conf); err != nil {
+			return cli.NewExitError(err, 1)
+		}
+
+		var srv *rvasp.Tenants
+		if srv, err = rvasp.NewTenants(confs); err != nil {
+			return cli.NewExitError(err, 1)
+		}
+
+		if err = srv.Serve(); err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		return nil
+	}
+
 	var srv *rvasp.Server
 	if srv, err = rvasp.New(conf); err != nil {
 		return cli.NewExitError(err, 1)
@@ -324,84 +889,385 @@ func resetdb(c *cli.Context) (err error) {
 	return nil
 }
 
-// Client method: get account status
-func account(c *cli.Context) (err error) {
-	req := &pb.AccountRequest{
-		Account:        c.String("account"),
-		NoTransactions: c.Bool("no-transactions"),
+// Apply pending schema migrations
+func migrateUp(c *cli.Context) (err error) {
+	var gdb *gorm.DB
+	if gdb, err = openDB(c); err != nil {
+		return cli.NewExitError(err, 1)
 	}
 
-	if req.Account == "" {
-		return cli.NewExitError("specify account email", 1)
+	if err = db.MigrateUp(gdb, c.Uint("to")); err != nil {
+		return cli.NewExitError(err, 1)
 	}
+	return migrateStatus(c)
+}
 
-	client, err := makeClient(c)
-	if err != nil {
+// Roll back schema migrations
+func migrateDown(c *cli.Context) (err error) {
+	if c.Int("steps") <= 0 {
+		return cli.NewExitError("specify a positive number of steps", 1)
+	}
+
+	var gdb *gorm.DB
+	if gdb, err = openDB(c); err != nil {
 		return cli.NewExitError(err, 1)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+	if err = db.MigrateDown(gdb, c.Int("steps")); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	return migrateStatus(c)
+}
 
-	rep, err := client.AccountStatus(ctx, req)
-	if err != nil {
+// Print the status of the schema migrations
+func migrateStatus(c *cli.Context) (err error) {
+	var gdb *gorm.DB
+	if gdb, err = openDB(c); err != nil {
 		return cli.NewExitError(err, 1)
 	}
 
-	return printJSON(rep)
+	var migrations []db.MigrationStatus
+	if migrations, err = db.Status(gdb); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	for _, m := range migrations {
+		applied := "pending"
+		if m.Applied {
+			applied = m.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Printf("%4d  %-25s  %s\n", m.Version, applied, m.Name)
+	}
+
+	if err = db.CheckSchema(gdb); err != nil {
+		fmt.Println(err)
+	}
+	return nil
 }
 
-// Client method: transfer funds
-func transfer(c *cli.Context) (err error) {
-	req := &pb.TransferRequest{
-		Account:         c.String("account"),
-		Beneficiary:     c.String("beneficiary"),
-		BeneficiaryVasp: c.String("beneficiary-vasp"),
-		Amount:          float32(c.Float64("amount")),
-		AssetType:       c.String("asset-type"),
+// Export the database to fixtures
+func export(c *cli.Context) (err error) {
+	if c.String("out") == "" {
+		return cli.NewExitError("specify an output directory", 1)
 	}
 
-	if req.Account == "" {
-		return cli.NewExitError("specify account email", 1)
+	var gdb *gorm.DB
+	if gdb, err = openDB(c); err != nil {
+		return cli.NewExitError(err, 1)
 	}
 
-	if req.Beneficiary == "" && req.BeneficiaryVasp == "" {
-		return cli.NewExitError("specify a beneficiary or beneficiary vasp", 1)
+	opts := db.ExportOptions{
+		VASP:      c.String("vasp"),
+		Anonymize: c.Bool("anonymize"),
+	}
+	if err = db.Export(gdb, c.String("out"), opts); err != nil {
+		return cli.NewExitError(err, 1)
 	}
 
-	if req.Amount <= 0.0 {
-		return cli.NewExitError("specify a transfer amount", 1)
+	fmt.Printf("exported fixtures to %s\n", c.String("out"))
+	return nil
+}
+
+// Generate synthetic wallet fixtures
+func generateFixtures(c *cli.Context) (err error) {
+	if c.String("vasp") == "" {
+		return cli.NewExitError("specify a vasp to generate fixtures for", 1)
 	}
 
-	client, err := makeClient(c)
-	if err != nil {
+	if c.String("out") == "" {
+		return cli.NewExitError("specify an output directory", 1)
+	}
+
+	if c.Int("accounts") <= 0 {
+		return cli.NewExitError("specify a positive number of accounts", 1)
+	}
+
+	var vasps []db.VASP
+	if vasps, err = db.LoadVASPs(c.String("fixtures")); err != nil {
 		return cli.NewExitError(err, 1)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+	var vaspID uint
+	if vaspID, err = db.FindVASP(vasps, c.String("vasp")); err != nil {
+		return cli.NewExitError(err, 1)
+	}
 
-	rep, err := client.Transfer(ctx, req)
-	if err != nil {
-		// Extract the status from the error
-		var (
-			ok   bool
-			serr *status.Status
-		)
-		if serr, ok = status.FromError(err); !ok {
+	var policies []db.PolicyMix
+	if mix := c.String("policies"); mix != "" {
+		if policies, err = db.ParsePolicyMix(mix); err != nil {
 			return cli.NewExitError(err, 1)
 		}
-		fmt.Printf("[%d] %s\n", serr.Code(), serr.Message())
-		return nil
 	}
-	return printJSON(rep)
-}
 
-// Client method: transfer funds
-func stream(c *cli.Context) (err error) {
-	var req *pb.TransferRequest
-	if !c.Bool("listen") {
-		req = &pb.TransferRequest{
+	var (
+		wallets  []db.Wallet
+		accounts []db.Account
+	)
+	if wallets, accounts, err = db.LoadWallets(c.String("fixtures")); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	// Use the email domain of the existing wallets for the VASP if not specified
+	domain := c.String("domain")
+	for _, w := range wallets {
+		if domain != "" {
+			break
+		}
+		if _, host, ok := strings.Cut(w.Email, "@"); ok && w.ProviderID == vaspID {
+			domain = host
+		}
+	}
+	if domain == "" {
+		domain = vasps[vaspID-1].Name
+	}
+
+	generator := db.NewGenerator(c.Int64("seed"), policies)
+	if !c.Bool("merge") {
+		wallets, accounts = nil, nil
+	} else {
+		generator.Reserve(wallets)
+	}
+
+	var generatedWallets []db.Wallet
+	var generatedAccounts []db.Account
+	if generatedWallets, generatedAccounts, err = generator.Wallets(vaspID, domain, c.Int("accounts")); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	wallets = append(wallets, generatedWallets...)
+	accounts = append(accounts, generatedAccounts...)
+
+	if err = db.WriteVASPs(c.String("out"), vasps); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	if err = db.WriteWallets(c.String("out"), wallets, accounts); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	fmt.Printf("generated %d accounts for %s in %s\n", len(generatedWallets), vasps[vaspID-1].Name, c.String("out"))
+	return nil
+}
+
+// Sign a jwt that may access the integration and demo APIs
+func signToken(c *cli.Context) (err error) {
+	accounts := c.StringSlice("account")
+	if len(accounts) == 0 {
+		return cli.NewExitError("specify at least one account or * for all accounts", 1)
+	}
+
+	var token string
+	if token, err = rvasp.NewToken(c.String("secret"), c.String("subject"), accounts, c.Duration("expires")); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	fmt.Println(token)
+	return nil
+}
+
+// Open the database from the configuration and the db flag
+func openDB(c *cli.Context) (_ *gorm.DB, err error) {
+	var conf *config.Config
+	if conf, err = config.New(); err != nil {
+		return nil, err
+	}
+
+	if dsn := c.String("db"); dsn != "" {
+		conf.Database.DSN = dsn
+	}
+	return db.OpenDB(conf)
+}
+
+// Client method: get account status
+func account(c *cli.Context) (err error) {
+	req := &pb.AccountRequest{
+		Account:        c.String("account"),
+		NoTransactions: c.Bool("no-transactions"),
+		History:        c.Bool("history"),
+	}
+
+	if req.Account == "" {
+		return cli.NewExitError("specify account email", 1)
+	}
+
+	client, err := makeClient(c)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	rep, err := client.AccountStatus(ctx, req)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	return printJSON(rep)
+}
+
+// Client method: get the state transition history of a transaction
+func history(c *cli.Context) (err error) {
+	if c.NArg() != 1 {
+		return cli.NewExitError("specify the envelope id of the transaction", 1)
+	}
+
+	client, err := makeClient(c)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	rep, err := client.TransactionHistory(ctx, &pb.TransactionHistoryRequest{EnvelopeId: c.Args().First()})
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	return printJSON(rep)
+}
+
+// Client method: register a webhook
+func registerWebhook(c *cli.Context) (err error) {
+	if c.NArg() != 1 {
+		return cli.NewExitError("specify the url of the webhook", 1)
+	}
+
+	client, err := makeClient(c)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	rep, err := client.RegisterWebhook(ctx, &pb.Webhook{
+		Url:     c.Args().First(),
+		Account: c.String("account"),
+		Secret:  c.String("secret"),
+	})
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	return printJSON(rep)
+}
+
+// Client method: list the registered webhooks
+func listWebhooks(c *cli.Context) (err error) {
+	client, err := makeClient(c)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	rep, err := client.ListWebhooks(ctx, &pb.Empty{})
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	return printJSON(rep)
+}
+
+// Client method: delete a webhook
+func deleteWebhook(c *cli.Context) (err error) {
+	if c.NArg() != 1 {
+		return cli.NewExitError("specify the id of the webhook", 1)
+	}
+
+	var id uint64
+	if id, err = strconv.ParseUint(c.Args().First(), 10, 64); err != nil {
+		return cli.NewExitError("could not parse webhook id", 1)
+	}
+
+	client, err := makeClient(c)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, err = client.DeleteWebhook(ctx, &pb.Webhook{Id: id}); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	return nil
+}
+
+// Client method: show the webhook delivery log
+func webhookDeliveries(c *cli.Context) (err error) {
+	client, err := makeClient(c)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	rep, err := client.WebhookDeliveries(ctx, &pb.WebhookDeliveriesRequest{
+		WebhookId:  c.Uint64("webhook"),
+		EnvelopeId: c.String("envelope"),
+	})
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	return printJSON(rep)
+}
+
+// Client method: transfer funds
+func transfer(c *cli.Context) (err error) {
+	req := &pb.TransferRequest{
+		Account:         c.String("account"),
+		Beneficiary:     c.String("beneficiary"),
+		BeneficiaryVasp: c.String("beneficiary-vasp"),
+		Amount:          float32(c.Float64("amount")),
+		AssetType:       c.String("asset-type"),
+	}
+
+	if req.Account == "" {
+		return cli.NewExitError("specify account email", 1)
+	}
+
+	if req.Beneficiary == "" && req.BeneficiaryVasp == "" {
+		return cli.NewExitError("specify a beneficiary or beneficiary vasp", 1)
+	}
+
+	if req.Amount <= 0.0 {
+		return cli.NewExitError("specify a transfer amount", 1)
+	}
+
+	client, err := makeClient(c)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	rep, err := client.Transfer(ctx, req)
+	if err != nil {
+		// Extract the status from the error
+		var (
+			ok   bool
+			serr *status.Status
+		)
+		if serr, ok = status.FromError(err); !ok {
+			return cli.NewExitError(err, 1)
+		}
+		fmt.Printf("[%d] %s\n", serr.Code(), serr.Message())
+		return nil
+	}
+	return printJSON(rep)
+}
+
+// Client method: transfer funds
+func stream(c *cli.Context) (err error) {
+	var req *pb.TransferRequest
+	if !c.Bool("listen") {
+		req = &pb.TransferRequest{
 			Account:          c.String("account"),
 			Beneficiary:      c.String("beneficiary"),
 			Amount:           float32(c.Float64("amount")),
@@ -490,10 +1356,406 @@ func rvaspStatus(c *cli.Context) (err error) {
 	return printJSON(status)
 }
 
+// Client method: send concurrent transfers and report throughput and latency
+func runLoadTest(c *cli.Context) (err error) {
+	opts := loadtest.Options{
+		Concurrency:   c.Int("concurrency"),
+		Duration:      c.Duration("duration"),
+		Accounts:      c.StringSlice("accounts"),
+		Beneficiaries: c.StringSlice("beneficiaries"),
+		Amount:        float32(c.Float64("amount")),
+		Timeout:       c.Duration("timeout"),
+		Streams:       c.Int("streams"),
+		Seed:          c.Int64("seed"),
+	}
+
+	if opts.Assets, err = loadtest.ParseAssetMix(c.String("assets")); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	if err = opts.Validate(); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	client, err := makeClient(c)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	var demo pb.TRISADemoClient
+	if opts.Streams > 0 {
+		if demo, err = makeDemoClient(c); err != nil {
+			return cli.NewExitError(err, 1)
+		}
+	}
+
+	fmt.Printf("sending transfers to %s with %d workers for %s\n", c.String("endpoint"), opts.Concurrency, opts.Duration)
+	var report *loadtest.Report
+	if report, err = loadtest.Run(context.Background(), client, demo, opts); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	report.Print(os.Stdout)
+	return nil
+}
+
+// Client method: run scenarios and report the results
+func runScenarios(c *cli.Context) (err error) {
+	if c.NArg() == 0 {
+		return cli.NewExitError("specify at least one scenario file", 1)
+	}
+
+	overrides := make(map[string]string)
+	for _, endpoint := range c.StringSlice("endpoint") {
+		name, addr, ok := strings.Cut(endpoint, "=")
+		if !ok || name == "" || addr == "" {
+			return cli.NewExitError(fmt.Errorf("could not parse endpoint %q, expected name=addr", endpoint), 1)
+		}
+		overrides[name] = addr
+	}
+
+	results := make([]*scenario.Result, 0, c.NArg())
+	for _, path := range c.Args() {
+		var s *scenario.Scenario
+		if s, err = scenario.Load(path); err != nil {
+			return cli.NewExitError(err, 1)
+		}
+
+		clients := make(map[string]pb.TRISAIntegrationClient, len(s.Endpoints))
+		for name, addr := range s.Endpoints {
+			if override, ok := overrides[name]; ok {
+				addr = override
+			}
+
+			var cc *grpc.ClientConn
+			var opts []grpc.DialOption
+			if opts, err = dialOptions(c); err != nil {
+				return cli.NewExitError(err, 1)
+			}
+
+			if cc, err = grpc.Dial(addr, opts...); err != nil {
+				return cli.NewExitError(err, 1)
+			}
+			defer cc.Close()
+			clients[name] = pb.NewTRISAIntegrationClient(cc)
+		}
+
+		runner := scenario.NewRunner(clients)
+		runner.PollInterval = c.Duration("poll")
+
+		fmt.Printf("running scenario %s\n", s.Name)
+		result := runner.Run(context.Background(), s)
+		for _, step := range result.Steps {
+			if step.Failure != "" {
+				fmt.Printf("  FAIL %s (%s): %s\n", step.Name, step.Duration.Round(time.Millisecond), step.Failure)
+			} else {
+				fmt.Printf("  PASS %s (%s)\n", step.Name, step.Duration.Round(time.Millisecond))
+			}
+		}
+		results = append(results, result)
+	}
+
+	if path := c.String("junit"); path != "" {
+		var f *os.File
+		if f, err = os.Create(path); err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		defer f.Close()
+
+		if err = scenario.WriteJUnit(f, results...); err != nil {
+			return cli.NewExitError(err, 1)
+		}
+	}
+
+	var failures, steps int
+	for _, result := range results {
+		failures += result.Failures()
+		steps += len(result.Steps)
+	}
+
+	if failures > 0 {
+		return cli.NewExitError(fmt.Sprintf("%d of %d steps failed", failures, steps), 1)
+	}
+	fmt.Printf("all %d steps passed\n", steps)
+	return nil
+}
+
+// Client method: run the conformance checks against a remote TRISA node
+func runConformance(c *cli.Context) (err error) {
+	opts := rvasp.ConformanceOptions{
+		Target:           c.String("target"),
+		CommonName:       c.String("common-name"),
+		Beneficiary:      c.String("beneficiary"),
+		AsyncBeneficiary: c.String("async-beneficiary"),
+		AssetType:        c.String("asset-type"),
+		Timeout:          c.Duration("timeout"),
+	}
+
+	if opts.Target == "" || opts.CommonName == "" {
+		return cli.NewExitError("specify the target endpoint and common name", 1)
+	}
+
+	if err = opts.LoadFixtures(c.String("fixtures"), c.String("vasp")); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	var sz *trust.Serializer
+	if sz, err = trust.NewSerializer(false); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	var certs *trust.Provider
+	if certs, err = sz.ReadFile(c.String("certs")); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	var chain trust.ProviderPool
+	if chain, err = sz.ReadPoolFile(c.String("chain")); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	var suite *rvasp.Conformance
+	if suite, err = rvasp.NewConformance(certs, chain, opts); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	defer suite.Close()
+
+	fmt.Printf("running conformance checks against %s (%s)\n", opts.Target, opts.CommonName)
+	report := suite.Run(context.Background())
+	for _, check := range report.Checks {
+		line := fmt.Sprintf("  %s %s (%s)", strings.ToUpper(string(check.Status)), check.Name, check.Duration.Round(time.Millisecond))
+		if check.Message != "" {
+			line += ": " + check.Message
+		}
+		fmt.Println(line)
+	}
+	fmt.Printf("score: %.1f%% (%d passed, %d failed, %d skipped)\n", report.Score, report.Passed, report.Failed, report.Skipped)
+
+	if path := c.String("out"); path != "" {
+		if err = writeFile(path, report.WriteJSON); err != nil {
+			return cli.NewExitError(err, 1)
+		}
+	}
+
+	if path := c.String("html"); path != "" {
+		if err = writeFile(path, report.WriteHTML); err != nil {
+			return cli.NewExitError(err, 1)
+		}
+	}
+
+	if !report.Compliant {
+		if report.Failed == 0 && report.Skipped > 0 {
+			return cli.NewExitError("conformance could not be established because checks were skipped", 1)
+		}
+		return cli.NewExitError("the remote node is not conformant", 1)
+	}
+	return nil
+}
+
+// Client method: resend recorded secure envelopes to a TRISA node
+func replay(c *cli.Context) (err error) {
+	opts := rvasp.ReplayOptions{
+		Target:     c.String("target"),
+		CommonName: c.String("common-name"),
+		Direction:  db.Outbound,
+		Timeout:    c.Duration("timeout"),
+	}
+
+	if opts.Target == "" || opts.CommonName == "" {
+		return cli.NewExitError("specify the target endpoint and common name", 1)
+	}
+
+	if c.Bool("inbound") {
+		opts.Direction = db.Inbound
+	}
+
+	var gdb *gorm.DB
+	if gdb, err = openDB(c); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	// Find the rVASP that recorded the envelopes by its common name or short name
+	var vasps []db.VASP
+	if err = gdb.Order("id").Find(&vasps).Error; err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	var idx uint
+	if idx, err = db.FindVASP(vasps, c.String("vasp")); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	var vaspdb *db.DB
+	if vaspdb, err = db.NewVASPDB(gdb, vasps[idx-1].Name); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	query := vaspdb.Query().Order("id")
+	if id := c.String("envelope"); id != "" {
+		query = vaspdb.LookupEnvelopes(id)
+	}
+
+	var records []db.Envelope
+	if err = query.Find(&records).Error; err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	if len(records) == 0 {
+		return cli.NewExitError("no recorded envelopes found", 1)
+	}
+
+	var sz *trust.Serializer
+	if sz, err = trust.NewSerializer(false); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	var certs *trust.Provider
+	if certs, err = sz.ReadFile(c.String("certs")); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	var chain trust.ProviderPool
+	if chain, err = sz.ReadPoolFile(c.String("chain")); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	var replayer *rvasp.Replayer
+	if replayer, err = rvasp.NewReplayer(certs, chain, opts); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	defer replayer.Close()
+
+	fmt.Printf("replaying %s envelopes to %s (%s)\n", opts.Direction, opts.Target, opts.CommonName)
+	mismatches := 0
+	for _, result := range replayer.Replay(context.Background(), records) {
+		switch {
+		case result.Err != nil:
+			mismatches++
+			fmt.Printf("  ERROR %s: %s\n", result.Envelope, result.Err)
+		case !result.Match():
+			mismatches++
+			fmt.Printf("  MISMATCH %s: recorded %s (%s), replayed %s (%s)\n", result.Envelope, result.RecordedState, result.RecordedCode, result.ReplayedState, result.ReplayedCode)
+		default:
+			fmt.Printf("  MATCH %s: %s\n", result.Envelope, result.ReplayedState)
+		}
+	}
+
+	if mismatches > 0 {
+		return cli.NewExitError(fmt.Sprintf("%d replayed envelopes did not match the recorded replies", mismatches), 1)
+	}
+	return nil
+}
+
+// Client method: decrypt a secure envelope and print its payload
+func openEnvelope(c *cli.Context) (err error) {
+	if c.NArg() != 1 {
+		return cli.NewExitError("specify the path to the envelope", 1)
+	}
+
+	var msg *protocol.SecureEnvelope
+	if msg, err = inspect.ReadEnvelope(c.Args().First()); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	var key *rsa.PrivateKey
+	if key, err = inspect.LoadPrivateKey(c.String("key")); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	var payload *protocol.Payload
+	if payload, err = inspect.Open(msg, key); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	var data []byte
+	if data, err = inspect.MarshalPayload(payload); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	fmt.Println(string(data))
+	return nil
+}
+
+// Client method: seal identity and transaction payloads in a secure envelope
+func sealEnvelope(c *cli.Context) (err error) {
+	if c.String("identity") == "" || c.String("transaction") == "" {
+		return cli.NewExitError("specify the identity and transaction payloads", 1)
+	}
+
+	var payload *protocol.Payload
+	if payload, err = inspect.LoadPayload(c.String("identity"), c.String("transaction"), c.Bool("pending")); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	var key *rsa.PublicKey
+	if key, err = inspect.LoadPublicKey(c.String("key")); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	var msg *protocol.SecureEnvelope
+	if msg, err = inspect.Seal(payload, key, c.String("id")); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	write := func(w io.Writer) error {
+		return inspect.WriteEnvelope(w, msg, c.Bool("binary"))
+	}
+
+	if path := c.String("out"); path != "" {
+		if err = writeFile(path, write); err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		return nil
+	}
+
+	if err = write(os.Stdout); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	return nil
+}
+
+// Client method: validate a secure envelope
+func checkEnvelope(c *cli.Context) (err error) {
+	if c.NArg() != 1 {
+		return cli.NewExitError("specify the path to the envelope", 1)
+	}
+
+	var msg *protocol.SecureEnvelope
+	if msg, err = inspect.ReadEnvelope(c.Args().First()); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	var key *rsa.PrivateKey
+	if path := c.String("key"); path != "" {
+		if key, err = inspect.LoadPrivateKey(path); err != nil {
+			return cli.NewExitError(err, 1)
+		}
+	}
+
+	report := inspect.Check(msg, key)
+	report.Print(os.Stdout)
+	if !report.Valid() {
+		return cli.NewExitError(fmt.Sprintf("%d problems found with the envelope", len(report.Problems)), 1)
+	}
+	return nil
+}
+
+// writeFile creates the file at path and writes its contents with the write function.
+func writeFile(path string, write func(io.Writer) error) (err error) {
+	var f *os.File
+	if f, err = os.Create(path); err != nil {
+		return err
+	}
+	defer f.Close()
+	return write(f)
+}
+
 // helper function to create the GRPC client with default options
 func makeClient(c *cli.Context) (_ pb.TRISAIntegrationClient, err error) {
 	var opts []grpc.DialOption
-	opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if opts, err = dialOptions(c); err != nil {
+		return nil, err
+	}
 
 	var cc *grpc.ClientConn
 	if cc, err = grpc.Dial(c.String("endpoint"), opts...); err != nil {
@@ -504,7 +1766,9 @@ func makeClient(c *cli.Context) (_ pb.TRISAIntegrationClient, err error) {
 
 func makeDemoClient(c *cli.Context) (_ pb.TRISADemoClient, err error) {
 	var opts []grpc.DialOption
-	opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if opts, err = dialOptions(c); err != nil {
+		return nil, err
+	}
 
 	var cc *grpc.ClientConn
 	if cc, err = grpc.Dial(c.String("endpoint"), opts...); err != nil {
@@ -513,6 +1777,30 @@ func makeDemoClient(c *cli.Context) (_ pb.TRISADemoClient, err error) {
 	return pb.NewTRISADemoClient(cc), nil
 }
 
+// dialOptions returns the transport and per-RPC credentials specified by the global
+// token, tls, and ca-cert flags.
+func dialOptions(c *cli.Context) (opts []grpc.DialOption, err error) {
+	secure := c.GlobalBool("tls") || c.GlobalString("ca-cert") != ""
+	if secure {
+		var creds credentials.TransportCredentials
+		if path := c.GlobalString("ca-cert"); path != "" {
+			if creds, err = credentials.NewClientTLSFromFile(path, ""); err != nil {
+				return nil, fmt.Errorf("could not load ca cert: %s", err)
+			}
+		} else {
+			creds = credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12})
+		}
+		opts = append(opts, grpc.WithTransportCredentials(creds))
+	} else {
+		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	}
+
+	if token := c.GlobalString("token"); token != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(rvasp.TokenCredentials{Token: token, Secure: secure}))
+	}
+	return opts, nil
+}
+
 // helper function to print JSON response and exit
 func printJSON(v protoreflect.ProtoMessage) error {
 	jsonpb := protojson.MarshalOptions{
@@ -10,6 +10,9 @@ import (
 	"github.com/rs/zerolog/log"
 	"github.com/trisacrypto/testnet/pkg/rvasp/db"
 	pb "github.com/trisacrypto/testnet/pkg/rvasp/pb/v1"
+	"github.com/trisacrypto/trisa/pkg/ivms101"
+	protocol "github.com/trisacrypto/trisa/pkg/trisa/api/v1beta1"
+	"google.golang.org/protobuf/encoding/protojson"
 )
 
 // AsyncHandler is a go routine that periodically reads pending messages off the
@@ -46,6 +49,12 @@ func (s *TRISA) AsyncHandler(stop <-chan struct{}) {
 	}
 }
 
+// HandleAsync handles the pending transactions of the rVASP once rather than on the
+// interval of the AsyncHandler, which allows tests to step through async handshakes.
+func (s *Server) HandleAsync(ctx context.Context) {
+	s.trisa.handleAsync(ctx)
+}
+
 func (s *TRISA) handleAsync(ctx context.Context) {
 	// Recover from panics that occur during async handling.
 	defer func() {
@@ -80,8 +89,12 @@ txloop:
 		// Verify pending transaction has not expired
 		if now.After(tx.NotAfter) {
 			log.Info().Uint("id", tx.ID).Time("not_after", tx.NotAfter).Msg("transaction expired")
-			tx.SetState(pb.TransactionState_EXPIRED)
-			if err = s.parent.db.Save(&tx).Error; err != nil {
+			if err = tx.SetState(pb.TransactionState_EXPIRED, db.ActorAsync, "reply window closed before the transaction was completed"); err != nil {
+				log.Error().Err(err).Uint("id", tx.ID).Msg("could not expire transaction")
+				continue txloop
+			}
+
+			if err = s.parent.db.Save(tx).Error; err != nil {
 				log.Error().Err(err).Uint("id", tx.ID).Msg("could not save expired transaction")
 			}
 			continue txloop
@@ -93,14 +106,18 @@ txloop:
 			// originator
 			if err = s.acknowledgeTransaction(tx); err != nil {
 				log.Warn().Err(err).Uint("id", tx.ID).Msg("could not acknowledge transaction")
-				tx.SetState(pb.TransactionState_FAILED)
+				if err = tx.SetState(pb.TransactionState_FAILED, db.ActorAsync, err.Error()); err != nil {
+					log.Error().Err(err).Uint("id", tx.ID).Msg("could not fail transaction")
+				}
 			}
 		case pb.TransactionState_PENDING_RECEIVED:
 			// We are the originator, so send a new transfer to the beneficiary to
 			// continue the async handshake
 			if err = s.parent.continueAsync(tx); err != nil {
 				log.Warn().Err(err).Uint("id", tx.ID).Msg("could not send transaction")
-				tx.SetState(pb.TransactionState_FAILED)
+				if err = tx.SetState(pb.TransactionState_FAILED, db.ActorAsync, err.Error()); err != nil {
+					log.Error().Err(err).Uint("id", tx.ID).Msg("could not fail transaction")
+				}
 			}
 		default:
 			log.Error().Uint("id", tx.ID).Str("state", tx.StateString).Msg("unexpected transaction state")
@@ -130,12 +147,30 @@ func (s *TRISA) acknowledgeTransaction(tx *db.Transaction) (err error) {
 		return err
 	}
 
+	// Screen the originator again since transfers may have been held for review, e.g.
+	// so that hits can be cleared by removing the entry from the watchlist.
+	identity := &ivms101.IdentityPayload{}
+	if err = protojson.Unmarshal([]byte(tx.Identity), identity); err != nil {
+		log.Error().Err(err).Msg("could not unmarshal identity from transaction")
+		return fmt.Errorf("could not unmarshal identity from transaction: %s", err)
+	}
+
+	if err = s.parent.screener.Reload(); err != nil {
+		log.Warn().Err(err).Msg("could not reload watchlist, screening with the current watchlist")
+	}
+
+	if hit := s.parent.screener.Screen(identity.GetOriginator().GetOriginatorPersons()); hit != nil {
+		reject := hit.Reject()
+		return s.sendRejected(tx, reject, reject.Error())
+	}
+
+	// Transfers held for review are continued asynchronously whatever the policy
 	policy := wallet.BeneficiaryPolicy
 	switch policy {
-	case db.AsyncRepair:
+	case db.AsyncRepair, db.SyncRepair, db.SyncRequire:
 		return s.sendAsync(tx)
 	case db.AsyncReject:
-		return s.sendRejected(tx)
+		return s.sendRejected(tx, protocol.Errorf(protocol.Rejected, "rejected by beneficiary"), "rejected by the beneficiary policy")
 	default:
 		return fmt.Errorf("unknown policy '%s' for wallet '%s'", policy, wallet.Address)
 	}
@@ -0,0 +1,279 @@
+package rvasp
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/rs/zerolog/log"
+	"github.com/trisacrypto/testnet/pkg/rvasp/config"
+	"github.com/trisacrypto/testnet/pkg/rvasp/db"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// AllAccounts is the scope of credentials that may access every account of the rVASP.
+const AllAccounts = "*"
+
+// Methods that do not require authentication, e.g. so that load balancers can check
+// the health of the rVASP.
+var unauthenticated = map[string]struct{}{
+	"/rvasp.v1.TRISAIntegration/Status": {},
+}
+
+// Authenticator authenticates requests to the integration and demo APIs with static
+// API keys or JWTs signed with a shared secret. Credentials are sent in the
+// authorization metadata as a bearer token or in the x-api-key metadata, and each API
+// key or JWT is scoped to the accounts that it may access.
+type Authenticator struct {
+	keys   []config.APIKeyConfig
+	secret []byte
+}
+
+// NewAuthenticator loads the API keys and JWT secret of the auth config. Returns nil if
+// authentication is not enabled, in which case requests are not authenticated.
+func NewAuthenticator(conf config.AuthConfig) (a *Authenticator, err error) {
+	if !conf.Enabled() {
+		return nil, nil
+	}
+
+	a = &Authenticator{}
+	if conf.KeysPath != "" {
+		if a.keys, err = config.LoadAPIKeys(conf.KeysPath); err != nil {
+			return nil, err
+		}
+	}
+
+	if conf.JWTSecret != "" {
+		a.secret = []byte(conf.JWTSecret)
+	}
+	return a, nil
+}
+
+// Authenticate the API key or JWT and return the scope of the credentials.
+func (a *Authenticator) Authenticate(token string) (_ *Scope, err error) {
+	if token == "" {
+		return nil, status.Error(codes.Unauthenticated, "missing credentials: specify an api key or bearer token")
+	}
+
+	// Compare every key in constant time so that keys cannot be guessed from timing
+	var scope *Scope
+	for _, key := range a.keys {
+		if subtle.ConstantTimeCompare([]byte(key.Key), []byte(token)) == 1 {
+			scope = &Scope{Subject: key.Name, Accounts: key.Accounts}
+		}
+	}
+
+	if scope != nil {
+		return scope, nil
+	}
+
+	if a.secret != nil {
+		claims := &Claims{}
+		if _, err = jwt.ParseWithClaims(token, claims, a.keyFunc); err == nil {
+			return &Scope{Subject: claims.Subject, Accounts: claims.Accounts}, nil
+		}
+		log.Debug().Err(err).Msg("could not parse jwt")
+	}
+	return nil, status.Error(codes.Unauthenticated, "invalid credentials")
+}
+
+func (a *Authenticator) keyFunc(token *jwt.Token) (interface{}, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		return nil, fmt.Errorf("unexpected signing method %s", token.Header["alg"])
+	}
+	return a.secret, nil
+}
+
+// UnaryInterceptor authenticates unary requests and adds the scope of the credentials
+// to the context of the handler.
+func (a *Authenticator) UnaryInterceptor(ctx context.Context, in interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (out interface{}, err error) {
+	if _, ok := unauthenticated[info.FullMethod]; ok {
+		return handler(ctx, in)
+	}
+
+	var scope *Scope
+	if scope, err = a.Authenticate(tokenFromContext(ctx)); err != nil {
+		log.Info().Str("method", info.FullMethod).Msg("unauthenticated request")
+		return nil, err
+	}
+	return handler(WithScope(ctx, scope), in)
+}
+
+// StreamInterceptor authenticates streams and adds the scope of the credentials to the
+// context of the stream.
+func (a *Authenticator) StreamInterceptor(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	var scope *Scope
+	if scope, err = a.Authenticate(tokenFromContext(stream.Context())); err != nil {
+		log.Info().Str("method", info.FullMethod).Msg("unauthenticated stream")
+		return err
+	}
+	return handler(srv, &scopedStream{ServerStream: stream, ctx: WithScope(stream.Context(), scope)})
+}
+
+// scopedStream replaces the context of a server stream with the authenticated context.
+type scopedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *scopedStream) Context() context.Context {
+	return s.ctx
+}
+
+// tokenFromContext returns the bearer token or API key in the incoming metadata.
+func tokenFromContext(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	for _, value := range md.Get("authorization") {
+		if token, ok := bearerToken(value); ok {
+			return token
+		}
+	}
+
+	if keys := md.Get("x-api-key"); len(keys) > 0 {
+		return strings.TrimSpace(keys[0])
+	}
+	return ""
+}
+
+// tokenFromRequest returns the bearer token or API key in the headers of the request,
+// or in the access_token query parameter since browsers cannot set the headers of
+// WebSocket connections.
+func tokenFromRequest(r *http.Request) string {
+	if token, ok := bearerToken(r.Header.Get("Authorization")); ok {
+		return token
+	}
+
+	if key := r.Header.Get("X-Api-Key"); key != "" {
+		return strings.TrimSpace(key)
+	}
+	return r.URL.Query().Get("access_token")
+}
+
+func bearerToken(value string) (string, bool) {
+	if len(value) > 7 && strings.EqualFold(value[:7], "bearer ") {
+		return strings.TrimSpace(value[7:]), true
+	}
+	return "", false
+}
+
+// Scope is the set of accounts that authenticated credentials may access, specified by
+// email or wallet address. A nil scope allows every account since requests are only
+// scoped if authentication is enabled.
+type Scope struct {
+	Subject  string
+	Accounts []string
+}
+
+// All returns true if the scope allows every account.
+func (s *Scope) All() bool {
+	if s == nil {
+		return true
+	}
+
+	for _, account := range s.Accounts {
+		if account == AllAccounts {
+			return true
+		}
+	}
+	return false
+}
+
+// Allows returns true if any of the identifiers of an account (e.g. its email and
+// wallet address) are in the scope.
+func (s *Scope) Allows(identifiers ...string) bool {
+	if s.All() {
+		return true
+	}
+
+	for _, account := range s.Accounts {
+		for _, id := range identifiers {
+			if id != "" && strings.EqualFold(account, id) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+type scopeKey struct{}
+
+// WithScope returns a context with the scope of the authenticated credentials.
+func WithScope(ctx context.Context, scope *Scope) context.Context {
+	return context.WithValue(ctx, scopeKey{}, scope)
+}
+
+// ScopeFromContext returns the scope of the authenticated credentials, or nil if the
+// request was not authenticated.
+func ScopeFromContext(ctx context.Context) *Scope {
+	scope, _ := ctx.Value(scopeKey{}).(*Scope)
+	return scope
+}
+
+// authorize returns a PermissionDenied error if the account is not in the scope of the
+// credentials of the request.
+func authorize(ctx context.Context, account db.Account) error {
+	if scope := ScopeFromContext(ctx); !scope.Allows(account.Email, account.WalletAddress) {
+		log.Info().Str("subject", scope.Subject).Str("account", account.Email).Msg("account not in scope")
+		return status.Errorf(codes.PermissionDenied, "credentials do not allow access to account %s", account.Email)
+	}
+	return nil
+}
+
+// Claims of the JWTs that may access the integration and demo APIs.
+type Claims struct {
+	jwt.RegisteredClaims
+	Accounts []string `json:"accounts"`
+}
+
+// NewToken signs a JWT with the shared secret for the subject that may access the
+// accounts until the token expires. Specify AllAccounts to allow every account.
+func NewToken(secret, subject string, accounts []string, expires time.Duration) (_ string, err error) {
+	if secret == "" {
+		return "", errors.New("a jwt secret is required to sign tokens")
+	}
+
+	now := time.Now()
+	claims := &Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:  subject,
+			IssuedAt: jwt.NewNumericDate(now),
+		},
+		Accounts: accounts,
+	}
+
+	if expires > 0 {
+		claims.ExpiresAt = jwt.NewNumericDate(now.Add(expires))
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+}
+
+// TokenCredentials sends an API key or JWT as a bearer token with every request made
+// by a gRPC client of the integration and demo APIs.
+type TokenCredentials struct {
+	Token string
+
+	// Refuse to send the token over connections without TLS
+	Secure bool
+}
+
+// GetRequestMetadata implements credentials.PerRPCCredentials
+func (c TokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + c.Token}, nil
+}
+
+// RequireTransportSecurity implements credentials.PerRPCCredentials
+func (c TokenCredentials) RequireTransportSecurity() bool {
+	return c.Secure
+}
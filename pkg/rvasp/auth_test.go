@@ -0,0 +1,258 @@
+package rvasp_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+	"github.com/trisacrypto/testnet/pkg/rvasp"
+	"github.com/trisacrypto/testnet/pkg/rvasp/config"
+	"github.com/trisacrypto/testnet/pkg/rvasp/harness"
+	"github.com/trisacrypto/testnet/pkg/rvasp/jsonpb"
+	pb "github.com/trisacrypto/testnet/pkg/rvasp/pb/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	jwtSecret = "the rvasps are not a bank"
+	maryKey   = "mary-0f6b3c9f7a2e4d1c8b5a"
+	adminKey  = "admin-9d2e6f1a4b7c3e8d5f0a"
+)
+
+func TestAuthentication(t *testing.T) {
+	keys := filepath.Join(t.TempDir(), "keys.yml")
+	require.NoError(t, os.WriteFile(keys, []byte("keys:\n  - {name: mary, key: "+maryKey+", accounts: [mary@alicevasp.us]}\n  - {name: admin, key: "+adminKey+", accounts: ['*']}\n"), 0644))
+
+	h := harness.NewWithConfig(t, func(conf *config.Config) {
+		conf.Auth = config.AuthConfig{KeysPath: keys, JWTSecret: jwtSecret}
+	}, "alice", "bob")
+	alice := h.VASP("alice")
+	ctx := context.Background()
+
+	// dial returns an integration client that authenticates with the token
+	dial := func(token string) pb.TRISAIntegrationClient {
+		cc, err := alice.Dial(grpc.WithPerRPCCredentials(rvasp.TokenCredentials{Token: token}))
+		require.NoError(t, err)
+		t.Cleanup(func() { cc.Close() })
+		return pb.NewTRISAIntegrationClient(cc)
+	}
+
+	// Requests without credentials are rejected except for the status
+	_, err := alice.Client.AccountStatus(ctx, &pb.AccountRequest{Account: "mary@alicevasp.us"})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = alice.Client.Status(ctx, &pb.Empty{})
+	require.NoError(t, err)
+
+	_, err = dial("not-a-key").AccountStatus(ctx, &pb.AccountRequest{Account: "mary@alicevasp.us"})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// API keys may only access the accounts in their scope
+	mary := dial(maryKey)
+	account, err := mary.AccountStatus(ctx, &pb.AccountRequest{Account: "mary@alicevasp.us", NoTransactions: true})
+	require.NoError(t, err)
+	require.Equal(t, "mary@alicevasp.us", account.Email)
+
+	_, err = mary.AccountStatus(ctx, &pb.AccountRequest{Account: "jane@alicevasp.us"})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = mary.Transfer(ctx, &pb.TransferRequest{Account: "jane@alicevasp.us", Beneficiary: "robert@bobvasp.co.uk", Amount: 0.1, AssetType: "Bitcoin"})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	transfer, err := mary.Transfer(ctx, &pb.TransferRequest{Account: "mary@alicevasp.us", Beneficiary: "robert@bobvasp.co.uk", Amount: 0.1, AssetType: "Bitcoin"})
+	require.NoError(t, err)
+	require.Nil(t, transfer.Error)
+	require.Equal(t, pb.TransactionState_COMPLETED, transfer.Transaction.State)
+
+	_, err = mary.TransactionHistory(ctx, &pb.TransactionHistoryRequest{EnvelopeId: transfer.Transaction.EnvelopeId})
+	require.NoError(t, err)
+
+	// JWTs are scoped by their accounts claim
+	token, err := rvasp.NewToken(jwtSecret, "jane", []string{"jane@alicevasp.us"}, time.Hour)
+	require.NoError(t, err)
+	jane := dial(token)
+
+	_, err = jane.AccountStatus(ctx, &pb.AccountRequest{Account: "jane@alicevasp.us", NoTransactions: true})
+	require.NoError(t, err)
+
+	_, err = jane.AccountStatus(ctx, &pb.AccountRequest{Account: "mary@alicevasp.us"})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = jane.TransactionHistory(ctx, &pb.TransactionHistoryRequest{EnvelopeId: transfer.Transaction.EnvelopeId})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	forged, err := rvasp.NewToken("not the secret", "jane", []string{"*"}, time.Hour)
+	require.NoError(t, err)
+	_, err = dial(forged).AccountStatus(ctx, &pb.AccountRequest{Account: "jane@alicevasp.us"})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// Webhooks for every account require credentials for every account
+	_, err = mary.RegisterWebhook(ctx, &pb.Webhook{Url: "https://example.com/all"})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	admin := dial(adminKey)
+	all, err := admin.RegisterWebhook(ctx, &pb.Webhook{Url: "https://example.com/all"})
+	require.NoError(t, err)
+
+	_, err = mary.RegisterWebhook(ctx, &pb.Webhook{Url: "https://example.com/mary", Account: "mary@alicevasp.us"})
+	require.NoError(t, err)
+
+	webhooks, err := mary.ListWebhooks(ctx, &pb.Empty{})
+	require.NoError(t, err)
+	require.Len(t, webhooks.Webhooks, 1)
+	require.Equal(t, "mary@alicevasp.us", webhooks.Webhooks[0].Account)
+
+	webhooks, err = admin.ListWebhooks(ctx, &pb.Empty{})
+	require.NoError(t, err)
+	require.Len(t, webhooks.Webhooks, 2)
+
+	_, err = mary.DeleteWebhook(ctx, &pb.Webhook{Id: all.Id})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = admin.DeleteWebhook(ctx, &pb.Webhook{Id: all.Id})
+	require.NoError(t, err)
+}
+
+func TestLiveUpdatesScope(t *testing.T) {
+	h := harness.NewWithConfig(t, func(conf *config.Config) {
+		conf.Auth = config.AuthConfig{JWTSecret: jwtSecret}
+	}, "alice", "bob")
+	alice := h.VASP("alice")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// connect opens a live updates stream that authenticates with a token for the accounts
+	connect := func(client string, accounts ...string) (pb.TRISAIntegrationClient, pb.TRISADemo_LiveUpdatesClient) {
+		token, err := rvasp.NewToken(jwtSecret, client, accounts, time.Hour)
+		require.NoError(t, err)
+
+		cc, err := alice.Dial(grpc.WithPerRPCCredentials(rvasp.TokenCredentials{Token: token}))
+		require.NoError(t, err)
+		t.Cleanup(func() { cc.Close() })
+
+		stream, err := pb.NewTRISADemoClient(cc).LiveUpdates(ctx)
+		require.NoError(t, err)
+		return pb.NewTRISAIntegrationClient(cc), stream
+	}
+
+	// command sends the command on the stream and returns the acknowledgement, skipping
+	// any updates received before it
+	command := func(stream pb.TRISADemo_LiveUpdatesClient, cmd *pb.Command) (*pb.Message, error) {
+		if err := stream.Send(cmd); err != nil {
+			return nil, err
+		}
+
+		for {
+			msg, err := stream.Recv()
+			if err != nil || msg.Type == cmd.Type {
+				return msg, err
+			}
+		}
+	}
+
+	replay := &pb.Command{Type: pb.RPC_REPLAY, Id: 2, Client: "mary", Request: &pb.Command_Replay{Replay: &pb.Replay{}}}
+
+	// Updates about transfers of other accounts are not sent to streams scoped to mary
+	admin, _ := connect("admin", rvasp.AllAccounts)
+	_, err := admin.Transfer(ctx, &pb.TransferRequest{Account: "alice@alicevasp.us", Beneficiary: "robert@bobvasp.co.uk", Amount: 0.1, AssetType: "Bitcoin"})
+	require.NoError(t, err)
+
+	mary, stream := connect("mary", "mary@alicevasp.us")
+	msg, err := command(stream, replay)
+	require.NoError(t, err)
+	require.Equal(t, "replayed 0 updates", msg.Update)
+
+	_, err = mary.Transfer(ctx, &pb.TransferRequest{Account: "mary@alicevasp.us", Beneficiary: "robert@bobvasp.co.uk", Amount: 0.1, AssetType: "Bitcoin"})
+	require.NoError(t, err)
+
+	replay.Id = 3
+	msg, err = command(stream, replay)
+	require.NoError(t, err)
+	require.NotEqual(t, "replayed 0 updates", msg.Update)
+
+	// Subscribing to accounts in the scope by email or wallet address is allowed
+	account, err := mary.AccountStatus(ctx, &pb.AccountRequest{Account: "mary@alicevasp.us", NoTransactions: true})
+	require.NoError(t, err)
+
+	subscribe := func(id uint64, accounts ...string) (*pb.Message, error) {
+		return command(stream, &pb.Command{
+			Type:    pb.RPC_SUBSCRIBE,
+			Id:      id,
+			Client:  "mary",
+			Request: &pb.Command_Subscription{Subscription: &pb.Subscription{Accounts: accounts}},
+		})
+	}
+
+	msg, err = subscribe(4, "mary@alicevasp.us", account.WalletAddress)
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), msg.Id)
+
+	// Subscribing to an account outside of the scope closes the stream
+	_, err = subscribe(5, "jane@alicevasp.us")
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func TestGatewayAuthentication(t *testing.T) {
+	h := harness.NewWithConfig(t, func(conf *config.Config) {
+		conf.Auth = config.AuthConfig{JWTSecret: jwtSecret}
+	}, "alice")
+	srv := httptest.NewServer(h.VASP("alice").Server.GatewayHandler())
+	defer srv.Close()
+
+	token, err := rvasp.NewToken(jwtSecret, "mary", []string{"mary@alicevasp.us"}, time.Hour)
+	require.NoError(t, err)
+
+	// get returns the status code of the request with the headers
+	get := func(path string, headers ...string) int {
+		req, err := http.NewRequest(http.MethodGet, srv.URL+path, nil)
+		require.NoError(t, err)
+		for i := 0; i < len(headers); i += 2 {
+			req.Header.Set(headers[i], headers[i+1])
+		}
+
+		rep, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		rep.Body.Close()
+		return rep.StatusCode
+	}
+
+	require.Equal(t, http.StatusOK, get("/v1/status"))
+	require.Equal(t, http.StatusUnauthorized, get("/v1/accounts/mary@alicevasp.us"))
+	require.Equal(t, http.StatusOK, get("/v1/accounts/mary@alicevasp.us", "Authorization", "Bearer "+token))
+	require.Equal(t, http.StatusOK, get("/v1/accounts/mary@alicevasp.us", "X-Api-Key", token))
+	require.Equal(t, http.StatusForbidden, get("/v1/accounts/jane@alicevasp.us", "Authorization", "Bearer "+token))
+
+	// WebSocket connections are authenticated before the connection is upgraded
+	endpoint := "ws" + strings.TrimPrefix(srv.URL, "http") + rvasp.LiveUpdatesPath + "?client=browser"
+	_, rep, err := websocket.DefaultDialer.Dial(endpoint, nil)
+	require.Error(t, err)
+	require.Equal(t, http.StatusUnauthorized, rep.StatusCode)
+
+	conn, _, err := websocket.DefaultDialer.Dial(endpoint+"&access_token="+token, nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	data, err := jsonpb.Marshal(&pb.Command{
+		Type:    pb.RPC_ACCOUNT,
+		Id:      1,
+		Request: &pb.Command_Account{Account: &pb.AccountRequest{Account: "mary@alicevasp.us", NoTransactions: true}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, data))
+
+	_, data, err = conn.ReadMessage()
+	require.NoError(t, err)
+	msg := &pb.Message{}
+	require.NoError(t, jsonpb.Unmarshal(data, msg))
+	require.Equal(t, "mary@alicevasp.us", msg.GetAccount().Email)
+}
@@ -25,13 +25,14 @@ func New(bufSize int) *GRPCListener {
 }
 
 func (g *GRPCListener) Connect(creds grpc.DialOption) (err error) {
-	if g.Conn, err = grpc.DialContext(context.Background(), "alice", grpc.WithContextDialer(g.dialer), creds); err != nil {
+	if g.Conn, err = grpc.DialContext(context.Background(), "alice", grpc.WithContextDialer(g.Dialer), creds); err != nil {
 		return err
 	}
 	return nil
 }
 
-func (g *GRPCListener) dialer(context.Context, string) (net.Conn, error) {
+// Dialer connects to the bufconn listener and can be used as a gRPC context dialer.
+func (g *GRPCListener) Dialer(context.Context, string) (net.Conn, error) {
 	return g.Listener.Dial()
 }
 
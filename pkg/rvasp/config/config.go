@@ -1,13 +1,17 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/kelseyhightower/envconfig"
 	"github.com/rs/zerolog"
 	activity "github.com/trisacrypto/directory/pkg/utils/activity"
+	protocol "github.com/trisacrypto/trisa/pkg/trisa/api/v1beta1"
+	"gopkg.in/yaml.v3"
 )
 
 // Config uses envconfig to load required settings from the environment and
@@ -15,20 +19,30 @@ import (
 //
 // TODO: also store separate signing key instead of using the cert key.
 type Config struct {
-	Name           string          `envconfig:"RVASP_NAME"`
-	BindAddr       string          `envconfig:"RVASP_BIND_ADDR" default:":4434"`
-	TRISABindAddr  string          `envconfig:"RVASP_TRISA_BIND_ADDR" default:":4435"`
-	FixturesPath   string          `envconfig:"RVASP_FIXTURES_PATH"`
-	CertPath       string          `envconfig:"RVASP_CERT_PATH"`
-	TrustChainPath string          `envconfig:"RVASP_TRUST_CHAIN_PATH"`
-	AsyncInterval  time.Duration   `envconfig:"RVASP_ASYNC_INTERVAL" default:"1m"`
-	AsyncNotBefore time.Duration   `envconfig:"RVASP_ASYNC_NOT_BEFORE" default:"5m"`
-	AsyncNotAfter  time.Duration   `envconfig:"RVASP_ASYNC_NOT_AFTER" default:"1h"`
-	ConsoleLog     bool            `envconfig:"RVASP_CONSOLE_LOG" default:"false"`
-	LogLevel       LogLevelDecoder `envconfig:"RVASP_LOG_LEVEL" default:"info"`
-	GDS            GDSConfig
-	Database       DatabaseConfig
-	Activity       activity.Config
+	Name            string          `envconfig:"RVASP_NAME"`
+	BindAddr        string          `envconfig:"RVASP_BIND_ADDR" default:":4434"`
+	TRISABindAddr   string          `envconfig:"RVASP_TRISA_BIND_ADDR" default:":4435"`
+	FixturesPath    string          `envconfig:"RVASP_FIXTURES_PATH"`
+	TenantsPath     string          `envconfig:"RVASP_TENANTS_PATH"`
+	CertPath        string          `envconfig:"RVASP_CERT_PATH"`
+	TrustChainPath  string          `envconfig:"RVASP_TRUST_CHAIN_PATH"`
+	AsyncInterval   time.Duration   `envconfig:"RVASP_ASYNC_INTERVAL" default:"1m"`
+	AsyncNotBefore  time.Duration   `envconfig:"RVASP_ASYNC_NOT_BEFORE" default:"5m"`
+	AsyncNotAfter   time.Duration   `envconfig:"RVASP_ASYNC_NOT_AFTER" default:"1h"`
+	RecordEnvelopes bool            `envconfig:"RVASP_RECORD_ENVELOPES" default:"false"`
+	DemoPacing      time.Duration   `envconfig:"RVASP_DEMO_PACING" default:"500ms"`
+	ConsoleLog      bool            `envconfig:"RVASP_CONSOLE_LOG" default:"false"`
+	LogLevel        LogLevelDecoder `envconfig:"RVASP_LOG_LEVEL" default:"info"`
+	GDS             GDSConfig
+	Database        DatabaseConfig
+	Webhooks        WebhooksConfig
+	Gateway         GatewayConfig
+	Updates         UpdatesConfig
+	Auth            AuthConfig
+	RateLimit       RateLimitConfig `split_words:"true"`
+	Counterparties  CounterpartiesConfig
+	Screening       ScreeningConfig
+	Activity        activity.Config
 }
 
 // GDSConfig is the configuration for connecting to GDS
@@ -43,12 +57,146 @@ type DatabaseConfig struct {
 	MaxRetries int    `split_words:"true" default:"0"`
 }
 
+// WebhooksConfig is the configuration for delivering transaction events to webhooks.
+// Failed deliveries are retried up to the maximum number of attempts, doubling the
+// backoff between each attempt up to the maximum backoff. Webhooks may only be
+// registered for public addresses unless private addresses are allowed, e.g. to
+// deliver events to a receiver on the local machine during development.
+type WebhooksConfig struct {
+	MaxAttempts  int           `split_words:"true" default:"5"`
+	Backoff      time.Duration `default:"1s"`
+	MaxBackoff   time.Duration `split_words:"true" default:"1m"`
+	Timeout      time.Duration `default:"10s"`
+	AllowPrivate bool          `split_words:"true" default:"false"`
+}
+
+// GatewayConfig is the configuration of the HTTP gateway that serves the REST API of
+// the TRISAIntegration service and the WebSocket LiveUpdates stream; it is only served
+// if a bind address is specified. Browsers may only connect from the allowed origins;
+// if no origins are specified, only pages served from the same host are allowed and
+// "*" allows any origin.
+type GatewayConfig struct {
+	BindAddr string `split_words:"true"`
+	Origins  []string
+}
+
+// UpdatesConfig is the configuration of the LiveUpdates streams. The most recent
+// updates are buffered for replay and each client has a bounded queue of messages
+// waiting to be sent. When the queue of a slow client is full, the oldest message is
+// dropped or the client is disconnected depending on the slow consumer policy.
+type UpdatesConfig struct {
+	Buffer       int                `default:"1000"`
+	Queue        int                `default:"256"`
+	SlowConsumer SlowConsumerPolicy `split_words:"true" default:"drop"`
+}
+
+// AuthConfig is the configuration of the authentication of the integration API, which
+// is served over TLS if a certificate and key are specified. Requests are only
+// authenticated if an API keys file or a JWT secret is specified; each API key or JWT
+// is scoped to the accounts that it may access.
+type AuthConfig struct {
+	KeysPath    string `split_words:"true"`
+	JWTSecret   string `split_words:"true"`
+	TLSCertPath string `split_words:"true"`
+	TLSKeyPath  string `split_words:"true"`
+}
+
+// Enabled returns true if requests to the integration API must be authenticated.
+func (c AuthConfig) Enabled() bool {
+	return c.KeysPath != "" || c.JWTSecret != ""
+}
+
+// TLS returns true if the integration API is served over TLS.
+func (c AuthConfig) TLS() bool {
+	return c.TLSCertPath != "" && c.TLSKeyPath != ""
+}
+
+// Validate that the TLS certificate and key are specified together.
+func (c AuthConfig) Validate() error {
+	if (c.TLSCertPath == "") != (c.TLSKeyPath == "") {
+		return errors.New("invalid auth config: specify both a tls cert path and tls key path")
+	}
+	return nil
+}
+
+// RateLimitConfig is the configuration of the per-peer rate limits of the TRISA
+// service. Each peer, identified by the common name of its mTLS certificate, has a
+// token bucket that refills at the rate (requests per second) up to the burst and rate
+// limiting is disabled if the rate is zero. Peers may be given their own rate, e.g.
+// RVASP_RATE_LIMIT_PEERS=api.bob.vaspbot.com:50, and if a quota is specified each peer
+// may only send that many requests per quota window.
+type RateLimitConfig struct {
+	Rate        float64 `default:"0"`
+	Burst       int     `default:"20"`
+	Peers       map[string]float64
+	Quota       int           `default:"0"`
+	QuotaWindow time.Duration `split_words:"true" default:"24h"`
+}
+
+// Enabled returns true if requests from TRISA peers are rate limited.
+func (c RateLimitConfig) Enabled() bool {
+	return c.Rate > 0 || len(c.Peers) > 0 || c.Quota > 0
+}
+
+// CounterpartiesConfig determines which counterparty VASPs the rVASP does business
+// with, to simulate a VASP that refuses transfers with particular counterparties.
+// Counterparties are identified by the common name of their certificate and their
+// country is the ISO 3166 country of registration of the VASP. If an allow list is
+// specified, only the counterparties in the list are allowed; counterparties in a deny
+// list are always refused. Refused transfers are rejected with the TRISA error code.
+type CounterpartiesConfig struct {
+	AllowPeers     []string         `split_words:"true" yaml:"allow_peers"`
+	DenyPeers      []string         `split_words:"true" yaml:"deny_peers"`
+	AllowCountries []string         `split_words:"true" yaml:"allow_countries"`
+	DenyCountries  []string         `split_words:"true" yaml:"deny_countries"`
+	ErrorCode      ErrorCodeDecoder `split_words:"true" yaml:"error_code" default:"REJECTED"`
+}
+
+// Enabled returns true if any counterparty rules are specified.
+func (c CounterpartiesConfig) Enabled() bool {
+	return len(c.AllowPeers) > 0 || len(c.DenyPeers) > 0 || len(c.AllowCountries) > 0 || len(c.DenyCountries) > 0
+}
+
+// ScreeningConfig is the configuration of the simulated sanctions screening of the
+// originators of transfers received by the rVASP and of the beneficiaries returned to
+// the rVASP, which is enabled if a watchlist file (CSV or JSON) is specified. A name
+// matches a watchlist entry if their similarity is at least the threshold, from 0 to 1.
+// Transfers received from peers that match are rejected or held for review depending
+// on the action; transfers sent by the rVASP that match are always rejected since the
+// beneficiary has already replied.
+type ScreeningConfig struct {
+	WatchlistPath string          `split_words:"true"`
+	Threshold     float64         `default:"0.85"`
+	Action        ScreeningAction `default:"reject"`
+}
+
+// Enabled returns true if names are screened against a watchlist.
+func (c ScreeningConfig) Enabled() bool {
+	return c.WatchlistPath != ""
+}
+
+// Validate that the threshold is a similarity score.
+func (c ScreeningConfig) Validate() error {
+	if c.Enabled() && (c.Threshold <= 0 || c.Threshold > 1) {
+		return fmt.Errorf("invalid screening config: threshold %v must be greater than 0 and at most 1", c.Threshold)
+	}
+	return nil
+}
+
 // New creates a new Config object, loading environment variables and defaults.
 func New() (_ *Config, err error) {
 	var conf Config
 	if err = envconfig.Process("rvasp", &conf); err != nil {
 		return nil, err
 	}
+
+	if err = conf.Auth.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err = conf.Screening.Validate(); err != nil {
+		return nil, err
+	}
 	return &conf, nil
 }
 
@@ -78,3 +226,99 @@ func (ll *LogLevelDecoder) Decode(value string) error {
 	}
 	return nil
 }
+
+// ErrorCodeDecoder deserializes a TRISA protocol error code from its name, e.g.
+// REJECTED or HIGH_RISK, or from its number.
+type ErrorCodeDecoder protocol.Error_Code
+
+// Decode implements envconfig.Decoder
+func (c *ErrorCodeDecoder) Decode(value string) error {
+	value = strings.TrimSpace(strings.ToUpper(value))
+	if code, ok := protocol.Error_Code_value[value]; ok {
+		*c = ErrorCodeDecoder(code)
+		return nil
+	}
+
+	if code, err := strconv.ParseInt(value, 10, 32); err == nil {
+		if _, ok := protocol.Error_Code_name[int32(code)]; ok {
+			*c = ErrorCodeDecoder(code)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown trisa error code %q", value)
+}
+
+// UnmarshalYAML decodes the error code from the name or number in a YAML file.
+func (c *ErrorCodeDecoder) UnmarshalYAML(value *yaml.Node) error {
+	return c.Decode(value.Value)
+}
+
+// SlowConsumerPolicy determines what happens when the queue of a LiveUpdates client is
+// full because the client is not receiving messages as fast as they are sent.
+type SlowConsumerPolicy uint8
+
+const (
+	DropOldest SlowConsumerPolicy = iota // drop the oldest queued message
+	Disconnect                           // close the stream of the client
+)
+
+// Decode implements envconfig.Decoder
+func (p *SlowConsumerPolicy) Decode(value string) error {
+	value = strings.TrimSpace(strings.ToLower(value))
+	switch value {
+	case "drop", "drop_oldest":
+		*p = DropOldest
+	case "disconnect":
+		*p = Disconnect
+	default:
+		return fmt.Errorf("unknown slow consumer policy %q", value)
+	}
+	return nil
+}
+
+// String returns the name of the policy as it is decoded.
+func (p SlowConsumerPolicy) String() string {
+	switch p {
+	case DropOldest:
+		return "drop"
+	case Disconnect:
+		return "disconnect"
+	default:
+		return fmt.Sprintf("SlowConsumerPolicy(%d)", uint8(p))
+	}
+}
+
+// ScreeningAction determines what happens to a transfer received from a peer when the
+// originator matches an entry of the sanctions watchlist.
+type ScreeningAction uint8
+
+const (
+	RejectHits ScreeningAction = iota // reject the transfer with a compliance check failure
+	ReviewHits                        // hold the transfer for review with a pending reply
+)
+
+// Decode implements envconfig.Decoder
+func (a *ScreeningAction) Decode(value string) error {
+	value = strings.TrimSpace(strings.ToLower(value))
+	switch value {
+	case "reject":
+		*a = RejectHits
+	case "review", "hold":
+		*a = ReviewHits
+	default:
+		return fmt.Errorf("unknown screening action %q", value)
+	}
+	return nil
+}
+
+// String returns the name of the action as it is decoded.
+func (a ScreeningAction) String() string {
+	switch a {
+	case RejectHits:
+		return "reject"
+	case ReviewHits:
+		return "review"
+	default:
+		return fmt.Sprintf("ScreeningAction(%d)", uint8(a))
+	}
+}
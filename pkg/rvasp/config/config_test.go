@@ -0,0 +1,57 @@
+package config_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trisacrypto/testnet/pkg/rvasp/config"
+	protocol "github.com/trisacrypto/trisa/pkg/trisa/api/v1beta1"
+)
+
+func TestRateLimitConfig(t *testing.T) {
+	conf, err := config.New()
+	require.NoError(t, err)
+	require.False(t, conf.RateLimit.Enabled())
+
+	t.Setenv("RVASP_RATE_LIMIT_RATE", "2.5")
+	t.Setenv("RVASP_RATE_LIMIT_BURST", "5")
+	t.Setenv("RVASP_RATE_LIMIT_PEERS", "api.bob.vaspbot.com:50,api.evil.vaspbot.com:0.1")
+	t.Setenv("RVASP_RATE_LIMIT_QUOTA", "1000")
+	t.Setenv("RVASP_RATE_LIMIT_QUOTA_WINDOW", "1h")
+
+	conf, err = config.New()
+	require.NoError(t, err)
+	require.True(t, conf.RateLimit.Enabled())
+	require.Equal(t, config.RateLimitConfig{
+		Rate:        2.5,
+		Burst:       5,
+		Peers:       map[string]float64{"api.bob.vaspbot.com": 50, "api.evil.vaspbot.com": 0.1},
+		Quota:       1000,
+		QuotaWindow: time.Hour,
+	}, conf.RateLimit)
+}
+
+func TestCounterpartiesConfig(t *testing.T) {
+	conf, err := config.New()
+	require.NoError(t, err)
+	require.False(t, conf.Counterparties.Enabled())
+	require.Equal(t, config.ErrorCodeDecoder(protocol.Rejected), conf.Counterparties.ErrorCode)
+
+	t.Setenv("RVASP_COUNTERPARTIES_DENY_PEERS", "api.evil.vaspbot.com")
+	t.Setenv("RVASP_COUNTERPARTIES_ALLOW_COUNTRIES", "US,GB")
+	t.Setenv("RVASP_COUNTERPARTIES_ERROR_CODE", "high_risk")
+
+	conf, err = config.New()
+	require.NoError(t, err)
+	require.True(t, conf.Counterparties.Enabled())
+	require.Equal(t, []string{"api.evil.vaspbot.com"}, conf.Counterparties.DenyPeers)
+	require.Equal(t, []string{"US", "GB"}, conf.Counterparties.AllowCountries)
+	require.Equal(t, config.ErrorCodeDecoder(protocol.HighRisk), conf.Counterparties.ErrorCode)
+
+	var code config.ErrorCodeDecoder
+	require.NoError(t, code.Decode("90"))
+	require.Equal(t, config.ErrorCodeDecoder(protocol.ComplianceCheckFail), code)
+	require.EqualError(t, code.Decode("NOT_A_CODE"), `unknown trisa error code "NOT_A_CODE"`)
+	require.EqualError(t, code.Decode("12345"), `unknown trisa error code "12345"`)
+}
@@ -0,0 +1,81 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// APIKeysConfig describes the static API keys that may access the integration API. It
+// is loaded from the YAML file specified by RVASP_AUTH_KEYS_PATH, for example:
+//
+//	keys:
+//	  - name: alice-tester
+//	    key: 0f6b3c9f7a2e4d1c8b5a
+//	    accounts:
+//	      - mary@alicevasp.us
+//	  - name: operator
+//	    key: 9d2e6f1a4b7c3e8d5f0a
+//	    accounts:
+//	      - "*"
+//
+// Accounts are specified by email or wallet address and "*" allows every account.
+type APIKeysConfig struct {
+	Keys []APIKeyConfig `yaml:"keys"`
+}
+
+// APIKeyConfig is a single API key and the accounts it may access.
+type APIKeyConfig struct {
+	Name     string   `yaml:"name"`
+	Key      string   `yaml:"key"`
+	Accounts []string `yaml:"accounts"`
+}
+
+// LoadAPIKeys reads and validates the API keys file.
+func LoadAPIKeys(path string) (keys []APIKeyConfig, err error) {
+	var data []byte
+	if data, err = os.ReadFile(path); err != nil {
+		return nil, fmt.Errorf("could not read api keys: %s", err)
+	}
+
+	var conf APIKeysConfig
+	if err = yaml.Unmarshal(data, &conf); err != nil {
+		return nil, fmt.Errorf("could not parse api keys: %s", err)
+	}
+
+	if err = conf.Validate(); err != nil {
+		return nil, err
+	}
+	return conf.Keys, nil
+}
+
+// Validate that each key is named, has a secret and that names and secrets are unique.
+func (c APIKeysConfig) Validate() error {
+	if len(c.Keys) == 0 {
+		return errors.New("invalid api keys: no keys specified")
+	}
+
+	names := make(map[string]struct{}, len(c.Keys))
+	secrets := make(map[string]struct{}, len(c.Keys))
+	for i, key := range c.Keys {
+		switch {
+		case key.Name == "":
+			return fmt.Errorf("invalid api keys: key %d is missing a name", i)
+		case key.Key == "":
+			return fmt.Errorf("invalid api keys: %s is missing a key", key.Name)
+		}
+
+		if _, ok := names[key.Name]; ok {
+			return fmt.Errorf("invalid api keys: duplicate key name %s", key.Name)
+		}
+		names[key.Name] = struct{}{}
+
+		if _, ok := secrets[key.Key]; ok {
+			return fmt.Errorf("invalid api keys: %s reuses the key of another name", key.Name)
+		}
+		secrets[key.Key] = struct{}{}
+	}
+	return nil
+}
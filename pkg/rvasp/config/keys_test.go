@@ -0,0 +1,55 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trisacrypto/testnet/pkg/rvasp/config"
+)
+
+func TestAuthConfig(t *testing.T) {
+	t.Setenv("RVASP_AUTH_KEYS_PATH", "keys.yml")
+	t.Setenv("RVASP_AUTH_JWT_SECRET", "secret")
+	t.Setenv("RVASP_AUTH_TLS_CERT_PATH", "cert.pem")
+	t.Setenv("RVASP_AUTH_TLS_KEY_PATH", "key.pem")
+
+	conf, err := config.New()
+	require.NoError(t, err)
+	require.Equal(t, config.AuthConfig{KeysPath: "keys.yml", JWTSecret: "secret", TLSCertPath: "cert.pem", TLSKeyPath: "key.pem"}, conf.Auth)
+	require.True(t, conf.Auth.Enabled())
+	require.True(t, conf.Auth.TLS())
+
+	t.Setenv("RVASP_AUTH_TLS_KEY_PATH", "")
+	_, err = config.New()
+	require.EqualError(t, err, "invalid auth config: specify both a tls cert path and tls key path")
+}
+
+func TestLoadAPIKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.yml")
+	require.NoError(t, os.WriteFile(path, []byte("keys:\n  - {name: mary, key: abc, accounts: [mary@alicevasp.us]}\n  - {name: admin, key: xyz, accounts: ['*']}\n"), 0644))
+
+	keys, err := config.LoadAPIKeys(path)
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+	require.Equal(t, []string{"mary@alicevasp.us"}, keys[0].Accounts)
+	require.Equal(t, []string{"*"}, keys[1].Accounts)
+
+	testCases := []struct {
+		keys string
+		err  string
+	}{
+		{"keys: []", "invalid api keys: no keys specified"},
+		{"keys:\n  - {key: abc}", "invalid api keys: key 0 is missing a name"},
+		{"keys:\n  - {name: mary}", "invalid api keys: mary is missing a key"},
+		{"keys:\n  - {name: mary, key: abc}\n  - {name: mary, key: xyz}", "invalid api keys: duplicate key name mary"},
+		{"keys:\n  - {name: mary, key: abc}\n  - {name: jane, key: abc}", "invalid api keys: jane reuses the key of another name"},
+	}
+
+	for _, tc := range testCases {
+		require.NoError(t, os.WriteFile(path, []byte(tc.keys), 0644))
+		_, err := config.LoadAPIKeys(path)
+		require.EqualError(t, err, tc.err)
+	}
+}
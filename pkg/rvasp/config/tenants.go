@@ -0,0 +1,120 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TenantsConfig describes multiple rVASPs that are hosted in a single process and
+// share a database connection pool. It is loaded from the YAML file specified by
+// RVASP_TENANTS_PATH, for example:
+//
+//	vasps:
+//	  - name: api.alice.vaspbot.com
+//	    bind_addr: ":5434"
+//	    trisa_bind_addr: ":5435"
+//	    cert_path: fixtures/certs/alice/cert.pem
+//	    trust_chain_path: fixtures/certs/alice/cert.pem
+//	    gateway_bind_addr: ":5436"
+//	    counterparties:
+//	      deny_peers: [api.evil.vaspbot.com]
+//	      error_code: HIGH_RISK
+//
+// Settings that are not specific to a VASP (database, GDS, async intervals, logging)
+// are shared by all tenants and are loaded from the environment as usual.
+type TenantsConfig struct {
+	VASPs []TenantConfig `yaml:"vasps"`
+}
+
+// TenantConfig is the configuration of a single rVASP hosted in a multi-tenant process.
+type TenantConfig struct {
+	Name           string `yaml:"name"`
+	BindAddr       string `yaml:"bind_addr"`
+	TRISABindAddr  string `yaml:"trisa_bind_addr"`
+	CertPath       string `yaml:"cert_path"`
+	TrustChainPath string `yaml:"trust_chain_path"`
+
+	// Optional, the HTTP gateway is not served for the tenant if not specified
+	GatewayBindAddr string `yaml:"gateway_bind_addr"`
+
+	// Optional, replaces the shared counterparty rules for the tenant if specified
+	Counterparties *CounterpartiesConfig `yaml:"counterparties"`
+}
+
+// LoadTenants reads the tenants file and returns a configuration for each rVASP that
+// copies the shared settings from the base configuration.
+func LoadTenants(path string, base *Config) (confs []*Config, err error) {
+	var data []byte
+	if data, err = os.ReadFile(path); err != nil {
+		return nil, fmt.Errorf("could not read tenants config: %s", err)
+	}
+
+	var tenants TenantsConfig
+	if err = yaml.Unmarshal(data, &tenants); err != nil {
+		return nil, fmt.Errorf("could not parse tenants config: %s", err)
+	}
+
+	if err = tenants.Validate(); err != nil {
+		return nil, err
+	}
+
+	confs = make([]*Config, 0, len(tenants.VASPs))
+	for _, tenant := range tenants.VASPs {
+		conf := *base
+		conf.Name = tenant.Name
+		conf.BindAddr = tenant.BindAddr
+		conf.TRISABindAddr = tenant.TRISABindAddr
+		conf.CertPath = tenant.CertPath
+		conf.TrustChainPath = tenant.TrustChainPath
+		conf.Gateway.BindAddr = tenant.GatewayBindAddr
+		if tenant.Counterparties != nil {
+			conf.Counterparties = *tenant.Counterparties
+			if conf.Counterparties.ErrorCode == 0 {
+				conf.Counterparties.ErrorCode = base.Counterparties.ErrorCode
+			}
+		}
+		confs = append(confs, &conf)
+	}
+	return confs, nil
+}
+
+// Validate that each tenant is fully specified and that names and bind addresses are
+// not reused by multiple tenants.
+func (c TenantsConfig) Validate() error {
+	if len(c.VASPs) == 0 {
+		return errors.New("invalid tenants config: no vasps specified")
+	}
+
+	names := make(map[string]struct{}, len(c.VASPs))
+	addrs := make(map[string]struct{}, 3*len(c.VASPs))
+	for i, tenant := range c.VASPs {
+		switch {
+		case tenant.Name == "":
+			return fmt.Errorf("invalid tenants config: vasp %d is missing a name", i)
+		case tenant.BindAddr == "" || tenant.TRISABindAddr == "":
+			return fmt.Errorf("invalid tenants config: %s requires a bind_addr and trisa_bind_addr", tenant.Name)
+		case tenant.CertPath == "" || tenant.TrustChainPath == "":
+			return fmt.Errorf("invalid tenants config: %s requires a cert_path and trust_chain_path", tenant.Name)
+		}
+
+		if _, ok := names[tenant.Name]; ok {
+			return fmt.Errorf("invalid tenants config: duplicate vasp %s", tenant.Name)
+		}
+		names[tenant.Name] = struct{}{}
+
+		for _, addr := range []string{tenant.BindAddr, tenant.TRISABindAddr, tenant.GatewayBindAddr} {
+			if addr == "" {
+				continue
+			}
+
+			if _, ok := addrs[addr]; ok {
+				return fmt.Errorf("invalid tenants config: %s reuses bind address %s", tenant.Name, addr)
+			}
+			addrs[addr] = struct{}{}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trisacrypto/testnet/pkg/rvasp/config"
+	protocol "github.com/trisacrypto/trisa/pkg/trisa/api/v1beta1"
+)
+
+func TestLoadTenants(t *testing.T) {
+	base, err := config.New()
+	require.NoError(t, err)
+	base.Database.DSN = "postgres://localhost/rvasp"
+
+	// The example tenants config should be valid
+	confs, err := config.LoadTenants(filepath.Join("..", "..", "..", "containers", "rvasp", "tenants.yml"), base)
+	require.NoError(t, err)
+	require.Len(t, confs, 3)
+
+	names := []string{"alice", "bob", "evil"}
+	for i, conf := range confs {
+		require.Equal(t, names[i], conf.Name)
+		require.NotEqual(t, base.BindAddr, conf.BindAddr)
+		require.NotEmpty(t, conf.CertPath)
+		require.NotEmpty(t, conf.Gateway.BindAddr)
+
+		// Shared settings should be copied from the base config
+		require.Equal(t, base.Database.DSN, conf.Database.DSN)
+		require.Equal(t, base.AsyncInterval, conf.AsyncInterval)
+	}
+
+	testCases := []struct {
+		tenants string
+		err     string
+	}{
+		{"vasps: []", "invalid tenants config: no vasps specified"},
+		{"vasps:\n  - bind_addr: ':1'", "invalid tenants config: vasp 0 is missing a name"},
+		{"vasps:\n  - name: alice\n    bind_addr: ':1'", "invalid tenants config: alice requires a bind_addr and trisa_bind_addr"},
+		{"vasps:\n  - name: alice\n    bind_addr: ':1'\n    trisa_bind_addr: ':2'", "invalid tenants config: alice requires a cert_path and trust_chain_path"},
+		{"vasps:\n  - {name: alice, bind_addr: ':1', trisa_bind_addr: ':2', cert_path: a, trust_chain_path: a}\n  - {name: alice, bind_addr: ':3', trisa_bind_addr: ':4', cert_path: a, trust_chain_path: a}", "invalid tenants config: duplicate vasp alice"},
+		{"vasps:\n  - {name: alice, bind_addr: ':1', trisa_bind_addr: ':2', cert_path: a, trust_chain_path: a}\n  - {name: bob, bind_addr: ':3', trisa_bind_addr: ':2', cert_path: b, trust_chain_path: b}", "invalid tenants config: bob reuses bind address :2"},
+		{"vasps:\n  - {name: alice, bind_addr: ':1', trisa_bind_addr: ':2', cert_path: a, trust_chain_path: a, gateway_bind_addr: ':5'}\n  - {name: bob, bind_addr: ':3', trisa_bind_addr: ':4', cert_path: b, trust_chain_path: b, gateway_bind_addr: ':5'}", "invalid tenants config: bob reuses bind address :5"},
+	}
+
+	for _, tc := range testCases {
+		path := filepath.Join(t.TempDir(), "tenants.yml")
+		require.NoError(t, os.WriteFile(path, []byte(tc.tenants), 0644))
+		_, err := config.LoadTenants(path, base)
+		require.EqualError(t, err, tc.err)
+	}
+}
+
+func TestTenantCounterparties(t *testing.T) {
+	base, err := config.New()
+	require.NoError(t, err)
+	base.Counterparties.DenyCountries = []string{"KP"}
+
+	path := filepath.Join(t.TempDir(), "tenants.yml")
+	tenants := `vasps:
+  - {name: alice, bind_addr: ':1', trisa_bind_addr: ':2', cert_path: a, trust_chain_path: a}
+  - name: evil
+    bind_addr: ':3'
+    trisa_bind_addr: ':4'
+    cert_path: e
+    trust_chain_path: e
+    counterparties:
+      allow_peers: [api.evil.vaspbot.com]
+      error_code: HIGH_RISK
+  - name: bob
+    bind_addr: ':5'
+    trisa_bind_addr: ':6'
+    cert_path: b
+    trust_chain_path: b
+    counterparties:
+      deny_peers: [api.evil.vaspbot.com]
+`
+	require.NoError(t, os.WriteFile(path, []byte(tenants), 0644))
+
+	confs, err := config.LoadTenants(path, base)
+	require.NoError(t, err)
+	require.Len(t, confs, 3)
+
+	// Tenants without counterparty rules use the shared rules
+	require.Equal(t, base.Counterparties, confs[0].Counterparties)
+
+	// Tenant rules replace the shared rules, using the shared error code if not set
+	require.Equal(t, config.CounterpartiesConfig{AllowPeers: []string{"api.evil.vaspbot.com"}, ErrorCode: config.ErrorCodeDecoder(protocol.HighRisk)}, confs[1].Counterparties)
+	require.Equal(t, config.CounterpartiesConfig{DenyPeers: []string{"api.evil.vaspbot.com"}, ErrorCode: base.Counterparties.ErrorCode}, confs[2].Counterparties)
+
+	require.NoError(t, os.WriteFile(path, []byte("vasps:\n  - {name: alice, bind_addr: ':1', trisa_bind_addr: ':2', cert_path: a, trust_chain_path: a, counterparties: {error_code: NOPE}}"), 0644))
+	_, err = config.LoadTenants(path, base)
+	require.EqualError(t, err, `could not parse tenants config: unknown trisa error code "NOPE"`)
+}
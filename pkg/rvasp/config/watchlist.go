@@ -0,0 +1,119 @@
+package config
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// WatchlistEntry is a sanctioned individual or entity of the screening watchlist,
+// modeled on the entries of the OFAC SDN list. The watchlist is loaded from the CSV or
+// JSON file specified by RVASP_SCREENING_WATCHLIST_PATH. CSV files must have a header
+// row that includes a name column, the other columns are optional and aliases are
+// separated by semicolons, for example:
+//
+//	name,aliases,type,program,country
+//	Tom Marvolo Riddle,Lord Voldemort;You-Know-Who,individual,DARK,GG
+//
+// JSON files contain a list of entries with the same fields.
+type WatchlistEntry struct {
+	Name    string   `json:"name"`
+	Aliases []string `json:"aliases,omitempty"`
+	Type    string   `json:"type,omitempty"`
+	Program string   `json:"program,omitempty"`
+	Country string   `json:"country,omitempty"`
+}
+
+// Names returns the name and aliases of the entry.
+func (e WatchlistEntry) Names() []string {
+	return append([]string{e.Name}, e.Aliases...)
+}
+
+// LoadWatchlist reads the watchlist from a CSV or JSON file depending on its extension.
+func LoadWatchlist(path string) (entries []WatchlistEntry, err error) {
+	var f *os.File
+	if f, err = os.Open(path); err != nil {
+		return nil, fmt.Errorf("could not read watchlist: %s", err)
+	}
+	defer f.Close()
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".csv":
+		entries, err = readWatchlistCSV(f)
+	case ".json":
+		err = json.NewDecoder(f).Decode(&entries)
+	default:
+		return nil, fmt.Errorf("could not read watchlist: unknown extension %q, expected .csv or .json", ext)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("could not parse watchlist: %s", err)
+	}
+
+	for i, entry := range entries {
+		if strings.TrimSpace(entry.Name) == "" {
+			return nil, fmt.Errorf("invalid watchlist: entry %d is missing a name", i)
+		}
+	}
+	return entries, nil
+}
+
+func readWatchlistCSV(r io.Reader) (entries []WatchlistEntry, err error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var header []string
+	if header, err = reader.Read(); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("missing header row")
+		}
+		return nil, err
+	}
+
+	// Map the columns by name so that files may have extra columns in any order
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	if _, ok := columns["name"]; !ok {
+		return nil, errors.New("missing name column")
+	}
+
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	for {
+		var record []string
+		if record, err = reader.Read(); err != nil {
+			if errors.Is(err, io.EOF) {
+				return entries, nil
+			}
+			return nil, err
+		}
+
+		entry := WatchlistEntry{
+			Name:    field(record, "name"),
+			Type:    field(record, "type"),
+			Program: field(record, "program"),
+			Country: field(record, "country"),
+		}
+
+		for _, alias := range strings.Split(field(record, "aliases"), ";") {
+			if alias = strings.TrimSpace(alias); alias != "" {
+				entry.Aliases = append(entry.Aliases, alias)
+			}
+		}
+		entries = append(entries, entry)
+	}
+}
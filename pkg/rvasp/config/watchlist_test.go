@@ -0,0 +1,78 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trisacrypto/testnet/pkg/rvasp/config"
+)
+
+func TestScreeningConfig(t *testing.T) {
+	conf, err := config.New()
+	require.NoError(t, err)
+	require.False(t, conf.Screening.Enabled())
+	require.Equal(t, 0.85, conf.Screening.Threshold)
+	require.Equal(t, config.RejectHits, conf.Screening.Action)
+
+	t.Setenv("RVASP_SCREENING_WATCHLIST_PATH", "watchlist.csv")
+	t.Setenv("RVASP_SCREENING_THRESHOLD", "0.9")
+	t.Setenv("RVASP_SCREENING_ACTION", "Review")
+
+	conf, err = config.New()
+	require.NoError(t, err)
+	require.True(t, conf.Screening.Enabled())
+	require.Equal(t, config.ScreeningConfig{WatchlistPath: "watchlist.csv", Threshold: 0.9, Action: config.ReviewHits}, conf.Screening)
+
+	t.Setenv("RVASP_SCREENING_THRESHOLD", "1.5")
+	_, err = config.New()
+	require.EqualError(t, err, "invalid screening config: threshold 1.5 must be greater than 0 and at most 1")
+
+	var action config.ScreeningAction
+	require.EqualError(t, action.Decode("ignore"), `unknown screening action "ignore"`)
+}
+
+func TestLoadWatchlist(t *testing.T) {
+	// The example watchlist of the containers is valid
+	entries, err := config.LoadWatchlist(filepath.Join("..", "..", "..", "containers", "rvasp", "watchlist.csv"))
+	require.NoError(t, err)
+	require.Len(t, entries, 4)
+
+	dir := t.TempDir()
+	write := func(name, data string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(data), 0644))
+		return path
+	}
+
+	// CSV columns are matched by name and extra columns are ignored
+	entries, err = config.LoadWatchlist(write("sdn.csv", "ent_num,Country,NAME,aliases\n1,GG,Tom Marvolo Riddle,Lord Voldemort; You-Know-Who\n2,,\"Sokolov, Radomil\",\n"))
+	require.NoError(t, err)
+	require.Equal(t, []config.WatchlistEntry{
+		{Name: "Tom Marvolo Riddle", Aliases: []string{"Lord Voldemort", "You-Know-Who"}, Country: "GG"},
+		{Name: "Sokolov, Radomil"},
+	}, entries)
+	require.Equal(t, []string{"Tom Marvolo Riddle", "Lord Voldemort", "You-Know-Who"}, entries[0].Names())
+
+	entries, err = config.LoadWatchlist(write("sdn.json", `[{"name": "Evil Holdings Ltd", "aliases": ["Evil Corp"], "type": "entity", "program": "SDGT"}]`))
+	require.NoError(t, err)
+	require.Equal(t, []config.WatchlistEntry{{Name: "Evil Holdings Ltd", Aliases: []string{"Evil Corp"}, Type: "entity", Program: "SDGT"}}, entries)
+
+	testCases := []struct {
+		name string
+		data string
+		err  string
+	}{
+		{"empty.csv", "", "could not parse watchlist: missing header row"},
+		{"noname.csv", "alias,country\nEvil Corp,GG\n", "could not parse watchlist: missing name column"},
+		{"blank.csv", "name\nTom Riddle\n \n", "invalid watchlist: entry 1 is missing a name"},
+		{"blank.json", `[{"aliases": ["Evil Corp"]}]`, "invalid watchlist: entry 0 is missing a name"},
+		{"sdn.xml", "<sdnList/>", `could not read watchlist: unknown extension ".xml", expected .csv or .json`},
+	}
+
+	for _, tc := range testCases {
+		_, err := config.LoadWatchlist(write(tc.name, tc.data))
+		require.EqualError(t, err, tc.err, tc.name)
+	}
+}
@@ -0,0 +1,704 @@
+package rvasp
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"github.com/trisacrypto/testnet/pkg/rvasp/db"
+	"github.com/trisacrypto/trisa/pkg/ivms101"
+	protocol "github.com/trisacrypto/trisa/pkg/trisa/api/v1beta1"
+	"github.com/trisacrypto/trisa/pkg/trisa/crypto/aesgcm"
+	"github.com/trisacrypto/trisa/pkg/trisa/crypto/rsaoeap"
+	generic "github.com/trisacrypto/trisa/pkg/trisa/data/generic/v1beta1"
+	"github.com/trisacrypto/trisa/pkg/trisa/envelope"
+	"github.com/trisacrypto/trisa/pkg/trisa/mtls"
+	"github.com/trisacrypto/trisa/pkg/trust"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+// ConformanceOptions describe the remote TRISA node under test and the identities used
+// in the transfers sent to it.
+type ConformanceOptions struct {
+	// The endpoint and common name of the remote TRISA node
+	Target     string
+	CommonName string
+
+	// A beneficiary wallet address at the remote node that accepts transfers and a
+	// beneficiary that responds with pending messages. Checks that require a
+	// beneficiary are skipped if it is not specified.
+	Beneficiary      string
+	AsyncBeneficiary string
+
+	// The asset type and amount of the transfers
+	AssetType string
+	Amount    float64
+
+	// The identities of the originator and the VASPs in the transfers
+	OriginatorAddress string
+	Originator        *ivms101.Person
+	OriginatingVASP   *ivms101.Person
+	BeneficiaryVASP   *ivms101.Person
+
+	// The timeout of each check
+	Timeout time.Duration
+
+	// Additional options used to dial the remote node, e.g. a context dialer
+	DialOptions []grpc.DialOption
+}
+
+// LoadFixtures populates the originator from the first account of the local VASP in the
+// rVASP fixtures. If the remote node is also an rVASP in the fixtures, the beneficiary
+// VASP and any unspecified beneficiaries are populated from its wallets.
+func (o *ConformanceOptions) LoadFixtures(fixturesPath, local string) (err error) {
+	var vasps []db.VASP
+	if vasps, err = db.LoadVASPs(fixturesPath); err != nil {
+		return err
+	}
+
+	var localID uint
+	if localID, err = db.FindVASP(vasps, local); err != nil {
+		return err
+	}
+
+	if o.OriginatingVASP, err = vasps[localID-1].LoadIdentity(); err != nil {
+		return fmt.Errorf("could not load identity of %s: %s", vasps[localID-1].Name, err)
+	}
+
+	var (
+		wallets  []db.Wallet
+		accounts []db.Account
+	)
+	if wallets, accounts, err = db.LoadWallets(fixturesPath); err != nil {
+		return err
+	}
+
+	for _, account := range accounts {
+		if account.VaspID == localID {
+			o.OriginatorAddress = account.WalletAddress
+			if o.Originator, err = account.LoadIdentity(); err != nil {
+				return fmt.Errorf("could not load identity of %s: %s", account.Email, err)
+			}
+			break
+		}
+	}
+
+	if o.Originator == nil {
+		return fmt.Errorf("no accounts for %s in fixtures", vasps[localID-1].Name)
+	}
+
+	// The remote node does not have to be an rVASP
+	var targetID uint
+	if targetID, err = db.FindVASP(vasps, o.CommonName); err != nil {
+		return nil
+	}
+
+	if o.BeneficiaryVASP, err = vasps[targetID-1].LoadIdentity(); err != nil {
+		return fmt.Errorf("could not load identity of %s: %s", vasps[targetID-1].Name, err)
+	}
+
+	for _, wallet := range wallets {
+		if wallet.ProviderID != targetID {
+			continue
+		}
+
+		if o.Beneficiary == "" && wallet.BeneficiaryPolicy == db.SyncRepair {
+			o.Beneficiary = wallet.Address
+		}
+
+		if o.AsyncBeneficiary == "" && wallet.BeneficiaryPolicy == db.AsyncRepair {
+			o.AsyncBeneficiary = wallet.Address
+		}
+	}
+	return nil
+}
+
+// Conformance runs a catalogue of checks against a remote TRISA node, acting as the
+// originator of the transfers using the local TRISA certificates.
+type Conformance struct {
+	opts   ConformanceOptions
+	certs  *trust.Provider
+	sign   *rsa.PrivateKey
+	cc     *grpc.ClientConn
+	client protocol.TRISANetworkClient
+	health protocol.TRISAHealthClient
+
+	// The signing key of the remote node from the key exchange
+	key *rsa.PublicKey
+}
+
+// NewConformance connects to the remote TRISA node with mTLS using the certificates.
+func NewConformance(certs *trust.Provider, chain trust.ProviderPool, opts ConformanceOptions) (c *Conformance, err error) {
+	if opts.Target == "" || opts.CommonName == "" {
+		return nil, errors.New("the target endpoint and common name are required")
+	}
+
+	if opts.Originator == nil || opts.OriginatingVASP == nil {
+		return nil, errors.New("the originator and originating vasp identities are required")
+	}
+
+	if opts.AssetType == "" {
+		opts.AssetType = "Bitcoin"
+	}
+
+	if opts.Amount == 0 {
+		opts.Amount = 0.0001
+	}
+
+	if opts.Timeout == 0 {
+		opts.Timeout = 30 * time.Second
+	}
+
+	c = &Conformance{opts: opts, certs: certs}
+	if c.sign, err = certs.GetRSAKeys(); err != nil {
+		return nil, err
+	}
+
+	var creds grpc.DialOption
+	if creds, err = mtls.ClientCreds(opts.CommonName, certs, chain); err != nil {
+		return nil, err
+	}
+
+	dialOpts := append([]grpc.DialOption{creds}, opts.DialOptions...)
+	if c.cc, err = grpc.Dial(opts.Target, dialOpts...); err != nil {
+		return nil, fmt.Errorf("could not dial %s: %s", opts.Target, err)
+	}
+
+	c.client = protocol.NewTRISANetworkClient(c.cc)
+	c.health = protocol.NewTRISAHealthClient(c.cc)
+	return c, nil
+}
+
+// Close the connection to the remote TRISA node.
+func (c *Conformance) Close() error {
+	return c.cc.Close()
+}
+
+// Run all of the checks in the catalogue in order. Later checks depend on the signing
+// key from the key exchange check, so the checks cannot be run independently.
+func (c *Conformance) Run(ctx context.Context) *ConformanceReport {
+	report := &ConformanceReport{
+		Target:     c.opts.Target,
+		CommonName: c.opts.CommonName,
+		Started:    time.Now(),
+		Checks:     make([]*CheckResult, 0, len(ConformanceChecks)),
+	}
+
+	for _, check := range ConformanceChecks {
+		result := &CheckResult{
+			Name:        check.Name,
+			Description: check.Description,
+			Weight:      check.Weight,
+		}
+
+		cctx, cancel := context.WithTimeout(ctx, c.opts.Timeout)
+		start := time.Now()
+		err := check.run(cctx, c)
+		result.Duration = time.Since(start)
+		cancel()
+
+		var skip skipped
+		switch {
+		case err == nil:
+			result.Status = CheckPassed
+		case errors.As(err, &skip):
+			result.Status = CheckSkipped
+			result.Message = skip.Error()
+		default:
+			result.Status = CheckFailed
+			result.Message = err.Error()
+		}
+
+		log.Debug().Str("check", check.Name).Str("status", string(result.Status)).Str("message", result.Message).Msg("conformance check complete")
+		report.Checks = append(report.Checks, result)
+	}
+
+	report.Duration = time.Since(report.Started)
+	report.score()
+	return report
+}
+
+// ConformanceCheck is a single check of the behavior of a remote TRISA node. The weight
+// of the check determines its contribution to the conformance score.
+type ConformanceCheck struct {
+	Name        string
+	Description string
+	Weight      int
+	run         func(context.Context, *Conformance) error
+}
+
+// ConformanceChecks is the catalogue of checks run against the remote TRISA node.
+var ConformanceChecks = []ConformanceCheck{
+	{
+		Name:        "health",
+		Description: "The TRISA health service reports that the node is healthy",
+		Weight:      1,
+		run:         checkHealth,
+	},
+	{
+		Name:        "key-exchange",
+		Description: "Key exchange returns a PKIX encoded RSA public signing key",
+		Weight:      3,
+		run:         checkKeyExchange,
+	},
+	{
+		Name:        "unary-transfer",
+		Description: "A unary transfer is answered with a sealed payload containing a valid identity",
+		Weight:      3,
+		run:         checkUnaryTransfer,
+	},
+	{
+		Name:        "stream-transfer",
+		Description: "Transfers on a transfer stream are answered in order and the stream closes cleanly",
+		Weight:      2,
+		run:         checkStreamTransfer,
+	},
+	{
+		Name:        "error-envelope",
+		Description: "An envelope containing only a TRISA error is acknowledged with an error envelope",
+		Weight:      2,
+		run:         checkErrorEnvelope,
+	},
+	{
+		Name:        "async-pending",
+		Description: "A transfer to an asynchronous beneficiary is answered with a valid pending message",
+		Weight:      2,
+		run:         checkAsyncPending,
+	},
+	{
+		Name:        "invalid-algorithm",
+		Description: "An envelope with an unsupported encryption algorithm is rejected",
+		Weight:      2,
+		run:         checkInvalidAlgorithm,
+	},
+	{
+		Name:        "missing-identity",
+		Description: "A payload without an identity is rejected",
+		Weight:      1,
+		run:         checkMissingIdentity,
+	},
+	{
+		Name:        "missing-transaction",
+		Description: "A payload without a transaction is rejected",
+		Weight:      1,
+		run:         checkMissingTransaction,
+	},
+	{
+		Name:        "missing-sent-at",
+		Description: "A payload without a sent at timestamp is rejected",
+		Weight:      1,
+		run:         checkMissingSentAt,
+	},
+}
+
+// skipped is returned by checks that cannot be run with the specified options.
+type skipped string
+
+func (s skipped) Error() string {
+	return string(s)
+}
+
+func checkHealth(ctx context.Context, c *Conformance) (err error) {
+	var state *protocol.ServiceState
+	if state, err = c.health.Status(ctx, &protocol.HealthCheck{Attempts: 1}); err != nil {
+		return fmt.Errorf("health check failed: %s", err)
+	}
+
+	if state.Status != protocol.ServiceState_HEALTHY {
+		return fmt.Errorf("node reported status %s", state.Status)
+	}
+	return nil
+}
+
+func checkKeyExchange(ctx context.Context, c *Conformance) (err error) {
+	var local *protocol.SigningKey
+	if local, err = signingKey(c.certs); err != nil {
+		return fmt.Errorf("could not create local signing key: %s", err)
+	}
+
+	var remote *protocol.SigningKey
+	if remote, err = c.client.KeyExchange(ctx, local); err != nil {
+		return fmt.Errorf("key exchange failed: %s", err)
+	}
+
+	if len(remote.Data) == 0 {
+		return errors.New("key exchange response does not contain a public key")
+	}
+
+	var pub interface{}
+	if pub, err = x509.ParsePKIXPublicKey(remote.Data); err != nil {
+		return fmt.Errorf("could not parse PKIX public key: %s", err)
+	}
+
+	var ok bool
+	if c.key, ok = pub.(*rsa.PublicKey); !ok {
+		return fmt.Errorf("expected an RSA public key, received %T", pub)
+	}
+	return nil
+}
+
+func checkUnaryTransfer(ctx context.Context, c *Conformance) (err error) {
+	if c.opts.Beneficiary == "" {
+		return skipped("no beneficiary specified")
+	}
+
+	var in *protocol.SecureEnvelope
+	if in, err = c.seal(c.payload(c.opts.Beneficiary)); err != nil {
+		return err
+	}
+
+	var out *protocol.SecureEnvelope
+	if out, err = c.client.Transfer(ctx, in); err != nil {
+		return fmt.Errorf("transfer failed: %s", err)
+	}
+
+	_, err = c.open(in, out)
+	return err
+}
+
+func checkStreamTransfer(ctx context.Context, c *Conformance) (err error) {
+	if c.opts.Beneficiary == "" {
+		return skipped("no beneficiary specified")
+	}
+
+	var stream protocol.TRISANetwork_TransferStreamClient
+	if stream, err = c.client.TransferStream(ctx); err != nil {
+		return fmt.Errorf("could not open transfer stream: %s", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		var in *protocol.SecureEnvelope
+		if in, err = c.seal(c.payload(c.opts.Beneficiary)); err != nil {
+			return err
+		}
+
+		if err = stream.Send(in); err != nil {
+			return fmt.Errorf("could not send transfer %d on stream: %s", i+1, err)
+		}
+
+		var out *protocol.SecureEnvelope
+		if out, err = stream.Recv(); err != nil {
+			return fmt.Errorf("could not receive reply %d on stream: %s", i+1, err)
+		}
+
+		if _, err = c.open(in, out); err != nil {
+			return fmt.Errorf("reply %d: %s", i+1, err)
+		}
+	}
+
+	if err = stream.CloseSend(); err != nil {
+		return fmt.Errorf("could not close transfer stream: %s", err)
+	}
+
+	if _, err = stream.Recv(); err != io.EOF {
+		return fmt.Errorf("expected transfer stream to close cleanly: %v", err)
+	}
+	return nil
+}
+
+func checkErrorEnvelope(ctx context.Context, c *Conformance) (err error) {
+	reject := protocol.Errorf(protocol.ComplianceCheckFail, "conformance check error envelope")
+
+	var in *protocol.SecureEnvelope
+	if in, err = envelope.Reject(reject, envelope.WithEnvelopeID(uuid.New().String())); err != nil {
+		return fmt.Errorf("could not create error envelope: %s", err)
+	}
+
+	var out *protocol.SecureEnvelope
+	if out, err = c.client.Transfer(ctx, in); err != nil {
+		return fmt.Errorf("transfer failed: %s", err)
+	}
+
+	if out.Id != in.Id {
+		return fmt.Errorf("expected envelope id %s in reply, received %q", in.Id, out.Id)
+	}
+
+	if state := envelope.Status(out); state != envelope.Error {
+		return fmt.Errorf("expected an error envelope in reply, received envelope in state %s", state)
+	}
+	return nil
+}
+
+func checkAsyncPending(ctx context.Context, c *Conformance) (err error) {
+	if c.opts.AsyncBeneficiary == "" {
+		return skipped("no async beneficiary specified")
+	}
+
+	var in *protocol.SecureEnvelope
+	if in, err = c.seal(c.payload(c.opts.AsyncBeneficiary)); err != nil {
+		return err
+	}
+
+	var out *protocol.SecureEnvelope
+	if out, err = c.client.Transfer(ctx, in); err != nil {
+		return fmt.Errorf("transfer failed: %s", err)
+	}
+
+	var pending *generic.Pending
+	if pending, err = c.open(in, out); err != nil {
+		return err
+	}
+
+	if pending == nil {
+		return errors.New("expected a pending message, received a transaction")
+	}
+	return nil
+}
+
+func checkInvalidAlgorithm(ctx context.Context, c *Conformance) (err error) {
+	var in *protocol.SecureEnvelope
+	if in, err = c.seal(c.payload(c.opts.Beneficiary)); err != nil {
+		return err
+	}
+	in.EncryptionAlgorithm = "ROT13"
+
+	return c.expectRejection(ctx, in, protocol.UnhandledAlgorithm)
+}
+
+func checkMissingIdentity(ctx context.Context, c *Conformance) (err error) {
+	// Without a valid beneficiary the node may reject the payload for another reason
+	if c.opts.Beneficiary == "" {
+		return skipped("no beneficiary specified")
+	}
+
+	payload, perr := c.payload(c.opts.Beneficiary)
+	if perr == nil {
+		payload.Identity = nil
+	}
+
+	var in *protocol.SecureEnvelope
+	if in, err = c.sealInvalid(payload, perr); err != nil {
+		return err
+	}
+	return c.expectRejection(ctx, in, missingFields...)
+}
+
+func checkMissingTransaction(ctx context.Context, c *Conformance) (err error) {
+	if c.opts.Beneficiary == "" {
+		return skipped("no beneficiary specified")
+	}
+
+	payload, perr := c.payload(c.opts.Beneficiary)
+	if perr == nil {
+		payload.Transaction = nil
+	}
+
+	var in *protocol.SecureEnvelope
+	if in, err = c.sealInvalid(payload, perr); err != nil {
+		return err
+	}
+	return c.expectRejection(ctx, in, missingFields...)
+}
+
+func checkMissingSentAt(ctx context.Context, c *Conformance) (err error) {
+	if c.opts.Beneficiary == "" {
+		return skipped("no beneficiary specified")
+	}
+
+	payload, perr := c.payload(c.opts.Beneficiary)
+	if perr == nil {
+		payload.SentAt = ""
+	}
+
+	var in *protocol.SecureEnvelope
+	if in, err = c.sealInvalid(payload, perr); err != nil {
+		return err
+	}
+	return c.expectRejection(ctx, in, missingFields...)
+}
+
+// missingFields are the codes that reject payloads that are missing required fields;
+// the TRISA envelope package rejects them with a validation error rather than the more
+// specific missing fields error.
+var missingFields = []protocol.Error_Code{protocol.MissingFields, protocol.ValidationError}
+
+// payload creates a transfer payload from the originator to the beneficiary. The
+// payload is returned with any error so that it can be passed directly to seal.
+func (c *Conformance) payload(beneficiary string) (*protocol.Payload, error) {
+	identity := &ivms101.IdentityPayload{
+		Originator: &ivms101.Originator{
+			OriginatorPersons: []*ivms101.Person{c.opts.Originator},
+			AccountNumbers:    []string{c.opts.OriginatorAddress},
+		},
+		OriginatingVasp: &ivms101.OriginatingVasp{OriginatingVasp: c.opts.OriginatingVASP},
+		Beneficiary: &ivms101.Beneficiary{
+			BeneficiaryPersons: make([]*ivms101.Person, 0),
+			AccountNumbers:     []string{beneficiary},
+		},
+		BeneficiaryVasp: &ivms101.BeneficiaryVasp{BeneficiaryVasp: c.opts.BeneficiaryVASP},
+	}
+
+	transaction := &generic.Transaction{
+		Txid:        uuid.New().String(),
+		Originator:  c.opts.OriginatorAddress,
+		Beneficiary: beneficiary,
+		Amount:      c.opts.Amount,
+		Network:     "TestNet",
+		AssetType:   c.opts.AssetType,
+		Timestamp:   time.Now().Format(time.RFC3339),
+	}
+
+	return createTransferPayload(identity, transaction)
+}
+
+// seal the payload in a secure envelope with the signing key of the remote node.
+func (c *Conformance) seal(payload *protocol.Payload, err error) (_ *protocol.SecureEnvelope, _ error) {
+	if err != nil {
+		return nil, fmt.Errorf("could not create payload: %s", err)
+	}
+
+	if c.key == nil {
+		return nil, errors.New("no signing key available from key exchange")
+	}
+
+	var msg *protocol.SecureEnvelope
+	if msg, _, err = envelope.Seal(payload, envelope.WithEnvelopeID(uuid.New().String()), envelope.WithRSAPublicKey(c.key)); err != nil {
+		return nil, fmt.Errorf("could not seal envelope: %s", err)
+	}
+	return msg, nil
+}
+
+// sealInvalid seals a payload that is missing required fields in a secure envelope.
+// The envelope package refuses to seal invalid payloads, so the payload is encrypted
+// and the keys are sealed directly with the TRISA cryptography primitives.
+func (c *Conformance) sealInvalid(payload *protocol.Payload, err error) (_ *protocol.SecureEnvelope, _ error) {
+	if err != nil {
+		return nil, fmt.Errorf("could not create payload: %s", err)
+	}
+
+	if c.key == nil {
+		return nil, errors.New("no signing key available from key exchange")
+	}
+
+	var cipher *aesgcm.AESGCM
+	if cipher, err = aesgcm.New(nil, nil); err != nil {
+		return nil, fmt.Errorf("could not create cipher: %s", err)
+	}
+
+	var seal *rsaoeap.RSA
+	if seal, err = rsaoeap.New(c.key); err != nil {
+		return nil, fmt.Errorf("could not create sealing key: %s", err)
+	}
+
+	var cleartext []byte
+	if cleartext, err = proto.Marshal(payload); err != nil {
+		return nil, fmt.Errorf("could not marshal payload: %s", err)
+	}
+
+	msg := &protocol.SecureEnvelope{
+		Id:                  uuid.New().String(),
+		Timestamp:           time.Now().Format(time.RFC3339Nano),
+		EncryptionAlgorithm: cipher.EncryptionAlgorithm(),
+		HmacAlgorithm:       cipher.SignatureAlgorithm(),
+		Sealed:              true,
+	}
+
+	if msg.Payload, err = cipher.Encrypt(cleartext); err != nil {
+		return nil, fmt.Errorf("could not encrypt payload: %s", err)
+	}
+
+	if msg.Hmac, err = cipher.Sign(msg.Payload); err != nil {
+		return nil, fmt.Errorf("could not sign payload: %s", err)
+	}
+
+	if msg.EncryptionKey, err = seal.Encrypt(cipher.EncryptionKey()); err != nil {
+		return nil, fmt.Errorf("could not seal encryption key: %s", err)
+	}
+
+	if msg.HmacSecret, err = seal.Encrypt(cipher.HMACSecret()); err != nil {
+		return nil, fmt.Errorf("could not seal hmac secret: %s", err)
+	}
+
+	if msg.PublicKeySignature, err = seal.PublicKeySignature(); err != nil {
+		return nil, fmt.Errorf("could not compute public key signature: %s", err)
+	}
+	return msg, nil
+}
+
+// open the reply to a transfer, checking that the reply is a valid response payload with
+// a complete identity or a valid pending message. The pending message is returned if
+// the remote node responded asynchronously.
+func (c *Conformance) open(in, out *protocol.SecureEnvelope) (pending *generic.Pending, err error) {
+	if out.Id != in.Id {
+		return nil, fmt.Errorf("expected envelope id %s in reply, received %q", in.Id, out.Id)
+	}
+
+	if reject, isErr := envelope.Check(out); isErr {
+		if reject != nil {
+			return nil, fmt.Errorf("transfer was rejected: [%s] %s", reject.Code, reject.Message)
+		}
+		return nil, errors.New("reply is not a valid envelope")
+	}
+
+	var payload *protocol.Payload
+	if payload, _, err = envelope.Open(out, envelope.WithRSAPrivateKey(c.sign)); err != nil {
+		return nil, fmt.Errorf("could not open reply envelope: %s", err)
+	}
+
+	var (
+		identity   *ivms101.IdentityPayload
+		parseError *protocol.Error
+	)
+	if identity, _, pending, parseError = parsePayload(payload, true); parseError != nil {
+		return nil, fmt.Errorf("invalid reply payload: %s", parseError.Message)
+	}
+
+	if pending != nil {
+		var notBefore, notAfter time.Time
+		if notBefore, err = time.Parse(time.RFC3339, pending.ReplyNotBefore); err != nil {
+			return nil, fmt.Errorf("could not parse reply_not_before in pending message: %s", err)
+		}
+
+		if notAfter, err = time.Parse(time.RFC3339, pending.ReplyNotAfter); err != nil {
+			return nil, fmt.Errorf("could not parse reply_not_after in pending message: %s", err)
+		}
+
+		if !notBefore.Before(notAfter) {
+			return nil, errors.New("reply_not_before must be before reply_not_after in pending message")
+		}
+		return pending, nil
+	}
+
+	if verr := ValidateIdentityPayload(identity, true); verr != nil {
+		return nil, fmt.Errorf("invalid identity in reply: [%s] %s", verr.Code, verr.Message)
+	}
+	return nil, nil
+}
+
+// expectRejection sends the envelope and checks that the remote node rejects it with a
+// TRISA error with one of the expected codes, either in an error envelope or as a gRPC
+// error.
+func (c *Conformance) expectRejection(ctx context.Context, in *protocol.SecureEnvelope, codes ...protocol.Error_Code) (err error) {
+	var (
+		out    *protocol.SecureEnvelope
+		reject *protocol.Error
+		ok     bool
+	)
+	if out, err = c.client.Transfer(ctx, in); err != nil {
+		if reject, ok = protocol.Errorp(err); !ok {
+			return fmt.Errorf("expected a TRISA rejection, received: %s", err)
+		}
+	} else {
+		if reject, ok = envelope.Check(out); !ok {
+			return errors.New("expected a TRISA rejection but the transfer was accepted")
+		}
+
+		if reject == nil {
+			return errors.New("expected a TRISA rejection, received an invalid envelope")
+		}
+	}
+
+	for _, code := range codes {
+		if reject.Code == code {
+			return nil
+		}
+	}
+	return fmt.Errorf("expected a TRISA rejection with code %s, received [%s] %s", codes[0], reject.Code, reject.Message)
+}
@@ -0,0 +1,119 @@
+package rvasp
+
+import (
+	"encoding/json"
+	"html/template"
+	"io"
+	"time"
+)
+
+// CheckStatus is the outcome of a conformance check.
+type CheckStatus string
+
+const (
+	CheckPassed  CheckStatus = "pass"
+	CheckFailed  CheckStatus = "fail"
+	CheckSkipped CheckStatus = "skip"
+)
+
+// CheckResult is the outcome of a single conformance check.
+type CheckResult struct {
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	Weight      int           `json:"weight"`
+	Status      CheckStatus   `json:"status"`
+	Message     string        `json:"message,omitempty"`
+	Duration    time.Duration `json:"duration_ns"`
+}
+
+// ConformanceReport is the scored outcome of running the conformance checks against a
+// remote TRISA node. The score is the percentage of the weight of the checks that were
+// run which passed; skipped checks do not count towards the score but the node is only
+// compliant if all of the checks were run and passed.
+type ConformanceReport struct {
+	Target     string         `json:"target"`
+	CommonName string         `json:"common_name"`
+	Started    time.Time      `json:"started"`
+	Duration   time.Duration  `json:"duration_ns"`
+	Checks     []*CheckResult `json:"checks"`
+	Score      float64        `json:"score"`
+	Passed     int            `json:"passed"`
+	Failed     int            `json:"failed"`
+	Skipped    int            `json:"skipped"`
+	Compliant  bool           `json:"compliant"`
+}
+
+func (r *ConformanceReport) score() {
+	var total, passed int
+	r.Passed, r.Failed, r.Skipped = 0, 0, 0
+	for _, check := range r.Checks {
+		switch check.Status {
+		case CheckPassed:
+			r.Passed++
+			total += check.Weight
+			passed += check.Weight
+		case CheckFailed:
+			r.Failed++
+			total += check.Weight
+		case CheckSkipped:
+			r.Skipped++
+		}
+	}
+
+	if total > 0 {
+		r.Score = 100 * float64(passed) / float64(total)
+	}
+	r.Compliant = r.Failed == 0 && r.Skipped == 0 && r.Passed > 0
+}
+
+// WriteJSON writes the report as indented JSON.
+func (r *ConformanceReport) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// WriteHTML writes the report as a standalone HTML page.
+func (r *ConformanceReport) WriteHTML(w io.Writer) error {
+	return reportTemplate.Execute(w, r)
+}
+
+var reportTemplate = template.Must(template.New("conformance").Funcs(template.FuncMap{
+	"ms": func(d time.Duration) string { return d.Round(time.Millisecond).String() },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>TRISA Conformance Report: {{ .CommonName }}</title>
+  <style>
+    body { font-family: sans-serif; margin: 2em; }
+    table { border-collapse: collapse; width: 100%; }
+    th, td { border: 1px solid #ccc; padding: 0.4em 0.8em; text-align: left; }
+    .pass { color: #1a7f37; }
+    .fail { color: #cf222e; }
+    .skip { color: #6e7781; }
+  </style>
+</head>
+<body>
+  <h1>TRISA Conformance Report</h1>
+  <p>
+    <strong>Target:</strong> {{ .Target }} ({{ .CommonName }})<br>
+    <strong>Started:</strong> {{ .Started.Format "2006-01-02T15:04:05Z07:00" }} ({{ ms .Duration }})<br>
+    <strong>Score:</strong> {{ printf "%.1f" .Score }}% &mdash; {{ .Passed }} passed, {{ .Failed }} failed, {{ .Skipped }} skipped<br>
+    <strong>Compliant:</strong> {{ if .Compliant }}<span class="pass">yes</span>{{ else }}<span class="fail">no</span>{{ end }}
+  </p>
+  <table>
+    <tr><th>Check</th><th>Weight</th><th>Status</th><th>Duration</th><th>Message</th></tr>
+    {{- range .Checks }}
+    <tr>
+      <td><strong>{{ .Name }}</strong><br><small>{{ .Description }}</small></td>
+      <td>{{ .Weight }}</td>
+      <td class="{{ .Status }}">{{ .Status }}</td>
+      <td>{{ ms .Duration }}</td>
+      <td>{{ .Message }}</td>
+    </tr>
+    {{- end }}
+  </table>
+</body>
+</html>
+`))
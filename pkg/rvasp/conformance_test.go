@@ -0,0 +1,67 @@
+package rvasp_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trisacrypto/testnet/pkg/rvasp"
+	"github.com/trisacrypto/testnet/pkg/rvasp/harness"
+	"google.golang.org/grpc"
+)
+
+func TestConformance(t *testing.T) {
+	h := harness.New(t, "alice", "bob")
+	alice, bob := h.VASP("alice"), h.VASP("bob")
+
+	// Alice's certificates are used to run the checks against bob's TRISA service
+	opts := rvasp.ConformanceOptions{CommonName: bob.Name}
+	require.NoError(t, opts.LoadFixtures(fixturesPath, alice.Name))
+	require.NotEmpty(t, opts.OriginatorAddress)
+	require.NotNil(t, opts.Originator)
+	require.NotEmpty(t, opts.Beneficiary)
+	require.NotEmpty(t, opts.AsyncBeneficiary)
+	require.NotNil(t, opts.BeneficiaryVASP)
+
+	opts.Target = harness.ServerName
+	opts.CommonName = harness.ServerName
+	opts.DialOptions = []grpc.DialOption{bob.TRISADialer()}
+
+	certs, chain := alice.Certs()
+	suite, err := rvasp.NewConformance(certs, chain, opts)
+	require.NoError(t, err)
+	defer suite.Close()
+
+	report := suite.Run(context.Background())
+	require.Len(t, report.Checks, len(rvasp.ConformanceChecks))
+	for _, check := range report.Checks {
+		require.Equal(t, rvasp.CheckPassed, check.Status, "%s: %s", check.Name, check.Message)
+	}
+	require.True(t, report.Compliant)
+	require.Equal(t, 100.0, report.Score)
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, report.WriteJSON(buf))
+	decoded := &rvasp.ConformanceReport{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), decoded))
+	require.Equal(t, report.Passed, decoded.Passed)
+
+	buf.Reset()
+	require.NoError(t, report.WriteHTML(buf))
+	require.Contains(t, buf.String(), "key-exchange")
+
+	// Checks that require a beneficiary are skipped and do not count towards the score,
+	// but the node cannot be reported as compliant if any checks were skipped
+	opts.Beneficiary, opts.AsyncBeneficiary = "", ""
+	suite, err = rvasp.NewConformance(certs, chain, opts)
+	require.NoError(t, err)
+	defer suite.Close()
+
+	report = suite.Run(context.Background())
+	require.Equal(t, 6, report.Skipped)
+	require.Zero(t, report.Failed)
+	require.Equal(t, 100.0, report.Score)
+	require.False(t, report.Compliant)
+}
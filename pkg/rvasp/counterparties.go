@@ -0,0 +1,106 @@
+package rvasp
+
+import (
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/trisacrypto/testnet/pkg/rvasp/config"
+	"github.com/trisacrypto/testnet/pkg/rvasp/db"
+	"github.com/trisacrypto/trisa/pkg/ivms101"
+	protocol "github.com/trisacrypto/trisa/pkg/trisa/api/v1beta1"
+)
+
+// Counterparties evaluates the counterparty rules of the rVASP to simulate a VASP that
+// refuses to do business with particular counterparties, both before contacting the
+// beneficiary VASP of a transfer and when receiving a transfer from a peer. A nil
+// Counterparties allows every counterparty.
+type Counterparties struct {
+	conf config.CounterpartiesConfig
+	db   *db.DB
+}
+
+// NewCounterparties returns nil if no counterparty rules are configured.
+func NewCounterparties(conf config.CounterpartiesConfig, vaspdb *db.DB) *Counterparties {
+	if !conf.Enabled() {
+		return nil
+	}
+	return &Counterparties{conf: conf, db: vaspdb}
+}
+
+// Check returns the configured rejection error if the rVASP does not do business with
+// the counterparty with the common name. The country of the counterparty is the
+// country of registration of its VASP record or, if the counterparty has no record,
+// of the IVMS101 legal person sent by the counterparty (which may be nil).
+func (c *Counterparties) Check(name string, person *ivms101.Person) *protocol.Error {
+	if c == nil {
+		return nil
+	}
+
+	if contains(c.conf.DenyPeers, name) {
+		return c.refuse(name, "", "counterparty %s is denied", name)
+	}
+
+	if len(c.conf.AllowPeers) > 0 && !contains(c.conf.AllowPeers, name) {
+		return c.refuse(name, "", "counterparty %s is not allowed", name)
+	}
+
+	// Only lookup the country if there are country rules to evaluate
+	if len(c.conf.DenyCountries) == 0 && len(c.conf.AllowCountries) == 0 {
+		return nil
+	}
+
+	country := c.country(name, person)
+	if country != "" && contains(c.conf.DenyCountries, country) {
+		return c.refuse(name, country, "counterparties registered in %s are denied", country)
+	}
+
+	if len(c.conf.AllowCountries) > 0 && !contains(c.conf.AllowCountries, country) {
+		if country == "" {
+			return c.refuse(name, country, "could not determine the country of counterparty %s", name)
+		}
+		return c.refuse(name, country, "counterparties registered in %s are not allowed", country)
+	}
+	return nil
+}
+
+// country returns the country of registration of the counterparty or an empty string
+// if it is not known.
+func (c *Counterparties) country(name string, person *ivms101.Person) string {
+	var vasp db.VASP
+	if err := c.db.LookupVASP(name).First(&vasp).Error; err == nil {
+		if identity, err := vasp.LoadIdentity(); err == nil {
+			person = identity
+		} else {
+			log.Debug().Err(err).Str("counterparty", name).Msg("could not load counterparty identity")
+		}
+	}
+
+	if legal := person.GetLegalPerson(); legal != nil {
+		if legal.CountryOfRegistration != "" {
+			return legal.CountryOfRegistration
+		}
+
+		// Fall back to the country of the first geographic address
+		for _, addr := range legal.GeographicAddresses {
+			if addr.Country != "" {
+				return addr.Country
+			}
+		}
+	}
+	return ""
+}
+
+func (c *Counterparties) refuse(name, country, format string, a ...interface{}) *protocol.Error {
+	log.Info().Str("counterparty", name).Str("country", country).Msg("refusing to do business with counterparty")
+	return protocol.Errorf(protocol.Error_Code(c.conf.ErrorCode), format, a...)
+}
+
+// contains returns true if the value is in the list, ignoring case.
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if strings.EqualFold(strings.TrimSpace(item), value) {
+			return true
+		}
+	}
+	return false
+}
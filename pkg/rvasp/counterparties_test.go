@@ -0,0 +1,102 @@
+package rvasp_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trisacrypto/testnet/pkg/rvasp/config"
+	"github.com/trisacrypto/testnet/pkg/rvasp/db"
+	"github.com/trisacrypto/testnet/pkg/rvasp/harness"
+	pb "github.com/trisacrypto/testnet/pkg/rvasp/pb/v1"
+	protocol "github.com/trisacrypto/trisa/pkg/trisa/api/v1beta1"
+)
+
+func TestCounterparties(t *testing.T) {
+	testCases := []struct {
+		name     string
+		vasp     string // the rVASP with the counterparty rules
+		rules    config.CounterpartiesConfig
+		code     protocol.Error_Code
+		message  string
+		received bool // if the beneficiary received the transfer
+	}{
+		{
+			name:    "originator denies beneficiary",
+			vasp:    "api.alice.vaspbot.com",
+			rules:   config.CounterpartiesConfig{DenyPeers: []string{"api.bob.vaspbot.com"}, ErrorCode: config.ErrorCodeDecoder(protocol.Rejected)},
+			code:    protocol.Rejected,
+			message: "counterparty api.bob.vaspbot.com is denied",
+		},
+		{
+			name:    "originator only allows other countries",
+			vasp:    "api.alice.vaspbot.com",
+			rules:   config.CounterpartiesConfig{AllowCountries: []string{"US", "VG"}, ErrorCode: config.ErrorCodeDecoder(protocol.ComplianceCheckFail)},
+			code:    protocol.ComplianceCheckFail,
+			message: "counterparties registered in GB are not allowed",
+		},
+		{
+			name:     "beneficiary denies originator country",
+			vasp:     "api.bob.vaspbot.com",
+			rules:    config.CounterpartiesConfig{DenyCountries: []string{"us"}, ErrorCode: config.ErrorCodeDecoder(protocol.HighRisk)},
+			code:     protocol.HighRisk,
+			message:  "counterparties registered in US are denied",
+			received: true,
+		},
+		{
+			name:     "beneficiary only allows other peers",
+			vasp:     "api.bob.vaspbot.com",
+			rules:    config.CounterpartiesConfig{AllowPeers: []string{"api.charlie.vaspbot.com"}, ErrorCode: config.ErrorCodeDecoder(protocol.Rejected)},
+			code:     protocol.Rejected,
+			message:  "counterparty api.alice.vaspbot.com is not allowed",
+			received: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := harness.NewWithConfig(t, func(conf *config.Config) {
+				if conf.Name == tc.vasp {
+					conf.Counterparties = tc.rules
+				}
+			}, "alice", "bob")
+			alice, bob := h.VASP("alice"), h.VASP("bob")
+
+			reply, err := alice.Client.Transfer(context.Background(), &pb.TransferRequest{
+				Account:     "mary@alicevasp.us",
+				Beneficiary: "robert@bobvasp.co.uk",
+				Amount:      0.1,
+				AssetType:   "Bitcoin",
+			})
+			require.NoError(t, err)
+			require.NotNil(t, reply.Error)
+			require.Equal(t, int32(tc.code), reply.Error.Code)
+			require.Equal(t, tc.message, reply.Error.Message)
+			require.Equal(t, pb.TransactionState_REJECTED, reply.Transaction.State)
+
+			// Refused transfers are not recorded by the beneficiary
+			_, err = bob.Transaction(reply.Transaction.EnvelopeId)
+			require.Error(t, err)
+
+			// The recorded exchanges show if the beneficiary was contacted
+			var envelopes []db.Envelope
+			require.NoError(t, alice.DB.LookupEnvelopes(reply.Transaction.EnvelopeId).Find(&envelopes).Error)
+			require.Equal(t, tc.received, len(envelopes) > 0)
+		})
+	}
+
+	// Transfers to allowed counterparties are not affected by the rules
+	h := harness.NewWithConfig(t, func(conf *config.Config) {
+		conf.Counterparties = config.CounterpartiesConfig{DenyPeers: []string{"api.evil.vaspbot.com"}, DenyCountries: []string{"KP"}}
+	}, "alice", "bob")
+
+	reply, err := h.VASP("alice").Client.Transfer(context.Background(), &pb.TransferRequest{
+		Account:     "mary@alicevasp.us",
+		Beneficiary: "robert@bobvasp.co.uk",
+		Amount:      0.1,
+		AssetType:   "Bitcoin",
+	})
+	require.NoError(t, err)
+	require.Nil(t, reply.Error)
+	require.Equal(t, pb.TransactionState_COMPLETED, reply.Transaction.State)
+}
@@ -15,32 +15,40 @@ import (
 	"google.golang.org/grpc/status"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
-	"gorm.io/gorm/clause"
 )
 
 // DB is a wrapper around a gorm.DB instance that restricts query results to a single
 // VASP.
 type DB struct {
-	db   *gorm.DB
-	vasp VASP
+	db        *gorm.DB
+	vasp      VASP
+	observers []StateObserver
 }
 
 func NewDB(conf *config.Config) (d *DB, err error) {
-	d = &DB{}
-	if d.db, err = OpenDB(conf); err != nil {
+	var gdb *gorm.DB
+	if gdb, err = OpenDB(conf); err != nil {
 		return nil, err
 	}
 
-	if err = MigrateDB(d.db); err != nil {
+	// Refuse to start if the schema has not been migrated to the expected version
+	if err = CheckSchema(gdb); err != nil {
 		return nil, err
 	}
 
-	if err = d.db.Where("name = ?", conf.Name).First(&d.vasp).Error; err != nil {
+	return NewVASPDB(gdb, conf.Name)
+}
+
+// NewVASPDB restricts queries on an open database to the named VASP. Multiple rVASPs
+// hosted in the same process share the connection pool of the underlying gorm.DB.
+func NewVASPDB(gdb *gorm.DB, name string) (d *DB, err error) {
+	d = &DB{db: gdb}
+	if err = d.db.Where("name = ?", name).First(&d.vasp).Error; err != nil {
 		return nil, fmt.Errorf("could not fetch VASP info from database: %s", err)
 	}
 
-	if conf.Name != d.vasp.Name {
-		return nil, fmt.Errorf("expected name %q but have database name %q", conf.Name, d.vasp.Name)
+	if name != d.vasp.Name {
+		return nil, fmt.Errorf("expected name %q but have database name %q", name, d.vasp.Name)
 	}
 
 	return d, nil
@@ -62,8 +70,42 @@ func (d *DB) Create(value interface{}) *gorm.DB {
 	return d.db.Create(value)
 }
 
+// Save the value to the database. If the value is a transaction, the state observers
+// are notified of the state transitions that were saved with it.
 func (d *DB) Save(value interface{}) *gorm.DB {
-	return d.db.Save(value)
+	xfer, ok := value.(*Transaction)
+	if !ok || len(d.observers) == 0 {
+		return d.db.Save(value)
+	}
+
+	// State transitions are only assigned an ID once they have been saved
+	unsaved := make([]int, 0, 1)
+	for i, transition := range xfer.History {
+		if transition.ID == 0 {
+			unsaved = append(unsaved, i)
+		}
+	}
+
+	tx := d.db.Save(value)
+	if tx.Error == nil {
+		for _, i := range unsaved {
+			for _, observe := range d.observers {
+				observe(xfer, xfer.History[i])
+			}
+		}
+	}
+	return tx
+}
+
+// StateObserver is called with each state transition of a transaction once the
+// transaction has been saved. Observers are called synchronously by Save and must not
+// modify the transaction.
+type StateObserver func(xfer *Transaction, transition StateTransition)
+
+// Observe registers an observer of the state transitions of the transactions saved to
+// the database. Observers must be registered before the database is used concurrently.
+func (d *DB) Observe(observer StateObserver) {
+	d.observers = append(d.observers, observer)
 }
 
 // LookupAccount by email address or wallet address.
@@ -88,6 +130,11 @@ func (d *DB) LookupAnyBeneficiary(beneficiary string) *gorm.DB {
 	return d.db.Preload("Provider").Where("email = ?", beneficiary).Or("address = ?", beneficiary)
 }
 
+// LookupVASP by the common name of its certificate, not restricted to the local rVASP.
+func (d *DB) LookupVASP(name string) *gorm.DB {
+	return d.db.Where("name = ?", name)
+}
+
 // LookupIdentity by wallet address.
 func (d *DB) LookupIdentity(walletAddress string) *gorm.DB {
 	return d.Query().Where("wallet_address = ?", walletAddress)
@@ -103,15 +150,40 @@ func (d *DB) LookupTransaction(envelope string) *gorm.DB {
 	return d.Query().Where("envelope = ?", envelope)
 }
 
+// LookupEnvelopes returns the recorded secure envelopes with the envelope ID in the
+// order they were recorded.
+func (d *DB) LookupEnvelopes(envelope string) *gorm.DB {
+	return d.Query().Where("envelope = ?", envelope).Order("id")
+}
+
+// LookupHistory returns the state transitions of the transaction in the order they
+// occurred.
+func (d *DB) LookupHistory(transaction *Transaction) *gorm.DB {
+	return d.db.Where("transaction_id = ?", transaction.ID).Order("id")
+}
+
+// LookupWebhooks returns the webhooks that receive the state changes of the
+// transactions of the account, including the webhooks registered for every account.
+func (d *DB) LookupWebhooks(account uint) *gorm.DB {
+	return d.Query().Where("(account_id IS NULL OR account_id = ?)", account)
+}
+
+// LookupDeliveries returns the attempts to deliver events to the webhooks of the VASP
+// in the order they were made.
+func (d *DB) LookupDeliveries() *gorm.DB {
+	return d.db.Where("webhook_id IN (?)", d.Query().Model(&Webhook{}).Select("id")).Order("id")
+}
+
 // LookupWallet by wallet address.
 func (d *DB) LookupWallet(address string) *gorm.DB {
 	return d.Query().Where("address = ?", address)
 }
 
 // MakeTransaction returns a new Transaction from the originator and beneficiary
-// wallet addresses. Note: this does not store the transaction in the database to allow
-// the caller to modify the transaction fields before storage.
-func (d *DB) MakeTransaction(originator string, beneficiary string) (*Transaction, error) {
+// wallet addresses. The creation of the transaction by the actor is recorded as the
+// first transition in its history. Note: this does not store the transaction in the
+// database to allow the caller to modify the transaction fields before storage.
+func (d *DB) MakeTransaction(originator string, beneficiary string, actor Actor) (*Transaction, error) {
 	var originatorIdentity, beneficiaryIdentity Identity
 
 	// Fetch originator identity record
@@ -148,14 +220,24 @@ func (d *DB) MakeTransaction(originator string, beneficiary string) (*Transactio
 		}
 	}
 
+	now := time.Now()
 	return &Transaction{
 		Envelope:    uuid.New().String(),
 		Originator:  originatorIdentity,
 		Beneficiary: beneficiaryIdentity,
 		State:       pb.TransactionState_AWAITING_REPLY,
 		StateString: pb.TransactionState_AWAITING_REPLY.String(),
-		Timestamp:   time.Now(),
+		Timestamp:   now,
 		Vasp:        d.vasp,
+		History: []StateTransition{{
+			Previous:       pb.TransactionState_INVALID,
+			PreviousString: pb.TransactionState_INVALID.String(),
+			State:          pb.TransactionState_AWAITING_REPLY,
+			StateString:    pb.TransactionState_AWAITING_REPLY.String(),
+			Actor:          actor,
+			Reason:         "transaction created",
+			Timestamp:      now,
+		}},
 	}, nil
 }
 
@@ -270,6 +352,7 @@ type Transaction struct {
 	Transaction   string              `gorm:"not null"`
 	VaspID        uint                `gorm:"not null"`
 	Vasp          VASP                `gorm:"foreignKey:VaspID"`
+	History       []StateTransition   `gorm:"foreignKey:TransactionID"`
 }
 
 // TableName explicitly defines the name of the table for the model
@@ -277,10 +360,68 @@ func (Transaction) TableName() string {
 	return "transactions"
 }
 
-// Set the transaction state to a new value
-func (t *Transaction) SetState(state pb.TransactionState) {
+// SetState sets the transaction state to a new value and records the transition in the
+// history of the transaction, which is stored when the transaction is next saved. An
+// ErrInvalidTransition error is returned and the transaction is left unmodified if the
+// transaction cannot move from its current state to the new state.
+func (t *Transaction) SetState(state pb.TransactionState, actor Actor, reason string) (err error) {
+	if err = ValidateTransition(t.State, state); err != nil {
+		return err
+	}
+
+	t.History = append(t.History, StateTransition{
+		Previous:       t.State,
+		PreviousString: t.State.String(),
+		State:          state,
+		StateString:    state.String(),
+		Actor:          actor,
+		Reason:         reason,
+		Timestamp:      time.Now(),
+	})
+
 	t.State = state
 	t.StateString = state.String()
+	return nil
+}
+
+// Actor describes what caused the state of a transaction to change.
+type Actor string
+
+const (
+	ActorIntegration Actor = "integration" // a request to the rVASP integration or demo API
+	ActorPeer        Actor = "peer"        // a secure envelope received from a TRISA peer
+	ActorAsync       Actor = "async"       // the handler of pending asynchronous transactions
+)
+
+// StateTransition records a change in the state of a transaction so that support
+// requests about failed or stuck transactions can be investigated. The reason
+// describes why the transition occurred, e.g. the error that failed the transaction.
+type StateTransition struct {
+	gorm.Model
+	TransactionID  uint                `gorm:"not null;index"`
+	Previous       pb.TransactionState `gorm:"not null;default:0"`
+	PreviousString string              `gorm:"column:previous_string;not null"`
+	State          pb.TransactionState `gorm:"not null;default:0"`
+	StateString    string              `gorm:"column:state_string;not null"`
+	Actor          Actor               `gorm:"not null"`
+	Reason         string              `gorm:"not null;default:''"`
+	Timestamp      time.Time           `gorm:"not null"`
+}
+
+// TableName explicitly defines the name of the table for the model
+func (StateTransition) TableName() string {
+	return "state_transitions"
+}
+
+// Proto converts the state transition into a protocol buffer message.
+func (s StateTransition) Proto() *pb.StateTransition {
+	return &pb.StateTransition{
+		Previous:  s.Previous,
+		State:     s.State,
+		Actor:     string(s.Actor),
+		Reason:    s.Reason,
+		Timestamp: s.Timestamp.Format(time.RFC3339),
+	}
 }
 
 // Identity holds raw data for an originator or a beneficiary that was sent as
@@ -302,6 +443,78 @@ func (Identity) TableName() string {
 	return "identities"
 }
 
+// Direction describes whether a recorded envelope was received or sent by the rVASP.
+type Direction string
+
+const (
+	Inbound  Direction = "inbound"
+	Outbound Direction = "outbound"
+)
+
+// Envelope is a secure envelope exchanged with a remote peer, recorded exactly as it
+// was sent or received (i.e. still encrypted) so that TRISA conversations can be
+// replayed. The state is the state of the transaction once the exchange was handled
+// and the error describes any failure handling the exchange.
+type Envelope struct {
+	gorm.Model
+	Envelope    string              `gorm:"not null;index"`
+	Direction   Direction           `gorm:"not null"`
+	Peer        string              `gorm:"not null"`
+	Timestamp   time.Time           `gorm:"not null"`
+	State       pb.TransactionState `gorm:"not null;default:0"`
+	StateString string              `gorm:"column:state_string;not null"`
+	Error       string              `gorm:"not null;default:''"`
+	Data        []byte              `gorm:"not null"`
+	VaspID      uint                `gorm:"not null"`
+	Vasp        VASP                `gorm:"foreignKey:VaspID"`
+}
+
+// TableName explicitly defines the name of the table for the model
+func (Envelope) TableName() string {
+	return "envelopes"
+}
+
+// Webhook is a URL registered to receive an event whenever the state of a transaction
+// of the VASP changes, or only of the transactions of the account if one is specified.
+// Events are signed with the secret so that receivers can verify their origin.
+type Webhook struct {
+	gorm.Model
+	URL       string   `gorm:"not null"`
+	Secret    string   `gorm:"not null"`
+	AccountID *uint    `gorm:"null;index"`
+	Account   *Account `gorm:"foreignKey:AccountID"`
+	VaspID    uint     `gorm:"not null"`
+	Vasp      VASP     `gorm:"foreignKey:VaspID"`
+}
+
+// TableName explicitly defines the name of the table for the model
+func (Webhook) TableName() string {
+	return "webhooks"
+}
+
+// WebhookDelivery records an attempt to deliver a transaction event to a webhook. The
+// status code is the HTTP status returned by the webhook, if any, and the error
+// describes why the attempt failed.
+type WebhookDelivery struct {
+	gorm.Model
+	WebhookID   uint                `gorm:"not null;index"`
+	Webhook     Webhook             `gorm:"foreignKey:WebhookID"`
+	Event       string              `gorm:"not null;index"`
+	Envelope    string              `gorm:"not null"`
+	State       pb.TransactionState `gorm:"not null;default:0"`
+	StateString string              `gorm:"column:state_string;not null"`
+	Attempt     int                 `gorm:"not null"`
+	StatusCode  int                 `gorm:"not null;default:0"`
+	Error       string              `gorm:"not null;default:''"`
+	Delivered   bool                `gorm:"not null;default:false"`
+	Timestamp   time.Time           `gorm:"not null"`
+}
+
+// TableName explicitly defines the name of the table for the model
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
+
 // BalanceFloat converts the balance decimal into an exact two precision float32 for
 // use with the protocol buffers.
 func (a Account) BalanceFloat() float32 {
@@ -330,9 +543,11 @@ func (a Account) GetWallet(db *DB) (wallet *Wallet, err error) {
 // Transactions returns an ordered list of transactions associated with the account
 // ordered by the timestamp of the transaction, listing any pending transactions at the
 // top. This function may also support pagination and limiting functions, which is why
-// we're using it rather than having a direct relationship on the model.
+// we're using it rather than having a direct relationship on the model. The state
+// transition history is not loaded; use LookupHistory to fetch it.
 func (a Account) Transactions(db *DB) (records []Transaction, err error) {
-	if err = db.Query().Preload(clause.Associations).Where("account_id = ?", a.ID).Find(&records).Error; err != nil {
+	query := db.Query().Preload("Account").Preload("Originator").Preload("Beneficiary").Preload("Vasp")
+	if err = query.Where("account_id = ?", a.ID).Find(&records).Error; err != nil {
 		return nil, err
 	}
 	return records, nil
@@ -433,22 +648,18 @@ func OpenDB(conf *config.Config) (db *gorm.DB, err error) {
 	return db, fmt.Errorf("could not connect to database after %d retries: %s", conf.Database.MaxRetries, err)
 }
 
-// MigrateDB the schema based on the models defined above.
+// MigrateDB applies all pending schema migrations to the database.
 func MigrateDB(gdb *gorm.DB) (err error) {
-	// Migrate models
-	if err = gdb.AutoMigrate(&VASP{}, &Wallet{}, &Account{}, &Transaction{}, &Identity{}); err != nil {
-		return err
-	}
-
-	return nil
+	return MigrateUp(gdb, 0)
 }
 
 // ResetDB resets the database using the JSON fixtures.
 func ResetDB(gdb *gorm.DB, fixturesPath string) (err error) {
 	var (
-		vasps    []VASP
-		wallets  []Wallet
-		accounts []Account
+		vasps        []VASP
+		wallets      []Wallet
+		accounts     []Account
+		transactions []TransactionFixture
 	)
 
 	// Load the VASP fixtures
@@ -461,8 +672,17 @@ func ResetDB(gdb *gorm.DB, fixturesPath string) (err error) {
 		return err
 	}
 
+	// Load the transaction history fixtures and update the accounts to match
+	if transactions, err = LoadTransactions(fixturesPath); err != nil {
+		return err
+	}
+
+	if err = ReconcileAccounts(accounts, transactions); err != nil {
+		return err
+	}
+
 	// Reset the database
-	if err = gdb.Migrator().DropTable(&VASP{}, &Wallet{}, &Account{}, &Transaction{}, &Identity{}); err != nil {
+	if err = gdb.Migrator().DropTable(&WebhookDelivery{}, &Webhook{}, &StateTransition{}, &Envelope{}, &VASP{}, &Wallet{}, &Account{}, &Transaction{}, &Identity{}, &SchemaMigration{}); err != nil {
 		return err
 	}
 
@@ -486,5 +706,10 @@ func ResetDB(gdb *gorm.DB, fixturesPath string) (err error) {
 		return err
 	}
 
+	// Insert the transaction history into the database
+	if err = insertHistory(gdb, accounts, transactions); err != nil {
+		return err
+	}
+
 	return nil
 }
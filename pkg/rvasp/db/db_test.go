@@ -14,10 +14,17 @@ import (
 
 const (
 	FIXTURES_PATH = "../fixtures"
-	NUM_TABLES    = 5
-	NUM_INDICES   = 11
+	NUM_TABLES    = 9
 )
 
+// The number of tables and indices created by each schema migration
+var migrationCreates = []int{
+	5 + 11, // initial schema
+	1 + 2,  // envelopes table
+	1 + 2,  // state transitions table
+	2 + 5,  // webhooks tables
+}
+
 // Expect a query which does no row updates (e.g. CREATE TABLE, DROP TABLE, etc.)
 func expectExec(mock sqlmock.Sqlmock, query string) {
 	mock.ExpectExec(query).WillReturnResult(sqlmock.NewResult(0, 0))
@@ -37,6 +44,22 @@ func expectDropTable(mock sqlmock.Sqlmock, numDropped int) {
 	}
 }
 
+// Expect the schema migrations to be applied to an empty database
+func expectMigrate(mock sqlmock.Sqlmock) {
+	// Expecting the schema migrations table to be created and queried
+	expectCreate(mock, 1)
+	mock.ExpectQuery(`SELECT \* FROM "schema_migrations"`).WillReturnRows(sqlmock.NewRows([]string{"version", "name", "applied_at"}))
+
+	// Expecting the tables and indices of each migration to be created with the
+	// migration version in its own transaction
+	for _, numCreated := range migrationCreates {
+		mock.ExpectBegin()
+		expectCreate(mock, numCreated)
+		expectExec(mock, `INSERT INTO "schema_migrations"`)
+		mock.ExpectCommit()
+	}
+}
+
 // Expect a query which inserts a number of rows into a table
 func expectInsert(mock sqlmock.Sqlmock, table string, numInserted int) {
 	if numInserted > 1 {
@@ -83,21 +106,23 @@ func (s *dbTestSuite) TestMigrateDB() {
 
 	// We can't rely on the order in which the migration operations are executed, so
 	// this asserts that the number of operations is correct.
-	expectCreate(s.mock, NUM_TABLES+NUM_INDICES)
+	expectMigrate(s.mock)
 	require.NoError(db.MigrateDB(gdb))
 }
 
 func (s *dbTestSuite) TestResetDB() {
-	// Expecting the tables to be dropped
-	expectDropTable(s.mock, NUM_TABLES)
+	// Expecting the tables and the schema migrations table to be dropped
+	expectDropTable(s.mock, NUM_TABLES+1)
 
 	// Expecting the tables and indices to be created
-	expectCreate(s.mock, NUM_TABLES+NUM_INDICES)
+	expectMigrate(s.mock)
 
 	// Expecting table inserts
 	expectInsert(s.mock, "vasps", 3)
 	expectInsert(s.mock, "wallets", 12)
 	expectInsert(s.mock, "accounts", 12)
+	expectInsert(s.mock, "identities", 24)
+	expectInsert(s.mock, "transactions", 17)
 
 	// Reset the database
 	require.NoError(s.T(), db.ResetDB(s.db.GetDB(), FIXTURES_PATH))
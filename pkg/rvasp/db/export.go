@@ -0,0 +1,257 @@
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"strings"
+
+	pb "github.com/trisacrypto/testnet/pkg/rvasp/pb/v1"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ExportOptions filter and transform the records written by Export.
+type ExportOptions struct {
+	// Only export the wallets, accounts, and transactions of this VASP, matched by
+	// common name or DNS label (e.g. "bob"); all VASPs are exported if empty.
+	VASP string
+
+	// Replace the IVMS101 identities, names, and emails of accounts with generated
+	// ones and drop the identity payloads of transactions.
+	Anonymize bool
+}
+
+// Export writes the current state of the database to the fixtures directory in the
+// format read by ResetDB so that a database can be captured and reproduced locally.
+// All VASPs are always exported since wallets and transactions refer to them.
+func Export(gdb *gorm.DB, fixturesPath string, opts ExportOptions) (err error) {
+	var (
+		vasps        []VASP
+		wallets      []Wallet
+		accounts     []Account
+		transactions []Transaction
+	)
+
+	if err = gdb.Order("id").Find(&vasps).Error; err != nil {
+		return fmt.Errorf("could not fetch vasps: %s", err)
+	}
+
+	query := gdb.Order("id")
+	if opts.VASP != "" {
+		var position uint
+		if position, err = FindVASP(vasps, opts.VASP); err != nil {
+			return err
+		}
+		query = query.Where("vasp_id = ?", vasps[position-1].ID)
+	}
+
+	if err = query.Session(&gorm.Session{}).Find(&wallets).Error; err != nil {
+		return fmt.Errorf("could not fetch wallets: %s", err)
+	}
+
+	if err = query.Session(&gorm.Session{}).Find(&accounts).Error; err != nil {
+		return fmt.Errorf("could not fetch accounts: %s", err)
+	}
+
+	if err = query.Session(&gorm.Session{}).Preload(clause.Associations).Find(&transactions).Error; err != nil {
+		return fmt.Errorf("could not fetch transactions: %s", err)
+	}
+
+	return exportFixtures(fixturesPath, vasps, wallets, accounts, transactions, opts.Anonymize)
+}
+
+// exportFixtures converts the database records into fixtures and writes them. VASP IDs
+// are replaced by the position of the VASP in the vasps fixture.
+func exportFixtures(fixturesPath string, vasps []VASP, wallets []Wallet, accounts []Account, transactions []Transaction, anonymize bool) (err error) {
+	positions := make(map[uint]uint, len(vasps))
+	for i, v := range vasps {
+		positions[v.ID] = uint(i + 1)
+	}
+
+	var anon *anonymizer
+	if anonymize {
+		anon = newAnonymizer()
+		for i := range accounts {
+			if err = anon.Account(&accounts[i]); err != nil {
+				return err
+			}
+		}
+	}
+
+	for i, w := range wallets {
+		var ok bool
+		if wallets[i].ProviderID, ok = positions[w.ProviderID]; !ok {
+			return fmt.Errorf("wallet %s has unknown provider %d", w.Address, w.ProviderID)
+		}
+		wallets[i].VaspID = wallets[i].ProviderID
+
+		if anon != nil {
+			wallets[i].Email = anon.Email(w.Email)
+		}
+	}
+
+	history := make([]TransactionFixture, 0, len(transactions))
+	for _, t := range transactions {
+		fixture := TransactionFixture{
+			Envelope: t.Envelope,
+			Account:  t.Account.WalletAddress,
+			Originator: IdentityFixture{
+				WalletAddress: t.Originator.WalletAddress,
+				Email:         t.Originator.Email,
+				Provider:      t.Originator.Provider,
+			},
+			Beneficiary: IdentityFixture{
+				WalletAddress: t.Beneficiary.WalletAddress,
+				Email:         t.Beneficiary.Email,
+				Provider:      t.Beneficiary.Provider,
+			},
+			Amount:    t.Amount,
+			AssetType: t.AssetType,
+			State:     t.State.String(),
+			Timestamp: t.Timestamp,
+		}
+
+		if !t.NotBefore.IsZero() {
+			notBefore := t.NotBefore
+			fixture.NotBefore = &notBefore
+		}
+
+		if !t.NotAfter.IsZero() {
+			notAfter := t.NotAfter
+			fixture.NotAfter = &notAfter
+		}
+
+		if t.Identity != "" && anon == nil {
+			fixture.Identity = json.RawMessage(t.Identity)
+		}
+
+		if t.Transaction != "" {
+			fixture.Transaction = json.RawMessage(t.Transaction)
+		}
+
+		if anon != nil {
+			fixture.Originator.Email = anon.Email(fixture.Originator.Email)
+			fixture.Beneficiary.Email = anon.Email(fixture.Beneficiary.Email)
+		}
+
+		history = append(history, fixture)
+	}
+
+	if err = openingBalances(accounts, history); err != nil {
+		return err
+	}
+
+	if err = WriteVASPs(fixturesPath, vasps); err != nil {
+		return err
+	}
+
+	if err = WriteWallets(fixturesPath, wallets, accounts); err != nil {
+		return err
+	}
+
+	return WriteTransactions(fixturesPath, history)
+}
+
+// openingBalances replaces the current balances of the accounts with their balances
+// before the transaction history, so that ReconcileAccounts restores the current
+// balances when the exported fixtures are loaded.
+func openingBalances(accounts []Account, history []TransactionFixture) (err error) {
+	for _, t := range history {
+		if t.State != pb.TransactionState_COMPLETED.String() {
+			continue
+		}
+
+		var idx int
+		if idx, err = findAccount(accounts, t.Account); err != nil {
+			return fmt.Errorf("transaction %s: %s", t.Envelope, err)
+		}
+
+		account := &accounts[idx]
+		if account.WalletAddress == t.Originator.WalletAddress {
+			account.Balance = account.Balance.Add(t.Amount)
+		} else {
+			account.Balance = account.Balance.Sub(t.Amount)
+		}
+	}
+	return nil
+}
+
+// WriteTransactions writes the transaction history to the fixtures directory in the
+// format read by LoadTransactions.
+func WriteTransactions(fixturesPath string, transactions []TransactionFixture) (err error) {
+	if transactions == nil {
+		transactions = make([]TransactionFixture, 0)
+	}
+	return writeFile(fixturesPath, TRANSACTIONS_FILE, transactions)
+}
+
+// anonymizer replaces account identities with generated ones. Identities are derived
+// from the wallet address so that an account is always anonymized the same way, and
+// emails are mapped consistently across wallets, accounts, and transactions.
+type anonymizer struct {
+	names  *Generator
+	emails map[string]string
+}
+
+func newAnonymizer() *anonymizer {
+	return &anonymizer{
+		names:  NewGenerator(0, nil),
+		emails: make(map[string]string),
+	}
+}
+
+// Account replaces the identity, name, and email of the account.
+func (a *anonymizer) Account(account *Account) (err error) {
+	person := newSeededGenerator(account.WalletAddress).Identity()
+
+	var data []byte
+	if data, err = MarshalIdentity(person); err != nil {
+		return err
+	}
+
+	name := person.GetNaturalPerson().Name.NameIdentifiers[0]
+	account.Name = fmt.Sprintf("%s %s", name.SecondaryIdentifier, name.PrimaryIdentifier)
+	account.IVMS101 = string(data)
+
+	if email, ok := a.emails[account.Email]; ok {
+		account.Email = email
+		return nil
+	}
+
+	email := a.names.email(name.SecondaryIdentifier, name.PrimaryIdentifier, emailDomain(account.Email))
+	a.emails[account.Email] = email
+	account.Email = email
+	return nil
+}
+
+// Email returns the anonymized email for the original email, generating a new one
+// for emails that do not belong to an anonymized account.
+func (a *anonymizer) Email(original string) string {
+	if original == "" {
+		return ""
+	}
+
+	if email, ok := a.emails[original]; ok {
+		return email
+	}
+
+	name := newSeededGenerator(original).Identity().GetNaturalPerson().Name.NameIdentifiers[0]
+	email := a.names.email(name.SecondaryIdentifier, name.PrimaryIdentifier, emailDomain(original))
+	a.emails[original] = email
+	return email
+}
+
+// newSeededGenerator returns a generator seeded by the hash of the key.
+func newSeededGenerator(key string) *Generator {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	return NewGenerator(int64(h.Sum64()), nil)
+}
+
+func emailDomain(email string) string {
+	if _, domain, ok := strings.Cut(email, "@"); ok {
+		return domain
+	}
+	return "example.com"
+}
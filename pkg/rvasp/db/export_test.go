@@ -0,0 +1,201 @@
+package db
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	pb "github.com/trisacrypto/testnet/pkg/rvasp/pb/v1"
+)
+
+// loadRecords loads the fixtures as if they had been stored in a database where the
+// VASP IDs do not match the positions of the VASPs in the fixtures.
+func loadRecords(t *testing.T) (vasps []VASP, wallets []Wallet, accounts []Account, transactions []Transaction) {
+	var err error
+	vasps, err = LoadVASPs(FIXTURES_PATH)
+	require.NoError(t, err)
+
+	wallets, accounts, err = LoadWallets(FIXTURES_PATH)
+	require.NoError(t, err)
+
+	history, err := LoadTransactions(FIXTURES_PATH)
+	require.NoError(t, err)
+
+	// Accounts in the database have the balances that result from the history
+	for i := range accounts {
+		accounts[i].Balance = decimal.NewFromInt(1000)
+	}
+	require.NoError(t, ReconcileAccounts(accounts, history))
+
+	for i := range vasps {
+		vasps[i].ID = uint(i + 10)
+	}
+
+	for i := range wallets {
+		wallets[i].ProviderID += 9
+		wallets[i].VaspID += 9
+		accounts[i].VaspID += 9
+	}
+
+	for _, h := range history {
+		idx, err := findAccount(accounts, h.Account)
+		require.NoError(t, err)
+
+		transactions = append(transactions, Transaction{
+			Envelope:    h.Envelope,
+			Account:     accounts[idx],
+			Originator:  Identity{WalletAddress: h.Originator.WalletAddress, Email: h.Originator.Email, Provider: h.Originator.Provider},
+			Beneficiary: Identity{WalletAddress: h.Beneficiary.WalletAddress, Email: h.Beneficiary.Email, Provider: h.Beneficiary.Provider},
+			Amount:      h.Amount,
+			AssetType:   h.AssetType,
+			State:       pb.TransactionState(pb.TransactionState_value[h.State]),
+			Timestamp:   h.Timestamp,
+			Transaction: string(h.Transaction),
+			Identity:    `{"originator":{}}`,
+		})
+	}
+	return vasps, wallets, accounts, transactions
+}
+
+func TestExportFixtures(t *testing.T) {
+	vasps, wallets, accounts, transactions := loadRecords(t)
+	balances := accountBalances(accounts)
+
+	dir := t.TempDir()
+	require.NoError(t, exportFixtures(dir, vasps, wallets, accounts, transactions, false))
+
+	// The exported fixtures should be identical to the original fixtures
+	expectedWallets, expectedAccounts, err := LoadWallets(FIXTURES_PATH)
+	require.NoError(t, err)
+
+	actualWallets, actualAccounts, err := LoadWallets(dir)
+	require.NoError(t, err)
+	require.Equal(t, expectedWallets, actualWallets)
+	for i, account := range expectedAccounts {
+		require.Equal(t, account.Email, actualAccounts[i].Email)
+		require.JSONEq(t, account.IVMS101, actualAccounts[i].IVMS101)
+	}
+
+	expectedHistory, err := LoadTransactions(FIXTURES_PATH)
+	require.NoError(t, err)
+
+	actualHistory, err := LoadTransactions(dir)
+	require.NoError(t, err)
+	require.Len(t, actualHistory, len(expectedHistory))
+	for i, expected := range expectedHistory {
+		actual := actualHistory[i]
+		require.Equal(t, expected.Envelope, actual.Envelope)
+		require.Equal(t, expected.Originator, actual.Originator)
+		require.Equal(t, expected.Beneficiary, actual.Beneficiary)
+		require.Equal(t, expected.State, actual.State)
+		require.True(t, expected.Amount.Equal(actual.Amount))
+		require.JSONEq(t, string(expected.Transaction), string(actual.Transaction))
+		require.JSONEq(t, `{"originator":{}}`, string(actual.Identity))
+
+		idx, err := findAccount(expectedAccounts, expected.Account)
+		require.NoError(t, err)
+		require.Equal(t, expectedAccounts[idx].WalletAddress, actual.Account)
+	}
+
+	// Loading the exported fixtures restores the balances of the accounts
+	require.NoError(t, ReconcileAccounts(actualAccounts, actualHistory))
+	for i, balance := range balances {
+		require.True(t, balance.Equal(actualAccounts[i].Balance), "expected balance %s for %s, got %s", balance, actualAccounts[i].Email, actualAccounts[i].Balance)
+	}
+
+	// Wallets with unknown providers cannot be exported
+	wallets[0].ProviderID = 42
+	require.Error(t, exportFixtures(t.TempDir(), vasps, wallets, accounts, transactions, false))
+}
+
+func TestExportAnonymized(t *testing.T) {
+	vasps, wallets, accounts, transactions := loadRecords(t)
+	originals := make(map[string]Account)
+	for _, account := range accounts {
+		originals[account.WalletAddress] = account
+	}
+
+	dir := t.TempDir()
+	require.NoError(t, exportFixtures(dir, vasps, wallets, accounts, transactions, true))
+
+	actualWallets, actualAccounts, err := LoadWallets(dir)
+	require.NoError(t, err)
+
+	emails := make(map[string]struct{})
+	for i, account := range actualAccounts {
+		original := originals[account.WalletAddress]
+		require.NotEqual(t, original.Email, account.Email, "account emails should be anonymized")
+		require.NotEqual(t, original.Name, account.Name, "account names should be anonymized")
+		require.Equal(t, emailDomain(original.Email), emailDomain(account.Email), "account email domains should be preserved")
+		require.Equal(t, account.Email, actualWallets[i].Email)
+		emails[account.Email] = struct{}{}
+
+		identity, err := account.LoadIdentity()
+		require.NoError(t, err)
+		require.NoError(t, identity.GetNaturalPerson().Validate())
+	}
+
+	// Transaction identities should use the same anonymized emails as the accounts
+	history, err := LoadTransactions(dir)
+	require.NoError(t, err)
+	for _, h := range history {
+		require.Empty(t, h.Identity, "identity payloads should be dropped")
+		require.Contains(t, emails, h.Originator.Email)
+		require.Contains(t, emails, h.Beneficiary.Email)
+	}
+
+	// Anonymization should be deterministic
+	vasps, wallets, accounts, transactions = loadRecords(t)
+	other := t.TempDir()
+	require.NoError(t, exportFixtures(other, vasps, wallets, accounts, transactions, true))
+	for _, name := range []string{VASPS_FILE, WALLETS_FILE, TRANSACTIONS_FILE} {
+		expected, err := os.ReadFile(filepath.Join(dir, name))
+		require.NoError(t, err)
+		actual, err := os.ReadFile(filepath.Join(other, name))
+		require.NoError(t, err)
+		require.Equal(t, expected, actual, "expected %s to be identical", name)
+	}
+}
+
+func TestExportBalances(t *testing.T) {
+	vasps, wallets, accounts, transactions := loadRecords(t)
+
+	// An account that has sent more than a random opening balance
+	accounts[0].Balance = decimal.NewFromFloat(0.5)
+	transactions = append(transactions, Transaction{
+		Envelope:    "6d8c0b6c-5a1e-4f0b-9d45-3c3b4a1d9f10",
+		Account:     accounts[0],
+		Originator:  Identity{WalletAddress: accounts[0].WalletAddress, Email: accounts[0].Email},
+		Beneficiary: Identity{WalletAddress: accounts[1].WalletAddress, Email: accounts[1].Email},
+		Amount:      decimal.NewFromInt(25000),
+		AssetType:   "BTC",
+		State:       pb.TransactionState_COMPLETED,
+		Timestamp:   time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+	})
+
+	balances := accountBalances(accounts)
+	dir := t.TempDir()
+	require.NoError(t, exportFixtures(dir, vasps, wallets, accounts, transactions, false))
+
+	_, actualAccounts, err := LoadWallets(dir)
+	require.NoError(t, err)
+	history, err := LoadTransactions(dir)
+	require.NoError(t, err)
+
+	require.NoError(t, ReconcileAccounts(actualAccounts, history))
+	for i, balance := range balances {
+		require.True(t, balance.Equal(actualAccounts[i].Balance), "expected balance %s for %s, got %s", balance, actualAccounts[i].Email, actualAccounts[i].Balance)
+	}
+}
+
+// accountBalances returns the balances of the accounts before they are exported.
+func accountBalances(accounts []Account) []decimal.Decimal {
+	balances := make([]decimal.Decimal, 0, len(accounts))
+	for _, account := range accounts {
+		balances = append(balances, account.Balance)
+	}
+	return balances
+}
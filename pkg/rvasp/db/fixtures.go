@@ -1,14 +1,20 @@
 package db
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/rand"
 	"os"
 	"path/filepath"
+	"sort"
+	"time"
 
 	"github.com/shopspring/decimal"
+	pb "github.com/trisacrypto/testnet/pkg/rvasp/pb/v1"
 	"github.com/trisacrypto/testnet/pkg/utils"
+	"gorm.io/gorm"
 )
 
 const (
@@ -145,9 +151,9 @@ func LoadWallets(fixturesPath string) (wallets []Wallet, accounts []Account, err
 			return nil, nil, fmt.Errorf("could not parse wallet record: %v", record)
 		}
 
-		// Validate the number of fields
-		if len(fields) != 6 {
-			return nil, nil, fmt.Errorf("invalid number of wallet fields: got %d, expected 5", len(fields))
+		// Validate the number of fields, the opening balance is optional
+		if len(fields) != 6 && len(fields) != 7 {
+			return nil, nil, fmt.Errorf("invalid number of wallet fields: got %d, expected 6 or 7", len(fields))
 		}
 
 		// Parse the wallet fields
@@ -218,8 +224,19 @@ func LoadWallets(fixturesPath string) (wallets []Wallet, accounts []Account, err
 		}
 		a.IVMS101 = string(natural_person_bytes)
 
-		// Give the account a random positive balance
-		a.Balance = decimal.NewFromFloat32(float32(rand.Intn(4950) + 50 + (rand.Intn(100) / 100.0)))
+		// Use the opening balance of the account if specified, otherwise give the account
+		// a random positive balance
+		if len(fields) == 7 {
+			var balance string
+			if balance, ok = fields[6].(string); !ok {
+				return nil, nil, fmt.Errorf("could not parse opening balance for wallet %s: %v", w.Address, fields[6])
+			}
+			if a.Balance, err = decimal.NewFromString(balance); err != nil {
+				return nil, nil, fmt.Errorf("could not parse opening balance for wallet %s: %s", w.Address, err)
+			}
+		} else {
+			a.Balance = decimal.NewFromFloat32(float32(rand.Intn(4950) + 50 + (rand.Intn(100) / 100.0)))
+		}
 
 		wallets = append(wallets, w)
 		accounts = append(accounts, a)
@@ -227,3 +244,319 @@ func LoadWallets(fixturesPath string) (wallets []Wallet, accounts []Account, err
 
 	return wallets, accounts, nil
 }
+
+// TransactionFixture is a historical transaction in the transactions fixture file. The
+// transaction is recorded in the ledger of the local account, which is looked up by
+// wallet address or email; the transaction is a debit if the account is the
+// originator of the transaction and a credit otherwise.
+type TransactionFixture struct {
+	Envelope    string          `json:"envelope"`
+	Account     string          `json:"account"`
+	Originator  IdentityFixture `json:"originator"`
+	Beneficiary IdentityFixture `json:"beneficiary"`
+	Amount      decimal.Decimal `json:"amount"`
+	AssetType   string          `json:"asset_type"`
+	State       string          `json:"state"`
+	Timestamp   time.Time       `json:"timestamp"`
+	NotBefore   *time.Time      `json:"not_before,omitempty"`
+	NotAfter    *time.Time      `json:"not_after,omitempty"`
+	Identity    json.RawMessage `json:"identity,omitempty"`
+	Transaction json.RawMessage `json:"transaction,omitempty"`
+}
+
+// IdentityFixture is the originator or beneficiary of a transaction fixture.
+type IdentityFixture struct {
+	WalletAddress string `json:"wallet_address"`
+	Email         string `json:"email,omitempty"`
+	Provider      string `json:"provider,omitempty"`
+}
+
+// Load transactions from the fixtures directory. The transactions fixture is optional
+// so no transactions and no error are returned if the file does not exist.
+func LoadTransactions(fixturesPath string) (transactions []TransactionFixture, err error) {
+	var bytes []byte
+	if bytes, err = loadFile(fixturesPath, TRANSACTIONS_FILE); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if err = json.Unmarshal(bytes, &transactions); err != nil {
+		return nil, err
+	}
+
+	for i, t := range transactions {
+		if t.Envelope == "" {
+			return nil, fmt.Errorf("missing envelope id for transaction %d", i)
+		}
+
+		if t.Account == "" {
+			return nil, fmt.Errorf("missing account for transaction %s", t.Envelope)
+		}
+
+		if t.Originator.WalletAddress == "" || t.Beneficiary.WalletAddress == "" {
+			return nil, fmt.Errorf("missing originator or beneficiary wallet address for transaction %s", t.Envelope)
+		}
+
+		if !t.Amount.IsPositive() {
+			return nil, fmt.Errorf("invalid amount for transaction %s: %s", t.Envelope, t.Amount)
+		}
+
+		if _, ok := pb.TransactionState_value[t.State]; !ok || t.State == pb.TransactionState_INVALID.String() {
+			return nil, fmt.Errorf("invalid state for transaction %s: %q", t.Envelope, t.State)
+		}
+
+		if t.Timestamp.IsZero() {
+			return nil, fmt.Errorf("missing timestamp for transaction %s", t.Envelope)
+		}
+
+		// Payloads are stored as compact JSON strings
+		if transactions[i].Identity, err = compactJSON(t.Identity); err != nil {
+			return nil, fmt.Errorf("could not parse identity for transaction %s: %s", t.Envelope, err)
+		}
+		if transactions[i].Transaction, err = compactJSON(t.Transaction); err != nil {
+			return nil, fmt.Errorf("could not parse transaction payload for transaction %s: %s", t.Envelope, err)
+		}
+	}
+
+	return transactions, nil
+}
+
+// compactJSON removes insignificant whitespace from a raw JSON message.
+func compactJSON(data json.RawMessage) (json.RawMessage, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	buf := &bytes.Buffer{}
+	if err := json.Compact(buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// isPendingState returns true if the transaction has not yet reached a final state.
+func isPendingState(state pb.TransactionState) bool {
+	switch state {
+	case pb.TransactionState_AWAITING_REPLY, pb.TransactionState_PENDING_SENT,
+		pb.TransactionState_AWAITING_FULL_TRANSFER, pb.TransactionState_PENDING_RECEIVED,
+		pb.TransactionState_PENDING_ACKNOWLEDGED, pb.TransactionState_ACCEPTED:
+		return true
+	default:
+		return false
+	}
+}
+
+// findAccount returns the index of the account with the wallet address or email.
+func findAccount(accounts []Account, account string) (int, error) {
+	for i, a := range accounts {
+		if a.WalletAddress == account || a.Email == account {
+			return i, nil
+		}
+	}
+	return -1, fmt.Errorf("could not find account %q", account)
+}
+
+// ReconcileAccounts updates the balances and the completed and pending counters of the
+// accounts to be consistent with the transaction history. The loaded account balance
+// is treated as the opening balance before the history; completed debits are
+// subtracted from it and completed credits are added to it. An error is returned if
+// the history would overdraw an account.
+func ReconcileAccounts(accounts []Account, transactions []TransactionFixture) (err error) {
+	// Apply the transactions in chronological order so overdrafts are detected
+	history := make([]TransactionFixture, len(transactions))
+	copy(history, transactions)
+	sort.SliceStable(history, func(i, j int) bool {
+		return history[i].Timestamp.Before(history[j].Timestamp)
+	})
+
+	for _, t := range history {
+		var idx int
+		if idx, err = findAccount(accounts, t.Account); err != nil {
+			return fmt.Errorf("transaction %s: %s", t.Envelope, err)
+		}
+
+		account := &accounts[idx]
+		state := pb.TransactionState(pb.TransactionState_value[t.State])
+		switch {
+		case state == pb.TransactionState_COMPLETED:
+			account.Completed++
+			if account.WalletAddress == t.Originator.WalletAddress {
+				account.Balance = account.Balance.Sub(t.Amount)
+			} else {
+				account.Balance = account.Balance.Add(t.Amount)
+			}
+		case isPendingState(state):
+			account.Pending++
+		}
+
+		if account.Balance.IsNegative() {
+			return fmt.Errorf("transaction %s overdraws account %s", t.Envelope, account.Email)
+		}
+	}
+	return nil
+}
+
+// insertHistory stores the identities and transactions of the transaction fixtures.
+// The accounts must already be stored so that the transactions can reference them.
+func insertHistory(gdb *gorm.DB, accounts []Account, fixtures []TransactionFixture) (err error) {
+	if len(fixtures) == 0 {
+		return nil
+	}
+
+	// Identities are unique per VASP and wallet address
+	type identityKey struct {
+		vaspID  uint
+		address string
+	}
+
+	var identities []Identity
+	index := make(map[identityKey]int)
+	addIdentity := func(vaspID uint, fixture IdentityFixture) {
+		key := identityKey{vaspID, fixture.WalletAddress}
+		if idx, ok := index[key]; ok {
+			// Fill in any details missing from previous transactions
+			if identities[idx].Email == "" {
+				identities[idx].Email = fixture.Email
+			}
+			if identities[idx].Provider == "" {
+				identities[idx].Provider = fixture.Provider
+			}
+			return
+		}
+
+		index[key] = len(identities)
+		identities = append(identities, Identity{
+			WalletAddress: fixture.WalletAddress,
+			Email:         fixture.Email,
+			Provider:      fixture.Provider,
+			VaspID:        vaspID,
+		})
+	}
+
+	owners := make([]Account, 0, len(fixtures))
+	for _, t := range fixtures {
+		var idx int
+		if idx, err = findAccount(accounts, t.Account); err != nil {
+			return fmt.Errorf("transaction %s: %s", t.Envelope, err)
+		}
+		owners = append(owners, accounts[idx])
+
+		addIdentity(accounts[idx].VaspID, t.Originator)
+		addIdentity(accounts[idx].VaspID, t.Beneficiary)
+	}
+
+	if err = gdb.Create(&identities).Error; err != nil {
+		return err
+	}
+
+	transactions := make([]Transaction, 0, len(fixtures))
+	for i, t := range fixtures {
+		account := owners[i]
+		state := pb.TransactionState(pb.TransactionState_value[t.State])
+		xfer := Transaction{
+			Envelope:      t.Envelope,
+			AccountID:     account.ID,
+			OriginatorID:  identities[index[identityKey{account.VaspID, t.Originator.WalletAddress}]].ID,
+			BeneficiaryID: identities[index[identityKey{account.VaspID, t.Beneficiary.WalletAddress}]].ID,
+			Amount:        t.Amount,
+			AssetType:     t.AssetType,
+			Debit:         account.WalletAddress == t.Originator.WalletAddress,
+			State:         state,
+			StateString:   state.String(),
+			Timestamp:     t.Timestamp,
+			Identity:      string(t.Identity),
+			Transaction:   string(t.Transaction),
+			VaspID:        account.VaspID,
+		}
+
+		if t.NotBefore != nil {
+			xfer.NotBefore = *t.NotBefore
+		}
+		if t.NotAfter != nil {
+			xfer.NotAfter = *t.NotAfter
+		}
+		transactions = append(transactions, xfer)
+	}
+
+	return gdb.Create(&transactions).Error
+}
+
+// vaspFixture is the serialized form of a VASP record in the vasps fixture file.
+type vaspFixture struct {
+	CommonName  string          `json:"common_name"`
+	LegalPerson json.RawMessage `json:"legal_person"`
+}
+
+// Write data as indented JSON to a file in the fixtures directory, creating the
+// directory if it does not exist.
+func writeFile(dir, fixture string, data interface{}) (err error) {
+	var bytes []byte
+	if bytes, err = json.MarshalIndent(data, "", "\t"); err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(dir, fixture), append(bytes, '\n'), 0644)
+}
+
+// WriteVASPs writes the VASPs to the fixtures directory in the format read by
+// LoadVASPs. The position of each VASP in the slice determines its fixture ID.
+func WriteVASPs(fixturesPath string, vasps []VASP) (err error) {
+	records := make([]vaspFixture, 0, len(vasps))
+	for _, v := range vasps {
+		var identity map[string]json.RawMessage
+		if err = json.Unmarshal([]byte(v.IVMS101), &identity); err != nil {
+			return fmt.Errorf("could not parse identity for vasp %s: %s", v.Name, err)
+		}
+
+		var ok bool
+		record := vaspFixture{CommonName: v.Name}
+		if record.LegalPerson, ok = identity["legal_person"]; !ok {
+			return fmt.Errorf("could not parse legal person for vasp %s", v.Name)
+		}
+		records = append(records, record)
+	}
+
+	return writeFile(fixturesPath, VASPS_FILE, records)
+}
+
+// WriteWallets writes the wallets and their accounts to the fixtures directory in the
+// format read by LoadWallets. Accounts are matched to wallets by wallet address and
+// the wallet provider ID is written as the VASP fixture ID. The balance of the account
+// is written as its opening balance.
+func WriteWallets(fixturesPath string, wallets []Wallet, accounts []Account) (err error) {
+	identities := make(map[string]string, len(accounts))
+	balances := make(map[string]decimal.Decimal, len(accounts))
+	for _, a := range accounts {
+		identities[a.WalletAddress] = a.IVMS101
+		balances[a.WalletAddress] = a.Balance
+	}
+
+	records := make([][]interface{}, 0, len(wallets))
+	for _, w := range wallets {
+		var (
+			ok       bool
+			identity string
+		)
+		if identity, ok = identities[w.Address]; !ok || identity == "" {
+			return fmt.Errorf("no account identity found for wallet %s", w.Address)
+		}
+
+		records = append(records, []interface{}{
+			w.Address,
+			w.Email,
+			w.ProviderID,
+			w.OriginatorPolicy,
+			w.BeneficiaryPolicy,
+			json.RawMessage(identity),
+			balances[w.Address].String(),
+		})
+	}
+
+	return writeFile(fixturesPath, WALLETS_FILE, records)
+}
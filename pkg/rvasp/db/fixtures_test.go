@@ -3,7 +3,9 @@ package db
 import (
 	"testing"
 
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/require"
+	pb "github.com/trisacrypto/testnet/pkg/rvasp/pb/v1"
 	"github.com/trisacrypto/testnet/pkg/utils"
 )
 
@@ -55,3 +57,70 @@ func TestLoadWallets(t *testing.T) {
 	require.Equal(t, 12, mainnetWallets)
 	require.Equal(t, 4, charlieWallets)
 }
+
+func TestLoadTransactions(t *testing.T) {
+	transactions, err := LoadTransactions(FIXTURES_PATH)
+	require.NoError(t, err)
+	require.Len(t, transactions, 17)
+
+	// Every valid transaction state should be represented in the fixtures
+	states := make(map[string]struct{})
+	for _, transaction := range transactions {
+		states[transaction.State] = struct{}{}
+		require.NotContains(t, string(transaction.Transaction), "\n", "transaction payloads should be compacted")
+	}
+	require.Len(t, states, len(pb.TransactionState_name)-1)
+
+	// The transactions fixture is optional
+	transactions, err = LoadTransactions(t.TempDir())
+	require.NoError(t, err)
+	require.Empty(t, transactions)
+}
+
+func TestReconcileAccounts(t *testing.T) {
+	_, accounts, err := LoadWallets(FIXTURES_PATH)
+	require.NoError(t, err)
+
+	transactions, err := LoadTransactions(FIXTURES_PATH)
+	require.NoError(t, err)
+
+	opening := make(map[string]decimal.Decimal)
+	for _, account := range accounts {
+		opening[account.Email] = account.Balance
+	}
+
+	require.NoError(t, ReconcileAccounts(accounts, transactions))
+
+	balances := make(map[string]Account)
+	for _, account := range accounts {
+		balances[account.Email] = account
+	}
+
+	// Robert received 0.0001 from Mary and sent 0.5 to Jane
+	robert := balances["robert@bobvasp.co.uk"]
+	require.Equal(t, uint64(2), robert.Completed)
+	require.Equal(t, uint64(0), robert.Pending)
+	require.True(t, opening[robert.Email].Sub(decimal.RequireFromString("0.4999")).Equal(robert.Balance))
+
+	// Larry has an acknowledged transaction that has not completed
+	larry := balances["larry@bobvasp.co.uk"]
+	require.Equal(t, uint64(0), larry.Completed)
+	require.Equal(t, uint64(1), larry.Pending)
+	require.True(t, opening[larry.Email].Equal(larry.Balance))
+
+	// Failed and rejected transactions should not change the balance
+	george := balances["george@bobvasp.co.uk"]
+	require.Equal(t, uint64(1), george.Completed)
+	require.Equal(t, uint64(0), george.Pending)
+	require.True(t, opening[george.Email].Add(decimal.RequireFromString("0.0003")).Equal(george.Balance))
+
+	// Transactions that overdraw an account should be rejected
+	overdraft := []TransactionFixture{transactions[0]}
+	overdraft[0].Amount = decimal.NewFromInt(1000000)
+	require.Error(t, ReconcileAccounts(accounts, overdraft))
+
+	// Transactions must belong to a known account
+	unknown := []TransactionFixture{transactions[0]}
+	unknown[0].Account = "nobody@example.com"
+	require.Error(t, ReconcileAccounts(accounts, unknown))
+}
@@ -0,0 +1,387 @@
+package db
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/trisacrypto/testnet/pkg/utils"
+	"github.com/trisacrypto/trisa/pkg/ivms101"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// Testnet P2PKH version byte; generated addresses start with 'm' or 'n'.
+const testnetP2PKH = 111
+
+// PolicyMix is a weighted originator and beneficiary policy pair that is assigned to
+// generated wallets in proportion to its weight.
+type PolicyMix struct {
+	Originator  PolicyType
+	Beneficiary PolicyType
+	Weight      int
+}
+
+// DefaultPolicyMix assigns the policy pairs used by the hand-written fixtures evenly.
+var DefaultPolicyMix = []PolicyMix{
+	{SendPartial, SyncRepair, 1},
+	{SendFull, SyncRequire, 1},
+	{SendFull, AsyncRepair, 1},
+	{SendError, AsyncReject, 1},
+}
+
+// ParsePolicyMix parses a comma separated list of originator:beneficiary=weight
+// policy pairs, e.g. "SendPartial:SyncRepair=3,SendError:AsyncReject=1". The weight is
+// optional and defaults to 1.
+func ParsePolicyMix(s string) (mix []PolicyMix, err error) {
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		p := PolicyMix{Weight: 1}
+		if pair, weight, ok := strings.Cut(item, "="); ok {
+			item = pair
+			if p.Weight, err = strconv.Atoi(weight); err != nil || p.Weight < 1 {
+				return nil, fmt.Errorf("invalid weight for policy pair %q", item)
+			}
+		}
+
+		originator, beneficiary, ok := strings.Cut(item, ":")
+		if !ok {
+			return nil, fmt.Errorf("policy pair %q must be originator:beneficiary", item)
+		}
+
+		p.Originator, p.Beneficiary = PolicyType(originator), PolicyType(beneficiary)
+		if !isValidOriginatorPolicy(p.Originator) {
+			return nil, fmt.Errorf("invalid originator policy: %s", p.Originator)
+		}
+		if !isValidBeneficiaryPolicy(p.Beneficiary) {
+			return nil, fmt.Errorf("invalid beneficiary policy: %s", p.Beneficiary)
+		}
+		mix = append(mix, p)
+	}
+
+	if len(mix) == 0 {
+		return nil, fmt.Errorf("no policy pairs specified in %q", s)
+	}
+	return mix, nil
+}
+
+// FindVASP returns the fixture ID (the 1-based position) of the VASP whose common
+// name or one of its DNS labels matches name, e.g. "bob" or "api.bob.vaspbot.com".
+func FindVASP(vasps []VASP, name string) (id uint, err error) {
+	for i, v := range vasps {
+		if v.Name == name {
+			return uint(i + 1), nil
+		}
+
+		for _, label := range strings.Split(v.Name, ".") {
+			if label == name {
+				return uint(i + 1), nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("could not find vasp %q in fixtures", name)
+}
+
+// Generator creates synthetic wallet and account fixtures with valid testnet addresses
+// and valid IVMS101 natural person identities. The output of a generator is entirely
+// determined by its seed so that large datasets can be reproduced.
+type Generator struct {
+	rng      *rand.Rand
+	policies []PolicyMix
+	total    int
+	emails   map[string]struct{}
+	wallets  map[string]struct{}
+}
+
+// NewGenerator creates a deterministic fixture generator. If no policies are
+// specified then the DefaultPolicyMix is used.
+func NewGenerator(seed int64, policies []PolicyMix) *Generator {
+	if len(policies) == 0 {
+		policies = DefaultPolicyMix
+	}
+
+	g := &Generator{
+		rng:      rand.New(rand.NewSource(seed)),
+		policies: policies,
+		emails:   make(map[string]struct{}),
+		wallets:  make(map[string]struct{}),
+	}
+
+	for _, p := range policies {
+		g.total += p.Weight
+	}
+	return g
+}
+
+// Reserve marks the wallet addresses and emails of existing wallets as used so that
+// generated wallets can be merged with them without collisions.
+func (g *Generator) Reserve(wallets []Wallet) {
+	for _, w := range wallets {
+		g.wallets[w.Address] = struct{}{}
+		g.emails[w.Email] = struct{}{}
+	}
+}
+
+// Wallets generates n wallets and their accounts for the VASP with the specified
+// fixture ID, using the domain for the account email addresses.
+func (g *Generator) Wallets(vaspID uint, domain string, n int) (wallets []Wallet, accounts []Account, err error) {
+	wallets = make([]Wallet, 0, n)
+	accounts = make([]Account, 0, n)
+
+	for i := 0; i < n; i++ {
+		var (
+			person *ivms101.Person
+			data   []byte
+		)
+
+		w := Wallet{
+			Address:    g.Address(),
+			ProviderID: vaspID,
+			VaspID:     vaspID,
+		}
+
+		policy := g.policy()
+		w.OriginatorPolicy, w.BeneficiaryPolicy = policy.Originator, policy.Beneficiary
+
+		person = g.Identity()
+		name := person.GetNaturalPerson().Name.NameIdentifiers[0]
+		w.Email = g.email(name.SecondaryIdentifier, name.PrimaryIdentifier, domain)
+
+		if data, err = MarshalIdentity(person); err != nil {
+			return nil, nil, err
+		}
+
+		a := Account{
+			Name:          fmt.Sprintf("%s %s", name.SecondaryIdentifier, name.PrimaryIdentifier),
+			Email:         w.Email,
+			WalletAddress: w.Address,
+			IVMS101:       string(data),
+			Balance:       decimal.New(int64(g.rng.Intn(495000)+5000), -2),
+			VaspID:        vaspID,
+		}
+
+		wallets = append(wallets, w)
+		accounts = append(accounts, a)
+	}
+
+	return wallets, accounts, nil
+}
+
+// Address returns a new, unique testnet P2PKH bitcoin address.
+func (g *Generator) Address() string {
+	hash := make([]byte, 20)
+	for {
+		g.rng.Read(hash)
+
+		// The hash is always 20 bytes so this cannot error
+		addr, _ := utils.NewA25(testnetP2PKH, hash)
+		address := string(addr.Encode())
+		if _, ok := g.wallets[address]; !ok {
+			g.wallets[address] = struct{}{}
+			return address
+		}
+	}
+}
+
+// Identity returns a new IVMS101 natural person with a name, home address, passport
+// and date and place of birth from one of the generator's countries.
+func (g *Generator) Identity() *ivms101.Person {
+	c := countries[g.rng.Intn(len(countries))]
+	town := c.towns[g.rng.Intn(len(c.towns))]
+
+	// Birth dates are relative to a fixed date so that output only depends on the seed
+	born := time.Date(1945, 1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, g.rng.Intn(365*60))
+
+	person := &ivms101.NaturalPerson{
+		Name: &ivms101.NaturalPersonName{
+			NameIdentifiers: []*ivms101.NaturalPersonNameId{
+				{
+					PrimaryIdentifier:   c.surnames[g.rng.Intn(len(c.surnames))],
+					SecondaryIdentifier: c.forenames[g.rng.Intn(len(c.forenames))],
+					NameIdentifierType:  ivms101.NaturalPersonLegal,
+				},
+			},
+		},
+		GeographicAddresses: []*ivms101.Address{
+			{
+				AddressType:    ivms101.AddressTypeHome,
+				StreetName:     c.streets[g.rng.Intn(len(c.streets))],
+				BuildingNumber: strconv.Itoa(g.rng.Intn(250) + 1),
+				PostCode:       g.postCode(c.postCode),
+				TownName:       town,
+				Country:        c.code,
+			},
+		},
+		NationalIdentification: &ivms101.NationalIdentification{
+			NationalIdentifier:     g.digits(9),
+			NationalIdentifierType: ivms101.NationalIdentifierCCPT,
+			CountryOfIssue:         c.code,
+		},
+		DateAndPlaceOfBirth: &ivms101.DateAndPlaceOfBirth{
+			DateOfBirth:  born.Format("2006-01-02"),
+			PlaceOfBirth: fmt.Sprintf("%s, %s", c.towns[g.rng.Intn(len(c.towns))], c.name),
+		},
+		CountryOfResidence: c.code,
+	}
+	return person.Person()
+}
+
+// MarshalIdentity serializes an IVMS101 person in the JSON format used by the fixtures.
+// The output is compacted since protojson does not guarantee stable whitespace.
+func MarshalIdentity(person *ivms101.Person) (_ []byte, err error) {
+	var data []byte
+	if data, err = (protojson.MarshalOptions{UseProtoNames: true}).Marshal(person); err != nil {
+		return nil, err
+	}
+
+	buf := &bytes.Buffer{}
+	if err = json.Compact(buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// policy selects a policy pair from the mix in proportion to the weights.
+func (g *Generator) policy() PolicyMix {
+	n := g.rng.Intn(g.total)
+	for _, p := range g.policies {
+		if n < p.Weight {
+			return p
+		}
+		n -= p.Weight
+	}
+	return g.policies[len(g.policies)-1]
+}
+
+// email returns a unique email address for the name at the domain.
+func (g *Generator) email(forename, surname, domain string) string {
+	local := emailReplacer.Replace(strings.ToLower(forename + "." + surname))
+
+	email := fmt.Sprintf("%s@%s", local, domain)
+	for i := 2; ; i++ {
+		if _, ok := g.emails[email]; !ok {
+			g.emails[email] = struct{}{}
+			return email
+		}
+		email = fmt.Sprintf("%s%d@%s", local, i, domain)
+	}
+}
+
+// postCode fills a post code format, replacing 9 with a digit and A with a letter.
+func (g *Generator) postCode(format string) string {
+	code := []byte(format)
+	for i, c := range code {
+		switch c {
+		case '9':
+			code[i] = byte('0' + g.rng.Intn(10))
+		case 'A':
+			code[i] = byte('A' + g.rng.Intn(26))
+		}
+	}
+	return string(code)
+}
+
+func (g *Generator) digits(n int) string {
+	return g.postCode(strings.Repeat("9", n))
+}
+
+// Strips spaces and transliterates the accented characters used in the country data
+// so that generated email addresses are plain ASCII.
+var emailReplacer = strings.NewReplacer(
+	" ", "", "'", "", "ä", "a", "ã", "a", "ç", "c", "é", "e", "è", "e", "ë", "e",
+	"ô", "o", "ö", "o", "ü", "u", "ß", "ss",
+)
+
+// country contains the data used to generate identities for residents of a country.
+type country struct {
+	code      string
+	name      string
+	postCode  string
+	forenames []string
+	surnames  []string
+	towns     []string
+	streets   []string
+}
+
+var countries = []country{
+	{
+		code:      "GB",
+		name:      "United Kingdom",
+		postCode:  "AA9 9AA",
+		forenames: []string{"Oliver", "Amelia", "Harry", "Isla", "George", "Ava", "Jack", "Emily", "Charlotte", "Thomas"},
+		surnames:  []string{"Smith", "Jones", "Taylor", "Brown", "Williams", "Wilson", "Davies", "Evans", "Thomas", "Roberts"},
+		towns:     []string{"London", "Manchester", "Leeds", "Bristol", "Oxford", "York", "Norwich", "Cardiff"},
+		streets:   []string{"High Street", "Station Road", "Church Lane", "Victoria Road", "Mill Lane", "Park Avenue"},
+	},
+	{
+		code:      "US",
+		name:      "United States",
+		postCode:  "99999",
+		forenames: []string{"James", "Mary", "Robert", "Patricia", "Michael", "Jennifer", "David", "Linda", "Emma", "Daniel"},
+		surnames:  []string{"Johnson", "Miller", "Garcia", "Rodriguez", "Martinez", "Anderson", "Jackson", "White", "Harris", "Clark"},
+		towns:     []string{"New York", "Chicago", "Houston", "Phoenix", "Denver", "Seattle", "Boston", "Austin"},
+		streets:   []string{"Main Street", "Oak Avenue", "Maple Drive", "Washington Street", "Lake Road", "Elm Street"},
+	},
+	{
+		code:      "DE",
+		name:      "Germany",
+		postCode:  "99999",
+		forenames: []string{"Lukas", "Anna", "Felix", "Lena", "Jonas", "Hannah", "Paul", "Marie", "Leon", "Sophie"},
+		surnames:  []string{"Müller", "Schmidt", "Schneider", "Fischer", "Weber", "Meyer", "Wagner", "Becker", "Hoffmann", "Koch"},
+		towns:     []string{"Berlin", "Hamburg", "München", "Köln", "Frankfurt", "Stuttgart", "Leipzig", "Dresden"},
+		streets:   []string{"Hauptstraße", "Schulstraße", "Gartenstraße", "Bahnhofstraße", "Dorfstraße", "Bergstraße"},
+	},
+	{
+		code:      "FR",
+		name:      "France",
+		postCode:  "99999",
+		forenames: []string{"Gabriel", "Louise", "Raphaël", "Jade", "Arthur", "Alice", "Louis", "Chloé", "Jules", "Léa"},
+		surnames:  []string{"Martin", "Bernard", "Dubois", "Durand", "Leroy", "Moreau", "Simon", "Laurent", "Lefebvre", "Michel"},
+		towns:     []string{"Paris", "Lyon", "Marseille", "Toulouse", "Nantes", "Bordeaux", "Lille", "Strasbourg"},
+		streets:   []string{"Rue de la Paix", "Rue Victor Hugo", "Avenue Jean Jaurès", "Rue Pasteur", "Boulevard Voltaire"},
+	},
+	{
+		code:      "SG",
+		name:      "Singapore",
+		postCode:  "999999",
+		forenames: []string{"Wei Ling", "Jun Jie", "Hui Min", "Zhi Hao", "Siti", "Muhammad", "Priya", "Arjun", "Mei Ling", "Kai"},
+		surnames:  []string{"Tan", "Lim", "Lee", "Ng", "Wong", "Goh", "Chua", "Koh", "Rahman", "Kumar"},
+		towns:     []string{"Singapore", "Jurong", "Tampines", "Woodlands", "Bedok", "Ang Mo Kio"},
+		streets:   []string{"Orchard Road", "Tampines Avenue", "Bukit Timah Road", "Serangoon Road", "Marine Parade Road"},
+	},
+	{
+		code:      "CA",
+		name:      "Canada",
+		postCode:  "A9A 9A9",
+		forenames: []string{"Liam", "Olivia", "Noah", "Charlotte", "William", "Sophia", "Benjamin", "Chloe", "Ethan", "Maya"},
+		surnames:  []string{"Tremblay", "Gagnon", "Roy", "Côté", "Bouchard", "Gauthier", "Morin", "Lavoie", "Fortin", "Campbell"},
+		towns:     []string{"Toronto", "Montréal", "Vancouver", "Calgary", "Ottawa", "Halifax", "Winnipeg", "Québec"},
+		streets:   []string{"King Street", "Queen Street", "Yonge Street", "Rue Sainte-Catherine", "Granville Street"},
+	},
+	{
+		code:      "AU",
+		name:      "Australia",
+		postCode:  "9999",
+		forenames: []string{"Jack", "Mia", "William", "Grace", "Lucas", "Zoe", "Henry", "Ruby", "Oscar", "Matilda"},
+		surnames:  []string{"Nguyen", "Kelly", "Ryan", "Walker", "King", "Young", "Mitchell", "Hughes", "Murphy", "Cooper"},
+		towns:     []string{"Sydney", "Melbourne", "Brisbane", "Perth", "Adelaide", "Hobart", "Darwin", "Canberra"},
+		streets:   []string{"George Street", "Collins Street", "Queen Street", "Hay Street", "Pitt Street", "Smith Street"},
+	},
+	{
+		code:      "BR",
+		name:      "Brazil",
+		postCode:  "99999-999",
+		forenames: []string{"Miguel", "Helena", "Arthur", "Alice", "Heitor", "Laura", "Bernardo", "Manuela", "Davi", "Valentina"},
+		surnames:  []string{"Silva", "Santos", "Oliveira", "Souza", "Rodrigues", "Ferreira", "Alves", "Pereira", "Lima", "Gomes"},
+		towns:     []string{"São Paulo", "Rio de Janeiro", "Brasília", "Salvador", "Fortaleza", "Curitiba", "Recife"},
+		streets:   []string{"Rua das Flores", "Avenida Paulista", "Rua Augusta", "Avenida Atlântica", "Rua da Consolação"},
+	},
+}
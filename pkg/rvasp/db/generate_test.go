@@ -0,0 +1,120 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trisacrypto/testnet/pkg/utils"
+)
+
+func TestGenerateWallets(t *testing.T) {
+	wallets, accounts, err := NewGenerator(42, nil).Wallets(1, "bobvasp.co.uk", 200)
+	require.NoError(t, err)
+	require.Len(t, wallets, 200)
+	require.Len(t, accounts, 200)
+
+	emails := make(map[string]struct{})
+	for i, wallet := range wallets {
+		// Wallets should have unique, valid testnet addresses
+		btc, err := utils.ParseBTCAddress(wallet.Address)
+		require.NoError(t, err, "generated wallet address %s is invalid", wallet.Address)
+		require.True(t, btc.IsTestnet(), "generated wallet address %s is not a testnet address", wallet.Address)
+
+		require.NotContains(t, emails, wallet.Email, "generated emails must be unique")
+		emails[wallet.Email] = struct{}{}
+
+		require.True(t, isValidOriginatorPolicy(wallet.OriginatorPolicy))
+		require.True(t, isValidBeneficiaryPolicy(wallet.BeneficiaryPolicy))
+		require.Equal(t, uint(1), wallet.ProviderID)
+
+		// Accounts should have a valid ivms101.NaturalPerson
+		account := accounts[i]
+		require.Equal(t, wallet.Address, account.WalletAddress)
+		require.Equal(t, wallet.Email, account.Email)
+		identity, err := account.LoadIdentity()
+		require.NoError(t, err, "could not load identity for generated account %s", account.Name)
+		require.NoError(t, identity.GetNaturalPerson().Validate(), "generated identity for %s is invalid", account.Name)
+	}
+
+	// The same seed should produce the same fixtures
+	other, otherAccounts, err := NewGenerator(42, nil).Wallets(1, "bobvasp.co.uk", 200)
+	require.NoError(t, err)
+	require.Equal(t, wallets, other)
+	for i := range accounts {
+		require.Equal(t, accounts[i].IVMS101, otherAccounts[i].IVMS101)
+		require.True(t, accounts[i].Balance.Equal(otherAccounts[i].Balance))
+	}
+
+	// A different seed should produce different fixtures
+	other, _, err = NewGenerator(43, nil).Wallets(1, "bobvasp.co.uk", 200)
+	require.NoError(t, err)
+	require.NotEqual(t, wallets, other)
+}
+
+func TestGeneratePolicyMix(t *testing.T) {
+	_, err := ParsePolicyMix("SendFull")
+	require.EqualError(t, err, `policy pair "SendFull" must be originator:beneficiary`)
+
+	_, err = ParsePolicyMix("SendFull:SendError")
+	require.EqualError(t, err, "invalid beneficiary policy: SendError")
+
+	_, err = ParsePolicyMix("SendFull:SyncRepair=0")
+	require.EqualError(t, err, `invalid weight for policy pair "SendFull:SyncRepair"`)
+
+	mix, err := ParsePolicyMix("SendFull:SyncRequire=3, SendError:AsyncReject")
+	require.NoError(t, err)
+	require.Equal(t, []PolicyMix{{SendFull, SyncRequire, 3}, {SendError, AsyncReject, 1}}, mix)
+
+	// Only the policies in the mix should be assigned
+	wallets, _, err := NewGenerator(7, mix[:1]).Wallets(2, "alicevasp.us", 20)
+	require.NoError(t, err)
+	for _, wallet := range wallets {
+		require.Equal(t, SendFull, wallet.OriginatorPolicy)
+		require.Equal(t, SyncRequire, wallet.BeneficiaryPolicy)
+	}
+}
+
+func TestWriteFixtures(t *testing.T) {
+	vasps, err := LoadVASPs(FIXTURES_PATH)
+	require.NoError(t, err)
+
+	id, err := FindVASP(vasps, "bob")
+	require.NoError(t, err)
+	require.Equal(t, uint(1), id)
+
+	id, err = FindVASP(vasps, "api.alice.vaspbot.com")
+	require.NoError(t, err)
+	require.Equal(t, uint(2), id)
+
+	_, err = FindVASP(vasps, "mallory")
+	require.Error(t, err)
+
+	wallets, accounts, err := NewGenerator(42, nil).Wallets(id, "alicevasp.us", 50)
+	require.NoError(t, err)
+
+	// Written fixtures should be readable by the fixture loaders
+	dir := t.TempDir()
+	require.NoError(t, WriteVASPs(dir, vasps))
+	require.NoError(t, WriteWallets(dir, wallets, accounts))
+
+	actualVASPs, err := LoadVASPs(dir)
+	require.NoError(t, err)
+	require.Len(t, actualVASPs, len(vasps))
+	for i, vasp := range vasps {
+		require.Equal(t, vasp.Name, actualVASPs[i].Name)
+		require.JSONEq(t, vasp.IVMS101, actualVASPs[i].IVMS101)
+	}
+
+	actualWallets, actualAccounts, err := LoadWallets(dir)
+	require.NoError(t, err)
+	require.Equal(t, wallets, actualWallets)
+	for i, account := range accounts {
+		require.Equal(t, account.Name, actualAccounts[i].Name)
+		require.Equal(t, account.Email, actualAccounts[i].Email)
+		require.JSONEq(t, account.IVMS101, actualAccounts[i].IVMS101)
+		require.True(t, account.Balance.Equal(actualAccounts[i].Balance))
+	}
+
+	// Wallets must have an account identity to be written
+	require.Error(t, WriteWallets(dir, wallets, accounts[1:]))
+}
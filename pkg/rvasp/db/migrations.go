@@ -0,0 +1,406 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/shopspring/decimal"
+	pb "github.com/trisacrypto/testnet/pkg/rvasp/pb/v1"
+	"gorm.io/gorm"
+)
+
+// ErrSchemaBehind is returned when the database has not been migrated to the latest
+// schema version that this version of the rVASP expects.
+var ErrSchemaBehind = errors.New("database schema is behind, run rvasp migrate up")
+
+// ErrSchemaAhead is returned when the database has been migrated by a newer version
+// of the rVASP than this one.
+var ErrSchemaAhead = errors.New("database schema is ahead of this version of the rvasp")
+
+// Migration is a versioned change to the database schema. Migrations are applied in
+// order of their version and each migration must be reversible. Migrations must not
+// depend on the models in this package since the models describe the latest schema;
+// instead they should define a snapshot of the tables they modify.
+type Migration struct {
+	Version uint
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error
+}
+
+// SchemaMigration records a migration that has been applied to the database.
+type SchemaMigration struct {
+	Version   uint      `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+// TableName explicitly defines the name of the table for the model
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// MigrationStatus describes whether a migration has been applied to the database.
+type MigrationStatus struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// Migrations returns the ordered list of migrations that define the schema.
+func Migrations() []Migration {
+	return migrations
+}
+
+// LatestVersion returns the schema version that the models in this package expect.
+func LatestVersion() uint {
+	if len(migrations) == 0 {
+		return 0
+	}
+	return migrations[len(migrations)-1].Version
+}
+
+// SchemaVersion returns the version of the latest migration applied to the database
+// or 0 if no migrations have been applied.
+func SchemaVersion(gdb *gorm.DB) (version uint, err error) {
+	var applied []SchemaMigration
+	if applied, err = appliedMigrations(gdb); err != nil {
+		return 0, err
+	}
+
+	if len(applied) == 0 {
+		return 0, nil
+	}
+	return applied[len(applied)-1].Version, nil
+}
+
+// CheckSchema returns an error if the database schema does not match the latest
+// version expected by the models in this package.
+func CheckSchema(gdb *gorm.DB) (err error) {
+	var version uint
+	if version, err = SchemaVersion(gdb); err != nil {
+		return err
+	}
+
+	latest := LatestVersion()
+	switch {
+	case version < latest:
+		return fmt.Errorf("%w: have version %d, expected version %d", ErrSchemaBehind, version, latest)
+	case version > latest:
+		return fmt.Errorf("%w: have version %d, expected version %d", ErrSchemaAhead, version, latest)
+	}
+	return nil
+}
+
+// Status returns the status of all known migrations in the database.
+func Status(gdb *gorm.DB) (status []MigrationStatus, err error) {
+	var applied []SchemaMigration
+	if applied, err = appliedMigrations(gdb); err != nil {
+		return nil, err
+	}
+
+	index := make(map[uint]SchemaMigration, len(applied))
+	for _, m := range applied {
+		index[m.Version] = m
+	}
+
+	status = make([]MigrationStatus, 0, len(migrations))
+	for _, m := range migrations {
+		record, ok := index[m.Version]
+		status = append(status, MigrationStatus{Migration: m, Applied: ok, AppliedAt: record.AppliedAt})
+	}
+	return status, nil
+}
+
+// MigrateUp applies all pending migrations up to and including the target version.
+// If target is 0, all pending migrations are applied. Each migration is applied in its
+// own transaction along with the record of its version.
+func MigrateUp(gdb *gorm.DB, target uint) (err error) {
+	if target == 0 {
+		target = LatestVersion()
+	}
+
+	var version uint
+	if version, err = SchemaVersion(gdb); err != nil {
+		return err
+	}
+
+	if version > LatestVersion() {
+		return fmt.Errorf("%w: have version %d, expected version %d", ErrSchemaAhead, version, LatestVersion())
+	}
+
+	for _, m := range migrations {
+		if m.Version <= version || m.Version > target {
+			continue
+		}
+
+		if err = gdb.Transaction(func(tx *gorm.DB) error {
+			if err := m.Up(tx); err != nil {
+				return err
+			}
+			return tx.Create(&SchemaMigration{Version: m.Version, Name: m.Name, AppliedAt: time.Now()}).Error
+		}); err != nil {
+			return fmt.Errorf("could not apply migration %d (%s): %w", m.Version, m.Name, err)
+		}
+		log.Info().Uint("version", m.Version).Str("name", m.Name).Msg("applied migration")
+	}
+	return nil
+}
+
+// MigrateDown rolls back the specified number of the most recently applied migrations.
+func MigrateDown(gdb *gorm.DB, steps int) (err error) {
+	var applied []SchemaMigration
+	if applied, err = appliedMigrations(gdb); err != nil {
+		return err
+	}
+
+	index := make(map[uint]Migration, len(migrations))
+	for _, m := range migrations {
+		index[m.Version] = m
+	}
+
+	for i := len(applied) - 1; i >= 0 && steps > 0; i, steps = i-1, steps-1 {
+		m, ok := index[applied[i].Version]
+		if !ok {
+			return fmt.Errorf("cannot roll back unknown migration %d (%s)", applied[i].Version, applied[i].Name)
+		}
+
+		if err = gdb.Transaction(func(tx *gorm.DB) error {
+			if err := m.Down(tx); err != nil {
+				return err
+			}
+			return tx.Delete(&SchemaMigration{Version: m.Version}).Error
+		}); err != nil {
+			return fmt.Errorf("could not roll back migration %d (%s): %w", m.Version, m.Name, err)
+		}
+		log.Info().Uint("version", m.Version).Str("name", m.Name).Msg("rolled back migration")
+	}
+	return nil
+}
+
+// appliedMigrations returns the migrations recorded in the database ordered by version,
+// creating the schema migrations table if it does not exist.
+func appliedMigrations(gdb *gorm.DB) (applied []SchemaMigration, err error) {
+	if !gdb.Migrator().HasTable(&SchemaMigration{}) {
+		if err = gdb.Migrator().CreateTable(&SchemaMigration{}); err != nil {
+			return nil, fmt.Errorf("could not create schema migrations table: %w", err)
+		}
+	}
+
+	if err = gdb.Order("version").Find(&applied).Error; err != nil {
+		return nil, fmt.Errorf("could not fetch schema migrations: %w", err)
+	}
+	return applied, nil
+}
+
+// migrations defines the schema of the rVASP database. New migrations must be appended
+// to the end of the list with the next version number; applied migrations must never
+// be modified.
+var migrations = []Migration{
+	{
+		Version: 1,
+		Name:    "create initial schema",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&v1VASP{}, &v1Wallet{}, &v1Account{}, &v1Transaction{}, &v1Identity{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&v1Transaction{}, &v1Identity{}, &v1Account{}, &v1Wallet{}, &v1VASP{})
+		},
+	},
+	{
+		Version: 2,
+		Name:    "create envelopes table",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&v2Envelope{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&v2Envelope{})
+		},
+	},
+	{
+		Version: 3,
+		Name:    "create state transitions table",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&v3StateTransition{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&v3StateTransition{})
+		},
+	},
+	{
+		Version: 4,
+		Name:    "create webhooks tables",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&v4Webhook{}, &v4WebhookDelivery{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&v4WebhookDelivery{}, &v4Webhook{})
+		},
+	},
+}
+
+// Snapshot of the schema created by the initial migration. Databases created with
+// AutoMigrate before versioned migrations were introduced have this schema, so the
+// migration is a no-op for them other than recording the version.
+type v1VASP struct {
+	gorm.Model
+	Name      string     `gorm:"uniqueIndex;size:255;not null"`
+	LegalName *string    `gorm:"column:legal_name;null"`
+	URL       *string    `gorm:"null"`
+	Country   *string    `gorm:"null"`
+	Endpoint  *string    `gorm:"null"`
+	PubKey    *string    `gorm:"null"`
+	NotAfter  *time.Time `gorm:"null"`
+	IVMS101   string     `gorm:"column:ivms101"`
+}
+
+func (v1VASP) TableName() string {
+	return "vasps"
+}
+
+type v1Wallet struct {
+	gorm.Model
+	Address           string     `gorm:"uniqueIndex"`
+	Email             string     `gorm:"uniqueIndex"`
+	OriginatorPolicy  PolicyType `gorm:"column:originator_policy"`
+	BeneficiaryPolicy PolicyType `gorm:"column:beneficiary_policy"`
+	ProviderID        uint       `gorm:"not null"`
+	Provider          v1VASP     `gorm:"foreignKey:ProviderID"`
+	VaspID            uint       `gorm:"not null"`
+	Vasp              v1VASP     `gorm:"foreignKey:VaspID"`
+}
+
+func (v1Wallet) TableName() string {
+	return "wallets"
+}
+
+type v1Account struct {
+	gorm.Model
+	Name          string          `gorm:"not null"`
+	Email         string          `gorm:"uniqueIndex;not null"`
+	WalletAddress string          `gorm:"uniqueIndex;not null;column:wallet_address"`
+	Wallet        v1Wallet        `gorm:"foreignKey:WalletAddress;references:Address"`
+	Balance       decimal.Decimal `gorm:"type:numeric(15,2);default:0.0"`
+	Completed     uint64          `gorm:"not null;default:0"`
+	Pending       uint64          `gorm:"not null;default:0"`
+	IVMS101       string          `gorm:"column:ivms101;not null"`
+	VaspID        uint            `gorm:"not null"`
+	Vasp          v1VASP          `gorm:"foreignKey:VaspID"`
+}
+
+func (v1Account) TableName() string {
+	return "accounts"
+}
+
+type v1Transaction struct {
+	gorm.Model
+	Envelope      string              `gorm:"not null"`
+	AccountID     uint                `gorm:"not null"`
+	Account       v1Account           `gorm:"foreignKey:AccountID"`
+	OriginatorID  uint                `gorm:"column:originator_id;not null"`
+	Originator    v1Identity          `gorm:"foreignKey:OriginatorID"`
+	BeneficiaryID uint                `gorm:"column:beneficiary_id;not null"`
+	Beneficiary   v1Identity          `gorm:"foreignKey:BeneficiaryID"`
+	Amount        decimal.Decimal     `gorm:"type:decimal(15,8)"`
+	AssetType     string              `gorm:"not null"`
+	Debit         bool                `gorm:"not null"`
+	State         pb.TransactionState `gorm:"not null;default:0"`
+	StateString   string              `gorm:"column:state_string;not null"`
+	Timestamp     time.Time           `gorm:"not null"`
+	NotBefore     time.Time           `gorm:"not null"`
+	NotAfter      time.Time           `gorm:"not null"`
+	Identity      string              `gorm:"not null"`
+	Transaction   string              `gorm:"not null"`
+	VaspID        uint                `gorm:"not null"`
+	Vasp          v1VASP              `gorm:"foreignKey:VaspID"`
+}
+
+func (v1Transaction) TableName() string {
+	return "transactions"
+}
+
+type v1Identity struct {
+	gorm.Model
+	WalletAddress string `gorm:"not null;column:wallet_address;index:wallet_index,unique"`
+	Email         string `gorm:"not null"`
+	Provider      string `gorm:"not null"`
+	VaspID        uint   `gorm:"not null;index:wallet_index,unique"`
+	Vasp          v1VASP `gorm:"foreignKey:VaspID"`
+}
+
+func (v1Identity) TableName() string {
+	return "identities"
+}
+
+// Snapshot of the recorded secure envelopes table created by the second migration.
+type v2Envelope struct {
+	gorm.Model
+	Envelope    string              `gorm:"not null;index"`
+	Direction   string              `gorm:"not null"`
+	Peer        string              `gorm:"not null"`
+	Timestamp   time.Time           `gorm:"not null"`
+	State       pb.TransactionState `gorm:"not null;default:0"`
+	StateString string              `gorm:"column:state_string;not null"`
+	Error       string              `gorm:"not null;default:''"`
+	Data        []byte              `gorm:"not null"`
+	VaspID      uint                `gorm:"not null"`
+	Vasp        v1VASP              `gorm:"foreignKey:VaspID"`
+}
+
+func (v2Envelope) TableName() string {
+	return "envelopes"
+}
+
+// Snapshot of the transaction state transitions table created by the third migration.
+type v3StateTransition struct {
+	gorm.Model
+	TransactionID  uint                `gorm:"not null;index"`
+	Transaction    v1Transaction       `gorm:"foreignKey:TransactionID"`
+	Previous       pb.TransactionState `gorm:"not null;default:0"`
+	PreviousString string              `gorm:"column:previous_string;not null"`
+	State          pb.TransactionState `gorm:"not null;default:0"`
+	StateString    string              `gorm:"column:state_string;not null"`
+	Actor          string              `gorm:"not null"`
+	Reason         string              `gorm:"not null;default:''"`
+	Timestamp      time.Time           `gorm:"not null"`
+}
+
+func (v3StateTransition) TableName() string {
+	return "state_transitions"
+}
+
+// Snapshot of the webhooks and delivery log tables created by the fourth migration.
+type v4Webhook struct {
+	gorm.Model
+	URL       string     `gorm:"not null"`
+	Secret    string     `gorm:"not null"`
+	AccountID *uint      `gorm:"null;index"`
+	Account   *v1Account `gorm:"foreignKey:AccountID"`
+	VaspID    uint       `gorm:"not null"`
+	Vasp      v1VASP     `gorm:"foreignKey:VaspID"`
+}
+
+func (v4Webhook) TableName() string {
+	return "webhooks"
+}
+
+type v4WebhookDelivery struct {
+	gorm.Model
+	WebhookID   uint                `gorm:"not null;index"`
+	Webhook     v4Webhook           `gorm:"foreignKey:WebhookID"`
+	Event       string              `gorm:"not null;index"`
+	Envelope    string              `gorm:"not null"`
+	State       pb.TransactionState `gorm:"not null;default:0"`
+	StateString string              `gorm:"column:state_string;not null"`
+	Attempt     int                 `gorm:"not null"`
+	StatusCode  int                 `gorm:"not null;default:0"`
+	Error       string              `gorm:"not null;default:''"`
+	Delivered   bool                `gorm:"not null;default:false"`
+	Timestamp   time.Time           `gorm:"not null"`
+}
+
+func (v4WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
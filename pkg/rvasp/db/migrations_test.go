@@ -0,0 +1,69 @@
+package db_test
+
+import (
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/require"
+	"github.com/trisacrypto/testnet/pkg/rvasp/db"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestMigrations(t *testing.T) {
+	gdb, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Discard})
+	require.NoError(t, err)
+
+	// Migration versions must be sequential
+	for i, m := range db.Migrations() {
+		require.Equal(t, uint(i+1), m.Version, "migration %q has the wrong version", m.Name)
+		require.NotNil(t, m.Up)
+		require.NotNil(t, m.Down)
+	}
+
+	// An empty database is behind
+	require.ErrorIs(t, db.CheckSchema(gdb), db.ErrSchemaBehind)
+
+	status, err := db.Status(gdb)
+	require.NoError(t, err)
+	require.Len(t, status, len(db.Migrations()))
+	for _, m := range status {
+		require.False(t, m.Applied)
+	}
+
+	// Migrating up should create the tables and record the latest version
+	require.NoError(t, db.MigrateUp(gdb, 0))
+	require.NoError(t, db.CheckSchema(gdb))
+	for _, model := range []interface{}{&db.VASP{}, &db.Wallet{}, &db.Account{}, &db.Transaction{}, &db.Identity{}, &db.Envelope{}, &db.StateTransition{}, &db.Webhook{}, &db.WebhookDelivery{}} {
+		require.True(t, gdb.Migrator().HasTable(model))
+	}
+
+	version, err := db.SchemaVersion(gdb)
+	require.NoError(t, err)
+	require.Equal(t, db.LatestVersion(), version)
+
+	status, err = db.Status(gdb)
+	require.NoError(t, err)
+	for _, m := range status {
+		require.True(t, m.Applied)
+		require.False(t, m.AppliedAt.IsZero())
+	}
+
+	// Migrating up again should be a no-op
+	require.NoError(t, db.MigrateUp(gdb, 0))
+
+	// Migrating all the way down should drop the tables
+	require.NoError(t, db.MigrateDown(gdb, len(db.Migrations())))
+	require.ErrorIs(t, db.CheckSchema(gdb), db.ErrSchemaBehind)
+	require.False(t, gdb.Migrator().HasTable(&db.VASP{}))
+	require.False(t, gdb.Migrator().HasTable(&db.Envelope{}))
+	require.False(t, gdb.Migrator().HasTable(&db.StateTransition{}))
+	require.False(t, gdb.Migrator().HasTable(&db.Webhook{}))
+
+	// A database migrated by a newer rVASP is ahead
+	require.NoError(t, db.MigrateUp(gdb, 0))
+	require.NoError(t, gdb.Create(&db.SchemaMigration{Version: db.LatestVersion() + 1, Name: "from the future"}).Error)
+	require.ErrorIs(t, db.CheckSchema(gdb), db.ErrSchemaAhead)
+	require.ErrorIs(t, db.MigrateUp(gdb, 0), db.ErrSchemaAhead)
+	require.Error(t, db.MigrateDown(gdb, 1), "unknown migrations cannot be rolled back")
+}
@@ -0,0 +1,82 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+
+	pb "github.com/trisacrypto/testnet/pkg/rvasp/pb/v1"
+)
+
+// ErrInvalidTransition is returned when a transaction cannot move from its current
+// state to the requested state.
+var ErrInvalidTransition = errors.New("invalid transaction state transition")
+
+// transitions describes the legal state transitions of a transaction in the sync and
+// async TRISA flows, excluding the FAILED and REJECTED states which any transaction
+// that has not yet reached a final state can move to.
+//
+// In the sync flow the originator completes the transaction as soon as the beneficiary
+// replies. In the async flow the originator moves to PENDING_RECEIVED when the
+// beneficiary replies with a pending message, then to ACCEPTED once the transfer has
+// been acknowledged and to COMPLETED once the beneficiary returns the transaction. The
+// beneficiary moves to PENDING_SENT after replying with a pending message, then to
+// AWAITING_FULL_TRANSFER, PENDING_ACKNOWLEDGED and COMPLETED as the handshake
+// continues. Transactions which are still pending when the reply window closes expire.
+var transitions = map[pb.TransactionState][]pb.TransactionState{
+	pb.TransactionState_INVALID: {
+		pb.TransactionState_AWAITING_REPLY,
+	},
+	pb.TransactionState_AWAITING_REPLY: {
+		pb.TransactionState_COMPLETED,
+		pb.TransactionState_PENDING_RECEIVED,
+		pb.TransactionState_PENDING_SENT,
+	},
+	pb.TransactionState_PENDING_RECEIVED: {
+		pb.TransactionState_ACCEPTED,
+		pb.TransactionState_EXPIRED,
+	},
+	pb.TransactionState_ACCEPTED: {
+		pb.TransactionState_COMPLETED,
+	},
+	pb.TransactionState_PENDING_SENT: {
+		pb.TransactionState_AWAITING_FULL_TRANSFER,
+		pb.TransactionState_EXPIRED,
+	},
+	pb.TransactionState_AWAITING_FULL_TRANSFER: {
+		pb.TransactionState_PENDING_ACKNOWLEDGED,
+	},
+	pb.TransactionState_PENDING_ACKNOWLEDGED: {
+		pb.TransactionState_COMPLETED,
+		pb.TransactionState_EXPIRED,
+	},
+}
+
+// Final returns true if a transaction in the state cannot move to any other state.
+func Final(state pb.TransactionState) bool {
+	switch state {
+	case pb.TransactionState_FAILED, pb.TransactionState_EXPIRED, pb.TransactionState_REJECTED, pb.TransactionState_COMPLETED:
+		return true
+	default:
+		return false
+	}
+}
+
+// ValidateTransition returns an ErrInvalidTransition error if a transaction cannot
+// move from the first state to the second state.
+func ValidateTransition(from, to pb.TransactionState) error {
+	if Final(from) {
+		return fmt.Errorf("%w from %s to %s: transaction is already %s", ErrInvalidTransition, from, to, from)
+	}
+
+	// Transactions which have been created can always be failed or rejected
+	if from != pb.TransactionState_INVALID && (to == pb.TransactionState_FAILED || to == pb.TransactionState_REJECTED) {
+		return nil
+	}
+
+	for _, state := range transitions[from] {
+		if state == to {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w from %s to %s", ErrInvalidTransition, from, to)
+}
@@ -0,0 +1,81 @@
+package db_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trisacrypto/testnet/pkg/rvasp/db"
+	pb "github.com/trisacrypto/testnet/pkg/rvasp/pb/v1"
+)
+
+func TestValidateTransition(t *testing.T) {
+	var (
+		invalid     = pb.TransactionState_INVALID
+		awaiting    = pb.TransactionState_AWAITING_REPLY
+		sent        = pb.TransactionState_PENDING_SENT
+		full        = pb.TransactionState_AWAITING_FULL_TRANSFER
+		received    = pb.TransactionState_PENDING_RECEIVED
+		acknowledge = pb.TransactionState_PENDING_ACKNOWLEDGED
+		accepted    = pb.TransactionState_ACCEPTED
+		failed      = pb.TransactionState_FAILED
+		expired     = pb.TransactionState_EXPIRED
+		rejected    = pb.TransactionState_REJECTED
+		completed   = pb.TransactionState_COMPLETED
+	)
+
+	// The legal transitions of the state machine, all other transitions are illegal
+	legal := map[pb.TransactionState][]pb.TransactionState{
+		invalid:     {awaiting},
+		awaiting:    {completed, received, sent, failed, rejected},
+		received:    {accepted, expired, failed, rejected},
+		accepted:    {completed, failed, rejected},
+		sent:        {full, expired, failed, rejected},
+		full:        {acknowledge, failed, rejected},
+		acknowledge: {completed, expired, failed, rejected},
+		failed:      {},
+		expired:     {},
+		rejected:    {},
+		completed:   {},
+	}
+
+	for from, states := range legal {
+		require.Equal(t, len(states) == 0, db.Final(from), "%s should be final if it has no transitions", from)
+
+		for to := range pb.TransactionState_name {
+			to := pb.TransactionState(to)
+			err := db.ValidateTransition(from, to)
+
+			isLegal := false
+			for _, state := range states {
+				if state == to {
+					isLegal = true
+					break
+				}
+			}
+
+			if isLegal {
+				require.NoError(t, err, "expected %s to %s to be legal", from, to)
+			} else {
+				require.ErrorIs(t, err, db.ErrInvalidTransition, "expected %s to %s to be illegal", from, to)
+			}
+		}
+	}
+}
+
+func TestSetState(t *testing.T) {
+	xfer := &db.Transaction{State: pb.TransactionState_AWAITING_REPLY}
+
+	// Legal transitions update the state and record the transition
+	require.NoError(t, xfer.SetState(pb.TransactionState_PENDING_RECEIVED, db.ActorPeer, "pending"))
+	require.NoError(t, xfer.SetState(pb.TransactionState_FAILED, db.ActorAsync, "could not send transaction"))
+	require.Equal(t, pb.TransactionState_FAILED, xfer.State)
+	require.Equal(t, pb.TransactionState_FAILED.String(), xfer.StateString)
+	require.Len(t, xfer.History, 2)
+
+	// Illegal transitions leave the transaction unmodified
+	err := xfer.SetState(pb.TransactionState_COMPLETED, db.ActorIntegration, "completed")
+	require.ErrorIs(t, err, db.ErrInvalidTransition)
+	require.EqualError(t, err, "invalid transaction state transition from FAILED to COMPLETED: transaction is already FAILED")
+	require.Equal(t, pb.TransactionState_FAILED, xfer.State)
+	require.Len(t, xfer.History, 2)
+}
@@ -0,0 +1,370 @@
+package rvasp
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/trisacrypto/testnet/pkg/rvasp/jsonpb"
+	pb "github.com/trisacrypto/testnet/pkg/rvasp/pb/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Route maps a REST endpoint of the HTTP gateway onto a TRISAIntegration RPC. Path
+// parameters in braces and query parameters are bound to the fields of the request
+// message with the same protobuf name. If the route has a body, the request body is the
+// JSON encoded request message; parameters override the fields of the body.
+type Route struct {
+	Method  string
+	Path    string
+	RPC     string
+	Body    bool
+	Summary string
+}
+
+// Routes of the REST API of the TRISAIntegration service.
+var Routes = []Route{
+	{http.MethodPost, "/v1/transfers", "Transfer", true, "Send a transfer to the beneficiary using the TRISA protocol"},
+	{http.MethodGet, "/v1/accounts/{account}", "AccountStatus", false, "Get the status and transactions of an account"},
+	{http.MethodGet, "/v1/transactions/{envelope_id}/history", "TransactionHistory", false, "Get the state transition history of a transaction"},
+	{http.MethodPost, "/v1/webhooks", "RegisterWebhook", true, "Register a webhook for transaction state changes"},
+	{http.MethodGet, "/v1/webhooks", "ListWebhooks", false, "List the registered webhooks"},
+	{http.MethodDelete, "/v1/webhooks/{id}", "DeleteWebhook", false, "Delete a webhook"},
+	{http.MethodGet, "/v1/webhooks/deliveries", "WebhookDeliveries", false, "List the webhook delivery attempts"},
+	{http.MethodGet, "/v1/status", "Status", false, "Get the status of the rVASP"},
+}
+
+// Paths served by the HTTP gateway in addition to the REST API.
+const (
+	LiveUpdatesPath = "/v1/liveupdates"
+	OpenAPIPath     = "/v1/openapi.json"
+)
+
+// maxRequestBody is the maximum size in bytes of the JSON body of a REST API request
+// or of a command sent to the WebSocket gateway.
+const maxRequestBody = 1 << 20
+
+// GatewayHandler returns the HTTP gateway of the rVASP, which serves the REST API of
+// the TRISAIntegration service, its OpenAPI document and the WebSocket gateway to the
+// LiveUpdates stream.
+func (s *Server) GatewayHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle(LiveUpdatesPath, s.LiveUpdatesHandler())
+	mux.Handle(OpenAPIPath, s.cors(http.HandlerFunc(s.serveOpenAPI)))
+	mux.Handle("/v1/", s.cors(http.HandlerFunc(s.serveREST)))
+	return mux
+}
+
+// serveGateway serves the HTTP gateway on the gateway bind address if it is configured.
+func (s *Server) serveGateway() (err error) {
+	if s.conf.Gateway.BindAddr == "" {
+		return nil
+	}
+
+	var sock net.Listener
+	if sock, err = net.Listen("tcp", s.conf.Gateway.BindAddr); err != nil {
+		return fmt.Errorf("could not listen on %q", s.conf.Gateway.BindAddr)
+	}
+
+	s.gateway = &http.Server{Handler: s.GatewayHandler(), ReadHeaderTimeout: 10 * time.Second}
+	log.Info().Str("listen", s.conf.Gateway.BindAddr).Bool("tls", s.conf.Auth.TLS()).Msg("http gateway started")
+	go func() {
+		var err error
+		if s.conf.Auth.TLS() {
+			// The gateway is served with the same certificate as the integration API
+			err = s.gateway.ServeTLS(sock, s.conf.Auth.TLSCertPath, s.conf.Auth.TLSKeyPath)
+		} else {
+			err = s.gateway.Serve(sock)
+		}
+
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.echan <- err
+		}
+	}()
+	return nil
+}
+
+// serveREST dispatches a REST request to the TRISAIntegration RPC of the matching route
+// through the same interceptor as gRPC requests, and writes the JSON encoded reply.
+func (s *Server) serveREST(w http.ResponseWriter, r *http.Request) {
+	var (
+		route  *Route
+		params map[string]string
+		allow  []string
+	)
+
+	for i := range Routes {
+		if p, ok := matchPath(Routes[i].Path, r.URL.Path); ok {
+			if Routes[i].Method == r.Method {
+				route, params = &Routes[i], p
+				break
+			}
+			allow = append(allow, Routes[i].Method)
+		}
+	}
+
+	if route == nil {
+		if len(allow) > 0 {
+			w.Header().Set("Allow", strings.Join(allow, ", "))
+			writeError(w, http.StatusMethodNotAllowed, status.Errorf(codes.Unimplemented, "method %s not allowed", r.Method))
+			return
+		}
+		writeError(w, http.StatusNotFound, status.Errorf(codes.NotFound, "no route for %s", r.URL.Path))
+		return
+	}
+
+	var method *grpc.MethodDesc
+	for i := range pb.TRISAIntegration_ServiceDesc.Methods {
+		if pb.TRISAIntegration_ServiceDesc.Methods[i].MethodName == route.RPC {
+			method = &pb.TRISAIntegration_ServiceDesc.Methods[i]
+			break
+		}
+	}
+
+	if method == nil {
+		writeError(w, http.StatusNotImplemented, status.Errorf(codes.Unimplemented, "unknown rpc %s", route.RPC))
+		return
+	}
+
+	// The generated handler creates the request message and decodes it with dec
+	dec := func(in interface{}) (err error) {
+		msg := in.(proto.Message)
+		if route.Body {
+			var body []byte
+			if body, err = io.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestBody)); err != nil {
+				var tooLarge *http.MaxBytesError
+				if errors.As(err, &tooLarge) {
+					return status.Errorf(codes.InvalidArgument, "request body is larger than %d bytes", tooLarge.Limit)
+				}
+				return status.Errorf(codes.InvalidArgument, "could not read request body: %s", err)
+			}
+
+			if len(body) > 0 {
+				if err = jsonpb.Unmarshal(body, msg); err != nil {
+					return status.Errorf(codes.InvalidArgument, "could not parse request body: %s", err)
+				}
+			}
+		}
+
+		for name, values := range r.URL.Query() {
+			if err = bindField(msg, name, values[len(values)-1]); err != nil {
+				return status.Error(codes.InvalidArgument, err.Error())
+			}
+		}
+
+		for name, value := range params {
+			if err = bindField(msg, name, value); err != nil {
+				return status.Error(codes.InvalidArgument, err.Error())
+			}
+		}
+		return nil
+	}
+
+	// Pass the credentials of the request to the interceptors as gRPC metadata
+	md := metadata.MD{}
+	if auth := r.Header.Values("Authorization"); len(auth) > 0 {
+		md.Set("authorization", auth...)
+	}
+	if keys := r.Header.Values("X-Api-Key"); len(keys) > 0 {
+		md.Set("x-api-key", keys...)
+	}
+
+	ctx := metadata.NewIncomingContext(r.Context(), md)
+	out, err := method.Handler(s, ctx, dec, s.interceptor)
+	if err != nil {
+		writeError(w, HTTPStatusFromCode(status.Code(err)), err)
+		return
+	}
+
+	var data []byte
+	if data, err = jsonpb.Marshal(out.(proto.Message)); err != nil {
+		log.Error().Err(err).Str("rpc", route.RPC).Msg("could not marshal reply")
+		writeError(w, http.StatusInternalServerError, status.Error(codes.Internal, "could not marshal reply"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+// cors allows browsers to call the REST API from the allowed origins of the gateway.
+func (s *Server) cors(next http.Handler) http.Handler {
+	allowed := checkOrigin(s.conf.Gateway.Origins)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin != "" && allowed(r) {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Add("Vary", "Origin")
+		}
+
+		// Respond to preflight requests without calling the API
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			if origin == "" || !allowed(r) {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, DELETE")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Api-Key")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// matchPath returns the path parameters if the path matches the route pattern.
+func matchPath(pattern, path string) (params map[string]string, ok bool) {
+	parts := strings.Split(strings.Trim(pattern, "/"), "/")
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) != len(segments) {
+		return nil, false
+	}
+
+	params = make(map[string]string)
+	for i, part := range parts {
+		if strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}") {
+			if segments[i] == "" {
+				return nil, false
+			}
+			params[strings.Trim(part, "{}")] = segments[i]
+			continue
+		}
+
+		if part != segments[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// bindField sets the scalar field of the message with the protobuf name to the value
+// parsed from a path or query parameter.
+func bindField(msg proto.Message, name, value string) (err error) {
+	m := msg.ProtoReflect()
+	fd := m.Descriptor().Fields().ByName(protoreflect.Name(name))
+	if fd == nil {
+		fd = m.Descriptor().Fields().ByJSONName(name)
+	}
+
+	if fd == nil {
+		return fmt.Errorf("unknown parameter %q", name)
+	}
+
+	if fd.IsList() || fd.IsMap() {
+		return fmt.Errorf("parameter %q cannot be repeated", name)
+	}
+
+	var v protoreflect.Value
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		v = protoreflect.ValueOfString(value)
+	case protoreflect.BoolKind:
+		var b bool
+		if b, err = strconv.ParseBool(value); err == nil {
+			v = protoreflect.ValueOfBool(b)
+		}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		var i int64
+		if i, err = strconv.ParseInt(value, 10, 32); err == nil {
+			v = protoreflect.ValueOfInt32(int32(i))
+		}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		var i int64
+		if i, err = strconv.ParseInt(value, 10, 64); err == nil {
+			v = protoreflect.ValueOfInt64(i)
+		}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		var u uint64
+		if u, err = strconv.ParseUint(value, 10, 32); err == nil {
+			v = protoreflect.ValueOfUint32(uint32(u))
+		}
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		var u uint64
+		if u, err = strconv.ParseUint(value, 10, 64); err == nil {
+			v = protoreflect.ValueOfUint64(u)
+		}
+	case protoreflect.FloatKind:
+		var f float64
+		if f, err = strconv.ParseFloat(value, 32); err == nil {
+			v = protoreflect.ValueOfFloat32(float32(f))
+		}
+	case protoreflect.DoubleKind:
+		var f float64
+		if f, err = strconv.ParseFloat(value, 64); err == nil {
+			v = protoreflect.ValueOfFloat64(f)
+		}
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByName(protoreflect.Name(value)); ev != nil {
+			v = protoreflect.ValueOfEnum(ev.Number())
+		} else {
+			var i int64
+			if i, err = strconv.ParseInt(value, 10, 32); err == nil {
+				v = protoreflect.ValueOfEnum(protoreflect.EnumNumber(i))
+			}
+		}
+	default:
+		return fmt.Errorf("parameter %q cannot be set from a string", name)
+	}
+
+	if err != nil {
+		return fmt.Errorf("could not parse parameter %q: %s", name, err)
+	}
+
+	m.Set(fd, v)
+	return nil
+}
+
+// writeError writes the gRPC status of the error as JSON with the HTTP status code.
+func writeError(w http.ResponseWriter, code int, err error) {
+	data, merr := jsonpb.Marshal(status.Convert(err).Proto())
+	if merr != nil {
+		http.Error(w, err.Error(), code)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	w.Write(data)
+}
+
+// HTTPStatusFromCode returns the HTTP status code that corresponds to the gRPC code.
+func HTTPStatusFromCode(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return 499
+	case codes.InvalidArgument, codes.OutOfRange, codes.FailedPrecondition:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,151 @@
+package rvasp_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trisacrypto/testnet/pkg/rvasp"
+	"github.com/trisacrypto/testnet/pkg/rvasp/harness"
+	"github.com/trisacrypto/testnet/pkg/rvasp/jsonpb"
+	pb "github.com/trisacrypto/testnet/pkg/rvasp/pb/v1"
+	"google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestGateway(t *testing.T) {
+	h := harness.New(t, "alice", "bob")
+	alice := h.VASP("alice")
+
+	srv := httptest.NewServer(alice.Server.GatewayHandler())
+	defer srv.Close()
+
+	// do sends the request and unmarshals the JSON reply into out, returning the status
+	do := func(method, path, body string, out proto.Message) int {
+		req, err := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+		require.NoError(t, err)
+
+		rep, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer rep.Body.Close()
+
+		data, err := io.ReadAll(rep.Body)
+		require.NoError(t, err)
+		if out != nil {
+			require.NoError(t, jsonpb.Unmarshal(data, out), string(data))
+		}
+		return rep.StatusCode
+	}
+
+	serverStatus := &pb.ServerStatus{}
+	require.Equal(t, http.StatusOK, do(http.MethodGet, "/v1/status", "", serverStatus))
+	require.Equal(t, pb.ServerStatus_ONLINE, serverStatus.Status)
+
+	account := &pb.AccountReply{}
+	require.Equal(t, http.StatusOK, do(http.MethodGet, "/v1/accounts/mary@alicevasp.us?no_transactions=true", "", account))
+	require.Equal(t, "mary@alicevasp.us", account.Email)
+	require.Empty(t, account.Transactions)
+
+	transfer := &pb.TransferReply{}
+	require.Equal(t, http.StatusOK, do(http.MethodPost, "/v1/transfers", `{"account": "mary@alicevasp.us", "beneficiary": "robert@bobvasp.co.uk", "amount": 0.25, "asset_type": "Bitcoin"}`, transfer))
+	require.Nil(t, transfer.Error)
+	require.Equal(t, pb.TransactionState_COMPLETED, transfer.Transaction.State)
+
+	history := &pb.TransactionHistoryReply{}
+	require.Equal(t, http.StatusOK, do(http.MethodGet, "/v1/transactions/"+transfer.Transaction.EnvelopeId+"/history", "", history))
+	require.Equal(t, transfer.Transaction.EnvelopeId, history.Transaction.EnvelopeId)
+	require.NotEmpty(t, history.Transaction.History)
+
+	webhook := &pb.Webhook{}
+	require.Equal(t, http.StatusOK, do(http.MethodPost, "/v1/webhooks", `{"url": "https://example.com/hook"}`, webhook))
+	require.NotZero(t, webhook.Id)
+
+	webhooks := &pb.WebhookList{}
+	require.Equal(t, http.StatusOK, do(http.MethodGet, "/v1/webhooks", "", webhooks))
+	require.Len(t, webhooks.Webhooks, 1)
+
+	deliveries := &pb.WebhookDeliveriesReply{}
+	require.Equal(t, http.StatusOK, do(http.MethodGet, "/v1/webhooks/deliveries?envelope_id=unknown", "", deliveries))
+	require.Empty(t, deliveries.Deliveries)
+
+	require.Equal(t, http.StatusOK, do(http.MethodDelete, "/v1/webhooks/"+strconv.FormatUint(webhook.Id, 10), "", &pb.Empty{}))
+
+	// gRPC status codes are translated to HTTP status codes
+	testCases := []struct {
+		method string
+		path   string
+		body   string
+		status int
+		code   codes.Code
+	}{
+		{http.MethodGet, "/v1/accounts/nobody@example.com", "", http.StatusNotFound, codes.NotFound},
+		{http.MethodDelete, "/v1/webhooks/" + strconv.FormatUint(webhook.Id, 10), "", http.StatusNotFound, codes.NotFound},
+		{http.MethodPost, "/v1/webhooks", `{"url": "ftp://example.com"}`, http.StatusBadRequest, codes.InvalidArgument},
+		{http.MethodPost, "/v1/transfers", `{"amount": "lots"}`, http.StatusBadRequest, codes.InvalidArgument},
+		{http.MethodPost, "/v1/transfers", `{"account": "` + strings.Repeat("a", 1<<20) + `"}`, http.StatusBadRequest, codes.InvalidArgument},
+		{http.MethodGet, "/v1/accounts/mary@alicevasp.us?bogus=true", "", http.StatusBadRequest, codes.InvalidArgument},
+		{http.MethodGet, "/v1/accounts/mary@alicevasp.us?no_transactions=maybe", "", http.StatusBadRequest, codes.InvalidArgument},
+		{http.MethodPut, "/v1/status", "", http.StatusMethodNotAllowed, codes.Unimplemented},
+		{http.MethodGet, "/v1/unknown", "", http.StatusNotFound, codes.NotFound},
+	}
+
+	for _, tc := range testCases {
+		rep := &status.Status{}
+		require.Equal(t, tc.status, do(tc.method, tc.path, tc.body, rep), "%s %s", tc.method, tc.path)
+		require.Equal(t, int32(tc.code), rep.Code, "%s %s", tc.method, tc.path)
+		require.NotEmpty(t, rep.Message)
+	}
+
+	// Preflight requests are only allowed from the same host if no origins are configured
+	req, err := http.NewRequest(http.MethodOptions, srv.URL+"/v1/transfers", nil)
+	require.NoError(t, err)
+	req.Header.Set("Origin", "https://evil.example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	rep, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	rep.Body.Close()
+	require.Equal(t, http.StatusForbidden, rep.StatusCode)
+}
+
+func TestOpenAPI(t *testing.T) {
+	h := harness.New(t, "alice")
+	srv := httptest.NewServer(h.VASP("alice").Server.GatewayHandler())
+	defer srv.Close()
+
+	rep, err := http.Get(srv.URL + rvasp.OpenAPIPath)
+	require.NoError(t, err)
+	defer rep.Body.Close()
+	require.Equal(t, http.StatusOK, rep.StatusCode)
+
+	var doc struct {
+		OpenAPI    string                                       `json:"openapi"`
+		Paths      map[string]map[string]map[string]interface{} `json:"paths"`
+		Components struct {
+			Schemas map[string]map[string]interface{} `json:"schemas"`
+		} `json:"components"`
+	}
+	require.NoError(t, json.NewDecoder(rep.Body).Decode(&doc))
+	require.Equal(t, "3.0.3", doc.OpenAPI)
+
+	// Every route is documented with schemas for its request and reply
+	for _, route := range rvasp.Routes {
+		op, ok := doc.Paths[route.Path][strings.ToLower(route.Method)]
+		require.True(t, ok, "missing %s %s", route.Method, route.Path)
+		require.Equal(t, route.RPC, op["operationId"])
+		_, ok = op["requestBody"]
+		require.Equal(t, route.Body, ok)
+	}
+
+	for _, name := range []string{"TransferRequest", "TransferReply", "Transaction", "TransactionState", "AccountReply", "ServerStatus", "Status"} {
+		require.Contains(t, doc.Components.Schemas, name)
+	}
+
+	params := doc.Paths["/v1/accounts/{account}"]["get"]["parameters"].([]interface{})
+	require.Equal(t, map[string]interface{}{"name": "account", "in": "path", "required": true, "schema": map[string]interface{}{"type": "string"}}, params[0])
+}
@@ -0,0 +1,293 @@
+/*
+Package harness starts multiple rVASPs in memory for end-to-end tests. Each rVASP is a
+full server with its own certificates, TRISA service, and update manager, all of the
+rVASPs share a SQLite database that is reset from the rVASP fixtures, and the servers
+communicate over bufconn listeners rather than the network. The peers of each rVASP
+are wired to the other rVASPs so that no directory service is required.
+
+	h := harness.New(t, "alice", "bob")
+	reply, err := h.VASP("alice").Client.Transfer(ctx, &pb.TransferRequest{...})
+	h.HandleAsync()
+*/
+package harness
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/trisacrypto/testnet/pkg/rvasp"
+	"github.com/trisacrypto/testnet/pkg/rvasp/bufconn"
+	"github.com/trisacrypto/testnet/pkg/rvasp/config"
+	"github.com/trisacrypto/testnet/pkg/rvasp/db"
+	pb "github.com/trisacrypto/testnet/pkg/rvasp/pb/v1"
+	"github.com/trisacrypto/trisa/pkg/trisa/mtls"
+	"github.com/trisacrypto/trisa/pkg/trisa/peers"
+	"github.com/trisacrypto/trisa/pkg/trust"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const (
+	bufSize = 1024 * 1024
+
+	// The server name in the harness certificates used to connect to bufconn listeners
+	bufnet = "bufnet"
+
+	// ServerName is the common name to verify when dialing an rVASP in the harness.
+	ServerName = bufnet
+)
+
+// VASPs that can be started by the harness, these have certificates in testdata.
+var VASPs = []string{"alice", "bob", "evil", "charlie"}
+
+// Harness manages the in-memory rVASPs and their shared database.
+type Harness struct {
+	db        *gorm.DB
+	vasps     map[string]*VASP
+	order     []*VASP
+	configure func(conf *config.Config)
+}
+
+// VASP is a running rVASP and the clients connected to it.
+type VASP struct {
+	Name   string
+	Server *rvasp.Server
+	DB     *db.DB
+	Client pb.TRISAIntegrationClient
+	Demo   pb.TRISADemoClient
+
+	api   *bufconn.GRPCListener
+	trisa *bufconn.GRPCListener
+	certs *trust.Provider
+	chain trust.ProviderPool
+}
+
+// New starts the named rVASPs (e.g. "alice" and "bob"), or all of the VASPs if no names
+// are specified. The rVASPs are shut down when the test completes.
+func New(t testing.TB, names ...string) *Harness {
+	t.Helper()
+	return NewWithConfig(t, nil, names...)
+}
+
+// NewWithConfig starts the named rVASPs like New, calling configure (if not nil) to
+// modify the configuration of each rVASP before it is started, e.g. to enable auth.
+// Note that the Client and Demo of each VASP do not send credentials, use Dial to
+// connect with credentials.
+func NewWithConfig(t testing.TB, configure func(conf *config.Config), names ...string) *Harness {
+	t.Helper()
+	if len(names) == 0 {
+		names = VASPs
+	}
+
+	h := &Harness{vasps: make(map[string]*VASP, len(names)), configure: configure}
+	t.Cleanup(h.Shutdown)
+
+	// Create the shared database and load the fixtures
+	var err error
+	dsn := filepath.Join(t.TempDir(), "rvasp.db") + "?_pragma=busy_timeout(10000)&_pragma=journal_mode(WAL)"
+	if h.db, err = gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Discard}); err != nil {
+		t.Fatalf("could not open harness database: %s", err)
+	}
+
+	if err = db.ResetDB(h.db, filepath.Join(packageDir(), "..", "fixtures")); err != nil {
+		t.Fatalf("could not load harness fixtures: %s", err)
+	}
+
+	for _, name := range names {
+		var vasp *VASP
+		if vasp, err = h.start(name); err != nil {
+			t.Fatalf("could not start %s: %s", name, err)
+		}
+		h.vasps[name] = vasp
+		h.order = append(h.order, vasp)
+	}
+
+	// Wire the peers of each rVASP to the TRISA services of the other rVASPs
+	for _, local := range h.order {
+		for _, remote := range h.order {
+			if local == remote {
+				continue
+			}
+
+			if err = connect(local, remote); err != nil {
+				t.Fatalf("could not connect %s to %s: %s", local.Name, remote.Name, err)
+			}
+		}
+	}
+
+	return h
+}
+
+// start creates and runs the rVASP with the specified short name on bufconn listeners.
+func (h *Harness) start(name string) (vasp *VASP, err error) {
+	certPath := filepath.Join(packageDir(), "testdata", name+".pem")
+	conf := &config.Config{
+		Name:            fmt.Sprintf("api.%s.vaspbot.com", name),
+		CertPath:        certPath,
+		TrustChainPath:  certPath,
+		AsyncInterval:   time.Minute,
+		AsyncNotBefore:  0,
+		AsyncNotAfter:   time.Hour,
+		RecordEnvelopes: true,
+		Updates:         config.UpdatesConfig{Buffer: 100, Queue: 1000},
+		GDS:             config.GDSConfig{URL: bufnet, Insecure: true},
+		Webhooks:        config.WebhooksConfig{MaxAttempts: 3, Backoff: 10 * time.Millisecond, Timeout: 5 * time.Second, AllowPrivate: true},
+	}
+
+	if h.configure != nil {
+		h.configure(conf)
+	}
+
+	vasp = &VASP{Name: conf.Name}
+	if vasp.DB, err = db.NewVASPDB(h.db, conf.Name); err != nil {
+		return nil, err
+	}
+
+	if vasp.Server, err = rvasp.NewServer(conf, vasp.DB); err != nil {
+		return nil, err
+	}
+
+	if vasp.certs, vasp.chain, err = loadCerts(certPath); err != nil {
+		return nil, err
+	}
+
+	// Run the TRISA service and the rVASP API on bufconn listeners
+	vasp.trisa = bufconn.New(bufSize)
+	go vasp.Server.TRISA().Run(vasp.trisa.Listener)
+
+	vasp.api = bufconn.New(bufSize)
+	go vasp.Server.Run(vasp.api.Listener)
+
+	if err = vasp.api.Connect(grpc.WithTransportCredentials(insecure.NewCredentials())); err != nil {
+		return nil, err
+	}
+	vasp.Client = pb.NewTRISAIntegrationClient(vasp.api.Conn)
+	vasp.Demo = pb.NewTRISADemoClient(vasp.api.Conn)
+	return vasp, nil
+}
+
+// connect the peer for the remote rVASP in the local peers cache to the remote TRISA
+// service using the local mTLS credentials.
+func connect(local, remote *VASP) (err error) {
+	cache := local.Server.Peers()
+	if err = cache.Add(&peers.PeerInfo{CommonName: remote.Name, Endpoint: bufnet}); err != nil {
+		return err
+	}
+
+	var peer *peers.Peer
+	if peer, err = cache.Get(remote.Name); err != nil {
+		return err
+	}
+
+	var creds grpc.DialOption
+	if creds, err = mtls.ClientCreds(bufnet, local.certs, local.chain); err != nil {
+		return err
+	}
+	return peer.Connect(remote.TRISADialer(), creds)
+}
+
+// VASP returns the running rVASP with the short name, e.g. "alice".
+func (h *Harness) VASP(name string) *VASP {
+	return h.vasps[name]
+}
+
+// DB returns the database shared by all of the rVASPs.
+func (h *Harness) DB() *gorm.DB {
+	return h.db
+}
+
+// HandleAsync handles the pending transactions of each rVASP once, in the order the
+// rVASPs were started. Call HandleAsync repeatedly to step through async handshakes.
+func (h *Harness) HandleAsync() {
+	for _, vasp := range h.order {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		vasp.Server.HandleAsync(ctx)
+		cancel()
+	}
+}
+
+// Shutdown all of the rVASPs and close the shared database.
+func (h *Harness) Shutdown() {
+	for _, vasp := range h.order {
+		if vasp.api.Conn != nil {
+			vasp.api.Close()
+		}
+		vasp.Server.Shutdown()
+		vasp.api.Release()
+		vasp.trisa.Release()
+	}
+	h.order = nil
+
+	if h.db != nil {
+		if sqldb, err := h.db.DB(); err == nil {
+			sqldb.Close()
+		}
+	}
+}
+
+// Account returns the account of the rVASP by email or wallet address.
+func (v *VASP) Account(account string) (record *db.Account, err error) {
+	record = &db.Account{}
+	if err = v.DB.LookupAccount(account).First(record).Error; err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// Transaction returns the rVASP's record of the transaction with the envelope ID.
+func (v *VASP) Transaction(envelope string) (record *db.Transaction, err error) {
+	record = &db.Transaction{}
+	if err = v.DB.LookupTransaction(envelope).First(record).Error; err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// Dial the rVASP API over bufconn with the dial options, e.g. per-RPC credentials. The
+// caller must close the connection.
+func (v *VASP) Dial(opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	opts = append([]grpc.DialOption{grpc.WithContextDialer(v.api.Dialer), grpc.WithTransportCredentials(insecure.NewCredentials())}, opts...)
+	return grpc.DialContext(context.Background(), bufnet, opts...)
+}
+
+// Certs returns the mTLS certificates and trust pool of the rVASP.
+func (v *VASP) Certs() (*trust.Provider, trust.ProviderPool) {
+	return v.certs, v.chain
+}
+
+// TRISADialer returns a dial option that connects to the TRISA service of the rVASP
+// over bufconn; the connection must verify the ServerName rather than the rVASP name.
+func (v *VASP) TRISADialer() grpc.DialOption {
+	return grpc.WithContextDialer(v.trisa.Dialer)
+}
+
+// Load certificates and the trust pool from the certificate chain.
+func loadCerts(path string) (certs *trust.Provider, chain trust.ProviderPool, err error) {
+	var sz *trust.Serializer
+	if sz, err = trust.NewSerializer(false); err != nil {
+		return nil, nil, err
+	}
+
+	if certs, err = sz.ReadFile(path); err != nil {
+		return nil, nil, err
+	}
+
+	if chain, err = sz.ReadPoolFile(path); err != nil {
+		return nil, nil, err
+	}
+	return certs, chain, nil
+}
+
+// packageDir returns the directory of the harness source so that the fixtures and
+// certificates can be found regardless of the working directory of the test.
+func packageDir() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Dir(file)
+}
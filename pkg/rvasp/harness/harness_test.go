@@ -0,0 +1,87 @@
+package harness_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	"github.com/trisacrypto/testnet/pkg/rvasp/harness"
+	pb "github.com/trisacrypto/testnet/pkg/rvasp/pb/v1"
+)
+
+// Test every originator and beneficiary policy pair between two rVASPs end-to-end.
+func TestPolicies(t *testing.T) {
+	h := harness.New(t, "alice", "bob")
+	alice, bob := h.VASP("alice"), h.VASP("bob")
+
+	testCases := []struct {
+		originator  string // grouped by the originator policy of the wallet
+		beneficiary string // the beneficiary policy of the wallet is in the comment
+		reply       pb.TransactionState
+		final       pb.TransactionState
+		err         string
+	}{
+		// SendPartial
+		{"mary@alicevasp.us", "robert@bobvasp.co.uk", pb.TransactionState_COMPLETED, pb.TransactionState_COMPLETED, ""},                         // (SyncRepair)
+		{"mary@alicevasp.us", "george@bobvasp.co.uk", pb.TransactionState_REJECTED, pb.TransactionState_REJECTED, "missing beneficiary person"}, // (SyncRequire)
+		{"mary@alicevasp.us", "larry@bobvasp.co.uk", pb.TransactionState_AWAITING_REPLY, pb.TransactionState_COMPLETED, ""},                     // (AsyncRepair)
+		{"mary@alicevasp.us", "fred@bobvasp.co.uk", pb.TransactionState_AWAITING_REPLY, pb.TransactionState_REJECTED, ""},                       // (AsyncReject)
+
+		// SendFull
+		{"alice@alicevasp.us", "robert@bobvasp.co.uk", pb.TransactionState_COMPLETED, pb.TransactionState_COMPLETED, ""},     // (SyncRepair)
+		{"alice@alicevasp.us", "george@bobvasp.co.uk", pb.TransactionState_COMPLETED, pb.TransactionState_COMPLETED, ""},     // (SyncRequire)
+		{"alice@alicevasp.us", "larry@bobvasp.co.uk", pb.TransactionState_AWAITING_REPLY, pb.TransactionState_COMPLETED, ""}, // (AsyncRepair)
+		{"alice@alicevasp.us", "fred@bobvasp.co.uk", pb.TransactionState_AWAITING_REPLY, pb.TransactionState_REJECTED, ""},   // (AsyncReject)
+
+		// SendError
+		{"sarah@alicevasp.us", "robert@bobvasp.co.uk", pb.TransactionState_REJECTED, pb.TransactionState_REJECTED, "rVASP mock compliance check failed"}, // (SyncRepair)
+		{"sarah@alicevasp.us", "george@bobvasp.co.uk", pb.TransactionState_REJECTED, pb.TransactionState_REJECTED, "rVASP mock compliance check failed"}, // (SyncRequire)
+		{"sarah@alicevasp.us", "larry@bobvasp.co.uk", pb.TransactionState_REJECTED, pb.TransactionState_REJECTED, "rVASP mock compliance check failed"},  // (AsyncRepair)
+		{"sarah@alicevasp.us", "fred@bobvasp.co.uk", pb.TransactionState_REJECTED, pb.TransactionState_REJECTED, "rVASP mock compliance check failed"},   // (AsyncReject)
+	}
+
+	for _, tc := range testCases {
+		originator, err := alice.Account(tc.originator)
+		require.NoError(t, err)
+
+		reply, err := alice.Client.Transfer(context.Background(), &pb.TransferRequest{
+			Account:     tc.originator,
+			Beneficiary: tc.beneficiary,
+			Amount:      1.5,
+			AssetType:   "Bitcoin",
+		})
+		require.NoError(t, err, "%s to %s", tc.originator, tc.beneficiary)
+		require.Equal(t, tc.reply, reply.Transaction.State, "%s to %s", tc.originator, tc.beneficiary)
+		if tc.err != "" {
+			require.Contains(t, reply.Error.Message, tc.err)
+		} else {
+			require.Nil(t, reply.Error)
+		}
+
+		// Step through the async handshake, which takes two rounds to complete
+		h.HandleAsync()
+		h.HandleAsync()
+
+		// The originator should have the final transaction state and balance
+		envelope := reply.Transaction.EnvelopeId
+		xfer, err := alice.Transaction(envelope)
+		require.NoError(t, err)
+		require.Equal(t, tc.final, xfer.State, "%s to %s", tc.originator, tc.beneficiary)
+
+		account, err := alice.Account(tc.originator)
+		require.NoError(t, err)
+		if tc.final == pb.TransactionState_COMPLETED {
+			require.True(t, originator.Balance.Sub(decimal.NewFromFloat(1.5)).Equal(account.Balance), "expected originator to be debited")
+		} else {
+			require.True(t, originator.Balance.Equal(account.Balance), "expected originator balance to be unchanged")
+		}
+
+		// The beneficiary should agree on the final state if it received the transfer
+		if xfer, err = bob.Transaction(envelope); err == nil {
+			require.Equal(t, tc.final, xfer.State, "%s to %s", tc.originator, tc.beneficiary)
+		} else {
+			require.Contains(t, tc.err, "compliance check failed", "only rejected originators should not create beneficiary transactions")
+		}
+	}
+}
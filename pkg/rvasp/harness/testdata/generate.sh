@@ -0,0 +1,34 @@
+#!/bin/bash
+# Generates the certificates for the rVASPs started by the test harness. The common
+# names must match the VASP names in the rVASP fixtures.
+
+cd "$(dirname "$0")"
+
+# Create CA
+openssl req -x509 -newkey rsa:4096 -sha256 -days 10950 \
+    -nodes -keyout ca.key -out ca.crt \
+    -subj "/C=US/ST=California/L=Menlo Park/O=TRISA/OU=TestNet/CN=trisatest.dev" \
+    -addext "subjectAltName=DNS:trisatest.dev,DNS:*.trisatest.dev"
+
+for name in alice bob evil charlie; do
+    # Create certificate request
+    openssl req -new -newkey rsa:4096 \
+        -nodes -keyout $name.key.pem -out $name.csr \
+        -subj "/C=US/ST=New York/L=New York/O=${name^} VASP/OU=Testing/CN=api.$name.vaspbot.com" \
+        -addext "subjectAltName=DNS:api.$name.vaspbot.com,DNS:bufnet"
+
+    # Create signed certificate with CA
+    openssl x509 -req -days 10950 \
+        -CA ca.crt -CAkey ca.key \
+        -in $name.csr -out $name.pem \
+        -copy_extensions copyall
+
+    # Combine files into a single certificate chain
+    cat ca.crt >> $name.pem
+    cat $name.key.pem >> $name.pem
+
+    # Cleanup
+    rm $name.csr $name.key.pem
+done
+
+rm ca.key ca.crt ca.srl 2>/dev/null
@@ -0,0 +1,117 @@
+package rvasp_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trisacrypto/testnet/pkg/rvasp/harness"
+	pb "github.com/trisacrypto/testnet/pkg/rvasp/pb/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestTransactionHistory(t *testing.T) {
+	h := harness.New(t, "alice", "bob")
+	alice, bob := h.VASP("alice"), h.VASP("bob")
+	ctx := context.Background()
+
+	// SendPartial to AsyncRepair takes the async handshake through every pending state
+	reply, err := alice.Client.Transfer(ctx, &pb.TransferRequest{
+		Account:     "mary@alicevasp.us",
+		Beneficiary: "larry@bobvasp.co.uk",
+		Amount:      1.5,
+		AssetType:   "Bitcoin",
+	})
+	require.NoError(t, err)
+	require.Equal(t, pb.TransactionState_AWAITING_REPLY, reply.Transaction.State)
+
+	h.HandleAsync()
+	h.HandleAsync()
+
+	type transition struct {
+		previous pb.TransactionState
+		state    pb.TransactionState
+		actor    string
+	}
+
+	testCases := []struct {
+		vasp     *harness.VASP
+		expected []transition
+	}{
+		{
+			alice,
+			[]transition{
+				{pb.TransactionState_INVALID, pb.TransactionState_AWAITING_REPLY, "integration"},
+				{pb.TransactionState_AWAITING_REPLY, pb.TransactionState_PENDING_RECEIVED, "peer"},
+				{pb.TransactionState_PENDING_RECEIVED, pb.TransactionState_ACCEPTED, "async"},
+				{pb.TransactionState_ACCEPTED, pb.TransactionState_COMPLETED, "peer"},
+			},
+		},
+		{
+			bob,
+			[]transition{
+				{pb.TransactionState_INVALID, pb.TransactionState_AWAITING_REPLY, "peer"},
+				{pb.TransactionState_AWAITING_REPLY, pb.TransactionState_PENDING_SENT, "peer"},
+				{pb.TransactionState_PENDING_SENT, pb.TransactionState_AWAITING_FULL_TRANSFER, "async"},
+				{pb.TransactionState_AWAITING_FULL_TRANSFER, pb.TransactionState_PENDING_ACKNOWLEDGED, "peer"},
+				{pb.TransactionState_PENDING_ACKNOWLEDGED, pb.TransactionState_COMPLETED, "async"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		rep, err := tc.vasp.Client.TransactionHistory(ctx, &pb.TransactionHistoryRequest{EnvelopeId: reply.Transaction.EnvelopeId})
+		require.NoError(t, err, tc.vasp.Name)
+		require.Equal(t, pb.TransactionState_COMPLETED, rep.Transaction.State)
+		require.Len(t, rep.Transaction.History, len(tc.expected), tc.vasp.Name)
+
+		for i, expected := range tc.expected {
+			actual := rep.Transaction.History[i]
+			require.Equal(t, expected.previous, actual.Previous, "%s transition %d", tc.vasp.Name, i)
+			require.Equal(t, expected.state, actual.State, "%s transition %d", tc.vasp.Name, i)
+			require.Equal(t, expected.actor, actual.Actor, "%s transition %d", tc.vasp.Name, i)
+			require.NotEmpty(t, actual.Reason)
+			require.NotEmpty(t, actual.Timestamp)
+		}
+	}
+
+	// The history is only included in the account status on request
+	account, err := alice.Client.AccountStatus(ctx, &pb.AccountRequest{Account: "mary@alicevasp.us"})
+	require.NoError(t, err)
+	require.NotEmpty(t, account.Transactions)
+	for _, xfer := range account.Transactions {
+		require.Empty(t, xfer.History)
+	}
+
+	account, err = alice.Client.AccountStatus(ctx, &pb.AccountRequest{Account: "mary@alicevasp.us", History: true})
+	require.NoError(t, err)
+	found := false
+	for _, xfer := range account.Transactions {
+		if xfer.EnvelopeId == reply.Transaction.EnvelopeId {
+			require.Len(t, xfer.History, 4)
+			found = true
+		}
+	}
+	require.True(t, found, "transaction not in account status")
+
+	// Failures are recorded with the error that caused them
+	reply, err = alice.Client.Transfer(ctx, &pb.TransferRequest{
+		Account:     "mary@alicevasp.us",
+		Beneficiary: "george@bobvasp.co.uk",
+		Amount:      1.5,
+		AssetType:   "Bitcoin",
+	})
+	require.NoError(t, err)
+	require.Equal(t, pb.TransactionState_REJECTED, reply.Transaction.State)
+
+	rep, err := alice.Client.TransactionHistory(ctx, &pb.TransactionHistoryRequest{EnvelopeId: reply.Transaction.EnvelopeId})
+	require.NoError(t, err)
+	require.Len(t, rep.Transaction.History, 2)
+	require.Equal(t, pb.TransactionState_AWAITING_REPLY, rep.Transaction.History[0].State)
+	require.Equal(t, "integration", rep.Transaction.History[1].Actor)
+	require.Contains(t, rep.Transaction.History[1].Reason, "missing beneficiary person")
+
+	_, err = alice.Client.TransactionHistory(ctx, &pb.TransactionHistoryRequest{EnvelopeId: "unknown"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
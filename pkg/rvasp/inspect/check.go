@@ -0,0 +1,132 @@
+package inspect
+
+import (
+	"crypto/rsa"
+	"fmt"
+	"io"
+
+	protocol "github.com/trisacrypto/trisa/pkg/trisa/api/v1beta1"
+	"github.com/trisacrypto/trisa/pkg/trisa/crypto/aesgcm"
+	"github.com/trisacrypto/trisa/pkg/trisa/envelope"
+)
+
+// The algorithms supported by the rVASP TRISA service.
+const (
+	SupportedEncryption = "AES256-GCM"
+	SupportedHMAC       = "HMAC-SHA256"
+)
+
+// HMACStatus describes whether the HMAC signature of the payload could be verified.
+type HMACStatus string
+
+const (
+	HMACNotChecked HMACStatus = "not checked"
+	HMACVerified   HMACStatus = "verified"
+	HMACInvalid    HMACStatus = "invalid"
+)
+
+// Report describes the validity of a secure envelope.
+type Report struct {
+	ID                  string
+	State               envelope.State
+	EncryptionAlgorithm string
+	HMACAlgorithm       string
+	SupportedAlgorithms bool
+	HMAC                HMACStatus
+	Rejection           *protocol.Error
+	Problems            []string
+}
+
+// Valid returns true if no problems were found with the envelope.
+func (r *Report) Valid() bool {
+	return len(r.Problems) == 0
+}
+
+// Check validates the secure envelope: its state, whether it is a rejection, whether
+// its algorithms are supported by the rVASP and, if the private key of the recipient is
+// specified, whether the HMAC signature of the encrypted payload is valid.
+func Check(msg *protocol.SecureEnvelope, key *rsa.PrivateKey) *Report {
+	report := &Report{
+		ID:                  msg.Id,
+		State:               envelope.Status(msg),
+		EncryptionAlgorithm: msg.EncryptionAlgorithm,
+		HMACAlgorithm:       msg.HmacAlgorithm,
+		HMAC:                HMACNotChecked,
+	}
+
+	if err := envelope.Validate(msg); err != nil {
+		report.Problems = append(report.Problems, fmt.Sprintf("invalid envelope: %s", err))
+	}
+
+	var isErr bool
+	if report.Rejection, isErr = envelope.Check(msg); isErr && report.Rejection == nil {
+		report.Problems = append(report.Problems, "envelope is in an error state without a rejection")
+	}
+
+	// A rejection without a payload has no cryptographic algorithms to check
+	if len(msg.Payload) == 0 {
+		return report
+	}
+
+	report.SupportedAlgorithms = msg.EncryptionAlgorithm == SupportedEncryption && msg.HmacAlgorithm == SupportedHMAC
+	if !report.SupportedAlgorithms {
+		report.Problems = append(report.Problems, fmt.Sprintf("unsupported algorithms %q and %q, expected %s and %s", msg.EncryptionAlgorithm, msg.HmacAlgorithm, SupportedEncryption, SupportedHMAC))
+		return report
+	}
+
+	if key == nil && msg.Sealed {
+		return report
+	}
+
+	if err := verifyHMAC(msg, key); err != nil {
+		report.HMAC = HMACInvalid
+		report.Problems = append(report.Problems, err.Error())
+		return report
+	}
+	report.HMAC = HMACVerified
+	return report
+}
+
+// verifyHMAC unseals the HMAC secret if necessary and verifies the HMAC signature of the
+// encrypted payload without decrypting it.
+func verifyHMAC(msg *protocol.SecureEnvelope, key *rsa.PrivateKey) (err error) {
+	if msg.Sealed {
+		var env *envelope.Envelope
+		if env, err = envelope.Wrap(msg, envelope.WithRSAPrivateKey(key)); err != nil {
+			return fmt.Errorf("could not wrap envelope: %s", err)
+		}
+
+		if env, _, err = env.Unseal(); err != nil {
+			return fmt.Errorf("could not unseal envelope: %s", err)
+		}
+		msg = env.Proto()
+	}
+
+	var cipher *aesgcm.AESGCM
+	if cipher, err = aesgcm.New(msg.EncryptionKey, msg.HmacSecret); err != nil {
+		return fmt.Errorf("could not create cipher: %s", err)
+	}
+
+	if err = cipher.Verify(msg.Payload, msg.Hmac); err != nil {
+		return fmt.Errorf("hmac signature could not be verified: %s", err)
+	}
+	return nil
+}
+
+// Print a human readable summary of the report.
+func (r *Report) Print(w io.Writer) {
+	fmt.Fprintf(w, "envelope:   %s\n", r.ID)
+	fmt.Fprintf(w, "state:      %s\n", r.State)
+	if r.EncryptionAlgorithm != "" || r.HMACAlgorithm != "" {
+		fmt.Fprintf(w, "algorithms: %s, %s (supported: %t)\n", r.EncryptionAlgorithm, r.HMACAlgorithm, r.SupportedAlgorithms)
+	}
+	fmt.Fprintf(w, "hmac:       %s\n", r.HMAC)
+
+	if r.Rejection != nil {
+		fmt.Fprintf(w, "rejection:  [%s] %s (retry: %t)\n", r.Rejection.Code, r.Rejection.Message, r.Rejection.Retry)
+	}
+
+	for _, problem := range r.Problems {
+		fmt.Fprintf(w, "problem:    %s\n", problem)
+	}
+}
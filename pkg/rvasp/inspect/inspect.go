@@ -0,0 +1,247 @@
+/*
+Package inspect decodes, creates and checks TRISA secure envelopes so that the messages
+exchanged with a counterparty can be examined offline. Envelopes are opened and sealed
+with the same envelope.Open, envelope.Seal and envelope.Check calls used by the rVASP
+TRISA service, so an envelope that can be opened here can be opened by the rVASP.
+*/
+package inspect
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/trisacrypto/trisa/pkg/ivms101"
+	protocol "github.com/trisacrypto/trisa/pkg/trisa/api/v1beta1"
+	generic "github.com/trisacrypto/trisa/pkg/trisa/data/generic/v1beta1"
+	"github.com/trisacrypto/trisa/pkg/trisa/envelope"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// ReadEnvelope reads a secure envelope from a file containing either protocol buffer
+// JSON or binary protocol buffer data.
+func ReadEnvelope(path string) (msg *protocol.SecureEnvelope, err error) {
+	var data []byte
+	if data, err = os.ReadFile(path); err != nil {
+		return nil, err
+	}
+
+	msg = &protocol.SecureEnvelope{}
+	if jerr := protojson.Unmarshal(data, msg); jerr != nil {
+		msg.Reset()
+		if err = proto.Unmarshal(data, msg); err != nil {
+			return nil, fmt.Errorf("could not parse %s as a JSON or protobuf secure envelope: %s", path, jerr)
+		}
+	}
+	return msg, nil
+}
+
+// WriteEnvelope writes the secure envelope as protocol buffer JSON or, if binary is
+// true, as binary protocol buffer data.
+func WriteEnvelope(w io.Writer, msg *protocol.SecureEnvelope, binary bool) (err error) {
+	var data []byte
+	if binary {
+		data, err = proto.Marshal(msg)
+	} else {
+		data, err = marshal(msg)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(data)
+	return err
+}
+
+// Open decrypts the secure envelope with the private key of the recipient. If the
+// envelope is a rejection, the rejection is returned as the error.
+func Open(msg *protocol.SecureEnvelope, key *rsa.PrivateKey) (payload *protocol.Payload, err error) {
+	if reject, isErr := envelope.Check(msg); isErr {
+		if reject != nil {
+			return nil, fmt.Errorf("envelope is a rejection: %s", reject)
+		}
+		return nil, errors.New("envelope is not a valid secure envelope")
+	}
+
+	var reject *protocol.Error
+	if payload, reject, err = envelope.Open(msg, envelope.WithRSAPrivateKey(key)); err != nil {
+		if reject != nil {
+			return nil, fmt.Errorf("could not open envelope: %s", reject)
+		}
+		return nil, fmt.Errorf("could not open envelope: %s", err)
+	}
+	return payload, nil
+}
+
+// Seal the payload in a secure envelope with the public key of the recipient. A random
+// envelope ID is used if one is not specified.
+func Seal(payload *protocol.Payload, key *rsa.PublicKey, id string) (msg *protocol.SecureEnvelope, err error) {
+	if id == "" {
+		id = uuid.New().String()
+	}
+
+	var reject *protocol.Error
+	if msg, reject, err = envelope.Seal(payload, envelope.WithEnvelopeID(id), envelope.WithRSAPublicKey(key)); err != nil {
+		if reject != nil {
+			return nil, fmt.Errorf("could not seal envelope: %s", reject)
+		}
+		return nil, fmt.Errorf("could not seal envelope: %s", err)
+	}
+	return msg, nil
+}
+
+// LoadPayload creates a transfer payload from an IVMS101 identity payload and a generic
+// transaction (or pending message if pending is true) in protocol buffer JSON files.
+func LoadPayload(identityPath, transactionPath string, pending bool) (payload *protocol.Payload, err error) {
+	identity := &ivms101.IdentityPayload{}
+	if err = unmarshalFile(identityPath, identity); err != nil {
+		return nil, err
+	}
+
+	var transaction proto.Message = &generic.Transaction{}
+	if pending {
+		transaction = &generic.Pending{}
+	}
+
+	if err = unmarshalFile(transactionPath, transaction); err != nil {
+		return nil, err
+	}
+
+	payload = &protocol.Payload{SentAt: time.Now().Format(time.RFC3339)}
+	if payload.Identity, err = anypb.New(identity); err != nil {
+		return nil, fmt.Errorf("could not dump payload identity: %s", err)
+	}
+
+	if payload.Transaction, err = anypb.New(transaction); err != nil {
+		return nil, fmt.Errorf("could not dump payload transaction: %s", err)
+	}
+	return payload, nil
+}
+
+// MarshalPayload returns the payload as indented JSON, with the identity and the
+// transaction decoded from their protocol buffer any types.
+func MarshalPayload(payload *protocol.Payload) ([]byte, error) {
+	return marshal(payload)
+}
+
+// LoadPrivateKey loads an RSA private key from a PEM file, e.g. the certificates and
+// private key used by the rVASP for mTLS.
+func LoadPrivateKey(path string) (_ *rsa.PrivateKey, err error) {
+	var blocks []*pem.Block
+	if blocks, err = readPEM(path); err != nil {
+		return nil, err
+	}
+
+	for _, block := range blocks {
+		switch block.Type {
+		case "RSA PRIVATE KEY":
+			return x509.ParsePKCS1PrivateKey(block.Bytes)
+		case "PRIVATE KEY":
+			var key interface{}
+			if key, err = x509.ParsePKCS8PrivateKey(block.Bytes); err != nil {
+				return nil, err
+			}
+
+			if rsaKey, ok := key.(*rsa.PrivateKey); ok {
+				return rsaKey, nil
+			}
+			return nil, fmt.Errorf("expected an RSA private key, found %T", key)
+		}
+	}
+	return nil, fmt.Errorf("no private key found in %s", path)
+}
+
+// LoadPublicKey loads an RSA public key from a PEM file containing a certificate, a
+// public key, or a private key. If the file contains a certificate chain, the key of
+// the first certificate is returned.
+func LoadPublicKey(path string) (_ *rsa.PublicKey, err error) {
+	var blocks []*pem.Block
+	if blocks, err = readPEM(path); err != nil {
+		return nil, err
+	}
+
+	for _, block := range blocks {
+		var key interface{}
+		switch block.Type {
+		case "CERTIFICATE":
+			var cert *x509.Certificate
+			if cert, err = x509.ParseCertificate(block.Bytes); err != nil {
+				return nil, err
+			}
+			key = cert.PublicKey
+		case "PUBLIC KEY":
+			if key, err = x509.ParsePKIXPublicKey(block.Bytes); err != nil {
+				return nil, err
+			}
+		case "RSA PUBLIC KEY":
+			if key, err = x509.ParsePKCS1PublicKey(block.Bytes); err != nil {
+				return nil, err
+			}
+		default:
+			continue
+		}
+
+		if rsaKey, ok := key.(*rsa.PublicKey); ok {
+			return rsaKey, nil
+		}
+		return nil, fmt.Errorf("expected an RSA public key, found %T", key)
+	}
+
+	// Fall back to the public key of a private key
+	var priv *rsa.PrivateKey
+	if priv, err = LoadPrivateKey(path); err != nil {
+		return nil, fmt.Errorf("no public key found in %s", path)
+	}
+	return &priv.PublicKey, nil
+}
+
+func readPEM(path string) (blocks []*pem.Block, err error) {
+	var data []byte
+	if data, err = os.ReadFile(path); err != nil {
+		return nil, err
+	}
+
+	for {
+		var block *pem.Block
+		if block, data = pem.Decode(data); block == nil {
+			break
+		}
+		blocks = append(blocks, block)
+	}
+
+	if len(blocks) == 0 {
+		return nil, fmt.Errorf("no PEM data found in %s", path)
+	}
+	return blocks, nil
+}
+
+func unmarshalFile(path string, msg proto.Message) (err error) {
+	var data []byte
+	if data, err = os.ReadFile(path); err != nil {
+		return err
+	}
+
+	if err = protojson.Unmarshal(data, msg); err != nil {
+		return fmt.Errorf("could not parse %s: %s", path, err)
+	}
+	return nil
+}
+
+func marshal(msg proto.Message) ([]byte, error) {
+	jsonpb := protojson.MarshalOptions{
+		Multiline:     true,
+		Indent:        "  ",
+		AllowPartial:  true,
+		UseProtoNames: true,
+	}
+	return jsonpb.Marshal(msg)
+}
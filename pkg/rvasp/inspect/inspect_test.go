@@ -0,0 +1,122 @@
+package inspect_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trisacrypto/testnet/pkg/rvasp/inspect"
+	"github.com/trisacrypto/trisa/pkg/ivms101"
+	protocol "github.com/trisacrypto/trisa/pkg/trisa/api/v1beta1"
+	generic "github.com/trisacrypto/trisa/pkg/trisa/data/generic/v1beta1"
+	"github.com/trisacrypto/trisa/pkg/trisa/envelope"
+)
+
+var (
+	alicePEM = filepath.Join("..", "harness", "testdata", "alice.pem")
+	bobPEM   = filepath.Join("..", "harness", "testdata", "bob.pem")
+)
+
+func TestSealOpen(t *testing.T) {
+	payload, err := inspect.LoadPayload(filepath.Join("testdata", "identity.json"), filepath.Join("testdata", "transaction.json"), false)
+	require.NoError(t, err)
+
+	pub, err := inspect.LoadPublicKey(alicePEM)
+	require.NoError(t, err)
+
+	msg, err := inspect.Seal(payload, pub, "8b2f8c7e-7f3b-4a89-9c1a-2a4c1d1e5f60")
+	require.NoError(t, err)
+	require.Equal(t, "8b2f8c7e-7f3b-4a89-9c1a-2a4c1d1e5f60", msg.Id)
+
+	// Round trip the envelope through both file formats
+	dir := t.TempDir()
+	for _, binary := range []bool{false, true} {
+		path := filepath.Join(dir, "envelope")
+		buf := &bytes.Buffer{}
+		require.NoError(t, inspect.WriteEnvelope(buf, msg, binary))
+		require.NoError(t, os.WriteFile(path, buf.Bytes(), 0644))
+
+		read, err := inspect.ReadEnvelope(path)
+		require.NoError(t, err)
+		require.Equal(t, msg.Payload, read.Payload)
+	}
+
+	key, err := inspect.LoadPrivateKey(alicePEM)
+	require.NoError(t, err)
+
+	opened, err := inspect.Open(msg, key)
+	require.NoError(t, err)
+
+	identity := &ivms101.IdentityPayload{}
+	require.NoError(t, opened.Identity.UnmarshalTo(identity))
+	require.Equal(t, "Mary", identity.Originator.OriginatorPersons[0].GetNaturalPerson().Name.NameIdentifiers[0].SecondaryIdentifier)
+
+	transaction := &generic.Transaction{}
+	require.NoError(t, opened.Transaction.UnmarshalTo(transaction))
+	require.Equal(t, 1.5, transaction.Amount)
+
+	data, err := inspect.MarshalPayload(opened)
+	require.NoError(t, err)
+	require.Contains(t, string(data), "type.googleapis.com/ivms101.IdentityPayload")
+
+	// The envelope cannot be opened with the wrong key
+	bobKey, err := inspect.LoadPrivateKey(bobPEM)
+	require.NoError(t, err)
+	_, err = inspect.Open(msg, bobKey)
+	require.Error(t, err)
+}
+
+func TestCheck(t *testing.T) {
+	payload, err := inspect.LoadPayload(filepath.Join("testdata", "identity.json"), filepath.Join("testdata", "transaction.json"), false)
+	require.NoError(t, err)
+
+	pub, err := inspect.LoadPublicKey(alicePEM)
+	require.NoError(t, err)
+
+	key, err := inspect.LoadPrivateKey(alicePEM)
+	require.NoError(t, err)
+
+	msg, err := inspect.Seal(payload, pub, "")
+	require.NoError(t, err)
+
+	// Without a key the HMAC of a sealed envelope cannot be checked
+	report := inspect.Check(msg, nil)
+	require.True(t, report.Valid(), report.Problems)
+	require.Equal(t, envelope.Sealed, report.State)
+	require.True(t, report.SupportedAlgorithms)
+	require.Equal(t, inspect.HMACNotChecked, report.HMAC)
+
+	report = inspect.Check(msg, key)
+	require.True(t, report.Valid(), report.Problems)
+	require.Equal(t, inspect.HMACVerified, report.HMAC)
+
+	// A tampered payload fails HMAC verification
+	msg.Payload[0] ^= 0xff
+	report = inspect.Check(msg, key)
+	require.False(t, report.Valid())
+	require.Equal(t, inspect.HMACInvalid, report.HMAC)
+
+	// Unsupported algorithms are reported
+	msg.EncryptionAlgorithm = "ROT13"
+	report = inspect.Check(msg, key)
+	require.False(t, report.Valid())
+	require.False(t, report.SupportedAlgorithms)
+
+	// Rejections are reported without problems and cannot be opened
+	reject, err := envelope.Reject(protocol.Errorf(protocol.ComplianceCheckFail, "no thanks"), envelope.WithEnvelopeID(msg.Id))
+	require.NoError(t, err)
+
+	report = inspect.Check(reject, key)
+	require.True(t, report.Valid(), report.Problems)
+	require.Equal(t, envelope.Error, report.State)
+	require.Equal(t, protocol.ComplianceCheckFail, report.Rejection.Code)
+
+	buf := &bytes.Buffer{}
+	report.Print(buf)
+	require.Contains(t, buf.String(), "COMPLIANCE_CHECK_FAIL")
+
+	_, err = inspect.Open(reject, key)
+	require.EqualError(t, err, "envelope is a rejection: trisa rejection [COMPLIANCE_CHECK_FAIL]: no thanks")
+}
@@ -70,3 +70,19 @@ func StreamTraceInterceptor(srv interface{}, stream grpc.ServerStream, info *grp
 		Msg("gRPC stream closed")
 	return err
 }
+
+// chainUnaryInterceptors returns a unary interceptor that calls the interceptors in
+// order, so that the REST gateway can call the handlers through the same chain as the
+// gRPC server.
+func chainUnaryInterceptors(interceptors ...grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, in interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		next := handler
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, inner := interceptors[i], next
+			next = func(ctx context.Context, in interface{}) (interface{}, error) {
+				return interceptor(ctx, in, info, inner)
+			}
+		}
+		return next(ctx, in)
+	}
+}
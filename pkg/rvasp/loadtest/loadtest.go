@@ -0,0 +1,316 @@
+/*
+Package loadtest drives the TRISA integration API of an rVASP with concurrent transfers
+to measure how many transfers per second it sustains. Each worker sends transfers from
+a random account in the account pool to a random beneficiary in the beneficiary pool
+using an asset type chosen from a weighted asset mix. Optionally, LiveUpdates streams
+are held open during the test to measure the cost of broadcasting updates to clients.
+*/
+package loadtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	pb "github.com/trisacrypto/testnet/pkg/rvasp/pb/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Default options for the load test.
+const (
+	DefaultConcurrency = 8
+	DefaultDuration    = 30 * time.Second
+	DefaultAmount      = 0.01
+	DefaultTimeout     = 30 * time.Second
+	DefaultAssetType   = "Bitcoin"
+)
+
+// Options configure the load test.
+type Options struct {
+	// The number of workers sending transfers concurrently
+	Concurrency int
+
+	// How long to send transfers for
+	Duration time.Duration
+
+	// The originator accounts and beneficiaries of the transfers
+	Accounts      []string
+	Beneficiaries []string
+
+	// The weighted asset types of the transfers
+	Assets []AssetWeight
+
+	// The amount of each transfer and the timeout of each transfer request
+	Amount  float32
+	Timeout time.Duration
+
+	// The number of LiveUpdates streams to hold open during the test
+	Streams int
+
+	// The seed of the random account and asset selection
+	Seed int64
+}
+
+// AssetWeight is an asset type and its relative frequency in the transfers.
+type AssetWeight struct {
+	AssetType string
+	Weight    int
+}
+
+// ParseAssetMix parses weighted asset types, e.g. "Bitcoin=3,Ethereum=1". Asset types
+// without a weight have a weight of 1.
+func ParseAssetMix(s string) (assets []AssetWeight, err error) {
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		asset := AssetWeight{AssetType: part, Weight: 1}
+		if name, weight, ok := strings.Cut(part, "="); ok {
+			asset.AssetType = strings.TrimSpace(name)
+			if asset.Weight, err = strconv.Atoi(strings.TrimSpace(weight)); err != nil || asset.Weight <= 0 {
+				return nil, fmt.Errorf("invalid weight %q for asset type %q", weight, asset.AssetType)
+			}
+		}
+
+		if asset.AssetType == "" {
+			return nil, fmt.Errorf("could not parse asset mix %q", s)
+		}
+		assets = append(assets, asset)
+	}
+
+	if len(assets) == 0 {
+		return nil, fmt.Errorf("could not parse asset mix %q", s)
+	}
+	return assets, nil
+}
+
+// Validate the options and apply the defaults.
+func (o *Options) Validate() error {
+	if len(o.Accounts) == 0 {
+		return errors.New("at least one originator account is required")
+	}
+
+	if len(o.Beneficiaries) == 0 {
+		return errors.New("at least one beneficiary is required")
+	}
+
+	if o.Concurrency == 0 {
+		o.Concurrency = DefaultConcurrency
+	}
+
+	if o.Duration == 0 {
+		o.Duration = DefaultDuration
+	}
+
+	if o.Amount == 0 {
+		o.Amount = DefaultAmount
+	}
+
+	if o.Timeout == 0 {
+		o.Timeout = DefaultTimeout
+	}
+
+	if len(o.Assets) == 0 {
+		o.Assets = []AssetWeight{{AssetType: DefaultAssetType, Weight: 1}}
+	}
+
+	if o.Concurrency < 0 || o.Duration < 0 || o.Amount < 0 || o.Timeout < 0 || o.Streams < 0 {
+		return errors.New("concurrency, duration, amount, timeout and streams cannot be negative")
+	}
+
+	for _, asset := range o.Assets {
+		if asset.Weight <= 0 {
+			return fmt.Errorf("asset type %q must have a positive weight", asset.AssetType)
+		}
+	}
+	return nil
+}
+
+// Run the load test against the rVASP until the duration elapses or the context is
+// canceled. The demo client is only required if streams are configured.
+func Run(ctx context.Context, client pb.TRISAIntegrationClient, demo pb.TRISADemoClient, opts Options) (report *Report, err error) {
+	if err = opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	if opts.Streams > 0 && demo == nil {
+		return nil, errors.New("a demo client is required to open live updates streams")
+	}
+
+	warmup(ctx, client, &opts)
+
+	// Open the live updates streams before sending transfers; the streams are closed
+	// when the workers are done rather than at the deadline.
+	sctx, closeStreams := context.WithCancel(ctx)
+	defer closeStreams()
+
+	var streams sync.WaitGroup
+	stats := &streamStats{}
+	for i := 0; i < opts.Streams; i++ {
+		streams.Add(1)
+		go func(i int) {
+			defer streams.Done()
+			stats.listen(sctx, demo, fmt.Sprintf("loadtest-%d-%d", opts.Seed, i))
+		}(i)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, opts.Duration)
+	defer cancel()
+
+	workers := make([]*worker, opts.Concurrency)
+	var wg sync.WaitGroup
+	start := time.Now()
+	for i := range workers {
+		workers[i] = newWorker(client, &opts, opts.Seed+int64(i))
+		wg.Add(1)
+		go func(w *worker) {
+			defer wg.Done()
+			w.run(ctx)
+		}(workers[i])
+	}
+
+	wg.Wait()
+	elapsed := time.Since(start)
+	closeStreams()
+	streams.Wait()
+
+	report = newReport(elapsed, workers)
+	report.Streams = opts.Streams
+	report.StreamMessages = stats.messages
+	report.StreamErrors = stats.errors
+	return report, nil
+}
+
+// warmup sends a transfer to each beneficiary one at a time so that signing keys are
+// exchanged with the beneficiary VASPs before the workers start, otherwise the key
+// exchanges would skew the latencies of the first transfers.
+// Warm up transfers are not included in the report.
+func warmup(ctx context.Context, client pb.TRISAIntegrationClient, opts *Options) {
+	for _, beneficiary := range opts.Beneficiaries {
+		tctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+		client.Transfer(tctx, &pb.TransferRequest{
+			Account:     opts.Accounts[0],
+			Beneficiary: beneficiary,
+			Amount:      opts.Amount,
+			AssetType:   opts.Assets[0].AssetType,
+		})
+		cancel()
+	}
+}
+
+// worker sends transfers sequentially and records the outcome of each transfer.
+type worker struct {
+	client pb.TRISAIntegrationClient
+	opts   *Options
+	rand   *rand.Rand
+	total  int
+
+	latencies  []time.Duration
+	grpcErrors map[codes.Code]int
+	trisaCodes map[int32]int
+}
+
+func newWorker(client pb.TRISAIntegrationClient, opts *Options, seed int64) *worker {
+	w := &worker{
+		client:     client,
+		opts:       opts,
+		rand:       rand.New(rand.NewSource(seed)),
+		grpcErrors: make(map[codes.Code]int),
+		trisaCodes: make(map[int32]int),
+	}
+
+	for _, asset := range opts.Assets {
+		w.total += asset.Weight
+	}
+	return w
+}
+
+func (w *worker) run(ctx context.Context) {
+	for ctx.Err() == nil {
+		req := &pb.TransferRequest{
+			Account:     w.opts.Accounts[w.rand.Intn(len(w.opts.Accounts))],
+			Beneficiary: w.opts.Beneficiaries[w.rand.Intn(len(w.opts.Beneficiaries))],
+			Amount:      w.opts.Amount,
+			AssetType:   w.asset(),
+		}
+
+		// Each transfer gets the full timeout even if the test ends while it is in flight
+		tctx, cancel := context.WithTimeout(context.Background(), w.opts.Timeout)
+		start := time.Now()
+		rep, err := w.client.Transfer(tctx, req)
+		latency := time.Since(start)
+		cancel()
+
+		w.latencies = append(w.latencies, latency)
+		if err != nil {
+			w.grpcErrors[status.Code(err)]++
+			continue
+		}
+
+		if rep.Error != nil && rep.Error.Code != 0 {
+			w.trisaCodes[rep.Error.Code]++
+		}
+	}
+}
+
+// asset selects an asset type from the weighted asset mix.
+func (w *worker) asset() string {
+	n := w.rand.Intn(w.total)
+	for _, asset := range w.opts.Assets {
+		if n < asset.Weight {
+			return asset.AssetType
+		}
+		n -= asset.Weight
+	}
+	return w.opts.Assets[len(w.opts.Assets)-1].AssetType
+}
+
+// streamStats counts the messages received by the live updates streams.
+type streamStats struct {
+	sync.Mutex
+	messages uint64
+	errors   int
+}
+
+func (s *streamStats) listen(ctx context.Context, demo pb.TRISADemoClient, client string) {
+	stream, err := demo.LiveUpdates(ctx)
+	if err != nil {
+		s.fail(ctx)
+		return
+	}
+
+	if err = stream.Send(&pb.Command{Type: pb.RPC_NORPC, Client: client}); err != nil {
+		s.fail(ctx)
+		return
+	}
+
+	for {
+		if _, err = stream.Recv(); err != nil {
+			s.fail(ctx)
+			return
+		}
+
+		s.Lock()
+		s.messages++
+		s.Unlock()
+	}
+}
+
+// fail records a stream error unless the stream was closed because the test ended.
+func (s *streamStats) fail(ctx context.Context) {
+	if ctx.Err() != nil {
+		return
+	}
+
+	s.Lock()
+	s.errors++
+	s.Unlock()
+}
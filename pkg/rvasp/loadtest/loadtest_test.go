@@ -0,0 +1,108 @@
+package loadtest_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trisacrypto/testnet/pkg/rvasp/harness"
+	"github.com/trisacrypto/testnet/pkg/rvasp/loadtest"
+	"google.golang.org/grpc/codes"
+)
+
+func TestLoadTest(t *testing.T) {
+	h := harness.New(t, "alice", "bob")
+	alice := h.VASP("alice")
+
+	opts := loadtest.Options{
+		Concurrency: 4,
+		Duration:    500 * time.Millisecond,
+		Accounts:    []string{"mary@alicevasp.us", "alice@alicevasp.us", "sarah@alicevasp.us", "nobody@alicevasp.us"},
+		Beneficiaries: []string{
+			"robert@bobvasp.co.uk",
+			"george@bobvasp.co.uk",
+		},
+		Assets: []loadtest.AssetWeight{{AssetType: "Bitcoin", Weight: 3}, {AssetType: "Ethereum", Weight: 1}},
+	}
+
+	report, err := loadtest.Run(context.Background(), alice.Client, nil, opts)
+	require.NoError(t, err)
+	require.NotZero(t, report.Requests)
+	require.NotZero(t, report.Succeeded)
+	require.Greater(t, report.Throughput, 0.0)
+	require.LessOrEqual(t, report.Min, report.P50)
+	require.LessOrEqual(t, report.P50, report.P95)
+	require.LessOrEqual(t, report.P95, report.P99)
+	require.LessOrEqual(t, report.P99, report.Max)
+
+	// Transfers from the unknown account fail with a gRPC error, transfers from the
+	// SendError account and partial transfers to SyncRequire are rejected by TRISA.
+	require.NotZero(t, report.GRPCErrors[codes.NotFound])
+	require.NotZero(t, report.TRISAErrors[90])
+
+	var failed int
+	for _, n := range report.GRPCErrors {
+		failed += n
+	}
+	for _, n := range report.TRISAErrors {
+		failed += n
+	}
+	require.Equal(t, report.Requests, report.Succeeded+failed)
+	require.InDelta(t, float64(report.Succeeded)/report.Duration.Seconds(), report.Throughput, 1e-9, "throughput should only count successful transfers")
+
+	buf := &bytes.Buffer{}
+	report.Print(buf)
+	require.Contains(t, buf.String(), "transfers/sec")
+	require.Contains(t, buf.String(), "NotFound")
+	require.Contains(t, buf.String(), "COMPLIANCE_CHECK_FAIL (90)")
+
+	// Live updates streams should receive the updates broadcast during the transfers
+	opts.Concurrency = 1
+	opts.Streams = 2
+	opts.Accounts = []string{"mary@alicevasp.us"}
+	report, err = loadtest.Run(context.Background(), alice.Client, alice.Demo, opts)
+	require.NoError(t, err)
+	require.NotZero(t, report.Requests)
+	require.Equal(t, 2, report.Streams)
+	require.NotZero(t, report.StreamMessages)
+	require.Zero(t, report.StreamErrors)
+}
+
+func TestValidate(t *testing.T) {
+	opts := &loadtest.Options{Accounts: []string{"mary@alicevasp.us"}}
+	require.EqualError(t, opts.Validate(), "at least one beneficiary is required")
+
+	opts.Beneficiaries = []string{"robert@bobvasp.co.uk"}
+	require.NoError(t, opts.Validate())
+	require.Equal(t, loadtest.DefaultConcurrency, opts.Concurrency)
+	require.Equal(t, loadtest.DefaultDuration, opts.Duration)
+	require.Equal(t, float32(loadtest.DefaultAmount), opts.Amount)
+	require.Equal(t, []loadtest.AssetWeight{{AssetType: loadtest.DefaultAssetType, Weight: 1}}, opts.Assets)
+
+	opts.Streams = -1
+	require.Error(t, opts.Validate())
+
+	_, err := loadtest.Run(context.Background(), nil, nil, loadtest.Options{
+		Accounts:      []string{"mary@alicevasp.us"},
+		Beneficiaries: []string{"robert@bobvasp.co.uk"},
+		Streams:       1,
+	})
+	require.EqualError(t, err, "a demo client is required to open live updates streams")
+}
+
+func TestParseAssetMix(t *testing.T) {
+	assets, err := loadtest.ParseAssetMix("Bitcoin=3, Ethereum=1,Litecoin")
+	require.NoError(t, err)
+	require.Equal(t, []loadtest.AssetWeight{
+		{AssetType: "Bitcoin", Weight: 3},
+		{AssetType: "Ethereum", Weight: 1},
+		{AssetType: "Litecoin", Weight: 1},
+	}, assets)
+
+	for _, mix := range []string{"", "Bitcoin=0", "Bitcoin=x", "=2"} {
+		_, err = loadtest.ParseAssetMix(mix)
+		require.Error(t, err, mix)
+	}
+}
@@ -0,0 +1,135 @@
+package loadtest
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	protocol "github.com/trisacrypto/trisa/pkg/trisa/api/v1beta1"
+	"google.golang.org/grpc/codes"
+)
+
+// Report summarizes the throughput, latency and errors of a load test.
+type Report struct {
+	Duration  time.Duration
+	Requests  int
+	Succeeded int
+
+	// Successful transfers per second over the duration of the test
+	Throughput float64
+
+	// Latency of the transfer requests
+	Min, Mean, Max time.Duration
+	P50, P95, P99  time.Duration
+
+	// Failed requests by gRPC status code and transfers rejected by TRISA error code
+	GRPCErrors  map[codes.Code]int
+	TRISAErrors map[int32]int
+
+	// Live updates streams and the messages they received
+	Streams        int
+	StreamMessages uint64
+	StreamErrors   int
+}
+
+func newReport(elapsed time.Duration, workers []*worker) *Report {
+	report := &Report{
+		Duration:    elapsed,
+		GRPCErrors:  make(map[codes.Code]int),
+		TRISAErrors: make(map[int32]int),
+	}
+
+	var latencies []time.Duration
+	for _, w := range workers {
+		latencies = append(latencies, w.latencies...)
+		for code, n := range w.grpcErrors {
+			report.GRPCErrors[code] += n
+		}
+		for code, n := range w.trisaCodes {
+			report.TRISAErrors[code] += n
+		}
+	}
+
+	report.Requests = len(latencies)
+	report.Succeeded = report.Requests
+	for _, n := range report.GRPCErrors {
+		report.Succeeded -= n
+	}
+	for _, n := range report.TRISAErrors {
+		report.Succeeded -= n
+	}
+
+	if elapsed > 0 {
+		report.Throughput = float64(report.Succeeded) / elapsed.Seconds()
+	}
+
+	if len(latencies) > 0 {
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+		var total time.Duration
+		for _, latency := range latencies {
+			total += latency
+		}
+
+		report.Min = latencies[0]
+		report.Max = latencies[len(latencies)-1]
+		report.Mean = total / time.Duration(len(latencies))
+		report.P50 = percentile(latencies, 50)
+		report.P95 = percentile(latencies, 95)
+		report.P99 = percentile(latencies, 99)
+	}
+	return report
+}
+
+// percentile returns the nearest-rank percentile of the sorted latencies.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// Print a human readable summary of the report.
+func (r *Report) Print(w io.Writer) {
+	fmt.Fprintf(w, "duration:    %s\n", r.Duration.Round(time.Millisecond))
+	fmt.Fprintf(w, "requests:    %d (%d succeeded, %d failed)\n", r.Requests, r.Succeeded, r.Requests-r.Succeeded)
+	fmt.Fprintf(w, "throughput:  %.2f transfers/sec\n", r.Throughput)
+	fmt.Fprintf(w, "latency:     min %s, mean %s, max %s\n", round(r.Min), round(r.Mean), round(r.Max))
+	fmt.Fprintf(w, "percentiles: p50 %s, p95 %s, p99 %s\n", round(r.P50), round(r.P95), round(r.P99))
+
+	if len(r.GRPCErrors) > 0 {
+		fmt.Fprintln(w, "gRPC errors:")
+		grpcCodes := make([]codes.Code, 0, len(r.GRPCErrors))
+		for code := range r.GRPCErrors {
+			grpcCodes = append(grpcCodes, code)
+		}
+		sort.Slice(grpcCodes, func(i, j int) bool { return grpcCodes[i] < grpcCodes[j] })
+
+		for _, code := range grpcCodes {
+			fmt.Fprintf(w, "  %-30s %d\n", code, r.GRPCErrors[code])
+		}
+	}
+
+	if len(r.TRISAErrors) > 0 {
+		fmt.Fprintln(w, "TRISA errors:")
+		trisaCodes := make([]int32, 0, len(r.TRISAErrors))
+		for code := range r.TRISAErrors {
+			trisaCodes = append(trisaCodes, code)
+		}
+		sort.Slice(trisaCodes, func(i, j int) bool { return trisaCodes[i] < trisaCodes[j] })
+
+		for _, code := range trisaCodes {
+			fmt.Fprintf(w, "  %-30s %d\n", fmt.Sprintf("%s (%d)", protocol.Error_Code(code), code), r.TRISAErrors[code])
+		}
+	}
+
+	if r.Streams > 0 {
+		fmt.Fprintf(w, "streams:     %d (%d messages received, %d errors)\n", r.Streams, r.StreamMessages, r.StreamErrors)
+	}
+}
+
+func round(d time.Duration) time.Duration {
+	return d.Round(10 * time.Microsecond)
+}
@@ -56,7 +56,7 @@ func NewServerMock(conf *config.Config) (s *Server, mockDB sqlmock.Sqlmock, err
 		return nil, nil, err
 	}
 	s.vasp = s.db.GetVASP()
-	s.updates = NewUpdateManager()
+	s.updates = NewUpdateManager(s.conf.Updates)
 	return s, mockDB, nil
 }
 
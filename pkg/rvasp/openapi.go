@@ -0,0 +1,214 @@
+package rvasp
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/trisacrypto/testnet/pkg"
+	pb "github.com/trisacrypto/testnet/pkg/rvasp/pb/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// OpenAPI returns the OpenAPI 3 document of the REST API of the HTTP gateway. The
+// schemas are generated from the descriptors of api.proto so that the document always
+// matches the JSON encoding of the messages used by the gateway.
+func OpenAPI() map[string]interface{} {
+	service := pb.File_rvasp_v1_api_proto.Services().ByName("TRISAIntegration")
+	gen := &openAPIGenerator{schemas: make(map[string]interface{})}
+
+	paths := make(map[string]map[string]interface{})
+	for _, route := range Routes {
+		method := service.Methods().ByName(protoreflect.Name(route.RPC))
+		if method == nil {
+			log.Warn().Str("rpc", route.RPC).Msg("no rpc for gateway route")
+			continue
+		}
+
+		op := map[string]interface{}{
+			"operationId": route.RPC,
+			"summary":     route.Summary,
+			"responses": map[string]interface{}{
+				"200": jsonContent("Successful reply", gen.ref(method.Output())),
+				"default": jsonContent("The gRPC status of the failed request; the HTTP status code is translated from the gRPC code",
+					map[string]interface{}{"$ref": "#/components/schemas/Status"}),
+			},
+		}
+
+		if route.Body {
+			op["requestBody"] = map[string]interface{}{
+				"required": true,
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{"schema": gen.ref(method.Input())},
+				},
+			}
+		}
+
+		// Path parameters are required, other scalar fields may be passed as query
+		// parameters unless the request has a body.
+		params := make([]interface{}, 0)
+		fields := method.Input().Fields()
+		for i := 0; i < fields.Len(); i++ {
+			fd := fields.Get(i)
+			if fd.IsList() || fd.IsMap() || fd.Kind() == protoreflect.MessageKind || fd.Kind() == protoreflect.GroupKind {
+				continue
+			}
+
+			in := "query"
+			if strings.Contains(route.Path, "{"+string(fd.Name())+"}") {
+				in = "path"
+			} else if route.Body {
+				continue
+			}
+
+			params = append(params, map[string]interface{}{
+				"name":     string(fd.Name()),
+				"in":       in,
+				"required": in == "path",
+				"schema":   gen.field(fd),
+			})
+		}
+
+		if len(params) > 0 {
+			op["parameters"] = params
+		}
+
+		if _, ok := paths[route.Path]; !ok {
+			paths[route.Path] = make(map[string]interface{})
+		}
+		paths[route.Path][strings.ToLower(route.Method)] = op
+	}
+
+	gen.schemas["Status"] = map[string]interface{}{
+		"type":        "object",
+		"description": "gRPC status of a failed request",
+		"properties": map[string]interface{}{
+			"code":    map[string]interface{}{"type": "integer", "format": "int32", "description": "gRPC status code"},
+			"message": map[string]interface{}{"type": "string"},
+			"details": map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "object"}},
+		},
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":       "rVASP TRISA Integration API",
+			"description": "REST API of the TRISAIntegration service of the rVASP, generated from api.proto",
+			"version":     pkg.Version(),
+		},
+		"paths": paths,
+		"components": map[string]interface{}{
+			"schemas": gen.schemas,
+		},
+	}
+}
+
+// serveOpenAPI writes the OpenAPI document of the REST API.
+func (s *Server) serveOpenAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeError(w, http.StatusMethodNotAllowed, status.Errorf(codes.Unimplemented, "method %s not allowed", r.Method))
+		return
+	}
+
+	data, err := json.MarshalIndent(OpenAPI(), "", "  ")
+	if err != nil {
+		log.Error().Err(err).Msg("could not marshal openapi document")
+		writeError(w, http.StatusInternalServerError, status.Error(codes.Internal, "could not marshal openapi document"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+// openAPIGenerator collects the schemas of the messages and enums referenced by the API.
+type openAPIGenerator struct {
+	schemas map[string]interface{}
+}
+
+// ref returns a reference to the schema of the message, generating it if necessary.
+func (g *openAPIGenerator) ref(md protoreflect.MessageDescriptor) map[string]interface{} {
+	name := schemaName(md.FullName())
+	if _, ok := g.schemas[name]; !ok {
+		// Reserve the name before generating the properties for recursive messages
+		g.schemas[name] = nil
+
+		properties := make(map[string]interface{})
+		fields := md.Fields()
+		for i := 0; i < fields.Len(); i++ {
+			fd := fields.Get(i)
+			properties[string(fd.Name())] = g.field(fd)
+		}
+		g.schemas[name] = map[string]interface{}{"type": "object", "properties": properties}
+	}
+	return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+}
+
+// field returns the schema of the field using the protojson encoding of its kind.
+func (g *openAPIGenerator) field(fd protoreflect.FieldDescriptor) map[string]interface{} {
+	if fd.IsMap() {
+		return map[string]interface{}{"type": "object", "additionalProperties": g.value(fd.MapValue())}
+	}
+
+	if fd.IsList() {
+		return map[string]interface{}{"type": "array", "items": g.value(fd)}
+	}
+	return g.value(fd)
+}
+
+// value returns the schema of a single value of the field.
+func (g *openAPIGenerator) value(fd protoreflect.FieldDescriptor) map[string]interface{} {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return map[string]interface{}{"type": "string"}
+	case protoreflect.BytesKind:
+		return map[string]interface{}{"type": "string", "format": "byte"}
+	case protoreflect.BoolKind:
+		return map[string]interface{}{"type": "boolean"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return map[string]interface{}{"type": "integer", "format": "int32"}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return map[string]interface{}{"type": "integer", "format": "int64", "minimum": 0}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		// protojson encodes 64 bit integers as strings
+		return map[string]interface{}{"type": "string", "format": "int64"}
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return map[string]interface{}{"type": "string", "format": "uint64"}
+	case protoreflect.FloatKind:
+		return map[string]interface{}{"type": "number", "format": "float"}
+	case protoreflect.DoubleKind:
+		return map[string]interface{}{"type": "number", "format": "double"}
+	case protoreflect.EnumKind:
+		ed := fd.Enum()
+		name := schemaName(ed.FullName())
+		if _, ok := g.schemas[name]; !ok {
+			values := make([]string, 0, ed.Values().Len())
+			for i := 0; i < ed.Values().Len(); i++ {
+				values = append(values, string(ed.Values().Get(i).Name()))
+			}
+			g.schemas[name] = map[string]interface{}{"type": "string", "enum": values}
+		}
+		return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+	default:
+		return g.ref(fd.Message())
+	}
+}
+
+// schemaName strips the rvasp.v1 package from the name of messages and enums.
+func schemaName(name protoreflect.FullName) string {
+	return strings.TrimPrefix(string(name), string(pb.File_rvasp_v1_api_proto.Package())+".")
+}
+
+func jsonContent(description string, schema map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"description": description,
+		"content": map[string]interface{}{
+			"application/json": map[string]interface{}{"schema": schema},
+		},
+	}
+}
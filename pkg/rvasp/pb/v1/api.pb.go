@@ -98,9 +98,11 @@ func (TransactionState) EnumDescriptor() ([]byte, []int) {
 type RPC int32
 
 const (
-	RPC_NORPC    RPC = 0
-	RPC_TRANSFER RPC = 1
-	RPC_ACCOUNT  RPC = 2
+	RPC_NORPC     RPC = 0
+	RPC_TRANSFER  RPC = 1
+	RPC_ACCOUNT   RPC = 2
+	RPC_SUBSCRIBE RPC = 3
+	RPC_REPLAY    RPC = 4
 )
 
 // Enum value maps for RPC.
@@ -109,11 +111,15 @@ var (
 		0: "NORPC",
 		1: "TRANSFER",
 		2: "ACCOUNT",
+		3: "SUBSCRIBE",
+		4: "REPLAY",
 	}
 	RPC_value = map[string]int32{
-		"NORPC":    0,
-		"TRANSFER": 1,
-		"ACCOUNT":  2,
+		"NORPC":     0,
+		"TRANSFER":  1,
+		"ACCOUNT":   2,
+		"SUBSCRIBE": 3,
+		"REPLAY":    4,
 	}
 )
 
@@ -252,7 +258,7 @@ func (x ServerStatus_Status) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use ServerStatus_Status.Descriptor instead.
 func (ServerStatus_Status) EnumDescriptor() ([]byte, []int) {
-	return file_rvasp_v1_api_proto_rawDescGZIP(), []int{10, 0}
+	return file_rvasp_v1_api_proto_rawDescGZIP(), []int{20, 0}
 }
 
 // Allows for standardized error handling for demo purposes.
@@ -384,14 +390,15 @@ type Transaction struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Originator  *Account         `protobuf:"bytes,1,opt,name=originator,proto3" json:"originator,omitempty"`                       // Source described by wallet address or email of originator
-	Beneficiary *Account         `protobuf:"bytes,2,opt,name=beneficiary,proto3" json:"beneficiary,omitempty"`                     // Target described by wallet address or email of beneficiary
-	Amount      float32          `protobuf:"fixed32,3,opt,name=amount,proto3" json:"amount,omitempty"`                             // amount of the transaction
-	Timestamp   string           `protobuf:"bytes,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`                         // timestamp of completion on the account provider side
-	EnvelopeId  string           `protobuf:"bytes,5,opt,name=envelope_id,json=envelopeId,proto3" json:"envelope_id,omitempty"`     // envelope ID from TRISA (not included between TRISA peers)
-	Identity    string           `protobuf:"bytes,6,opt,name=identity,proto3" json:"identity,omitempty"`                           // identity payload from TRISA (not included between TRISA peers)
-	State       TransactionState `protobuf:"varint,7,opt,name=state,proto3,enum=rvasp.v1.TransactionState" json:"state,omitempty"` // state of the transaction
-	AssetType   string           `protobuf:"bytes,8,opt,name=asset_type,json=assetType,proto3" json:"asset_type,omitempty"`        // the type of virtual asset (for example, "Bitcoin")
+	Originator  *Account           `protobuf:"bytes,1,opt,name=originator,proto3" json:"originator,omitempty"`                       // Source described by wallet address or email of originator
+	Beneficiary *Account           `protobuf:"bytes,2,opt,name=beneficiary,proto3" json:"beneficiary,omitempty"`                     // Target described by wallet address or email of beneficiary
+	Amount      float32            `protobuf:"fixed32,3,opt,name=amount,proto3" json:"amount,omitempty"`                             // amount of the transaction
+	Timestamp   string             `protobuf:"bytes,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`                         // timestamp of completion on the account provider side
+	EnvelopeId  string             `protobuf:"bytes,5,opt,name=envelope_id,json=envelopeId,proto3" json:"envelope_id,omitempty"`     // envelope ID from TRISA (not included between TRISA peers)
+	Identity    string             `protobuf:"bytes,6,opt,name=identity,proto3" json:"identity,omitempty"`                           // identity payload from TRISA (not included between TRISA peers)
+	State       TransactionState   `protobuf:"varint,7,opt,name=state,proto3,enum=rvasp.v1.TransactionState" json:"state,omitempty"` // state of the transaction
+	AssetType   string             `protobuf:"bytes,8,opt,name=asset_type,json=assetType,proto3" json:"asset_type,omitempty"`        // the type of virtual asset (for example, "Bitcoin")
+	History     []*StateTransition `protobuf:"bytes,9,rep,name=history,proto3" json:"history,omitempty"`                             // state transitions of the transaction (only populated on request)
 }
 
 func (x *Transaction) Reset() {
@@ -482,6 +489,93 @@ func (x *Transaction) GetAssetType() string {
 	return ""
 }
 
+func (x *Transaction) GetHistory() []*StateTransition {
+	if x != nil {
+		return x.History
+	}
+	return nil
+}
+
+// Describes a change in the state of a transaction, what caused it and when.
+type StateTransition struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Previous  TransactionState `protobuf:"varint,1,opt,name=previous,proto3,enum=rvasp.v1.TransactionState" json:"previous,omitempty"` // state of the transaction before the transition
+	State     TransactionState `protobuf:"varint,2,opt,name=state,proto3,enum=rvasp.v1.TransactionState" json:"state,omitempty"`       // state of the transaction after the transition
+	Actor     string           `protobuf:"bytes,3,opt,name=actor,proto3" json:"actor,omitempty"`                                       // what caused the transition: integration, peer or async
+	Reason    string           `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`                                     // why the transition occurred, e.g. the error that failed the transaction
+	Timestamp string           `protobuf:"bytes,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`                               // RFC3339 timestamp of the transition
+}
+
+func (x *StateTransition) Reset() {
+	*x = StateTransition{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rvasp_v1_api_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StateTransition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StateTransition) ProtoMessage() {}
+
+func (x *StateTransition) ProtoReflect() protoreflect.Message {
+	mi := &file_rvasp_v1_api_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StateTransition.ProtoReflect.Descriptor instead.
+func (*StateTransition) Descriptor() ([]byte, []int) {
+	return file_rvasp_v1_api_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *StateTransition) GetPrevious() TransactionState {
+	if x != nil {
+		return x.Previous
+	}
+	return TransactionState_INVALID
+}
+
+func (x *StateTransition) GetState() TransactionState {
+	if x != nil {
+		return x.State
+	}
+	return TransactionState_INVALID
+}
+
+func (x *StateTransition) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *StateTransition) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *StateTransition) GetTimestamp() string {
+	if x != nil {
+		return x.Timestamp
+	}
+	return ""
+}
+
 // Initiates a transfer from the specified account to the specified wallet address or
 // email address for a known wallet at some other rVASP.
 type TransferRequest struct {
@@ -501,7 +595,7 @@ type TransferRequest struct {
 func (x *TransferRequest) Reset() {
 	*x = TransferRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_rvasp_v1_api_proto_msgTypes[3]
+		mi := &file_rvasp_v1_api_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -514,7 +608,7 @@ func (x *TransferRequest) String() string {
 func (*TransferRequest) ProtoMessage() {}
 
 func (x *TransferRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_rvasp_v1_api_proto_msgTypes[3]
+	mi := &file_rvasp_v1_api_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -527,7 +621,7 @@ func (x *TransferRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TransferRequest.ProtoReflect.Descriptor instead.
 func (*TransferRequest) Descriptor() ([]byte, []int) {
-	return file_rvasp_v1_api_proto_rawDescGZIP(), []int{3}
+	return file_rvasp_v1_api_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *TransferRequest) GetAccount() string {
@@ -594,7 +688,7 @@ type TransferReply struct {
 func (x *TransferReply) Reset() {
 	*x = TransferReply{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_rvasp_v1_api_proto_msgTypes[4]
+		mi := &file_rvasp_v1_api_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -607,7 +701,7 @@ func (x *TransferReply) String() string {
 func (*TransferReply) ProtoMessage() {}
 
 func (x *TransferReply) ProtoReflect() protoreflect.Message {
-	mi := &file_rvasp_v1_api_proto_msgTypes[4]
+	mi := &file_rvasp_v1_api_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -620,7 +714,7 @@ func (x *TransferReply) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TransferReply.ProtoReflect.Descriptor instead.
 func (*TransferReply) Descriptor() ([]byte, []int) {
-	return file_rvasp_v1_api_proto_rawDescGZIP(), []int{4}
+	return file_rvasp_v1_api_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *TransferReply) GetError() *Error {
@@ -649,12 +743,13 @@ type AccountRequest struct {
 	NoTransactions bool   `protobuf:"varint,2,opt,name=no_transactions,json=noTransactions,proto3" json:"no_transactions,omitempty"` // do not return list of transactions, just status info.
 	Page           uint32 `protobuf:"varint,3,opt,name=page,proto3" json:"page,omitempty"`                                           // not implemented yet
 	PerPage        uint32 `protobuf:"varint,4,opt,name=per_page,json=perPage,proto3" json:"per_page,omitempty"`                      // not implemented yet
+	History        bool   `protobuf:"varint,5,opt,name=history,proto3" json:"history,omitempty"`                                     // include the state transition history of each transaction.
 }
 
 func (x *AccountRequest) Reset() {
 	*x = AccountRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_rvasp_v1_api_proto_msgTypes[5]
+		mi := &file_rvasp_v1_api_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -667,7 +762,7 @@ func (x *AccountRequest) String() string {
 func (*AccountRequest) ProtoMessage() {}
 
 func (x *AccountRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_rvasp_v1_api_proto_msgTypes[5]
+	mi := &file_rvasp_v1_api_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -680,7 +775,7 @@ func (x *AccountRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AccountRequest.ProtoReflect.Descriptor instead.
 func (*AccountRequest) Descriptor() ([]byte, []int) {
-	return file_rvasp_v1_api_proto_rawDescGZIP(), []int{5}
+	return file_rvasp_v1_api_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *AccountRequest) GetAccount() string {
@@ -711,6 +806,13 @@ func (x *AccountRequest) GetPerPage() uint32 {
 	return 0
 }
 
+func (x *AccountRequest) GetHistory() bool {
+	if x != nil {
+		return x.History
+	}
+	return false
+}
+
 // Returns the account information and balance as well as transactions ordered from
 // most to least recent. An error is returned if the account cannot be found.
 type AccountReply struct {
@@ -731,7 +833,7 @@ type AccountReply struct {
 func (x *AccountReply) Reset() {
 	*x = AccountReply{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_rvasp_v1_api_proto_msgTypes[6]
+		mi := &file_rvasp_v1_api_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -744,7 +846,7 @@ func (x *AccountReply) String() string {
 func (*AccountReply) ProtoMessage() {}
 
 func (x *AccountReply) ProtoReflect() protoreflect.Message {
-	mi := &file_rvasp_v1_api_proto_msgTypes[6]
+	mi := &file_rvasp_v1_api_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -757,7 +859,7 @@ func (x *AccountReply) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AccountReply.ProtoReflect.Descriptor instead.
 func (*AccountReply) Descriptor() ([]byte, []int) {
-	return file_rvasp_v1_api_proto_rawDescGZIP(), []int{6}
+	return file_rvasp_v1_api_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *AccountReply) GetError() *Error {
@@ -816,42 +918,32 @@ func (x *AccountReply) GetTransactions() []*Transaction {
 	return nil
 }
 
-// A wrapper for the TransferRequet and AccountRequest RPCs to be sent via streaming.
-type Command struct {
+// Transaction history request is used to fetch the state transitions of a transaction.
+type TransactionHistoryRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Type   RPC    `protobuf:"varint,1,opt,name=type,proto3,enum=rvasp.v1.RPC" json:"type,omitempty"` // what type of command is being sent to the rVASP
-	Id     uint64 `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`                       // client side message id for req/rep tracking
-	Client string `protobuf:"bytes,3,opt,name=client,proto3" json:"client,omitempty"`                // unique ID so the rVASP can correctly dispatch messages
-	// only one of these fields can be set, and the field that is set should
-	// match the RPC type described above.
-	//
-	// Types that are assignable to Request:
-	//
-	//	*Command_Transfer
-	//	*Command_Account
-	Request isCommand_Request `protobuf_oneof:"request"`
+	EnvelopeId string `protobuf:"bytes,1,opt,name=envelope_id,json=envelopeId,proto3" json:"envelope_id,omitempty"` // envelope ID of the transaction
 }
 
-func (x *Command) Reset() {
-	*x = Command{}
+func (x *TransactionHistoryRequest) Reset() {
+	*x = TransactionHistoryRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_rvasp_v1_api_proto_msgTypes[7]
+		mi := &file_rvasp_v1_api_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *Command) String() string {
+func (x *TransactionHistoryRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Command) ProtoMessage() {}
+func (*TransactionHistoryRequest) ProtoMessage() {}
 
-func (x *Command) ProtoReflect() protoreflect.Message {
-	mi := &file_rvasp_v1_api_proto_msgTypes[7]
+func (x *TransactionHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rvasp_v1_api_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -862,111 +954,98 @@ func (x *Command) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Command.ProtoReflect.Descriptor instead.
-func (*Command) Descriptor() ([]byte, []int) {
-	return file_rvasp_v1_api_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use TransactionHistoryRequest.ProtoReflect.Descriptor instead.
+func (*TransactionHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_rvasp_v1_api_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *Command) GetType() RPC {
+func (x *TransactionHistoryRequest) GetEnvelopeId() string {
 	if x != nil {
-		return x.Type
+		return x.EnvelopeId
 	}
-	return RPC_NORPC
+	return ""
 }
 
-func (x *Command) GetId() uint64 {
-	if x != nil {
-		return x.Id
-	}
-	return 0
-}
+// Returns the transaction with its state transitions ordered from oldest to most
+// recent. An error is returned if the transaction cannot be found.
+type TransactionHistoryReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-func (x *Command) GetClient() string {
-	if x != nil {
-		return x.Client
-	}
-	return ""
+	Transaction *Transaction `protobuf:"bytes,1,opt,name=transaction,proto3" json:"transaction,omitempty"`
 }
 
-func (m *Command) GetRequest() isCommand_Request {
-	if m != nil {
-		return m.Request
+func (x *TransactionHistoryReply) Reset() {
+	*x = TransactionHistoryReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rvasp_v1_api_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return nil
 }
 
-func (x *Command) GetTransfer() *TransferRequest {
-	if x, ok := x.GetRequest().(*Command_Transfer); ok {
-		return x.Transfer
-	}
-	return nil
+func (x *TransactionHistoryReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *Command) GetAccount() *AccountRequest {
-	if x, ok := x.GetRequest().(*Command_Account); ok {
-		return x.Account
-	}
-	return nil
-}
+func (*TransactionHistoryReply) ProtoMessage() {}
 
-type isCommand_Request interface {
-	isCommand_Request()
+func (x *TransactionHistoryReply) ProtoReflect() protoreflect.Message {
+	mi := &file_rvasp_v1_api_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-type Command_Transfer struct {
-	Transfer *TransferRequest `protobuf:"bytes,11,opt,name=transfer,proto3,oneof"`
+// Deprecated: Use TransactionHistoryReply.ProtoReflect.Descriptor instead.
+func (*TransactionHistoryReply) Descriptor() ([]byte, []int) {
+	return file_rvasp_v1_api_proto_rawDescGZIP(), []int{9}
 }
 
-type Command_Account struct {
-	Account *AccountRequest `protobuf:"bytes,12,opt,name=account,proto3,oneof"`
+func (x *TransactionHistoryReply) GetTransaction() *Transaction {
+	if x != nil {
+		return x.Transaction
+	}
+	return nil
 }
 
-func (*Command_Transfer) isCommand_Request() {}
-
-func (*Command_Account) isCommand_Request() {}
-
-// Message is either a wrapper for a TransferReply or AccountReply RPCs or it is a live
-// update message sent from the rVASP to show the communication interactions of the
-// InterVASP protocol. If it is a wrapper, then type will be > 0 and the ID will match
-// the id of the command request sent by the client. Otherwise both of these fields will
-// be zero and the update string will be populated.
-type Message struct {
+// A webhook receives a signed JSON event whenever the state of a transaction changes,
+// either for every account of the rVASP or only for the specified account.
+type Webhook struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Type      RPC             `protobuf:"varint,1,opt,name=type,proto3,enum=rvasp.v1.RPC" json:"type,omitempty"`
-	Id        uint64          `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
-	Update    string          `protobuf:"bytes,3,opt,name=update,proto3" json:"update,omitempty"`
-	Timestamp string          `protobuf:"bytes,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
-	Category  MessageCategory `protobuf:"varint,5,opt,name=category,proto3,enum=rvasp.v1.MessageCategory" json:"category,omitempty"`
-	// if type and id are greater than zero, one of these fields will be set, matching
-	// the RPC type described above.
-	//
-	// Types that are assignable to Reply:
-	//
-	//	*Message_Transfer
-	//	*Message_Account
-	Reply isMessage_Reply `protobuf_oneof:"reply"`
+	Id      uint64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`          // assigned by the rVASP on registration, required to delete the webhook
+	Url     string `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`         // URL that events are posted to
+	Account string `protobuf:"bytes,3,opt,name=account,proto3" json:"account,omitempty"` // email address or wallet address of the account (optional, all accounts by default)
+	Secret  string `protobuf:"bytes,4,opt,name=secret,proto3" json:"secret,omitempty"`   // secret used to sign events, generated if not specified (only returned on registration)
+	Created string `protobuf:"bytes,5,opt,name=created,proto3" json:"created,omitempty"` // RFC3339 timestamp of the registration
 }
 
-func (x *Message) Reset() {
-	*x = Message{}
+func (x *Webhook) Reset() {
+	*x = Webhook{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_rvasp_v1_api_proto_msgTypes[8]
+		mi := &file_rvasp_v1_api_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *Message) String() string {
+func (x *Webhook) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Message) ProtoMessage() {}
+func (*Webhook) ProtoMessage() {}
 
-func (x *Message) ProtoReflect() protoreflect.Message {
-	mi := &file_rvasp_v1_api_proto_msgTypes[8]
+func (x *Webhook) ProtoReflect() protoreflect.Message {
+	mi := &file_rvasp_v1_api_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -977,106 +1056,972 @@ func (x *Message) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Message.ProtoReflect.Descriptor instead.
-func (*Message) Descriptor() ([]byte, []int) {
-	return file_rvasp_v1_api_proto_rawDescGZIP(), []int{8}
-}
-
-func (x *Message) GetType() RPC {
-	if x != nil {
-		return x.Type
-	}
-	return RPC_NORPC
+// Deprecated: Use Webhook.ProtoReflect.Descriptor instead.
+func (*Webhook) Descriptor() ([]byte, []int) {
+	return file_rvasp_v1_api_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *Message) GetId() uint64 {
+func (x *Webhook) GetId() uint64 {
 	if x != nil {
 		return x.Id
 	}
 	return 0
 }
 
-func (x *Message) GetUpdate() string {
+func (x *Webhook) GetUrl() string {
 	if x != nil {
-		return x.Update
+		return x.Url
 	}
 	return ""
 }
 
-func (x *Message) GetTimestamp() string {
+func (x *Webhook) GetAccount() string {
 	if x != nil {
-		return x.Timestamp
+		return x.Account
 	}
 	return ""
 }
 
-func (x *Message) GetCategory() MessageCategory {
+func (x *Webhook) GetSecret() string {
 	if x != nil {
-		return x.Category
+		return x.Secret
 	}
-	return MessageCategory_LEDGER
+	return ""
 }
 
-func (m *Message) GetReply() isMessage_Reply {
-	if m != nil {
-		return m.Reply
+func (x *Webhook) GetCreated() string {
+	if x != nil {
+		return x.Created
 	}
-	return nil
+	return ""
 }
 
-func (x *Message) GetTransfer() *TransferReply {
-	if x, ok := x.GetReply().(*Message_Transfer); ok {
-		return x.Transfer
-	}
-	return nil
+// Returns the webhooks registered with the rVASP.
+type WebhookList struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Webhooks []*Webhook `protobuf:"bytes,1,rep,name=webhooks,proto3" json:"webhooks,omitempty"`
 }
 
-func (x *Message) GetAccount() *AccountReply {
-	if x, ok := x.GetReply().(*Message_Account); ok {
-		return x.Account
+func (x *WebhookList) Reset() {
+	*x = WebhookList{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rvasp_v1_api_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return nil
 }
 
-type isMessage_Reply interface {
-	isMessage_Reply()
+func (x *WebhookList) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-type Message_Transfer struct {
-	Transfer *TransferReply `protobuf:"bytes,11,opt,name=transfer,proto3,oneof"`
-}
+func (*WebhookList) ProtoMessage() {}
+
+func (x *WebhookList) ProtoReflect() protoreflect.Message {
+	mi := &file_rvasp_v1_api_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WebhookList.ProtoReflect.Descriptor instead.
+func (*WebhookList) Descriptor() ([]byte, []int) {
+	return file_rvasp_v1_api_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *WebhookList) GetWebhooks() []*Webhook {
+	if x != nil {
+		return x.Webhooks
+	}
+	return nil
+}
+
+// Webhook deliveries request is used to fetch the delivery log, optionally filtered by
+// webhook or by the envelope ID of the transaction.
+type WebhookDeliveriesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	WebhookId  uint64 `protobuf:"varint,1,opt,name=webhook_id,json=webhookId,proto3" json:"webhook_id,omitempty"`   // only return the deliveries to the webhook (optional)
+	EnvelopeId string `protobuf:"bytes,2,opt,name=envelope_id,json=envelopeId,proto3" json:"envelope_id,omitempty"` // only return the deliveries for the transaction (optional)
+}
+
+func (x *WebhookDeliveriesRequest) Reset() {
+	*x = WebhookDeliveriesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rvasp_v1_api_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WebhookDeliveriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WebhookDeliveriesRequest) ProtoMessage() {}
+
+func (x *WebhookDeliveriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rvasp_v1_api_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WebhookDeliveriesRequest.ProtoReflect.Descriptor instead.
+func (*WebhookDeliveriesRequest) Descriptor() ([]byte, []int) {
+	return file_rvasp_v1_api_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *WebhookDeliveriesRequest) GetWebhookId() uint64 {
+	if x != nil {
+		return x.WebhookId
+	}
+	return 0
+}
+
+func (x *WebhookDeliveriesRequest) GetEnvelopeId() string {
+	if x != nil {
+		return x.EnvelopeId
+	}
+	return ""
+}
+
+// Describes an attempt to deliver an event to a webhook.
+type WebhookDelivery struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	WebhookId  uint64           `protobuf:"varint,1,opt,name=webhook_id,json=webhookId,proto3" json:"webhook_id,omitempty"`
+	EventId    string           `protobuf:"bytes,2,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`              // unique ID of the event, the same for every attempt
+	EnvelopeId string           `protobuf:"bytes,3,opt,name=envelope_id,json=envelopeId,proto3" json:"envelope_id,omitempty"`     // envelope ID of the transaction
+	State      TransactionState `protobuf:"varint,4,opt,name=state,proto3,enum=rvasp.v1.TransactionState" json:"state,omitempty"` // state of the transaction in the event
+	Attempt    uint32           `protobuf:"varint,5,opt,name=attempt,proto3" json:"attempt,omitempty"`                            // attempts are numbered from 1
+	StatusCode int32            `protobuf:"varint,6,opt,name=status_code,json=statusCode,proto3" json:"status_code,omitempty"`    // HTTP status code returned by the webhook, if any
+	Error      string           `protobuf:"bytes,7,opt,name=error,proto3" json:"error,omitempty"`                                 // why the attempt failed, empty if delivered
+	Delivered  bool             `protobuf:"varint,8,opt,name=delivered,proto3" json:"delivered,omitempty"`
+	Timestamp  string           `protobuf:"bytes,9,opt,name=timestamp,proto3" json:"timestamp,omitempty"` // RFC3339 timestamp of the attempt
+}
+
+func (x *WebhookDelivery) Reset() {
+	*x = WebhookDelivery{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rvasp_v1_api_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WebhookDelivery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WebhookDelivery) ProtoMessage() {}
+
+func (x *WebhookDelivery) ProtoReflect() protoreflect.Message {
+	mi := &file_rvasp_v1_api_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WebhookDelivery.ProtoReflect.Descriptor instead.
+func (*WebhookDelivery) Descriptor() ([]byte, []int) {
+	return file_rvasp_v1_api_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *WebhookDelivery) GetWebhookId() uint64 {
+	if x != nil {
+		return x.WebhookId
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetEnvelopeId() string {
+	if x != nil {
+		return x.EnvelopeId
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetState() TransactionState {
+	if x != nil {
+		return x.State
+	}
+	return TransactionState_INVALID
+}
+
+func (x *WebhookDelivery) GetAttempt() uint32 {
+	if x != nil {
+		return x.Attempt
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetStatusCode() int32 {
+	if x != nil {
+		return x.StatusCode
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetDelivered() bool {
+	if x != nil {
+		return x.Delivered
+	}
+	return false
+}
+
+func (x *WebhookDelivery) GetTimestamp() string {
+	if x != nil {
+		return x.Timestamp
+	}
+	return ""
+}
+
+// Returns the delivery attempts ordered from oldest to most recent.
+type WebhookDeliveriesReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Deliveries []*WebhookDelivery `protobuf:"bytes,1,rep,name=deliveries,proto3" json:"deliveries,omitempty"`
+}
+
+func (x *WebhookDeliveriesReply) Reset() {
+	*x = WebhookDeliveriesReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rvasp_v1_api_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WebhookDeliveriesReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WebhookDeliveriesReply) ProtoMessage() {}
+
+func (x *WebhookDeliveriesReply) ProtoReflect() protoreflect.Message {
+	mi := &file_rvasp_v1_api_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WebhookDeliveriesReply.ProtoReflect.Descriptor instead.
+func (*WebhookDeliveriesReply) Descriptor() ([]byte, []int) {
+	return file_rvasp_v1_api_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *WebhookDeliveriesReply) GetDeliveries() []*WebhookDelivery {
+	if x != nil {
+		return x.Deliveries
+	}
+	return nil
+}
+
+// Limits the live updates sent to a stream to the updates about the specified accounts
+// or transactions, and to the specified message categories. Updates about any of the
+// accounts or envelopes are sent; if neither is specified then updates about all
+// transactions are sent. If no categories are specified, all categories are sent.
+type Subscription struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Accounts    []string          `protobuf:"bytes,1,rep,name=accounts,proto3" json:"accounts,omitempty"`                                           // email or wallet addresses of the accounts
+	EnvelopeIds []string          `protobuf:"bytes,2,rep,name=envelope_ids,json=envelopeIds,proto3" json:"envelope_ids,omitempty"`                  // envelope IDs of the transactions
+	Categories  []MessageCategory `protobuf:"varint,3,rep,packed,name=categories,proto3,enum=rvasp.v1.MessageCategory" json:"categories,omitempty"` // categories of the updates
+}
+
+func (x *Subscription) Reset() {
+	*x = Subscription{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rvasp_v1_api_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Subscription) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Subscription) ProtoMessage() {}
+
+func (x *Subscription) ProtoReflect() protoreflect.Message {
+	mi := &file_rvasp_v1_api_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Subscription.ProtoReflect.Descriptor instead.
+func (*Subscription) Descriptor() ([]byte, []int) {
+	return file_rvasp_v1_api_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *Subscription) GetAccounts() []string {
+	if x != nil {
+		return x.Accounts
+	}
+	return nil
+}
+
+func (x *Subscription) GetEnvelopeIds() []string {
+	if x != nil {
+		return x.EnvelopeIds
+	}
+	return nil
+}
+
+func (x *Subscription) GetCategories() []MessageCategory {
+	if x != nil {
+		return x.Categories
+	}
+	return nil
+}
+
+// Requests the live updates that were broadcast since a sequence number or timestamp
+// and that are still buffered by the rVASP. Only updates that match the subscription of
+// the stream are replayed; if both fields are zero, all buffered updates are replayed.
+type Replay struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SinceSequence  uint64 `protobuf:"varint,1,opt,name=since_sequence,json=sinceSequence,proto3" json:"since_sequence,omitempty"`   // replay updates with a greater sequence number
+	SinceTimestamp string `protobuf:"bytes,2,opt,name=since_timestamp,json=sinceTimestamp,proto3" json:"since_timestamp,omitempty"` // RFC3339 timestamp, replay updates sent at or after it
+}
+
+func (x *Replay) Reset() {
+	*x = Replay{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rvasp_v1_api_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Replay) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Replay) ProtoMessage() {}
+
+func (x *Replay) ProtoReflect() protoreflect.Message {
+	mi := &file_rvasp_v1_api_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Replay.ProtoReflect.Descriptor instead.
+func (*Replay) Descriptor() ([]byte, []int) {
+	return file_rvasp_v1_api_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Replay) GetSinceSequence() uint64 {
+	if x != nil {
+		return x.SinceSequence
+	}
+	return 0
+}
+
+func (x *Replay) GetSinceTimestamp() string {
+	if x != nil {
+		return x.SinceTimestamp
+	}
+	return ""
+}
+
+// A wrapper for the TransferRequet and AccountRequest RPCs to be sent via streaming. A
+// SUBSCRIBE command replaces the subscription of the stream, which can be changed at
+// any time; streams without a subscription receive all updates.
+type Command struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type   RPC    `protobuf:"varint,1,opt,name=type,proto3,enum=rvasp.v1.RPC" json:"type,omitempty"` // what type of command is being sent to the rVASP
+	Id     uint64 `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`                       // client side message id for req/rep tracking
+	Client string `protobuf:"bytes,3,opt,name=client,proto3" json:"client,omitempty"`                // unique ID so the rVASP can correctly dispatch messages
+	// only one of these fields can be set, and the field that is set should
+	// match the RPC type described above.
+	//
+	// Types that are assignable to Request:
+	//	*Command_Transfer
+	//	*Command_Account
+	//	*Command_Subscription
+	//	*Command_Replay
+	Request isCommand_Request `protobuf_oneof:"request"`
+}
+
+func (x *Command) Reset() {
+	*x = Command{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rvasp_v1_api_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Command) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Command) ProtoMessage() {}
+
+func (x *Command) ProtoReflect() protoreflect.Message {
+	mi := &file_rvasp_v1_api_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Command.ProtoReflect.Descriptor instead.
+func (*Command) Descriptor() ([]byte, []int) {
+	return file_rvasp_v1_api_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *Command) GetType() RPC {
+	if x != nil {
+		return x.Type
+	}
+	return RPC_NORPC
+}
+
+func (x *Command) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Command) GetClient() string {
+	if x != nil {
+		return x.Client
+	}
+	return ""
+}
+
+func (m *Command) GetRequest() isCommand_Request {
+	if m != nil {
+		return m.Request
+	}
+	return nil
+}
+
+func (x *Command) GetTransfer() *TransferRequest {
+	if x, ok := x.GetRequest().(*Command_Transfer); ok {
+		return x.Transfer
+	}
+	return nil
+}
+
+func (x *Command) GetAccount() *AccountRequest {
+	if x, ok := x.GetRequest().(*Command_Account); ok {
+		return x.Account
+	}
+	return nil
+}
+
+func (x *Command) GetSubscription() *Subscription {
+	if x, ok := x.GetRequest().(*Command_Subscription); ok {
+		return x.Subscription
+	}
+	return nil
+}
+
+func (x *Command) GetReplay() *Replay {
+	if x, ok := x.GetRequest().(*Command_Replay); ok {
+		return x.Replay
+	}
+	return nil
+}
+
+type isCommand_Request interface {
+	isCommand_Request()
+}
+
+type Command_Transfer struct {
+	Transfer *TransferRequest `protobuf:"bytes,11,opt,name=transfer,proto3,oneof"`
+}
+
+type Command_Account struct {
+	Account *AccountRequest `protobuf:"bytes,12,opt,name=account,proto3,oneof"`
+}
+
+type Command_Subscription struct {
+	Subscription *Subscription `protobuf:"bytes,13,opt,name=subscription,proto3,oneof"`
+}
+
+type Command_Replay struct {
+	Replay *Replay `protobuf:"bytes,14,opt,name=replay,proto3,oneof"`
+}
+
+func (*Command_Transfer) isCommand_Request() {}
+
+func (*Command_Account) isCommand_Request() {}
+
+func (*Command_Subscription) isCommand_Request() {}
+
+func (*Command_Replay) isCommand_Request() {}
+
+// Message is either a wrapper for a TransferReply or AccountReply RPCs or it is a live
+// update message sent from the rVASP to show the communication interactions of the
+// InterVASP protocol. If it is a wrapper, then type will be > 0 and the ID will match
+// the id of the command request sent by the client. Otherwise both of these fields will
+// be zero and the update string will be populated. Live updates are numbered so that
+// clients can detect gaps and request a replay of the updates they missed; note that
+// updates filtered out by the subscription of the stream also appear as gaps.
+type Message struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type      RPC             `protobuf:"varint,1,opt,name=type,proto3,enum=rvasp.v1.RPC" json:"type,omitempty"`
+	Id        uint64          `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	Update    string          `protobuf:"bytes,3,opt,name=update,proto3" json:"update,omitempty"`
+	Timestamp string          `protobuf:"bytes,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Category  MessageCategory `protobuf:"varint,5,opt,name=category,proto3,enum=rvasp.v1.MessageCategory" json:"category,omitempty"`
+	Sequence  uint64          `protobuf:"varint,6,opt,name=sequence,proto3" json:"sequence,omitempty"` // increases by one with every live update broadcast by the rVASP
+	// if type and id are greater than zero, one of these fields will be set, matching
+	// the RPC type described above.
+	//
+	// Types that are assignable to Reply:
+	//	*Message_Transfer
+	//	*Message_Account
+	//	*Message_Subscription
+	Reply isMessage_Reply `protobuf_oneof:"reply"`
+}
+
+func (x *Message) Reset() {
+	*x = Message{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rvasp_v1_api_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Message) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Message) ProtoMessage() {}
+
+func (x *Message) ProtoReflect() protoreflect.Message {
+	mi := &file_rvasp_v1_api_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Message.ProtoReflect.Descriptor instead.
+func (*Message) Descriptor() ([]byte, []int) {
+	return file_rvasp_v1_api_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *Message) GetType() RPC {
+	if x != nil {
+		return x.Type
+	}
+	return RPC_NORPC
+}
+
+func (x *Message) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Message) GetUpdate() string {
+	if x != nil {
+		return x.Update
+	}
+	return ""
+}
+
+func (x *Message) GetTimestamp() string {
+	if x != nil {
+		return x.Timestamp
+	}
+	return ""
+}
+
+func (x *Message) GetCategory() MessageCategory {
+	if x != nil {
+		return x.Category
+	}
+	return MessageCategory_LEDGER
+}
+
+func (x *Message) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (m *Message) GetReply() isMessage_Reply {
+	if m != nil {
+		return m.Reply
+	}
+	return nil
+}
+
+func (x *Message) GetTransfer() *TransferReply {
+	if x, ok := x.GetReply().(*Message_Transfer); ok {
+		return x.Transfer
+	}
+	return nil
+}
+
+func (x *Message) GetAccount() *AccountReply {
+	if x, ok := x.GetReply().(*Message_Account); ok {
+		return x.Account
+	}
+	return nil
+}
+
+func (x *Message) GetSubscription() *Subscription {
+	if x, ok := x.GetReply().(*Message_Subscription); ok {
+		return x.Subscription
+	}
+	return nil
+}
+
+type isMessage_Reply interface {
+	isMessage_Reply()
+}
+
+type Message_Transfer struct {
+	Transfer *TransferReply `protobuf:"bytes,11,opt,name=transfer,proto3,oneof"`
+}
 
 type Message_Account struct {
 	Account *AccountReply `protobuf:"bytes,12,opt,name=account,proto3,oneof"`
 }
 
-func (*Message_Transfer) isMessage_Reply() {}
+type Message_Subscription struct {
+	Subscription *Subscription `protobuf:"bytes,13,opt,name=subscription,proto3,oneof"` // the subscription applied by a SUBSCRIBE command
+}
+
+func (*Message_Transfer) isMessage_Reply() {}
+
+func (*Message_Account) isMessage_Reply() {}
+
+func (*Message_Subscription) isMessage_Reply() {}
+
+type Empty struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *Empty) Reset() {
+	*x = Empty{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rvasp_v1_api_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Empty) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Empty) ProtoMessage() {}
+
+func (x *Empty) ProtoReflect() protoreflect.Message {
+	mi := &file_rvasp_v1_api_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Empty.ProtoReflect.Descriptor instead.
+func (*Empty) Descriptor() ([]byte, []int) {
+	return file_rvasp_v1_api_proto_rawDescGZIP(), []int{19}
+}
+
+type ServerStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Status      ServerStatus_Status `protobuf:"varint,1,opt,name=status,proto3,enum=rvasp.v1.ServerStatus_Status" json:"status,omitempty"`
+	Version     string              `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	CommonName  string              `protobuf:"bytes,3,opt,name=common_name,json=commonName,proto3" json:"common_name,omitempty"`
+	NotBefore   string              `protobuf:"bytes,4,opt,name=not_before,json=notBefore,proto3" json:"not_before,omitempty"`
+	NotAfter    string              `protobuf:"bytes,5,opt,name=not_after,json=notAfter,proto3" json:"not_after,omitempty"`
+	LiveUpdates *LiveUpdatesStats   `protobuf:"bytes,6,opt,name=live_updates,json=liveUpdates,proto3" json:"live_updates,omitempty"`
+	RateLimits  *RateLimitStats     `protobuf:"bytes,7,opt,name=rate_limits,json=rateLimits,proto3" json:"rate_limits,omitempty"`
+}
+
+func (x *ServerStatus) Reset() {
+	*x = ServerStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rvasp_v1_api_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ServerStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServerStatus) ProtoMessage() {}
+
+func (x *ServerStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_rvasp_v1_api_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServerStatus.ProtoReflect.Descriptor instead.
+func (*ServerStatus) Descriptor() ([]byte, []int) {
+	return file_rvasp_v1_api_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ServerStatus) GetStatus() ServerStatus_Status {
+	if x != nil {
+		return x.Status
+	}
+	return ServerStatus_UNKNOWN
+}
+
+func (x *ServerStatus) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *ServerStatus) GetCommonName() string {
+	if x != nil {
+		return x.CommonName
+	}
+	return ""
+}
+
+func (x *ServerStatus) GetNotBefore() string {
+	if x != nil {
+		return x.NotBefore
+	}
+	return ""
+}
+
+func (x *ServerStatus) GetNotAfter() string {
+	if x != nil {
+		return x.NotAfter
+	}
+	return ""
+}
+
+func (x *ServerStatus) GetLiveUpdates() *LiveUpdatesStats {
+	if x != nil {
+		return x.LiveUpdates
+	}
+	return nil
+}
+
+func (x *ServerStatus) GetRateLimits() *RateLimitStats {
+	if x != nil {
+		return x.RateLimits
+	}
+	return nil
+}
+
+// Delivery counters of the LiveUpdates streams since the rVASP started. Each client has
+// a bounded queue of messages waiting to be sent; messages are dropped or the client is
+// disconnected when the queue of a slow client is full.
+type LiveUpdatesStats struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Clients      uint32 `protobuf:"varint,1,opt,name=clients,proto3" json:"clients,omitempty"`           // the number of connected clients
+	Queued       uint64 `protobuf:"varint,2,opt,name=queued,proto3" json:"queued,omitempty"`             // messages queued for clients
+	Sent         uint64 `protobuf:"varint,3,opt,name=sent,proto3" json:"sent,omitempty"`                 // messages sent to clients
+	Dropped      uint64 `protobuf:"varint,4,opt,name=dropped,proto3" json:"dropped,omitempty"`           // messages dropped from the queue of slow clients
+	Disconnected uint64 `protobuf:"varint,5,opt,name=disconnected,proto3" json:"disconnected,omitempty"` // clients disconnected because their queue was full
+}
+
+func (x *LiveUpdatesStats) Reset() {
+	*x = LiveUpdatesStats{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rvasp_v1_api_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LiveUpdatesStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LiveUpdatesStats) ProtoMessage() {}
+
+func (x *LiveUpdatesStats) ProtoReflect() protoreflect.Message {
+	mi := &file_rvasp_v1_api_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LiveUpdatesStats.ProtoReflect.Descriptor instead.
+func (*LiveUpdatesStats) Descriptor() ([]byte, []int) {
+	return file_rvasp_v1_api_proto_rawDescGZIP(), []int{21}
+}
 
-func (*Message_Account) isMessage_Reply() {}
+func (x *LiveUpdatesStats) GetClients() uint32 {
+	if x != nil {
+		return x.Clients
+	}
+	return 0
+}
 
-type Empty struct {
+func (x *LiveUpdatesStats) GetQueued() uint64 {
+	if x != nil {
+		return x.Queued
+	}
+	return 0
+}
+
+func (x *LiveUpdatesStats) GetSent() uint64 {
+	if x != nil {
+		return x.Sent
+	}
+	return 0
+}
+
+func (x *LiveUpdatesStats) GetDropped() uint64 {
+	if x != nil {
+		return x.Dropped
+	}
+	return 0
+}
+
+func (x *LiveUpdatesStats) GetDisconnected() uint64 {
+	if x != nil {
+		return x.Disconnected
+	}
+	return 0
+}
+
+// Counters of the requests to the TRISA service that were allowed or rejected by the
+// per-peer rate limits since the rVASP started; not set if rate limiting is disabled.
+type RateLimitStats struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	Rate    float64          `protobuf:"fixed64,1,opt,name=rate,proto3" json:"rate,omitempty"`      // the default requests per second of each peer
+	Burst   uint32           `protobuf:"varint,2,opt,name=burst,proto3" json:"burst,omitempty"`     // the default token bucket size of each peer
+	Allowed uint64           `protobuf:"varint,3,opt,name=allowed,proto3" json:"allowed,omitempty"` // requests allowed for all peers
+	Limited uint64           `protobuf:"varint,4,opt,name=limited,proto3" json:"limited,omitempty"` // requests rejected for all peers
+	Peers   []*PeerRateLimit `protobuf:"bytes,5,rep,name=peers,proto3" json:"peers,omitempty"`      // the limits of each peer that sent requests
 }
 
-func (x *Empty) Reset() {
-	*x = Empty{}
+func (x *RateLimitStats) Reset() {
+	*x = RateLimitStats{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_rvasp_v1_api_proto_msgTypes[9]
+		mi := &file_rvasp_v1_api_proto_msgTypes[22]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *Empty) String() string {
+func (x *RateLimitStats) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Empty) ProtoMessage() {}
+func (*RateLimitStats) ProtoMessage() {}
 
-func (x *Empty) ProtoReflect() protoreflect.Message {
-	mi := &file_rvasp_v1_api_proto_msgTypes[9]
+func (x *RateLimitStats) ProtoReflect() protoreflect.Message {
+	mi := &file_rvasp_v1_api_proto_msgTypes[22]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1087,40 +2032,76 @@ func (x *Empty) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Empty.ProtoReflect.Descriptor instead.
-func (*Empty) Descriptor() ([]byte, []int) {
-	return file_rvasp_v1_api_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use RateLimitStats.ProtoReflect.Descriptor instead.
+func (*RateLimitStats) Descriptor() ([]byte, []int) {
+	return file_rvasp_v1_api_proto_rawDescGZIP(), []int{22}
 }
 
-type ServerStatus struct {
+func (x *RateLimitStats) GetRate() float64 {
+	if x != nil {
+		return x.Rate
+	}
+	return 0
+}
+
+func (x *RateLimitStats) GetBurst() uint32 {
+	if x != nil {
+		return x.Burst
+	}
+	return 0
+}
+
+func (x *RateLimitStats) GetAllowed() uint64 {
+	if x != nil {
+		return x.Allowed
+	}
+	return 0
+}
+
+func (x *RateLimitStats) GetLimited() uint64 {
+	if x != nil {
+		return x.Limited
+	}
+	return 0
+}
+
+func (x *RateLimitStats) GetPeers() []*PeerRateLimit {
+	if x != nil {
+		return x.Peers
+	}
+	return nil
+}
+
+type PeerRateLimit struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Status     ServerStatus_Status `protobuf:"varint,1,opt,name=status,proto3,enum=rvasp.v1.ServerStatus_Status" json:"status,omitempty"`
-	Version    string              `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
-	CommonName string              `protobuf:"bytes,3,opt,name=common_name,json=commonName,proto3" json:"common_name,omitempty"`
-	NotBefore  string              `protobuf:"bytes,4,opt,name=not_before,json=notBefore,proto3" json:"not_before,omitempty"`
-	NotAfter   string              `protobuf:"bytes,5,opt,name=not_after,json=notAfter,proto3" json:"not_after,omitempty"`
+	Peer           string  `protobuf:"bytes,1,opt,name=peer,proto3" json:"peer,omitempty"`                                            // the common name of the mTLS certificate of the peer
+	Rate           float64 `protobuf:"fixed64,2,opt,name=rate,proto3" json:"rate,omitempty"`                                          // the requests per second of the peer
+	Burst          uint32  `protobuf:"varint,3,opt,name=burst,proto3" json:"burst,omitempty"`                                         // the token bucket size of the peer
+	Allowed        uint64  `protobuf:"varint,4,opt,name=allowed,proto3" json:"allowed,omitempty"`                                     // requests allowed for the peer
+	Limited        uint64  `protobuf:"varint,5,opt,name=limited,proto3" json:"limited,omitempty"`                                     // requests rejected for the peer
+	QuotaRemaining uint32  `protobuf:"varint,6,opt,name=quota_remaining,json=quotaRemaining,proto3" json:"quota_remaining,omitempty"` // requests remaining in the current quota window, if any
 }
 
-func (x *ServerStatus) Reset() {
-	*x = ServerStatus{}
+func (x *PeerRateLimit) Reset() {
+	*x = PeerRateLimit{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_rvasp_v1_api_proto_msgTypes[10]
+		mi := &file_rvasp_v1_api_proto_msgTypes[23]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ServerStatus) String() string {
+func (x *PeerRateLimit) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ServerStatus) ProtoMessage() {}
+func (*PeerRateLimit) ProtoMessage() {}
 
-func (x *ServerStatus) ProtoReflect() protoreflect.Message {
-	mi := &file_rvasp_v1_api_proto_msgTypes[10]
+func (x *PeerRateLimit) ProtoReflect() protoreflect.Message {
+	mi := &file_rvasp_v1_api_proto_msgTypes[23]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1131,44 +2112,51 @@ func (x *ServerStatus) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ServerStatus.ProtoReflect.Descriptor instead.
-func (*ServerStatus) Descriptor() ([]byte, []int) {
-	return file_rvasp_v1_api_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use PeerRateLimit.ProtoReflect.Descriptor instead.
+func (*PeerRateLimit) Descriptor() ([]byte, []int) {
+	return file_rvasp_v1_api_proto_rawDescGZIP(), []int{23}
 }
 
-func (x *ServerStatus) GetStatus() ServerStatus_Status {
+func (x *PeerRateLimit) GetPeer() string {
 	if x != nil {
-		return x.Status
+		return x.Peer
 	}
-	return ServerStatus_UNKNOWN
+	return ""
 }
 
-func (x *ServerStatus) GetVersion() string {
+func (x *PeerRateLimit) GetRate() float64 {
 	if x != nil {
-		return x.Version
+		return x.Rate
 	}
-	return ""
+	return 0
 }
 
-func (x *ServerStatus) GetCommonName() string {
+func (x *PeerRateLimit) GetBurst() uint32 {
 	if x != nil {
-		return x.CommonName
+		return x.Burst
 	}
-	return ""
+	return 0
 }
 
-func (x *ServerStatus) GetNotBefore() string {
+func (x *PeerRateLimit) GetAllowed() uint64 {
 	if x != nil {
-		return x.NotBefore
+		return x.Allowed
 	}
-	return ""
+	return 0
 }
 
-func (x *ServerStatus) GetNotAfter() string {
+func (x *PeerRateLimit) GetLimited() uint64 {
 	if x != nil {
-		return x.NotAfter
+		return x.Limited
 	}
-	return ""
+	return 0
+}
+
+func (x *PeerRateLimit) GetQuotaRemaining() uint32 {
+	if x != nil {
+		return x.QuotaRemaining
+	}
+	return 0
 }
 
 var File_rvasp_v1_api_proto protoreflect.FileDescriptor
@@ -1185,7 +2173,7 @@ var file_rvasp_v1_api_proto_rawDesc = []byte{
 	0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x6d, 0x61, 0x69, 0x6c,
 	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x12, 0x1a, 0x0a,
 	0x08, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x08, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x22, 0xb9, 0x02, 0x0a, 0x0b, 0x54, 0x72,
+	0x08, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x22, 0xee, 0x02, 0x0a, 0x0b, 0x54, 0x72,
 	0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x31, 0x0a, 0x0a, 0x6f, 0x72, 0x69,
 	0x67, 0x69, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e,
 	0x72, 0x76, 0x61, 0x73, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74,
@@ -1205,69 +2193,158 @@ var file_rvasp_v1_api_proto_rawDesc = []byte{
 	0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52,
 	0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x61, 0x73, 0x73, 0x65, 0x74, 0x5f,
 	0x74, 0x79, 0x70, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x61, 0x73, 0x73, 0x65,
-	0x74, 0x54, 0x79, 0x70, 0x65, 0x22, 0x87, 0x02, 0x0a, 0x0f, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66,
-	0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x63, 0x63,
-	0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x61, 0x63, 0x63, 0x6f,
-	0x75, 0x6e, 0x74, 0x12, 0x20, 0x0a, 0x0b, 0x62, 0x65, 0x6e, 0x65, 0x66, 0x69, 0x63, 0x69, 0x61,
-	0x72, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x62, 0x65, 0x6e, 0x65, 0x66, 0x69,
-	0x63, 0x69, 0x61, 0x72, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18,
-	0x03, 0x20, 0x01, 0x28, 0x02, 0x52, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x29, 0x0a,
-	0x10, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x5f, 0x76, 0x61, 0x73,
-	0x70, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x61,
-	0x74, 0x69, 0x6e, 0x67, 0x56, 0x61, 0x73, 0x70, 0x12, 0x29, 0x0a, 0x10, 0x62, 0x65, 0x6e, 0x65,
-	0x66, 0x69, 0x63, 0x69, 0x61, 0x72, 0x79, 0x5f, 0x76, 0x61, 0x73, 0x70, 0x18, 0x05, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x0f, 0x62, 0x65, 0x6e, 0x65, 0x66, 0x69, 0x63, 0x69, 0x61, 0x72, 0x79, 0x56,
-	0x61, 0x73, 0x70, 0x12, 0x2b, 0x0a, 0x11, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x5f, 0x62, 0x65, 0x6e,
-	0x65, 0x66, 0x69, 0x63, 0x69, 0x61, 0x72, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x10,
-	0x63, 0x68, 0x65, 0x63, 0x6b, 0x42, 0x65, 0x6e, 0x65, 0x66, 0x69, 0x63, 0x69, 0x61, 0x72, 0x79,
-	0x12, 0x1d, 0x0a, 0x0a, 0x61, 0x73, 0x73, 0x65, 0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x08,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x61, 0x73, 0x73, 0x65, 0x74, 0x54, 0x79, 0x70, 0x65, 0x22,
-	0x6f, 0x0a, 0x0d, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x52, 0x65, 0x70, 0x6c, 0x79,
-	0x12, 0x25, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x0f, 0x2e, 0x72, 0x76, 0x61, 0x73, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x72, 0x72, 0x6f, 0x72,
-	0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x37, 0x0a, 0x0b, 0x74, 0x72, 0x61, 0x6e, 0x73,
-	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x72,
-	0x76, 0x61, 0x73, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74,
-	0x69, 0x6f, 0x6e, 0x52, 0x0b, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e,
-	0x22, 0x82, 0x01, 0x0a, 0x0e, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x27, 0x0a,
-	0x0f, 0x6e, 0x6f, 0x5f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0e, 0x6e, 0x6f, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61,
-	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x67, 0x65, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x70, 0x61, 0x67, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x70, 0x65,
-	0x72, 0x5f, 0x70, 0x61, 0x67, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x70, 0x65,
-	0x72, 0x50, 0x61, 0x67, 0x65, 0x22, 0x93, 0x02, 0x0a, 0x0c, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e,
-	0x74, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x25, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x72, 0x76, 0x61, 0x73, 0x70, 0x2e, 0x76, 0x31,
-	0x2e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x12, 0x0a,
-	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d,
-	0x65, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x05, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x12, 0x25, 0x0a, 0x0e, 0x77, 0x61, 0x6c, 0x6c, 0x65,
-	0x74, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x0d, 0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x18,
-	0x0a, 0x07, 0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x02, 0x52,
-	0x07, 0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x6f, 0x6d, 0x70,
-	0x6c, 0x65, 0x74, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x63, 0x6f, 0x6d,
-	0x70, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x65, 0x6e, 0x64, 0x69, 0x6e,
-	0x67, 0x18, 0x07, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x70, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67,
-	0x12, 0x39, 0x0a, 0x0c, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73,
-	0x18, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x72, 0x76, 0x61, 0x73, 0x70, 0x2e, 0x76,
-	0x31, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0c, 0x74,
-	0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0xce, 0x01, 0x0a, 0x07,
-	0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x21, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0d, 0x2e, 0x72, 0x76, 0x61, 0x73, 0x70, 0x2e, 0x76, 0x31,
-	0x2e, 0x52, 0x50, 0x43, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x69, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x6c,
-	0x69, 0x65, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x63, 0x6c, 0x69, 0x65,
-	0x6e, 0x74, 0x12, 0x37, 0x0a, 0x08, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x18, 0x0b,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x72, 0x76, 0x61, 0x73, 0x70, 0x2e, 0x76, 0x31, 0x2e,
-	0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x48,
-	0x00, 0x52, 0x08, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x12, 0x34, 0x0a, 0x07, 0x61,
-	0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x72,
-	0x76, 0x61, 0x73, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x48, 0x00, 0x52, 0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e,
-	0x74, 0x42, 0x09, 0x0a, 0x07, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x9d, 0x02, 0x0a,
+	0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x33, 0x0a, 0x07, 0x68, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79,
+	0x18, 0x09, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x72, 0x76, 0x61, 0x73, 0x70, 0x2e, 0x76,
+	0x31, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x69, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x07, 0x68, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x22, 0xc7, 0x01, 0x0a, 0x0f, 0x53,
+	0x74, 0x61, 0x74, 0x65, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x36,
+	0x0a, 0x08, 0x70, 0x72, 0x65, 0x76, 0x69, 0x6f, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x1a, 0x2e, 0x72, 0x76, 0x61, 0x73, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x72, 0x61, 0x6e,
+	0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x08, 0x70, 0x72,
+	0x65, 0x76, 0x69, 0x6f, 0x75, 0x73, 0x12, 0x30, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1a, 0x2e, 0x72, 0x76, 0x61, 0x73, 0x70, 0x2e, 0x76, 0x31,
+	0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74,
+	0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x63, 0x74, 0x6f,
+	0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x12, 0x16,
+	0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x22, 0x87, 0x02, 0x0a, 0x0f, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65,
+	0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x63, 0x63, 0x6f,
+	0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x61, 0x63, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x12, 0x20, 0x0a, 0x0b, 0x62, 0x65, 0x6e, 0x65, 0x66, 0x69, 0x63, 0x69, 0x61, 0x72,
+	0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x62, 0x65, 0x6e, 0x65, 0x66, 0x69, 0x63,
+	0x69, 0x61, 0x72, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x02, 0x52, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x29, 0x0a, 0x10,
+	0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x5f, 0x76, 0x61, 0x73, 0x70,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x61, 0x74,
+	0x69, 0x6e, 0x67, 0x56, 0x61, 0x73, 0x70, 0x12, 0x29, 0x0a, 0x10, 0x62, 0x65, 0x6e, 0x65, 0x66,
+	0x69, 0x63, 0x69, 0x61, 0x72, 0x79, 0x5f, 0x76, 0x61, 0x73, 0x70, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0f, 0x62, 0x65, 0x6e, 0x65, 0x66, 0x69, 0x63, 0x69, 0x61, 0x72, 0x79, 0x56, 0x61,
+	0x73, 0x70, 0x12, 0x2b, 0x0a, 0x11, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x5f, 0x62, 0x65, 0x6e, 0x65,
+	0x66, 0x69, 0x63, 0x69, 0x61, 0x72, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x10, 0x63,
+	0x68, 0x65, 0x63, 0x6b, 0x42, 0x65, 0x6e, 0x65, 0x66, 0x69, 0x63, 0x69, 0x61, 0x72, 0x79, 0x12,
+	0x1d, 0x0a, 0x0a, 0x61, 0x73, 0x73, 0x65, 0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x08, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x09, 0x61, 0x73, 0x73, 0x65, 0x74, 0x54, 0x79, 0x70, 0x65, 0x22, 0x6f,
+	0x0a, 0x0d, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12,
+	0x25, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f,
+	0x2e, 0x72, 0x76, 0x61, 0x73, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52,
+	0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x37, 0x0a, 0x0b, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x72, 0x76,
+	0x61, 0x73, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x0b, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x22,
+	0x9c, 0x01, 0x0a, 0x0e, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x27, 0x0a, 0x0f,
+	0x6e, 0x6f, 0x5f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0e, 0x6e, 0x6f, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x04, 0x70, 0x61, 0x67, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x70, 0x65, 0x72,
+	0x5f, 0x70, 0x61, 0x67, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x70, 0x65, 0x72,
+	0x50, 0x61, 0x67, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x68, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x68, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x22, 0x93,
+	0x02, 0x0a, 0x0c, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12,
+	0x25, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f,
+	0x2e, 0x72, 0x76, 0x61, 0x73, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52,
+	0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x6d,
+	0x61, 0x69, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x6d, 0x61, 0x69, 0x6c,
+	0x12, 0x25, 0x0a, 0x0e, 0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65,
+	0x73, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74,
+	0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x62, 0x61, 0x6c, 0x61, 0x6e,
+	0x63, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x02, 0x52, 0x07, 0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63,
+	0x65, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x63, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x12,
+	0x18, 0x0a, 0x07, 0x70, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x07, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x07, 0x70, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x12, 0x39, 0x0a, 0x0c, 0x74, 0x72, 0x61,
+	0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x15, 0x2e, 0x72, 0x76, 0x61, 0x73, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73,
+	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0c, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x22, 0x3c, 0x0a, 0x19, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x65, 0x6e, 0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65, 0x5f, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x65, 0x6e, 0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65,
+	0x49, 0x64, 0x22, 0x52, 0x0a, 0x17, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x37, 0x0a,
+	0x0b, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x15, 0x2e, 0x72, 0x76, 0x61, 0x73, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x72,
+	0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0b, 0x74, 0x72, 0x61, 0x6e, 0x73,
+	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x77, 0x0a, 0x07, 0x57, 0x65, 0x62, 0x68, 0x6f, 0x6f,
+	0x6b, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x69,
+	0x64, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x75, 0x72, 0x6c, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x16, 0x0a,
+	0x06, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73,
+	0x65, 0x63, 0x72, 0x65, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x22,
+	0x3c, 0x0a, 0x0b, 0x57, 0x65, 0x62, 0x68, 0x6f, 0x6f, 0x6b, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x2d,
+	0x0a, 0x08, 0x77, 0x65, 0x62, 0x68, 0x6f, 0x6f, 0x6b, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x11, 0x2e, 0x72, 0x76, 0x61, 0x73, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x65, 0x62, 0x68,
+	0x6f, 0x6f, 0x6b, 0x52, 0x08, 0x77, 0x65, 0x62, 0x68, 0x6f, 0x6f, 0x6b, 0x73, 0x22, 0x5a, 0x0a,
+	0x18, 0x57, 0x65, 0x62, 0x68, 0x6f, 0x6f, 0x6b, 0x44, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x69,
+	0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x77, 0x65, 0x62,
+	0x68, 0x6f, 0x6f, 0x6b, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x77,
+	0x65, 0x62, 0x68, 0x6f, 0x6f, 0x6b, 0x49, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x65, 0x6e, 0x76, 0x65,
+	0x6c, 0x6f, 0x70, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x65,
+	0x6e, 0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65, 0x49, 0x64, 0x22, 0xab, 0x02, 0x0a, 0x0f, 0x57, 0x65,
+	0x62, 0x68, 0x6f, 0x6f, 0x6b, 0x44, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x12, 0x1d, 0x0a,
+	0x0a, 0x77, 0x65, 0x62, 0x68, 0x6f, 0x6f, 0x6b, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x09, 0x77, 0x65, 0x62, 0x68, 0x6f, 0x6f, 0x6b, 0x49, 0x64, 0x12, 0x19, 0x0a, 0x08,
+	0x65, 0x76, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
+	0x65, 0x76, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x65, 0x6e, 0x76, 0x65, 0x6c,
+	0x6f, 0x70, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x65, 0x6e,
+	0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65, 0x49, 0x64, 0x12, 0x30, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74,
+	0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1a, 0x2e, 0x72, 0x76, 0x61, 0x73, 0x70, 0x2e,
+	0x76, 0x31, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74,
+	0x61, 0x74, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x74,
+	0x74, 0x65, 0x6d, 0x70, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x61, 0x74, 0x74,
+	0x65, 0x6d, 0x70, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x5f, 0x63,
+	0x6f, 0x64, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x73, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x1c, 0x0a, 0x09, 0x64,
+	0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x65, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09,
+	0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x65, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x74, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x22, 0x53, 0x0a, 0x16, 0x57, 0x65, 0x62, 0x68, 0x6f,
+	0x6f, 0x6b, 0x44, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x69, 0x65, 0x73, 0x52, 0x65, 0x70, 0x6c,
+	0x79, 0x12, 0x39, 0x0a, 0x0a, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x69, 0x65, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x72, 0x76, 0x61, 0x73, 0x70, 0x2e, 0x76, 0x31,
+	0x2e, 0x57, 0x65, 0x62, 0x68, 0x6f, 0x6f, 0x6b, 0x44, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79,
+	0x52, 0x0a, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x69, 0x65, 0x73, 0x22, 0x88, 0x01, 0x0a,
+	0x0c, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a,
+	0x08, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x08, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x65, 0x6e, 0x76,
+	0x65, 0x6c, 0x6f, 0x70, 0x65, 0x5f, 0x69, 0x64, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x0b, 0x65, 0x6e, 0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65, 0x49, 0x64, 0x73, 0x12, 0x39, 0x0a, 0x0a,
+	0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0e,
+	0x32, 0x19, 0x2e, 0x72, 0x76, 0x61, 0x73, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x43, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x52, 0x0a, 0x63, 0x61, 0x74,
+	0x65, 0x67, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x22, 0x58, 0x0a, 0x06, 0x52, 0x65, 0x70, 0x6c, 0x61,
+	0x79, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x69, 0x6e, 0x63, 0x65, 0x5f, 0x73, 0x65, 0x71, 0x75, 0x65,
+	0x6e, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0d, 0x73, 0x69, 0x6e, 0x63, 0x65,
+	0x53, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x27, 0x0a, 0x0f, 0x73, 0x69, 0x6e, 0x63,
+	0x65, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0e, 0x73, 0x69, 0x6e, 0x63, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x22, 0xb8, 0x02, 0x0a, 0x07, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x21, 0x0a,
+	0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0d, 0x2e, 0x72, 0x76,
+	0x61, 0x73, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x50, 0x43, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65,
+	0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x69, 0x64,
+	0x12, 0x16, 0x0a, 0x06, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x12, 0x37, 0x0a, 0x08, 0x74, 0x72, 0x61, 0x6e,
+	0x73, 0x66, 0x65, 0x72, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x72, 0x76, 0x61,
+	0x73, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x48, 0x00, 0x52, 0x08, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65,
+	0x72, 0x12, 0x34, 0x0a, 0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x0c, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x18, 0x2e, 0x72, 0x76, 0x61, 0x73, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x63,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x48, 0x00, 0x52, 0x07,
+	0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x3c, 0x0a, 0x0c, 0x73, 0x75, 0x62, 0x73, 0x63,
+	0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e,
+	0x72, 0x76, 0x61, 0x73, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69,
+	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x0c, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69,
+	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x2a, 0x0a, 0x06, 0x72, 0x65, 0x70, 0x6c, 0x61, 0x79, 0x18,
+	0x0e, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x72, 0x76, 0x61, 0x73, 0x70, 0x2e, 0x76, 0x31,
+	0x2e, 0x52, 0x65, 0x70, 0x6c, 0x61, 0x79, 0x48, 0x00, 0x52, 0x06, 0x72, 0x65, 0x70, 0x6c, 0x61,
+	0x79, 0x42, 0x09, 0x0a, 0x07, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0xf7, 0x02, 0x0a,
 	0x07, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x21, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65,
 	0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0d, 0x2e, 0x72, 0x76, 0x61, 0x73, 0x70, 0x2e, 0x76,
 	0x31, 0x2e, 0x52, 0x50, 0x43, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69,
@@ -1278,74 +2355,142 @@ var file_rvasp_v1_api_proto_rawDesc = []byte{
 	0x70, 0x12, 0x35, 0x0a, 0x08, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x18, 0x05, 0x20,
 	0x01, 0x28, 0x0e, 0x32, 0x19, 0x2e, 0x72, 0x76, 0x61, 0x73, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x4d,
 	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x43, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x52, 0x08,
-	0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x12, 0x35, 0x0a, 0x08, 0x74, 0x72, 0x61, 0x6e,
-	0x73, 0x66, 0x65, 0x72, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x72, 0x76, 0x61,
+	0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x65, 0x71, 0x75,
+	0x65, 0x6e, 0x63, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x73, 0x65, 0x71, 0x75,
+	0x65, 0x6e, 0x63, 0x65, 0x12, 0x35, 0x0a, 0x08, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72,
+	0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x72, 0x76, 0x61, 0x73, 0x70, 0x2e, 0x76,
+	0x31, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x48,
+	0x00, 0x52, 0x08, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x12, 0x32, 0x0a, 0x07, 0x61,
+	0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x72,
+	0x76, 0x61, 0x73, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x52,
+	0x65, 0x70, 0x6c, 0x79, 0x48, 0x00, 0x52, 0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12,
+	0x3c, 0x0a, 0x0c, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18,
+	0x0d, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x72, 0x76, 0x61, 0x73, 0x70, 0x2e, 0x76, 0x31,
+	0x2e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52,
+	0x0c, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x07, 0x0a,
+	0x05, 0x72, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x07, 0x0a, 0x05, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22,
+	0x86, 0x03, 0x0a, 0x0c, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x12, 0x35, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x1d, 0x2e, 0x72, 0x76, 0x61, 0x73, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x72, 0x76,
+	0x65, 0x72, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52,
+	0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x12, 0x1f, 0x0a, 0x0b, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x5f, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x4e, 0x61,
+	0x6d, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x6e, 0x6f, 0x74, 0x5f, 0x62, 0x65, 0x66, 0x6f, 0x72, 0x65,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6e, 0x6f, 0x74, 0x42, 0x65, 0x66, 0x6f, 0x72,
+	0x65, 0x12, 0x1b, 0x0a, 0x09, 0x6e, 0x6f, 0x74, 0x5f, 0x61, 0x66, 0x74, 0x65, 0x72, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6e, 0x6f, 0x74, 0x41, 0x66, 0x74, 0x65, 0x72, 0x12, 0x3d,
+	0x0a, 0x0c, 0x6c, 0x69, 0x76, 0x65, 0x5f, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x73, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x72, 0x76, 0x61, 0x73, 0x70, 0x2e, 0x76, 0x31, 0x2e,
+	0x4c, 0x69, 0x76, 0x65, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x73, 0x53, 0x74, 0x61, 0x74, 0x73,
+	0x52, 0x0b, 0x6c, 0x69, 0x76, 0x65, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x73, 0x12, 0x39, 0x0a,
+	0x0b, 0x72, 0x61, 0x74, 0x65, 0x5f, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x73, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x18, 0x2e, 0x72, 0x76, 0x61, 0x73, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x61,
+	0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x0a, 0x72, 0x61,
+	0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x73, 0x22, 0x4e, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x12, 0x0b, 0x0a, 0x07, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12,
+	0x0a, 0x0a, 0x06, 0x4f, 0x4e, 0x4c, 0x49, 0x4e, 0x45, 0x10, 0x01, 0x12, 0x0f, 0x0a, 0x0b, 0x4d,
+	0x41, 0x49, 0x4e, 0x54, 0x45, 0x4e, 0x41, 0x4e, 0x43, 0x45, 0x10, 0x02, 0x12, 0x0d, 0x0a, 0x09,
+	0x55, 0x4e, 0x48, 0x45, 0x41, 0x4c, 0x54, 0x48, 0x59, 0x10, 0x03, 0x12, 0x0b, 0x0a, 0x07, 0x4f,
+	0x46, 0x46, 0x4c, 0x49, 0x4e, 0x45, 0x10, 0x04, 0x22, 0x96, 0x01, 0x0a, 0x10, 0x4c, 0x69, 0x76,
+	0x65, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x73, 0x53, 0x74, 0x61, 0x74, 0x73, 0x12, 0x18, 0x0a,
+	0x07, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07,
+	0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x71, 0x75, 0x65, 0x75, 0x65,
+	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x71, 0x75, 0x65, 0x75, 0x65, 0x64, 0x12,
+	0x12, 0x0a, 0x04, 0x73, 0x65, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x04, 0x73,
+	0x65, 0x6e, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x72, 0x6f, 0x70, 0x70, 0x65, 0x64, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x64, 0x72, 0x6f, 0x70, 0x70, 0x65, 0x64, 0x12, 0x22, 0x0a,
+	0x0c, 0x64, 0x69, 0x73, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x65, 0x64, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x0c, 0x64, 0x69, 0x73, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x65,
+	0x64, 0x22, 0x9d, 0x01, 0x0a, 0x0e, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x53,
+	0x74, 0x61, 0x74, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x04, 0x72, 0x61, 0x74, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x62, 0x75, 0x72, 0x73,
+	0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x62, 0x75, 0x72, 0x73, 0x74, 0x12, 0x18,
+	0x0a, 0x07, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x07, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x6c, 0x69, 0x6d, 0x69,
+	0x74, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x6c, 0x69, 0x6d, 0x69, 0x74,
+	0x65, 0x64, 0x12, 0x2d, 0x0a, 0x05, 0x70, 0x65, 0x65, 0x72, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x17, 0x2e, 0x72, 0x76, 0x61, 0x73, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x65, 0x65,
+	0x72, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x52, 0x05, 0x70, 0x65, 0x65, 0x72,
+	0x73, 0x22, 0xaa, 0x01, 0x0a, 0x0d, 0x50, 0x65, 0x65, 0x72, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69,
+	0x6d, 0x69, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x65, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x70, 0x65, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x61, 0x74, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x72, 0x61, 0x74, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x62,
+	0x75, 0x72, 0x73, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x62, 0x75, 0x72, 0x73,
+	0x74, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x07, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x6c,
+	0x69, 0x6d, 0x69, 0x74, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x6c, 0x69,
+	0x6d, 0x69, 0x74, 0x65, 0x64, 0x12, 0x27, 0x0a, 0x0f, 0x71, 0x75, 0x6f, 0x74, 0x61, 0x5f, 0x72,
+	0x65, 0x6d, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0e,
+	0x71, 0x75, 0x6f, 0x74, 0x61, 0x52, 0x65, 0x6d, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x2a, 0xd5,
+	0x01, 0x0a, 0x10, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74,
+	0x61, 0x74, 0x65, 0x12, 0x0b, 0x0a, 0x07, 0x49, 0x4e, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x10, 0x00,
+	0x12, 0x12, 0x0a, 0x0e, 0x41, 0x57, 0x41, 0x49, 0x54, 0x49, 0x4e, 0x47, 0x5f, 0x52, 0x45, 0x50,
+	0x4c, 0x59, 0x10, 0x01, 0x12, 0x10, 0x0a, 0x0c, 0x50, 0x45, 0x4e, 0x44, 0x49, 0x4e, 0x47, 0x5f,
+	0x53, 0x45, 0x4e, 0x54, 0x10, 0x02, 0x12, 0x1a, 0x0a, 0x16, 0x41, 0x57, 0x41, 0x49, 0x54, 0x49,
+	0x4e, 0x47, 0x5f, 0x46, 0x55, 0x4c, 0x4c, 0x5f, 0x54, 0x52, 0x41, 0x4e, 0x53, 0x46, 0x45, 0x52,
+	0x10, 0x03, 0x12, 0x14, 0x0a, 0x10, 0x50, 0x45, 0x4e, 0x44, 0x49, 0x4e, 0x47, 0x5f, 0x52, 0x45,
+	0x43, 0x45, 0x49, 0x56, 0x45, 0x44, 0x10, 0x04, 0x12, 0x18, 0x0a, 0x14, 0x50, 0x45, 0x4e, 0x44,
+	0x49, 0x4e, 0x47, 0x5f, 0x41, 0x43, 0x4b, 0x4e, 0x4f, 0x57, 0x4c, 0x45, 0x44, 0x47, 0x45, 0x44,
+	0x10, 0x05, 0x12, 0x0c, 0x0a, 0x08, 0x41, 0x43, 0x43, 0x45, 0x50, 0x54, 0x45, 0x44, 0x10, 0x06,
+	0x12, 0x0a, 0x0a, 0x06, 0x46, 0x41, 0x49, 0x4c, 0x45, 0x44, 0x10, 0x07, 0x12, 0x0b, 0x0a, 0x07,
+	0x45, 0x58, 0x50, 0x49, 0x52, 0x45, 0x44, 0x10, 0x08, 0x12, 0x0c, 0x0a, 0x08, 0x52, 0x45, 0x4a,
+	0x45, 0x43, 0x54, 0x45, 0x44, 0x10, 0x09, 0x12, 0x0d, 0x0a, 0x09, 0x43, 0x4f, 0x4d, 0x50, 0x4c,
+	0x45, 0x54, 0x45, 0x44, 0x10, 0x0a, 0x2a, 0x46, 0x0a, 0x03, 0x52, 0x50, 0x43, 0x12, 0x09, 0x0a,
+	0x05, 0x4e, 0x4f, 0x52, 0x50, 0x43, 0x10, 0x00, 0x12, 0x0c, 0x0a, 0x08, 0x54, 0x52, 0x41, 0x4e,
+	0x53, 0x46, 0x45, 0x52, 0x10, 0x01, 0x12, 0x0b, 0x0a, 0x07, 0x41, 0x43, 0x43, 0x4f, 0x55, 0x4e,
+	0x54, 0x10, 0x02, 0x12, 0x0d, 0x0a, 0x09, 0x53, 0x55, 0x42, 0x53, 0x43, 0x52, 0x49, 0x42, 0x45,
+	0x10, 0x03, 0x12, 0x0a, 0x0a, 0x06, 0x52, 0x45, 0x50, 0x4c, 0x41, 0x59, 0x10, 0x04, 0x2a, 0x53,
+	0x0a, 0x0f, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x43, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72,
+	0x79, 0x12, 0x0a, 0x0a, 0x06, 0x4c, 0x45, 0x44, 0x47, 0x45, 0x52, 0x10, 0x00, 0x12, 0x0b, 0x0a,
+	0x07, 0x54, 0x52, 0x49, 0x53, 0x41, 0x44, 0x53, 0x10, 0x01, 0x12, 0x0c, 0x0a, 0x08, 0x54, 0x52,
+	0x49, 0x53, 0x41, 0x50, 0x32, 0x50, 0x10, 0x02, 0x12, 0x0e, 0x0a, 0x0a, 0x42, 0x4c, 0x4f, 0x43,
+	0x4b, 0x43, 0x48, 0x41, 0x49, 0x4e, 0x10, 0x03, 0x12, 0x09, 0x0a, 0x05, 0x45, 0x52, 0x52, 0x4f,
+	0x52, 0x10, 0x04, 0x32, 0x44, 0x0a, 0x09, 0x54, 0x52, 0x49, 0x53, 0x41, 0x44, 0x65, 0x6d, 0x6f,
+	0x12, 0x37, 0x0a, 0x0b, 0x4c, 0x69, 0x76, 0x65, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x73, 0x12,
+	0x11, 0x2e, 0x72, 0x76, 0x61, 0x73, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x61,
+	0x6e, 0x64, 0x1a, 0x11, 0x2e, 0x72, 0x76, 0x61, 0x73, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x28, 0x01, 0x30, 0x01, 0x32, 0xa7, 0x04, 0x0a, 0x10, 0x54, 0x52,
+	0x49, 0x53, 0x41, 0x49, 0x6e, 0x74, 0x65, 0x67, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x3e,
+	0x0a, 0x08, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x12, 0x19, 0x2e, 0x72, 0x76, 0x61,
 	0x73, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x52, 0x65,
-	0x70, 0x6c, 0x79, 0x48, 0x00, 0x52, 0x08, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x12,
-	0x32, 0x0a, 0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x16, 0x2e, 0x72, 0x76, 0x61, 0x73, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x63, 0x63, 0x6f,
-	0x75, 0x6e, 0x74, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x48, 0x00, 0x52, 0x07, 0x61, 0x63, 0x63, 0x6f,
-	0x75, 0x6e, 0x74, 0x42, 0x07, 0x0a, 0x05, 0x72, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x07, 0x0a, 0x05,
-	0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x8c, 0x02, 0x0a, 0x0c, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72,
-	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x35, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1d, 0x2e, 0x72, 0x76, 0x61, 0x73, 0x70, 0x2e, 0x76,
-	0x31, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x2e, 0x53,
-	0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x18, 0x0a,
-	0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
-	0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1f, 0x0a, 0x0b, 0x63, 0x6f, 0x6d, 0x6d, 0x6f,
-	0x6e, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x6f,
-	0x6d, 0x6d, 0x6f, 0x6e, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x6e, 0x6f, 0x74, 0x5f,
-	0x62, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6e, 0x6f,
-	0x74, 0x42, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x6e, 0x6f, 0x74, 0x5f, 0x61,
-	0x66, 0x74, 0x65, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6e, 0x6f, 0x74, 0x41,
-	0x66, 0x74, 0x65, 0x72, 0x22, 0x4e, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x0b,
-	0x0a, 0x07, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x4f,
-	0x4e, 0x4c, 0x49, 0x4e, 0x45, 0x10, 0x01, 0x12, 0x0f, 0x0a, 0x0b, 0x4d, 0x41, 0x49, 0x4e, 0x54,
-	0x45, 0x4e, 0x41, 0x4e, 0x43, 0x45, 0x10, 0x02, 0x12, 0x0d, 0x0a, 0x09, 0x55, 0x4e, 0x48, 0x45,
-	0x41, 0x4c, 0x54, 0x48, 0x59, 0x10, 0x03, 0x12, 0x0b, 0x0a, 0x07, 0x4f, 0x46, 0x46, 0x4c, 0x49,
-	0x4e, 0x45, 0x10, 0x04, 0x2a, 0xd5, 0x01, 0x0a, 0x10, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63,
-	0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x0b, 0x0a, 0x07, 0x49, 0x4e, 0x56,
-	0x41, 0x4c, 0x49, 0x44, 0x10, 0x00, 0x12, 0x12, 0x0a, 0x0e, 0x41, 0x57, 0x41, 0x49, 0x54, 0x49,
-	0x4e, 0x47, 0x5f, 0x52, 0x45, 0x50, 0x4c, 0x59, 0x10, 0x01, 0x12, 0x10, 0x0a, 0x0c, 0x50, 0x45,
-	0x4e, 0x44, 0x49, 0x4e, 0x47, 0x5f, 0x53, 0x45, 0x4e, 0x54, 0x10, 0x02, 0x12, 0x1a, 0x0a, 0x16,
-	0x41, 0x57, 0x41, 0x49, 0x54, 0x49, 0x4e, 0x47, 0x5f, 0x46, 0x55, 0x4c, 0x4c, 0x5f, 0x54, 0x52,
-	0x41, 0x4e, 0x53, 0x46, 0x45, 0x52, 0x10, 0x03, 0x12, 0x14, 0x0a, 0x10, 0x50, 0x45, 0x4e, 0x44,
-	0x49, 0x4e, 0x47, 0x5f, 0x52, 0x45, 0x43, 0x45, 0x49, 0x56, 0x45, 0x44, 0x10, 0x04, 0x12, 0x18,
-	0x0a, 0x14, 0x50, 0x45, 0x4e, 0x44, 0x49, 0x4e, 0x47, 0x5f, 0x41, 0x43, 0x4b, 0x4e, 0x4f, 0x57,
-	0x4c, 0x45, 0x44, 0x47, 0x45, 0x44, 0x10, 0x05, 0x12, 0x0c, 0x0a, 0x08, 0x41, 0x43, 0x43, 0x45,
-	0x50, 0x54, 0x45, 0x44, 0x10, 0x06, 0x12, 0x0a, 0x0a, 0x06, 0x46, 0x41, 0x49, 0x4c, 0x45, 0x44,
-	0x10, 0x07, 0x12, 0x0b, 0x0a, 0x07, 0x45, 0x58, 0x50, 0x49, 0x52, 0x45, 0x44, 0x10, 0x08, 0x12,
-	0x0c, 0x0a, 0x08, 0x52, 0x45, 0x4a, 0x45, 0x43, 0x54, 0x45, 0x44, 0x10, 0x09, 0x12, 0x0d, 0x0a,
-	0x09, 0x43, 0x4f, 0x4d, 0x50, 0x4c, 0x45, 0x54, 0x45, 0x44, 0x10, 0x0a, 0x2a, 0x2b, 0x0a, 0x03,
-	0x52, 0x50, 0x43, 0x12, 0x09, 0x0a, 0x05, 0x4e, 0x4f, 0x52, 0x50, 0x43, 0x10, 0x00, 0x12, 0x0c,
-	0x0a, 0x08, 0x54, 0x52, 0x41, 0x4e, 0x53, 0x46, 0x45, 0x52, 0x10, 0x01, 0x12, 0x0b, 0x0a, 0x07,
-	0x41, 0x43, 0x43, 0x4f, 0x55, 0x4e, 0x54, 0x10, 0x02, 0x2a, 0x53, 0x0a, 0x0f, 0x4d, 0x65, 0x73,
-	0x73, 0x61, 0x67, 0x65, 0x43, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x12, 0x0a, 0x0a, 0x06,
-	0x4c, 0x45, 0x44, 0x47, 0x45, 0x52, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x54, 0x52, 0x49, 0x53,
-	0x41, 0x44, 0x53, 0x10, 0x01, 0x12, 0x0c, 0x0a, 0x08, 0x54, 0x52, 0x49, 0x53, 0x41, 0x50, 0x32,
-	0x50, 0x10, 0x02, 0x12, 0x0e, 0x0a, 0x0a, 0x42, 0x4c, 0x4f, 0x43, 0x4b, 0x43, 0x48, 0x41, 0x49,
-	0x4e, 0x10, 0x03, 0x12, 0x09, 0x0a, 0x05, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x10, 0x04, 0x32, 0x44,
-	0x0a, 0x09, 0x54, 0x52, 0x49, 0x53, 0x41, 0x44, 0x65, 0x6d, 0x6f, 0x12, 0x37, 0x0a, 0x0b, 0x4c,
-	0x69, 0x76, 0x65, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x73, 0x12, 0x11, 0x2e, 0x72, 0x76, 0x61,
-	0x73, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x1a, 0x11, 0x2e,
-	0x72, 0x76, 0x61, 0x73, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
-	0x28, 0x01, 0x30, 0x01, 0x32, 0xc8, 0x01, 0x0a, 0x10, 0x54, 0x52, 0x49, 0x53, 0x41, 0x49, 0x6e,
-	0x74, 0x65, 0x67, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x3e, 0x0a, 0x08, 0x54, 0x72, 0x61,
-	0x6e, 0x73, 0x66, 0x65, 0x72, 0x12, 0x19, 0x2e, 0x72, 0x76, 0x61, 0x73, 0x70, 0x2e, 0x76, 0x31,
-	0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x17, 0x2e, 0x72, 0x76, 0x61, 0x73, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x72, 0x61, 0x6e,
-	0x73, 0x66, 0x65, 0x72, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x41, 0x0a, 0x0d, 0x41, 0x63, 0x63,
-	0x6f, 0x75, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x18, 0x2e, 0x72, 0x76, 0x61,
-	0x73, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x72, 0x76, 0x61, 0x73, 0x70, 0x2e, 0x76, 0x31, 0x2e,
-	0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x31, 0x0a, 0x06,
-	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x0f, 0x2e, 0x72, 0x76, 0x61, 0x73, 0x70, 0x2e, 0x76,
-	0x31, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x16, 0x2e, 0x72, 0x76, 0x61, 0x73, 0x70, 0x2e,
-	0x76, 0x31, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x42,
-	0x34, 0x5a, 0x32, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x74, 0x72,
-	0x69, 0x73, 0x61, 0x63, 0x72, 0x79, 0x70, 0x74, 0x6f, 0x2f, 0x74, 0x65, 0x73, 0x74, 0x6e, 0x65,
-	0x74, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x72, 0x76, 0x61, 0x73, 0x70, 0x2f, 0x70, 0x62, 0x2f, 0x76,
-	0x31, 0x3b, 0x61, 0x70, 0x69, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x72, 0x76, 0x61, 0x73, 0x70, 0x2e, 0x76, 0x31,
+	0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x41,
+	0x0a, 0x0d, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12,
+	0x18, 0x2e, 0x72, 0x76, 0x61, 0x73, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x63, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x72, 0x76, 0x61, 0x73,
+	0x70, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x65, 0x70, 0x6c,
+	0x79, 0x12, 0x5c, 0x0a, 0x12, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x23, 0x2e, 0x72, 0x76, 0x61, 0x73, 0x70, 0x2e,
+	0x76, 0x31, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x69,
+	0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e, 0x72,
+	0x76, 0x61, 0x73, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12,
+	0x37, 0x0a, 0x0f, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x57, 0x65, 0x62, 0x68, 0x6f,
+	0x6f, 0x6b, 0x12, 0x11, 0x2e, 0x72, 0x76, 0x61, 0x73, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x65,
+	0x62, 0x68, 0x6f, 0x6f, 0x6b, 0x1a, 0x11, 0x2e, 0x72, 0x76, 0x61, 0x73, 0x70, 0x2e, 0x76, 0x31,
+	0x2e, 0x57, 0x65, 0x62, 0x68, 0x6f, 0x6f, 0x6b, 0x12, 0x36, 0x0a, 0x0c, 0x4c, 0x69, 0x73, 0x74,
+	0x57, 0x65, 0x62, 0x68, 0x6f, 0x6f, 0x6b, 0x73, 0x12, 0x0f, 0x2e, 0x72, 0x76, 0x61, 0x73, 0x70,
+	0x2e, 0x76, 0x31, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x15, 0x2e, 0x72, 0x76, 0x61, 0x73,
+	0x70, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x65, 0x62, 0x68, 0x6f, 0x6f, 0x6b, 0x4c, 0x69, 0x73, 0x74,
+	0x12, 0x33, 0x0a, 0x0d, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x57, 0x65, 0x62, 0x68, 0x6f, 0x6f,
+	0x6b, 0x12, 0x11, 0x2e, 0x72, 0x76, 0x61, 0x73, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x65, 0x62,
+	0x68, 0x6f, 0x6f, 0x6b, 0x1a, 0x0f, 0x2e, 0x72, 0x76, 0x61, 0x73, 0x70, 0x2e, 0x76, 0x31, 0x2e,
+	0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x59, 0x0a, 0x11, 0x57, 0x65, 0x62, 0x68, 0x6f, 0x6f, 0x6b,
+	0x44, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x69, 0x65, 0x73, 0x12, 0x22, 0x2e, 0x72, 0x76, 0x61,
+	0x73, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x65, 0x62, 0x68, 0x6f, 0x6f, 0x6b, 0x44, 0x65, 0x6c,
+	0x69, 0x76, 0x65, 0x72, 0x69, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20,
+	0x2e, 0x72, 0x76, 0x61, 0x73, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x65, 0x62, 0x68, 0x6f, 0x6f,
+	0x6b, 0x44, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x69, 0x65, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79,
+	0x12, 0x31, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x0f, 0x2e, 0x72, 0x76, 0x61,
+	0x73, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x16, 0x2e, 0x72, 0x76,
+	0x61, 0x73, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x42, 0x34, 0x5a, 0x32, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f,
+	0x6d, 0x2f, 0x74, 0x72, 0x69, 0x73, 0x61, 0x63, 0x72, 0x79, 0x70, 0x74, 0x6f, 0x2f, 0x74, 0x65,
+	0x73, 0x74, 0x6e, 0x65, 0x74, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x72, 0x76, 0x61, 0x73, 0x70, 0x2f,
+	0x70, 0x62, 0x2f, 0x76, 0x31, 0x3b, 0x61, 0x70, 0x69, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x33,
 }
 
 var (
@@ -1361,53 +2506,90 @@ func file_rvasp_v1_api_proto_rawDescGZIP() []byte {
 }
 
 var file_rvasp_v1_api_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
-var file_rvasp_v1_api_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_rvasp_v1_api_proto_msgTypes = make([]protoimpl.MessageInfo, 24)
 var file_rvasp_v1_api_proto_goTypes = []interface{}{
-	(TransactionState)(0),    // 0: rvasp.v1.TransactionState
-	(RPC)(0),                 // 1: rvasp.v1.RPC
-	(MessageCategory)(0),     // 2: rvasp.v1.MessageCategory
-	(ServerStatus_Status)(0), // 3: rvasp.v1.ServerStatus.Status
-	(*Error)(nil),            // 4: rvasp.v1.Error
-	(*Account)(nil),          // 5: rvasp.v1.Account
-	(*Transaction)(nil),      // 6: rvasp.v1.Transaction
-	(*TransferRequest)(nil),  // 7: rvasp.v1.TransferRequest
-	(*TransferReply)(nil),    // 8: rvasp.v1.TransferReply
-	(*AccountRequest)(nil),   // 9: rvasp.v1.AccountRequest
-	(*AccountReply)(nil),     // 10: rvasp.v1.AccountReply
-	(*Command)(nil),          // 11: rvasp.v1.Command
-	(*Message)(nil),          // 12: rvasp.v1.Message
-	(*Empty)(nil),            // 13: rvasp.v1.Empty
-	(*ServerStatus)(nil),     // 14: rvasp.v1.ServerStatus
+	(TransactionState)(0),             // 0: rvasp.v1.TransactionState
+	(RPC)(0),                          // 1: rvasp.v1.RPC
+	(MessageCategory)(0),              // 2: rvasp.v1.MessageCategory
+	(ServerStatus_Status)(0),          // 3: rvasp.v1.ServerStatus.Status
+	(*Error)(nil),                     // 4: rvasp.v1.Error
+	(*Account)(nil),                   // 5: rvasp.v1.Account
+	(*Transaction)(nil),               // 6: rvasp.v1.Transaction
+	(*StateTransition)(nil),           // 7: rvasp.v1.StateTransition
+	(*TransferRequest)(nil),           // 8: rvasp.v1.TransferRequest
+	(*TransferReply)(nil),             // 9: rvasp.v1.TransferReply
+	(*AccountRequest)(nil),            // 10: rvasp.v1.AccountRequest
+	(*AccountReply)(nil),              // 11: rvasp.v1.AccountReply
+	(*TransactionHistoryRequest)(nil), // 12: rvasp.v1.TransactionHistoryRequest
+	(*TransactionHistoryReply)(nil),   // 13: rvasp.v1.TransactionHistoryReply
+	(*Webhook)(nil),                   // 14: rvasp.v1.Webhook
+	(*WebhookList)(nil),               // 15: rvasp.v1.WebhookList
+	(*WebhookDeliveriesRequest)(nil),  // 16: rvasp.v1.WebhookDeliveriesRequest
+	(*WebhookDelivery)(nil),           // 17: rvasp.v1.WebhookDelivery
+	(*WebhookDeliveriesReply)(nil),    // 18: rvasp.v1.WebhookDeliveriesReply
+	(*Subscription)(nil),              // 19: rvasp.v1.Subscription
+	(*Replay)(nil),                    // 20: rvasp.v1.Replay
+	(*Command)(nil),                   // 21: rvasp.v1.Command
+	(*Message)(nil),                   // 22: rvasp.v1.Message
+	(*Empty)(nil),                     // 23: rvasp.v1.Empty
+	(*ServerStatus)(nil),              // 24: rvasp.v1.ServerStatus
+	(*LiveUpdatesStats)(nil),          // 25: rvasp.v1.LiveUpdatesStats
+	(*RateLimitStats)(nil),            // 26: rvasp.v1.RateLimitStats
+	(*PeerRateLimit)(nil),             // 27: rvasp.v1.PeerRateLimit
 }
 var file_rvasp_v1_api_proto_depIdxs = []int32{
 	5,  // 0: rvasp.v1.Transaction.originator:type_name -> rvasp.v1.Account
 	5,  // 1: rvasp.v1.Transaction.beneficiary:type_name -> rvasp.v1.Account
 	0,  // 2: rvasp.v1.Transaction.state:type_name -> rvasp.v1.TransactionState
-	4,  // 3: rvasp.v1.TransferReply.error:type_name -> rvasp.v1.Error
-	6,  // 4: rvasp.v1.TransferReply.transaction:type_name -> rvasp.v1.Transaction
-	4,  // 5: rvasp.v1.AccountReply.error:type_name -> rvasp.v1.Error
-	6,  // 6: rvasp.v1.AccountReply.transactions:type_name -> rvasp.v1.Transaction
-	1,  // 7: rvasp.v1.Command.type:type_name -> rvasp.v1.RPC
-	7,  // 8: rvasp.v1.Command.transfer:type_name -> rvasp.v1.TransferRequest
-	9,  // 9: rvasp.v1.Command.account:type_name -> rvasp.v1.AccountRequest
-	1,  // 10: rvasp.v1.Message.type:type_name -> rvasp.v1.RPC
-	2,  // 11: rvasp.v1.Message.category:type_name -> rvasp.v1.MessageCategory
-	8,  // 12: rvasp.v1.Message.transfer:type_name -> rvasp.v1.TransferReply
-	10, // 13: rvasp.v1.Message.account:type_name -> rvasp.v1.AccountReply
-	3,  // 14: rvasp.v1.ServerStatus.status:type_name -> rvasp.v1.ServerStatus.Status
-	11, // 15: rvasp.v1.TRISADemo.LiveUpdates:input_type -> rvasp.v1.Command
-	7,  // 16: rvasp.v1.TRISAIntegration.Transfer:input_type -> rvasp.v1.TransferRequest
-	9,  // 17: rvasp.v1.TRISAIntegration.AccountStatus:input_type -> rvasp.v1.AccountRequest
-	13, // 18: rvasp.v1.TRISAIntegration.Status:input_type -> rvasp.v1.Empty
-	12, // 19: rvasp.v1.TRISADemo.LiveUpdates:output_type -> rvasp.v1.Message
-	8,  // 20: rvasp.v1.TRISAIntegration.Transfer:output_type -> rvasp.v1.TransferReply
-	10, // 21: rvasp.v1.TRISAIntegration.AccountStatus:output_type -> rvasp.v1.AccountReply
-	14, // 22: rvasp.v1.TRISAIntegration.Status:output_type -> rvasp.v1.ServerStatus
-	19, // [19:23] is the sub-list for method output_type
-	15, // [15:19] is the sub-list for method input_type
-	15, // [15:15] is the sub-list for extension type_name
-	15, // [15:15] is the sub-list for extension extendee
-	0,  // [0:15] is the sub-list for field type_name
+	7,  // 3: rvasp.v1.Transaction.history:type_name -> rvasp.v1.StateTransition
+	0,  // 4: rvasp.v1.StateTransition.previous:type_name -> rvasp.v1.TransactionState
+	0,  // 5: rvasp.v1.StateTransition.state:type_name -> rvasp.v1.TransactionState
+	4,  // 6: rvasp.v1.TransferReply.error:type_name -> rvasp.v1.Error
+	6,  // 7: rvasp.v1.TransferReply.transaction:type_name -> rvasp.v1.Transaction
+	4,  // 8: rvasp.v1.AccountReply.error:type_name -> rvasp.v1.Error
+	6,  // 9: rvasp.v1.AccountReply.transactions:type_name -> rvasp.v1.Transaction
+	6,  // 10: rvasp.v1.TransactionHistoryReply.transaction:type_name -> rvasp.v1.Transaction
+	14, // 11: rvasp.v1.WebhookList.webhooks:type_name -> rvasp.v1.Webhook
+	0,  // 12: rvasp.v1.WebhookDelivery.state:type_name -> rvasp.v1.TransactionState
+	17, // 13: rvasp.v1.WebhookDeliveriesReply.deliveries:type_name -> rvasp.v1.WebhookDelivery
+	2,  // 14: rvasp.v1.Subscription.categories:type_name -> rvasp.v1.MessageCategory
+	1,  // 15: rvasp.v1.Command.type:type_name -> rvasp.v1.RPC
+	8,  // 16: rvasp.v1.Command.transfer:type_name -> rvasp.v1.TransferRequest
+	10, // 17: rvasp.v1.Command.account:type_name -> rvasp.v1.AccountRequest
+	19, // 18: rvasp.v1.Command.subscription:type_name -> rvasp.v1.Subscription
+	20, // 19: rvasp.v1.Command.replay:type_name -> rvasp.v1.Replay
+	1,  // 20: rvasp.v1.Message.type:type_name -> rvasp.v1.RPC
+	2,  // 21: rvasp.v1.Message.category:type_name -> rvasp.v1.MessageCategory
+	9,  // 22: rvasp.v1.Message.transfer:type_name -> rvasp.v1.TransferReply
+	11, // 23: rvasp.v1.Message.account:type_name -> rvasp.v1.AccountReply
+	19, // 24: rvasp.v1.Message.subscription:type_name -> rvasp.v1.Subscription
+	3,  // 25: rvasp.v1.ServerStatus.status:type_name -> rvasp.v1.ServerStatus.Status
+	25, // 26: rvasp.v1.ServerStatus.live_updates:type_name -> rvasp.v1.LiveUpdatesStats
+	26, // 27: rvasp.v1.ServerStatus.rate_limits:type_name -> rvasp.v1.RateLimitStats
+	27, // 28: rvasp.v1.RateLimitStats.peers:type_name -> rvasp.v1.PeerRateLimit
+	21, // 29: rvasp.v1.TRISADemo.LiveUpdates:input_type -> rvasp.v1.Command
+	8,  // 30: rvasp.v1.TRISAIntegration.Transfer:input_type -> rvasp.v1.TransferRequest
+	10, // 31: rvasp.v1.TRISAIntegration.AccountStatus:input_type -> rvasp.v1.AccountRequest
+	12, // 32: rvasp.v1.TRISAIntegration.TransactionHistory:input_type -> rvasp.v1.TransactionHistoryRequest
+	14, // 33: rvasp.v1.TRISAIntegration.RegisterWebhook:input_type -> rvasp.v1.Webhook
+	23, // 34: rvasp.v1.TRISAIntegration.ListWebhooks:input_type -> rvasp.v1.Empty
+	14, // 35: rvasp.v1.TRISAIntegration.DeleteWebhook:input_type -> rvasp.v1.Webhook
+	16, // 36: rvasp.v1.TRISAIntegration.WebhookDeliveries:input_type -> rvasp.v1.WebhookDeliveriesRequest
+	23, // 37: rvasp.v1.TRISAIntegration.Status:input_type -> rvasp.v1.Empty
+	22, // 38: rvasp.v1.TRISADemo.LiveUpdates:output_type -> rvasp.v1.Message
+	9,  // 39: rvasp.v1.TRISAIntegration.Transfer:output_type -> rvasp.v1.TransferReply
+	11, // 40: rvasp.v1.TRISAIntegration.AccountStatus:output_type -> rvasp.v1.AccountReply
+	13, // 41: rvasp.v1.TRISAIntegration.TransactionHistory:output_type -> rvasp.v1.TransactionHistoryReply
+	14, // 42: rvasp.v1.TRISAIntegration.RegisterWebhook:output_type -> rvasp.v1.Webhook
+	15, // 43: rvasp.v1.TRISAIntegration.ListWebhooks:output_type -> rvasp.v1.WebhookList
+	23, // 44: rvasp.v1.TRISAIntegration.DeleteWebhook:output_type -> rvasp.v1.Empty
+	18, // 45: rvasp.v1.TRISAIntegration.WebhookDeliveries:output_type -> rvasp.v1.WebhookDeliveriesReply
+	24, // 46: rvasp.v1.TRISAIntegration.Status:output_type -> rvasp.v1.ServerStatus
+	38, // [38:47] is the sub-list for method output_type
+	29, // [29:38] is the sub-list for method input_type
+	29, // [29:29] is the sub-list for extension type_name
+	29, // [29:29] is the sub-list for extension extendee
+	0,  // [0:29] is the sub-list for field type_name
 }
 
 func init() { file_rvasp_v1_api_proto_init() }
@@ -1453,7 +2635,7 @@ func file_rvasp_v1_api_proto_init() {
 			}
 		}
 		file_rvasp_v1_api_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*TransferRequest); i {
+			switch v := v.(*StateTransition); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1465,7 +2647,7 @@ func file_rvasp_v1_api_proto_init() {
 			}
 		}
 		file_rvasp_v1_api_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*TransferReply); i {
+			switch v := v.(*TransferRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1477,7 +2659,7 @@ func file_rvasp_v1_api_proto_init() {
 			}
 		}
 		file_rvasp_v1_api_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AccountRequest); i {
+			switch v := v.(*TransferReply); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1489,7 +2671,7 @@ func file_rvasp_v1_api_proto_init() {
 			}
 		}
 		file_rvasp_v1_api_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AccountReply); i {
+			switch v := v.(*AccountRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1501,7 +2683,7 @@ func file_rvasp_v1_api_proto_init() {
 			}
 		}
 		file_rvasp_v1_api_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Command); i {
+			switch v := v.(*AccountReply); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1513,7 +2695,7 @@ func file_rvasp_v1_api_proto_init() {
 			}
 		}
 		file_rvasp_v1_api_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Message); i {
+			switch v := v.(*TransactionHistoryRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1525,7 +2707,7 @@ func file_rvasp_v1_api_proto_init() {
 			}
 		}
 		file_rvasp_v1_api_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Empty); i {
+			switch v := v.(*TransactionHistoryReply); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1537,6 +2719,126 @@ func file_rvasp_v1_api_proto_init() {
 			}
 		}
 		file_rvasp_v1_api_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Webhook); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rvasp_v1_api_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WebhookList); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rvasp_v1_api_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WebhookDeliveriesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rvasp_v1_api_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WebhookDelivery); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rvasp_v1_api_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WebhookDeliveriesReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rvasp_v1_api_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Subscription); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rvasp_v1_api_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Replay); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rvasp_v1_api_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Command); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rvasp_v1_api_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Message); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rvasp_v1_api_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Empty); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rvasp_v1_api_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ServerStatus); i {
 			case 0:
 				return &v.state
@@ -1548,14 +2850,53 @@ func file_rvasp_v1_api_proto_init() {
 				return nil
 			}
 		}
+		file_rvasp_v1_api_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LiveUpdatesStats); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rvasp_v1_api_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RateLimitStats); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rvasp_v1_api_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PeerRateLimit); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
-	file_rvasp_v1_api_proto_msgTypes[7].OneofWrappers = []interface{}{
+	file_rvasp_v1_api_proto_msgTypes[17].OneofWrappers = []interface{}{
 		(*Command_Transfer)(nil),
 		(*Command_Account)(nil),
+		(*Command_Subscription)(nil),
+		(*Command_Replay)(nil),
 	}
-	file_rvasp_v1_api_proto_msgTypes[8].OneofWrappers = []interface{}{
+	file_rvasp_v1_api_proto_msgTypes[18].OneofWrappers = []interface{}{
 		(*Message_Transfer)(nil),
 		(*Message_Account)(nil),
+		(*Message_Subscription)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -1563,7 +2904,7 @@ func file_rvasp_v1_api_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_rvasp_v1_api_proto_rawDesc,
 			NumEnums:      4,
-			NumMessages:   11,
+			NumMessages:   24,
 			NumExtensions: 0,
 			NumServices:   2,
 		},
@@ -141,9 +141,14 @@ var TRISADemo_ServiceDesc = grpc.ServiceDesc{
 }
 
 const (
-	TRISAIntegration_Transfer_FullMethodName      = "/rvasp.v1.TRISAIntegration/Transfer"
-	TRISAIntegration_AccountStatus_FullMethodName = "/rvasp.v1.TRISAIntegration/AccountStatus"
-	TRISAIntegration_Status_FullMethodName        = "/rvasp.v1.TRISAIntegration/Status"
+	TRISAIntegration_Transfer_FullMethodName           = "/rvasp.v1.TRISAIntegration/Transfer"
+	TRISAIntegration_AccountStatus_FullMethodName      = "/rvasp.v1.TRISAIntegration/AccountStatus"
+	TRISAIntegration_TransactionHistory_FullMethodName = "/rvasp.v1.TRISAIntegration/TransactionHistory"
+	TRISAIntegration_RegisterWebhook_FullMethodName    = "/rvasp.v1.TRISAIntegration/RegisterWebhook"
+	TRISAIntegration_ListWebhooks_FullMethodName       = "/rvasp.v1.TRISAIntegration/ListWebhooks"
+	TRISAIntegration_DeleteWebhook_FullMethodName      = "/rvasp.v1.TRISAIntegration/DeleteWebhook"
+	TRISAIntegration_WebhookDeliveries_FullMethodName  = "/rvasp.v1.TRISAIntegration/WebhookDeliveries"
+	TRISAIntegration_Status_FullMethodName             = "/rvasp.v1.TRISAIntegration/Status"
 )
 
 // TRISAIntegrationClient is the client API for TRISAIntegration service.
@@ -152,6 +157,11 @@ const (
 type TRISAIntegrationClient interface {
 	Transfer(ctx context.Context, in *TransferRequest, opts ...grpc.CallOption) (*TransferReply, error)
 	AccountStatus(ctx context.Context, in *AccountRequest, opts ...grpc.CallOption) (*AccountReply, error)
+	TransactionHistory(ctx context.Context, in *TransactionHistoryRequest, opts ...grpc.CallOption) (*TransactionHistoryReply, error)
+	RegisterWebhook(ctx context.Context, in *Webhook, opts ...grpc.CallOption) (*Webhook, error)
+	ListWebhooks(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*WebhookList, error)
+	DeleteWebhook(ctx context.Context, in *Webhook, opts ...grpc.CallOption) (*Empty, error)
+	WebhookDeliveries(ctx context.Context, in *WebhookDeliveriesRequest, opts ...grpc.CallOption) (*WebhookDeliveriesReply, error)
 	Status(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ServerStatus, error)
 }
 
@@ -181,6 +191,51 @@ func (c *tRISAIntegrationClient) AccountStatus(ctx context.Context, in *AccountR
 	return out, nil
 }
 
+func (c *tRISAIntegrationClient) TransactionHistory(ctx context.Context, in *TransactionHistoryRequest, opts ...grpc.CallOption) (*TransactionHistoryReply, error) {
+	out := new(TransactionHistoryReply)
+	err := c.cc.Invoke(ctx, TRISAIntegration_TransactionHistory_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tRISAIntegrationClient) RegisterWebhook(ctx context.Context, in *Webhook, opts ...grpc.CallOption) (*Webhook, error) {
+	out := new(Webhook)
+	err := c.cc.Invoke(ctx, TRISAIntegration_RegisterWebhook_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tRISAIntegrationClient) ListWebhooks(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*WebhookList, error) {
+	out := new(WebhookList)
+	err := c.cc.Invoke(ctx, TRISAIntegration_ListWebhooks_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tRISAIntegrationClient) DeleteWebhook(ctx context.Context, in *Webhook, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, TRISAIntegration_DeleteWebhook_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tRISAIntegrationClient) WebhookDeliveries(ctx context.Context, in *WebhookDeliveriesRequest, opts ...grpc.CallOption) (*WebhookDeliveriesReply, error) {
+	out := new(WebhookDeliveriesReply)
+	err := c.cc.Invoke(ctx, TRISAIntegration_WebhookDeliveries_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *tRISAIntegrationClient) Status(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ServerStatus, error) {
 	out := new(ServerStatus)
 	err := c.cc.Invoke(ctx, TRISAIntegration_Status_FullMethodName, in, out, opts...)
@@ -196,6 +251,11 @@ func (c *tRISAIntegrationClient) Status(ctx context.Context, in *Empty, opts ...
 type TRISAIntegrationServer interface {
 	Transfer(context.Context, *TransferRequest) (*TransferReply, error)
 	AccountStatus(context.Context, *AccountRequest) (*AccountReply, error)
+	TransactionHistory(context.Context, *TransactionHistoryRequest) (*TransactionHistoryReply, error)
+	RegisterWebhook(context.Context, *Webhook) (*Webhook, error)
+	ListWebhooks(context.Context, *Empty) (*WebhookList, error)
+	DeleteWebhook(context.Context, *Webhook) (*Empty, error)
+	WebhookDeliveries(context.Context, *WebhookDeliveriesRequest) (*WebhookDeliveriesReply, error)
 	Status(context.Context, *Empty) (*ServerStatus, error)
 	mustEmbedUnimplementedTRISAIntegrationServer()
 }
@@ -210,6 +270,21 @@ func (UnimplementedTRISAIntegrationServer) Transfer(context.Context, *TransferRe
 func (UnimplementedTRISAIntegrationServer) AccountStatus(context.Context, *AccountRequest) (*AccountReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method AccountStatus not implemented")
 }
+func (UnimplementedTRISAIntegrationServer) TransactionHistory(context.Context, *TransactionHistoryRequest) (*TransactionHistoryReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TransactionHistory not implemented")
+}
+func (UnimplementedTRISAIntegrationServer) RegisterWebhook(context.Context, *Webhook) (*Webhook, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterWebhook not implemented")
+}
+func (UnimplementedTRISAIntegrationServer) ListWebhooks(context.Context, *Empty) (*WebhookList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWebhooks not implemented")
+}
+func (UnimplementedTRISAIntegrationServer) DeleteWebhook(context.Context, *Webhook) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteWebhook not implemented")
+}
+func (UnimplementedTRISAIntegrationServer) WebhookDeliveries(context.Context, *WebhookDeliveriesRequest) (*WebhookDeliveriesReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WebhookDeliveries not implemented")
+}
 func (UnimplementedTRISAIntegrationServer) Status(context.Context, *Empty) (*ServerStatus, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Status not implemented")
 }
@@ -262,6 +337,96 @@ func _TRISAIntegration_AccountStatus_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TRISAIntegration_TransactionHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransactionHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TRISAIntegrationServer).TransactionHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TRISAIntegration_TransactionHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TRISAIntegrationServer).TransactionHistory(ctx, req.(*TransactionHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TRISAIntegration_RegisterWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Webhook)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TRISAIntegrationServer).RegisterWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TRISAIntegration_RegisterWebhook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TRISAIntegrationServer).RegisterWebhook(ctx, req.(*Webhook))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TRISAIntegration_ListWebhooks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TRISAIntegrationServer).ListWebhooks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TRISAIntegration_ListWebhooks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TRISAIntegrationServer).ListWebhooks(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TRISAIntegration_DeleteWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Webhook)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TRISAIntegrationServer).DeleteWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TRISAIntegration_DeleteWebhook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TRISAIntegrationServer).DeleteWebhook(ctx, req.(*Webhook))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TRISAIntegration_WebhookDeliveries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WebhookDeliveriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TRISAIntegrationServer).WebhookDeliveries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TRISAIntegration_WebhookDeliveries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TRISAIntegrationServer).WebhookDeliveries(ctx, req.(*WebhookDeliveriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _TRISAIntegration_Status_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(Empty)
 	if err := dec(in); err != nil {
@@ -295,6 +460,26 @@ var TRISAIntegration_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "AccountStatus",
 			Handler:    _TRISAIntegration_AccountStatus_Handler,
 		},
+		{
+			MethodName: "TransactionHistory",
+			Handler:    _TRISAIntegration_TransactionHistory_Handler,
+		},
+		{
+			MethodName: "RegisterWebhook",
+			Handler:    _TRISAIntegration_RegisterWebhook_Handler,
+		},
+		{
+			MethodName: "ListWebhooks",
+			Handler:    _TRISAIntegration_ListWebhooks_Handler,
+		},
+		{
+			MethodName: "DeleteWebhook",
+			Handler:    _TRISAIntegration_DeleteWebhook_Handler,
+		},
+		{
+			MethodName: "WebhookDeliveries",
+			Handler:    _TRISAIntegration_WebhookDeliveries_Handler,
+		},
 		{
 			MethodName: "Status",
 			Handler:    _TRISAIntegration_Status_Handler,
@@ -9,6 +9,7 @@ import (
 
 // Error codes for quick reference and lookups
 const (
+	ErrForbidden = 403
 	ErrNotFound  = 404
 	ErrWrongVASP = 405
 	ErrInternal  = 500
@@ -3,6 +3,7 @@ package rvasp
 import (
 	"crypto/rsa"
 	"fmt"
+	"sync"
 
 	"github.com/rs/zerolog/log"
 	activity "github.com/trisacrypto/directory/pkg/utils/activity"
@@ -40,6 +41,11 @@ func (s *Server) fetchSigningKey(peer *peers.Peer) (key *rsa.PublicKey, err erro
 		return nil, fmt.Errorf("could not fetch endpoint from remote peer: %s", err)
 	}
 
+	// Keys sent by the peer to the KeyExchange handler are cached by the server
+	if key = s.keys.Get(peer.String()); key != nil {
+		return key, nil
+	}
+
 	if peer.SigningKey() == nil {
 		// send key exchange activity to network activity handler
 		activity.KeyExchange().Add()
@@ -56,7 +62,45 @@ func (s *Server) fetchSigningKey(peer *peers.Peer) (key *rsa.PublicKey, err erro
 		}
 	}
 
-	return peer.SigningKey(), nil
+	key = peer.SigningKey()
+	s.keys.Set(peer.String(), key)
+	return key, nil
+}
+
+// signingKey returns the cached signing key of the peer without a key exchange, or nil
+// if the key of the peer is not known.
+func (s *Server) signingKey(peer *peers.Peer) *rsa.PublicKey {
+	if key := s.keys.Get(peer.String()); key != nil {
+		return key
+	}
+	return peer.SigningKey()
+}
+
+// SigningKeys caches the signing keys of remote peers by common name. The keys of a
+// peer are locked for the duration of an outgoing key exchange with the peer, so the
+// KeyExchange handler stores the keys it receives here rather than on the peer;
+// otherwise two rVASPs that exchange keys with each other at the same time would each
+// wait for the other to release its peer.
+type SigningKeys struct {
+	sync.RWMutex
+	keys map[string]*rsa.PublicKey
+}
+
+// Get returns the signing key of the peer or nil if it has not been cached.
+func (k *SigningKeys) Get(commonName string) *rsa.PublicKey {
+	k.RLock()
+	defer k.RUnlock()
+	return k.keys[commonName]
+}
+
+// Set caches the signing key of the peer, replacing any previous key.
+func (k *SigningKeys) Set(commonName string, key *rsa.PublicKey) {
+	k.Lock()
+	defer k.Unlock()
+	if k.keys == nil {
+		k.keys = make(map[string]*rsa.PublicKey)
+	}
+	k.keys[commonName] = key
 }
 
 // resolveEndpoint ensures that the peer has an endpoint to connect to, and performs a
@@ -0,0 +1,52 @@
+package rvasp_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trisacrypto/testnet/pkg/rvasp/harness"
+	pb "github.com/trisacrypto/testnet/pkg/rvasp/pb/v1"
+)
+
+// Both rVASPs start without the signing key of the other, so concurrent transfers in
+// both directions exchange keys with each other at the same time.
+func TestConcurrentKeyExchange(t *testing.T) {
+	h := harness.New(t, "alice", "bob")
+	alice, bob := h.VASP("alice"), h.VASP("bob")
+
+	transfers := []struct {
+		vasp        *harness.VASP
+		account     string
+		beneficiary string
+	}{
+		{alice, "mary@alicevasp.us", "robert@bobvasp.co.uk"},
+		{alice, "sarah@alicevasp.us", "robert@bobvasp.co.uk"},
+		{bob, "robert@bobvasp.co.uk", "mary@alicevasp.us"},
+		{bob, "fred@bobvasp.co.uk", "mary@alicevasp.us"},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 4*len(transfers))
+	for i := 0; i < 4; i++ {
+		for _, tc := range transfers {
+			wg.Add(1)
+			go func(vasp *harness.VASP, account, beneficiary string) {
+				defer wg.Done()
+				_, err := vasp.Client.Transfer(ctx, &pb.TransferRequest{Account: account, Beneficiary: beneficiary, Amount: 0.1, AssetType: "Bitcoin"})
+				errs <- err
+			}(tc.vasp, tc.account, tc.beneficiary)
+		}
+	}
+
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err, "transfers should not block on key exchanges")
+	}
+}
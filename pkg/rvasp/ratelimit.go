@@ -0,0 +1,158 @@
+package rvasp
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/trisacrypto/testnet/pkg/rvasp/config"
+	pb "github.com/trisacrypto/testnet/pkg/rvasp/pb/v1"
+	"golang.org/x/time/rate"
+)
+
+// PeerLimiter rate limits the requests of each TRISA peer with a token bucket keyed by
+// the common name of the mTLS certificate of the peer, so that a single misbehaving
+// peer cannot swamp a shared rVASP. A nil PeerLimiter allows every request.
+type PeerLimiter struct {
+	sync.Mutex
+	conf    config.RateLimitConfig
+	peers   map[string]*peerLimit
+	allowed uint64
+	limited uint64
+}
+
+// peerLimit is the token bucket, quota window, and counters of a single peer.
+type peerLimit struct {
+	bucket    *rate.Limiter
+	window    time.Time
+	used      int
+	allowed   uint64
+	limited   uint64
+	throttled bool
+}
+
+// NewPeerLimiter returns nil if rate limiting is not enabled.
+func NewPeerLimiter(conf config.RateLimitConfig) *PeerLimiter {
+	if !conf.Enabled() {
+		return nil
+	}
+	return &PeerLimiter{conf: conf, peers: make(map[string]*peerLimit)}
+}
+
+// Allow returns true if the peer may send another request. Otherwise it returns how
+// long the peer should wait before retrying.
+func (l *PeerLimiter) Allow(peer string) (ok bool, retryAfter time.Duration) {
+	if l == nil {
+		return true, 0
+	}
+
+	l.Lock()
+	defer l.Unlock()
+	now := time.Now()
+
+	limit, exists := l.peers[peer]
+	if !exists {
+		r, burst := l.limit(peer)
+		limit = &peerLimit{bucket: rate.NewLimiter(r, burst), window: now}
+		l.peers[peer] = limit
+	}
+
+	// Start a new quota window once the current one has elapsed
+	if l.conf.Quota > 0 && now.Sub(limit.window) >= l.conf.QuotaWindow {
+		limit.window = now
+		limit.used = 0
+	}
+
+	switch {
+	case l.conf.Quota > 0 && limit.used >= l.conf.Quota:
+		retryAfter = limit.window.Add(l.conf.QuotaWindow).Sub(now)
+	default:
+		// The burst is at least one so the reservation is always ok
+		reservation := limit.bucket.ReserveN(now, 1)
+		if delay := reservation.DelayFrom(now); delay > 0 {
+			// Return the token, the request is rejected rather than delayed
+			reservation.CancelAt(now)
+			retryAfter = delay
+		} else {
+			limit.used++
+			limit.allowed++
+			limit.throttled = false
+			l.allowed++
+			return true, 0
+		}
+	}
+
+	// Only log the first rejection after an allowed request to avoid flooding the logs
+	// with the requests of a peer in a retry loop.
+	if !limit.throttled {
+		log.Warn().Str("peer", peer).Dur("retry_after", retryAfter).Msg("peer is rate limited")
+		limit.throttled = true
+	}
+	limit.limited++
+	l.limited++
+	return false, retryAfter
+}
+
+// limit returns the rate and burst of the peer, peers without a configured rate are
+// not limited by a token bucket but may still be limited by the quota.
+func (l *PeerLimiter) limit(peer string) (_ rate.Limit, burst int) {
+	r, ok := l.conf.Peers[peer]
+	if !ok {
+		r = l.conf.Rate
+	}
+
+	if burst = l.conf.Burst; burst < 1 {
+		burst = 1
+	}
+
+	if r <= 0 {
+		return rate.Inf, burst
+	}
+	return rate.Limit(r), burst
+}
+
+// Stats returns the rate limit counters of each peer, or nil if rate limiting is not
+// enabled.
+func (l *PeerLimiter) Stats() *pb.RateLimitStats {
+	if l == nil {
+		return nil
+	}
+
+	l.Lock()
+	defer l.Unlock()
+	now := time.Now()
+
+	stats := &pb.RateLimitStats{
+		Rate:    l.conf.Rate,
+		Burst:   uint32(l.conf.Burst),
+		Allowed: l.allowed,
+		Limited: l.limited,
+		Peers:   make([]*pb.PeerRateLimit, 0, len(l.peers)),
+	}
+
+	for peer, limit := range l.peers {
+		ps := &pb.PeerRateLimit{
+			Peer:    peer,
+			Burst:   uint32(limit.bucket.Burst()),
+			Allowed: limit.allowed,
+			Limited: limit.limited,
+		}
+
+		if r := limit.bucket.Limit(); r != rate.Inf {
+			ps.Rate = float64(r)
+		}
+
+		if l.conf.Quota > 0 {
+			if now.Sub(limit.window) >= l.conf.QuotaWindow {
+				ps.QuotaRemaining = uint32(l.conf.Quota)
+			} else if l.conf.Quota > limit.used {
+				ps.QuotaRemaining = uint32(l.conf.Quota - limit.used)
+			}
+		}
+		stats.Peers = append(stats.Peers, ps)
+	}
+
+	sort.Slice(stats.Peers, func(i, j int) bool { return stats.Peers[i].Peer < stats.Peers[j].Peer })
+	return stats
+}
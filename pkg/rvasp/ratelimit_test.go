@@ -0,0 +1,107 @@
+package rvasp_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trisacrypto/testnet/pkg/rvasp"
+	"github.com/trisacrypto/testnet/pkg/rvasp/config"
+	"github.com/trisacrypto/testnet/pkg/rvasp/harness"
+	pb "github.com/trisacrypto/testnet/pkg/rvasp/pb/v1"
+)
+
+func TestPeerLimiter(t *testing.T) {
+	// Rate limiting is disabled by default
+	require.Nil(t, rvasp.NewPeerLimiter(config.RateLimitConfig{Burst: 20}))
+	var disabled *rvasp.PeerLimiter
+	ok, _ := disabled.Allow("alice")
+	require.True(t, ok)
+	require.Nil(t, disabled.Stats())
+
+	limits := rvasp.NewPeerLimiter(config.RateLimitConfig{
+		Rate:  0.001,
+		Burst: 2,
+		Peers: map[string]float64{"loadtest": 1000},
+	})
+
+	// Each peer has its own bucket
+	for _, peer := range []string{"alice", "bob"} {
+		for i := 0; i < 2; i++ {
+			ok, _ := limits.Allow(peer)
+			require.True(t, ok, "request %d of %s", i, peer)
+		}
+
+		ok, retryAfter := limits.Allow(peer)
+		require.False(t, ok)
+		require.Greater(t, retryAfter, time.Minute)
+	}
+
+	// Peers with their own rate refill faster
+	for i := 0; i < 2; i++ {
+		ok, _ := limits.Allow("loadtest")
+		require.True(t, ok)
+	}
+	time.Sleep(10 * time.Millisecond)
+	ok, _ = limits.Allow("loadtest")
+	require.True(t, ok)
+
+	stats := limits.Stats()
+	require.Equal(t, uint64(7), stats.Allowed)
+	require.Equal(t, uint64(2), stats.Limited)
+	require.Len(t, stats.Peers, 3)
+	require.Equal(t, &pb.PeerRateLimit{Peer: "alice", Rate: 0.001, Burst: 2, Allowed: 2, Limited: 1}, stats.Peers[0])
+	require.Equal(t, float64(1000), stats.Peers[2].Rate)
+
+	// Quotas limit the requests in each window even if the bucket is not limited
+	limits = rvasp.NewPeerLimiter(config.RateLimitConfig{Burst: 20, Quota: 2, QuotaWindow: 50 * time.Millisecond})
+	for i := 0; i < 2; i++ {
+		ok, _ := limits.Allow("alice")
+		require.True(t, ok)
+	}
+
+	ok, retryAfter := limits.Allow("alice")
+	require.False(t, ok)
+	require.LessOrEqual(t, retryAfter, 50*time.Millisecond)
+	require.Equal(t, uint32(0), limits.Stats().Peers[0].QuotaRemaining)
+
+	time.Sleep(retryAfter)
+	ok, _ = limits.Allow("alice")
+	require.True(t, ok)
+	require.Equal(t, uint32(1), limits.Stats().Peers[0].QuotaRemaining)
+}
+
+func TestRateLimitedPeer(t *testing.T) {
+	h := harness.NewWithConfig(t, func(conf *config.Config) {
+		conf.RateLimit = config.RateLimitConfig{Rate: 0.001, Burst: 2}
+	}, "alice", "bob")
+	alice, bob := h.VASP("alice"), h.VASP("bob")
+	ctx := context.Background()
+
+	req := &pb.TransferRequest{Account: "mary@alicevasp.us", Beneficiary: "robert@bobvasp.co.uk", Amount: 0.1, AssetType: "Bitcoin"}
+
+	// The key exchange and first transfer use the burst of alice at bob
+	reply, err := alice.Client.Transfer(ctx, req)
+	require.NoError(t, err)
+	require.Nil(t, reply.Error)
+
+	// Further requests from alice are rejected by bob and may be retried
+	reply, err = alice.Client.Transfer(ctx, req)
+	require.NoError(t, err)
+	require.NotNil(t, reply.Error)
+	require.Contains(t, reply.Error.Message, "rate limit exceeded")
+
+	status, err := bob.Client.Status(ctx, &pb.Empty{})
+	require.NoError(t, err)
+	require.NotNil(t, status.RateLimits)
+	require.Len(t, status.RateLimits.Peers, 1)
+	require.Equal(t, alice.Name, status.RateLimits.Peers[0].Peer)
+	require.Equal(t, uint64(2), status.RateLimits.Peers[0].Allowed)
+	require.Equal(t, uint64(1), status.RateLimits.Peers[0].Limited)
+
+	// The rVASPs without rate limits do not report stats
+	status, err = harness.New(t, "charlie").VASP("charlie").Client.Status(ctx, &pb.Empty{})
+	require.NoError(t, err)
+	require.Nil(t, status.RateLimits)
+}
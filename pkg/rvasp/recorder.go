@@ -0,0 +1,124 @@
+package rvasp
+
+import (
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/trisacrypto/testnet/pkg/rvasp/db"
+	pb "github.com/trisacrypto/testnet/pkg/rvasp/pb/v1"
+	protocol "github.com/trisacrypto/trisa/pkg/trisa/api/v1beta1"
+	"github.com/trisacrypto/trisa/pkg/trisa/envelope"
+	"google.golang.org/protobuf/proto"
+)
+
+// Recorder persists the secure envelopes exchanged with remote peers exactly as they
+// were sent or received so that TRISA conversations can be replayed with rvasp replay.
+// A nil Recorder does not record anything so that recording can be disabled without
+// checks at every call site.
+type Recorder struct {
+	db *db.DB
+}
+
+// NewRecorder returns a recorder that stores envelopes in the database of the VASP.
+func NewRecorder(vaspdb *db.DB) *Recorder {
+	return &Recorder{db: vaspdb}
+}
+
+// Exchange starts recording a request and reply exchanged with the named peer.
+func (r *Recorder) Exchange(peer string) *Exchange {
+	if r == nil {
+		return nil
+	}
+	return &Exchange{recorder: r, peer: peer}
+}
+
+// Exchange collects the envelopes of a single request and reply so that they can be
+// saved with the resulting state of the transaction once the exchange is handled.
+type Exchange struct {
+	recorder *Recorder
+	peer     string
+	records  []*db.Envelope
+}
+
+// Inbound records an envelope received from the peer.
+func (e *Exchange) Inbound(msg *protocol.SecureEnvelope) {
+	e.add(db.Inbound, msg)
+}
+
+// Outbound records an envelope sent to the peer.
+func (e *Exchange) Outbound(msg *protocol.SecureEnvelope) {
+	e.add(db.Outbound, msg)
+}
+
+func (e *Exchange) add(direction db.Direction, msg *protocol.SecureEnvelope) {
+	if e == nil || msg == nil {
+		return
+	}
+
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		log.Warn().Err(err).Str("envelope", msg.Id).Msg("could not marshal envelope for recording")
+		return
+	}
+
+	e.records = append(e.records, &db.Envelope{
+		Envelope:  msg.Id,
+		Direction: direction,
+		Peer:      e.peer,
+		Timestamp: time.Now(),
+		Data:      data,
+		VaspID:    e.recorder.db.GetVASP().ID,
+	})
+}
+
+// Reply records the envelope sent in reply to an inbound envelope and saves the
+// exchange with the state of the transaction the local rVASP recorded for the envelope.
+// Rejections are saved as rejected, even if no transaction was recorded for them.
+func (e *Exchange) Reply(out *protocol.SecureEnvelope, handleErr error) {
+	if e == nil || len(e.records) == 0 {
+		return
+	}
+	e.Outbound(out)
+
+	state := pb.TransactionState_INVALID
+	if out != nil && envelope.Status(out) == envelope.Error {
+		state = pb.TransactionState_REJECTED
+	} else {
+		var xfer db.Transaction
+		if err := e.recorder.db.LookupTransaction(e.records[0].Envelope).First(&xfer).Error; err == nil {
+			state = xfer.State
+		}
+	}
+	e.Save(state, handleErr)
+}
+
+// Save the recorded envelopes with the resulting state of the transaction and the error
+// that occurred while handling the exchange, if any. As in the transfer handlers, TRISA
+// errors reject the transaction and any other error fails it. Recording errors are
+// logged rather than returned so that recording never interferes with the exchange.
+func (e *Exchange) Save(state pb.TransactionState, handleErr error) {
+	if e == nil || len(e.records) == 0 {
+		return
+	}
+
+	if handleErr != nil {
+		if _, ok := handleErr.(*protocol.Error); ok {
+			state = pb.TransactionState_REJECTED
+		} else {
+			state = pb.TransactionState_FAILED
+		}
+	}
+
+	for _, record := range e.records {
+		record.State = state
+		record.StateString = state.String()
+		if handleErr != nil {
+			record.Error = handleErr.Error()
+		}
+	}
+
+	if err := e.recorder.db.Create(e.records).Error; err != nil {
+		log.Warn().Err(err).Str("peer", e.peer).Msg("could not record secure envelopes")
+	}
+	e.records = nil
+}
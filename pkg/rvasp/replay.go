@@ -0,0 +1,160 @@
+package rvasp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/trisacrypto/testnet/pkg/rvasp/db"
+	protocol "github.com/trisacrypto/trisa/pkg/trisa/api/v1beta1"
+	"github.com/trisacrypto/trisa/pkg/trisa/envelope"
+	"github.com/trisacrypto/trisa/pkg/trisa/mtls"
+	"github.com/trisacrypto/trisa/pkg/trust"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+// ReplayOptions describe the TRISA node that recorded envelopes are replayed to.
+type ReplayOptions struct {
+	// The endpoint and common name of the TRISA node
+	Target     string
+	CommonName string
+
+	// The direction of the recorded envelopes to resend: outbound envelopes are resent
+	// to the peer they were sent to and inbound envelopes are fed to the rVASP that
+	// received them.
+	Direction db.Direction
+
+	// The timeout of each transfer
+	Timeout time.Duration
+
+	// Additional options used to dial the TRISA node, e.g. a context dialer
+	DialOptions []grpc.DialOption
+}
+
+// Replayer resends recorded secure envelopes to a TRISA node and compares the replies
+// with the recorded replies.
+type Replayer struct {
+	opts   ReplayOptions
+	cc     *grpc.ClientConn
+	client protocol.TRISANetworkClient
+}
+
+// ReplayResult compares the reply to a resent envelope with the recorded reply. Because
+// the replayed envelopes are encrypted as recorded, replies are compared by their state
+// and TRISA error code rather than by their contents.
+type ReplayResult struct {
+	Envelope      string
+	Peer          string
+	RecordedState envelope.State
+	ReplayedState envelope.State
+	RecordedCode  protocol.Error_Code
+	ReplayedCode  protocol.Error_Code
+	Err           error
+}
+
+// Match returns true if the envelope was resent and the reply matches the recorded reply.
+func (r *ReplayResult) Match() bool {
+	return r.Err == nil && r.RecordedState == r.ReplayedState && r.RecordedCode == r.ReplayedCode
+}
+
+// NewReplayer connects to the TRISA node with mTLS using the certificates.
+func NewReplayer(certs *trust.Provider, chain trust.ProviderPool, opts ReplayOptions) (r *Replayer, err error) {
+	if opts.Target == "" || opts.CommonName == "" {
+		return nil, errors.New("the target endpoint and common name are required")
+	}
+
+	switch opts.Direction {
+	case db.Outbound, db.Inbound:
+	case "":
+		opts.Direction = db.Outbound
+	default:
+		return nil, fmt.Errorf("unknown direction %q", opts.Direction)
+	}
+
+	if opts.Timeout == 0 {
+		opts.Timeout = 30 * time.Second
+	}
+
+	var creds grpc.DialOption
+	if creds, err = mtls.ClientCreds(opts.CommonName, certs, chain); err != nil {
+		return nil, err
+	}
+
+	r = &Replayer{opts: opts}
+	dialOpts := append([]grpc.DialOption{creds}, opts.DialOptions...)
+	if r.cc, err = grpc.Dial(opts.Target, dialOpts...); err != nil {
+		return nil, fmt.Errorf("could not dial %s: %s", opts.Target, err)
+	}
+
+	r.client = protocol.NewTRISANetworkClient(r.cc)
+	return r, nil
+}
+
+// Close the connection to the TRISA node.
+func (r *Replayer) Close() error {
+	return r.cc.Close()
+}
+
+// Replay resends the recorded envelopes in the direction of the replayer in the order
+// they were recorded. Each resent envelope is compared with the next recorded envelope
+// in the opposite direction for the same envelope ID, which is the recorded reply.
+func (r *Replayer) Replay(ctx context.Context, records []db.Envelope) (results []*ReplayResult) {
+	for i, record := range records {
+		if record.Direction != r.opts.Direction {
+			continue
+		}
+
+		result := &ReplayResult{Envelope: record.Envelope, Peer: record.Peer}
+		results = append(results, result)
+
+		var reply *protocol.SecureEnvelope
+		if reply, result.Err = recordedReply(records[i+1:], record); result.Err != nil {
+			continue
+		}
+		result.RecordedState, result.RecordedCode = replyStatus(reply)
+
+		in := &protocol.SecureEnvelope{}
+		if result.Err = proto.Unmarshal(record.Data, in); result.Err != nil {
+			result.Err = fmt.Errorf("could not unmarshal recorded envelope: %s", result.Err)
+			continue
+		}
+
+		tctx, cancel := context.WithTimeout(ctx, r.opts.Timeout)
+		out, err := r.client.Transfer(tctx, in)
+		cancel()
+
+		if err != nil {
+			result.Err = fmt.Errorf("could not resend envelope: %s", err)
+			continue
+		}
+		result.ReplayedState, result.ReplayedCode = replyStatus(out)
+	}
+	return results
+}
+
+// recordedReply returns the first envelope in the opposite direction with the same
+// envelope ID as the request from the envelopes recorded after it.
+func recordedReply(records []db.Envelope, request db.Envelope) (reply *protocol.SecureEnvelope, err error) {
+	for _, record := range records {
+		if record.Envelope != request.Envelope || record.Direction == request.Direction {
+			continue
+		}
+
+		reply = &protocol.SecureEnvelope{}
+		if err = proto.Unmarshal(record.Data, reply); err != nil {
+			return nil, fmt.Errorf("could not unmarshal recorded reply: %s", err)
+		}
+		return reply, nil
+	}
+	return nil, errors.New("no reply was recorded for the envelope")
+}
+
+// replyStatus returns the state of the reply and the code of its TRISA error, if any.
+func replyStatus(msg *protocol.SecureEnvelope) (envelope.State, protocol.Error_Code) {
+	if reject, isErr := envelope.Check(msg); isErr && reject != nil {
+		return envelope.Status(msg), reject.Code
+	}
+	return envelope.Status(msg), 0
+}
@@ -0,0 +1,96 @@
+package rvasp_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trisacrypto/testnet/pkg/rvasp"
+	"github.com/trisacrypto/testnet/pkg/rvasp/db"
+	"github.com/trisacrypto/testnet/pkg/rvasp/harness"
+	pb "github.com/trisacrypto/testnet/pkg/rvasp/pb/v1"
+	protocol "github.com/trisacrypto/trisa/pkg/trisa/api/v1beta1"
+	"github.com/trisacrypto/trisa/pkg/trisa/envelope"
+	"google.golang.org/grpc"
+)
+
+func TestRecordReplay(t *testing.T) {
+	h := harness.New(t, "alice", "bob")
+	alice, bob := h.VASP("alice"), h.VASP("bob")
+
+	testCases := []struct {
+		originator string
+		state      pb.TransactionState
+		reply      envelope.State
+	}{
+		{"mary@alicevasp.us", pb.TransactionState_COMPLETED, envelope.Sealed}, // SendPartial to SyncRepair
+		{"sarah@alicevasp.us", pb.TransactionState_REJECTED, envelope.Error},  // SendError
+	}
+
+	for _, tc := range testCases {
+		reply, err := alice.Client.Transfer(context.Background(), &pb.TransferRequest{
+			Account:     tc.originator,
+			Beneficiary: "robert@bobvasp.co.uk",
+			Amount:      1.5,
+			AssetType:   "Bitcoin",
+		})
+		require.NoError(t, err)
+		require.Equal(t, tc.state, reply.Transaction.State)
+		id := reply.Transaction.EnvelopeId
+
+		// Both rVASPs record the request and the reply with the resulting state
+		for _, vasp := range []*harness.VASP{alice, bob} {
+			var records []db.Envelope
+			require.NoError(t, vasp.DB.LookupEnvelopes(id).Find(&records).Error)
+			require.Len(t, records, 2, "%s envelopes recorded by %s", id, vasp.Name)
+
+			for _, record := range records {
+				require.Equal(t, tc.state, record.State)
+				require.NotEmpty(t, record.Data)
+			}
+		}
+
+		// The originator sent the request and received the reply
+		var records []db.Envelope
+		require.NoError(t, alice.DB.LookupEnvelopes(id).Find(&records).Error)
+		require.Equal(t, db.Outbound, records[0].Direction)
+		require.Equal(t, db.Inbound, records[1].Direction)
+		require.Equal(t, bob.Name, records[0].Peer)
+	}
+
+	// Resend the outbound envelopes recorded by alice with alice's certificates to a
+	// bob that has not seen the transactions before
+	var records []db.Envelope
+	require.NoError(t, alice.DB.Query().Order("id").Find(&records).Error)
+
+	fresh := harness.New(t, "alice", "bob").VASP("bob")
+	results := replay(t, alice, fresh, records)
+	require.Len(t, results, len(testCases))
+	for i, result := range results {
+		require.NoError(t, result.Err)
+		require.Equal(t, testCases[i].reply, result.RecordedState)
+		require.True(t, result.Match(), "recorded %s (%s), replayed %s (%s)", result.RecordedState, result.RecordedCode, result.ReplayedState, result.ReplayedCode)
+	}
+
+	// The original bob rejects the envelopes since the transactions are finalized
+	results = replay(t, alice, bob, records)
+	require.Len(t, results, len(testCases))
+	for _, result := range results {
+		require.NoError(t, result.Err)
+		require.Equal(t, envelope.Error, result.ReplayedState)
+		require.Equal(t, protocol.ComplianceCheckFail, result.ReplayedCode)
+	}
+}
+
+// Replay the records with the certificates of the sender to the target rVASP.
+func replay(t *testing.T, sender, target *harness.VASP, records []db.Envelope) []*rvasp.ReplayResult {
+	certs, chain := sender.Certs()
+	replayer, err := rvasp.NewReplayer(certs, chain, rvasp.ReplayOptions{
+		Target:      harness.ServerName,
+		CommonName:  harness.ServerName,
+		DialOptions: []grpc.DialOption{target.TRISADialer()},
+	})
+	require.NoError(t, err)
+	defer replayer.Close()
+	return replayer.Replay(context.Background(), records)
+}
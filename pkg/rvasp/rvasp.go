@@ -4,12 +4,11 @@ import (
 	"context"
 	"crypto/rsa"
 	"crypto/x509"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
-	"math/rand"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"time"
@@ -31,10 +30,10 @@ import (
 	"github.com/trisacrypto/trisa/pkg/trisa/peers"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/encoding/protojson"
-	"google.golang.org/protobuf/types/known/anypb"
 	"gorm.io/gorm"
 )
 
@@ -60,20 +59,64 @@ func New(conf *config.Config) (s *Server, err error) {
 			return nil, err
 		}
 	}
+	setupLogging(conf)
 
-	// Set the global level
-	zerolog.SetGlobalLevel(zerolog.Level(conf.LogLevel))
-
-	// Set human readable logging if specified
-	if conf.ConsoleLog {
-		log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
+	var vaspdb *db.DB
+	if vaspdb, err = db.NewDB(conf); err != nil {
+		return nil, err
 	}
 
-	s = &Server{conf: conf, echan: make(chan error, 1)}
-	if s.db, err = db.NewDB(conf); err != nil {
+	if s, err = newServer(conf, vaspdb, make(chan error, 1)); err != nil {
 		return nil, err
 	}
-	s.vasp = s.db.GetVASP()
+
+	// Start the activity publisher
+	if err = activity.Start(conf.Activity); err != nil {
+		return nil, fmt.Errorf("could not start the activity publisher: %s", err)
+	}
+
+	return s, nil
+}
+
+// NewServer creates a rVASP server for the VASP that the database is restricted to
+// without configuring the global logger or the activity publisher. Use Start or Run to
+// serve requests.
+func NewServer(conf *config.Config, vaspdb *db.DB) (s *Server, err error) {
+	return newServer(conf, vaspdb, make(chan error, 1))
+}
+
+// newServer creates a rVASP server for the VASP that the database is restricted to.
+// Errors from the running servers are sent on the error channel, which may be shared
+// by multiple servers hosted in the same process.
+func newServer(conf *config.Config, vaspdb *db.DB, echan chan error) (s *Server, err error) {
+	s = &Server{conf: conf, db: vaspdb, vasp: vaspdb.GetVASP(), echan: echan}
+
+	// Authenticate requests to the integration and demo APIs if enabled
+	if s.auth, err = NewAuthenticator(s.conf.Auth); err != nil {
+		return nil, fmt.Errorf("could not create authenticator: %s", err)
+	}
+
+	// Initialize the gRPC server with panic recovery and tracing, then authentication
+	unary := []grpc.UnaryServerInterceptor{UnaryTraceInterceptor}
+	stream := []grpc.StreamServerInterceptor{StreamTraceInterceptor}
+	if s.auth != nil {
+		unary = append(unary, s.auth.UnaryInterceptor)
+		stream = append(stream, s.auth.StreamInterceptor)
+	}
+	s.interceptor = chainUnaryInterceptors(unary...)
+
+	opts := []grpc.ServerOption{grpc.UnaryInterceptor(s.interceptor), grpc.ChainStreamInterceptor(stream...)}
+	if s.conf.Auth.TLS() {
+		var creds credentials.TransportCredentials
+		if creds, err = credentials.NewServerTLSFromFile(s.conf.Auth.TLSCertPath, s.conf.Auth.TLSKeyPath); err != nil {
+			return nil, fmt.Errorf("could not load tls credentials: %s", err)
+		}
+		opts = append(opts, grpc.Creds(creds))
+	}
+
+	s.srv = grpc.NewServer(opts...)
+	pb.RegisterTRISADemoServer(s.srv, s)
+	pb.RegisterTRISAIntegrationServer(s.srv, s)
 
 	// Create the TRISA service
 	if s.trisa, err = NewTRISA(s); err != nil {
@@ -89,37 +132,65 @@ func New(conf *config.Config) (s *Server, err error) {
 		// connect insecurely for the purposes of local testing.
 		s.peers.Connect(grpc.WithTransportCredentials(insecure.NewCredentials()))
 	}
-	s.updates = NewUpdateManager()
+	s.updates = NewUpdateManager(s.conf.Updates)
 
-	// Start the activity publisher
-	if err = activity.Start(conf.Activity); err != nil {
-		return nil, fmt.Errorf("could not start the activity publisher: %s", err)
+	// Refuse to do business with counterparties according to the configured rules
+	s.counterparties = NewCounterparties(s.conf.Counterparties, s.db)
+
+	// Screen the names of originators and beneficiaries against the watchlist if enabled
+	if s.screener, err = NewScreener(s.conf.Screening); err != nil {
+		return nil, fmt.Errorf("could not create screener: %s", err)
 	}
 
+	// Record the envelopes exchanged with remote peers if enabled
+	if s.conf.RecordEnvelopes {
+		s.records = NewRecorder(s.db)
+	}
+
+	// Notify the registered webhooks when the state of a transaction changes
+	s.webhooks = NewWebhooks(s.db, s.conf.Webhooks)
+	s.db.Observe(s.webhooks.Notify)
+
+	// Broadcast state transitions so that live updates include asynchronous exchanges
+	s.db.Observe(s.updates.Transition)
 	return s, nil
 }
 
+// setupLogging configures the global logger, which is shared by all rVASPs in the process.
+func setupLogging(conf *config.Config) {
+	// Set the global level
+	zerolog.SetGlobalLevel(zerolog.Level(conf.LogLevel))
+
+	// Set human readable logging if specified
+	if conf.ConsoleLog {
+		log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
+	}
+}
+
 // Server implements the GRPC TRISAIntegration and TRISADemo services.
 type Server struct {
 	pb.UnimplementedTRISADemoServer
 	pb.UnimplementedTRISAIntegrationServer
-	conf    *config.Config
-	srv     *grpc.Server
-	db      *db.DB
-	vasp    db.VASP
-	trisa   *TRISA
-	echan   chan error
-	peers   *peers.Peers
-	updates *UpdateManager
+	conf           *config.Config
+	srv            *grpc.Server
+	interceptor    grpc.UnaryServerInterceptor
+	auth           *Authenticator
+	db             *db.DB
+	vasp           db.VASP
+	trisa          *TRISA
+	echan          chan error
+	peers          *peers.Peers
+	keys           SigningKeys
+	updates        *UpdateManager
+	records        *Recorder
+	counterparties *Counterparties
+	screener       *Screener
+	webhooks       *Webhooks
+	gateway        *http.Server
 }
 
 // Serve GRPC requests on the specified address.
 func (s *Server) Serve() (err error) {
-	// Initialize the gRPC server with panic recovery and tracing
-	s.srv = grpc.NewServer(grpc.UnaryInterceptor(UnaryTraceInterceptor), grpc.StreamInterceptor(StreamTraceInterceptor))
-	pb.RegisterTRISADemoServer(s.srv, s)
-	pb.RegisterTRISAIntegrationServer(s.srv, s)
-
 	// Catch OS signals for graceful shutdowns
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt)
@@ -128,6 +199,21 @@ func (s *Server) Serve() (err error) {
 		s.echan <- s.Shutdown()
 	}()
 
+	if err = s.Start(); err != nil {
+		return err
+	}
+
+	// Listen for any errors that might have occurred and wait for all go routines to finish
+	if err = <-s.echan; err != nil {
+		return err
+	}
+	return nil
+}
+
+// Start the TRISA service and the GRPC server on their bind addresses without
+// blocking. Errors that occur while the servers are running are sent on the error
+// channel of the server.
+func (s *Server) Start() (err error) {
 	// Run the TRISA service on the TRISABindAddr
 	if err = s.trisa.Serve(); err != nil {
 		return err
@@ -136,38 +222,66 @@ func (s *Server) Serve() (err error) {
 	// Listen for TCP requests on the specified address and port
 	var sock net.Listener
 	if sock, err = net.Listen("tcp", s.conf.BindAddr); err != nil {
+		s.trisa.Shutdown()
 		return fmt.Errorf("could not listen on %q", s.conf.BindAddr)
 	}
-	defer sock.Close()
+
+	// Run the HTTP gateway on the gateway bind address if configured
+	if err = s.serveGateway(); err != nil {
+		sock.Close()
+		s.trisa.Shutdown()
+		return err
+	}
 
 	// Run the server
-	go func() {
-		log.Info().
-			Str("listen", s.conf.BindAddr).
-			Str("version", pkg.Version()).
-			Str("name", s.vasp.Name).
-			Msg("server started")
+	log.Info().
+		Str("listen", s.conf.BindAddr).
+		Str("version", pkg.Version()).
+		Str("name", s.vasp.Name).
+		Bool("tls", s.conf.Auth.TLS()).
+		Bool("auth", s.auth != nil).
+		Msg("server started")
+	go s.Run(sock)
 
-		if err := s.srv.Serve(sock); err != nil {
-			s.echan <- err
-		}
-	}()
+	return nil
+}
 
-	// Listen for any errors that might have occurred and wait for all go routines to finish
-	if err = <-s.echan; err != nil {
-		return err
+// Run the gRPC server on the listener. This method is extracted from the Start function
+// so that tests can run the server on a bufconn listener without the TRISA service
+// listening on a live port.
+func (s *Server) Run(sock net.Listener) {
+	defer sock.Close()
+	if err := s.srv.Serve(sock); err != nil {
+		s.echan <- err
 	}
-	return nil
+}
+
+// TRISA returns the TRISA service of the rVASP.
+func (s *Server) TRISA() *TRISA {
+	return s.trisa
+}
+
+// Peers returns the cache of remote TRISA peers used by the rVASP.
+func (s *Server) Peers() *peers.Peers {
+	return s.peers
 }
 
 // Shutdown the rVASP Service gracefully
 func (s *Server) Shutdown() (err error) {
-	log.Info().Msg("gracefully shutting down")
+	log.Info().Str("name", s.vasp.Name).Msg("gracefully shutting down")
+	if s.gateway != nil {
+		// Stops accepting connections; open WebSocket connections are hijacked from the
+		// HTTP server and are closed by the client or when the process exits.
+		if err = s.gateway.Close(); err != nil {
+			log.Warn().Err(err).Msg("could not close http gateway")
+		}
+	}
 	s.srv.GracefulStop()
 	if err = s.trisa.Shutdown(); err != nil {
 		log.Error().Err(err).Msg("could not shutdown trisa server")
 		return err
 	}
+	s.webhooks.Close()
 	log.Debug().Msg("successful shutdown")
 	return nil
 }
@@ -176,6 +290,28 @@ func (s *Server) Shutdown() (err error) {
 // protocol to perform identity verification prior to establishing the transaction in
 // the blockchain between crypto wallet addresses.
 func (s *Server) Transfer(ctx context.Context, req *pb.TransferRequest) (reply *pb.TransferReply, err error) {
+	return s.transfer(ctx, req, newProgress(s.updates, 0, 0))
+}
+
+// transfer performs the transfer request using the originator policy of the account's
+// wallet, broadcasting each step of the exchange to the live updates streams. Both the
+// integration API and the demo perform transfers with this method so that the demo
+// shows exactly the behavior of the rVASP, including asynchronous exchanges.
+func (s *Server) transfer(ctx context.Context, req *pb.TransferRequest, progress *progress) (reply *pb.TransferReply, err error) {
+	progress.Add(req.Account, req.Beneficiary)
+	progress.update(pb.MessageCategory_LEDGER, "starting transaction of %0.2f from %s to %s", req.Amount, req.Account, req.Beneficiary)
+
+	// Reject malformed beneficiary wallet addresses before doing any work
+	if addrError := ValidateBeneficiaryAddress(req.AssetType, req.Beneficiary); addrError != nil {
+		log.Warn().Str("message", addrError.Error()).Msg("invalid beneficiary wallet address")
+		return &pb.TransferReply{
+			Error: &pb.Error{
+				Code:    int32(addrError.Code),
+				Message: addrError.Message,
+			},
+		}, nil
+	}
+
 	// Get originator account and confirm it belongs to this RVASP
 	var account db.Account
 	if err = s.db.LookupAccount(req.Account).First(&account).Error; err != nil {
@@ -187,6 +323,13 @@ func (s *Server) Transfer(ctx context.Context, req *pb.TransferRequest) (reply *
 		return nil, status.Errorf(codes.FailedPrecondition, "could not lookup account: %s", err)
 	}
 
+	// Only transfer from accounts in the scope of the credentials of the request
+	if err = authorize(ctx, account); err != nil {
+		return nil, err
+	}
+	progress.Add(account.Email, account.WalletAddress)
+	progress.update(pb.MessageCategory_LEDGER, "account %04d accessed successfully", account.ID)
+
 	// Retrieve the policy for the originator account
 	var wallet db.Wallet
 	if err = s.db.LookupWallet(account.WalletAddress).First(&wallet).Error; err != nil {
@@ -203,16 +346,42 @@ func (s *Server) Transfer(ctx context.Context, req *pb.TransferRequest) (reply *
 	if beneficiary, err = s.fetchBeneficiaryWallet(req); err != nil {
 		return nil, err
 	}
+	progress.Add(beneficiary.Address, beneficiary.Email)
+	progress.update(pb.MessageCategory_BLOCKCHAIN, "wallet %s provided by %s", beneficiary.Address, beneficiary.Provider.Name)
 
 	// Create a new Transaction
 	var xfer *db.Transaction
-	if xfer, err = s.db.MakeTransaction(account.WalletAddress, beneficiary.Address); err != nil {
+	if xfer, err = s.db.MakeTransaction(account.WalletAddress, beneficiary.Address, db.ActorIntegration); err != nil {
 		return nil, err
 	}
 	xfer.Account = account
 	xfer.Amount = decimal.NewFromFloat32(req.Amount)
 	xfer.AssetType = req.AssetType
 	xfer.Debit = true
+	progress.Envelope = xfer.Envelope
+
+	// Refuse the transfer without contacting the beneficiary VASP if the rVASP does not
+	// do business with it; transfers between local accounts are always allowed.
+	if beneficiary.Provider.Name != s.vasp.Name {
+		if refusal := s.counterparties.Check(beneficiary.Provider.Name, nil); refusal != nil {
+			log.Warn().Str("beneficiary_vasp", beneficiary.Provider.Name).Str("message", refusal.Message).Msg("refusing transfer to counterparty")
+			progress.update(pb.MessageCategory_ERROR, "transaction refused, %s does not do business with %s: %s", s.vasp.Name, beneficiary.Provider.Name, refusal.Message)
+			if err = xfer.SetState(pb.TransactionState_REJECTED, db.ActorIntegration, refusal.Error()); err != nil {
+				log.Error().Err(err).Msg("could not reject transaction")
+				return nil, status.Errorf(codes.FailedPrecondition, "could not reject transaction: %s", err)
+			}
+
+			if err = s.db.Save(xfer).Error; err != nil {
+				log.Error().Err(err).Msg("could not save transaction")
+				return nil, status.Errorf(codes.Internal, "could not save transaction: %s", err)
+			}
+
+			return &pb.TransferReply{
+				Error:       &pb.Error{Code: int32(refusal.Code), Message: refusal.Message},
+				Transaction: xfer.Proto(),
+			}, nil
+		}
+	}
 
 	// Run the scenario for the wallet's configured policy
 	var transferError error
@@ -222,14 +391,14 @@ func (s *Server) Transfer(ctx context.Context, req *pb.TransferRequest) (reply *
 	case db.SendPartial:
 		// Send a transfer request to the beneficiary containing partial beneficiary
 		// identity information.
-		transferError = s.sendTransfer(xfer, beneficiary, true)
+		transferError = s.sendTransfer(xfer, beneficiary, true, progress)
 	case db.SendFull:
 		// Send a transfer request to the beneficiary containing full beneficiary
 		// identity information.
-		transferError = s.sendTransfer(xfer, beneficiary, false)
+		transferError = s.sendTransfer(xfer, beneficiary, false, progress)
 	case db.SendError:
 		// Send a TRISA error to the beneficiary.
-		transferError = s.sendError(xfer, beneficiary)
+		transferError = s.sendError(xfer, beneficiary, progress)
 	default:
 		log.Error().Str("wallet", account.WalletAddress).Str("policy", string(policy)).Msg("unknown policy")
 		return nil, status.Errorf(codes.FailedPrecondition, "unknown originator policy '%s' for wallet '%s'", policy, account.WalletAddress)
@@ -240,18 +409,34 @@ func (s *Server) Transfer(ctx context.Context, req *pb.TransferRequest) (reply *
 
 	// Handle rVASP errors and TRISA protocol errors
 	if transferError != nil {
+		// Rejections by the local sanctions screening are not reported as rejections
+		// by the beneficiary VASP
+		rejectedBy := beneficiary.Provider.Name
+		if local, ok := transferError.(*screeningRejection); ok {
+			rejectedBy = s.vasp.Name + " sanctions screening"
+			transferError = local.reject
+		}
+
 		switch err := transferError.(type) {
 		case *protocol.Error:
 			log.Warn().Str("message", err.Error()).Msg("TRISA protocol error while performing transfer")
+			progress.update(pb.MessageCategory_ERROR, "transaction rejected by %s: %s", rejectedBy, err.Message)
 			reply.Error = &pb.Error{
 				Code:    int32(err.Code),
 				Message: err.Message,
 			}
-			xfer.SetState(pb.TransactionState_REJECTED)
 			transferError = nil
+			if serr := xfer.SetState(pb.TransactionState_REJECTED, db.ActorIntegration, err.Error()); serr != nil {
+				log.Error().Err(serr).Msg("could not reject transaction")
+				transferError = status.Errorf(codes.FailedPrecondition, "could not reject transaction: %s", serr)
+			}
 		default:
 			log.Warn().Err(err).Msg("error while performing transfer")
-			xfer.SetState(pb.TransactionState_FAILED)
+			progress.update(pb.MessageCategory_ERROR, "transaction failed: %s", status.Convert(err).Message())
+			if serr := xfer.SetState(pb.TransactionState_FAILED, db.ActorIntegration, err.Error()); serr != nil {
+				log.Error().Err(serr).Msg("could not fail transaction")
+				transferError = status.Errorf(codes.FailedPrecondition, "could not fail transaction: %s", serr)
+			}
 		}
 	}
 
@@ -270,7 +455,7 @@ func (s *Server) Transfer(ctx context.Context, req *pb.TransferRequest) (reply *
 
 // fetchBeneficiary fetches the beneficiary Wallet from the request.
 func (s *Server) fetchBeneficiaryWallet(req *pb.TransferRequest) (wallet *db.Wallet, err error) {
-	if req.BeneficiaryVasp != "" {
+	if req.BeneficiaryVasp != "" && !req.CheckBeneficiary {
 		// If a beneficiary VASP is provided, assume the transfer is to an external
 		// VASP (not a local wallet)
 		wallet = &db.Wallet{
@@ -292,6 +477,7 @@ func (s *Server) fetchBeneficiaryWallet(req *pb.TransferRequest) (wallet *db.Wal
 			return nil, status.Errorf(codes.FailedPrecondition, "could not lookup beneficiary: %s", err)
 		}
 
+		// The demo UI checks that the wallet belongs to the selected beneficiary VASP
 		if req.CheckBeneficiary {
 			if req.BeneficiaryVasp != wallet.Provider.Name {
 				log.Warn().
@@ -309,16 +495,32 @@ func (s *Server) fetchBeneficiaryWallet(req *pb.TransferRequest) (wallet *db.Wal
 // to the beneficiary. If partial is true, then the full beneficiary identity
 // information is not included in the payload. This function handles pending responses
 // from the beneficiary saving the transaction in an "await" state in the database.
-func (s *Server) sendTransfer(xfer *db.Transaction, beneficiary *db.Wallet, partial bool) (err error) {
+func (s *Server) sendTransfer(xfer *db.Transaction, beneficiary *db.Wallet, partial bool, progress *progress) (err error) {
+	// Screen the beneficiary before the transfer is sent so that the beneficiary VASP
+	// does not complete a transfer that the originator would reject
+	var hit *Hit
+	if hit, err = s.screenBeneficiary(beneficiary); err != nil {
+		return err
+	}
+
+	if hit != nil {
+		progress.update(pb.MessageCategory_TRISAP2P, "beneficiary screening hit: %s", hit)
+		return &screeningRejection{reject: hit.Reject()}
+	}
+
 	// Fetch the remote peer
 	var peer *peers.Peer
+	progress.update(pb.MessageCategory_TRISADS, "search for %s in directory service", beneficiary.Provider.Name)
 	if peer, err = s.fetchPeer(beneficiary.Provider.Name); err != nil {
 		log.Warn().Err(err).Msg("could not fetch beneficiary peer")
 		return status.Errorf(codes.FailedPrecondition, "could not fetch beneficiary peer: %s", err)
 	}
+	info := peer.Info()
+	progress.update(pb.MessageCategory_TRISADS, "identified TRISA remote peer %s at %s via directory service", info.ID, info.Endpoint)
 
 	// Fetch the signing key
 	var signKey *rsa.PublicKey
+	progress.update(pb.MessageCategory_TRISAP2P, "exchanging peer signing keys")
 	if signKey, err = s.fetchSigningKey(peer); err != nil {
 		log.Warn().Err(err).Msg("could not fetch signing key from beneficiary peer")
 		return status.Errorf(codes.FailedPrecondition, "could not fetch signing key from beneficiary peer: %s", err)
@@ -336,6 +538,7 @@ func (s *Server) sendTransfer(xfer *db.Transaction, beneficiary *db.Wallet, part
 		log.Error().Err(err).Msg("could not save originator account")
 		return status.Errorf(codes.FailedPrecondition, "could not save originator account: %s", err)
 	}
+	progress.update(pb.MessageCategory_BLOCKCHAIN, "ready to execute transaction")
 
 	// Create an identity and transaction payload for TRISA exchange
 	transaction := &generic.Transaction{
@@ -373,6 +576,7 @@ func (s *Server) sendTransfer(xfer *db.Transaction, beneficiary *db.Wallet, part
 		log.Error().Err(err).Msg("could not create transfer payload")
 		return status.Errorf(codes.Internal, "could not create transfer payload: %s", err)
 	}
+	progress.update(pb.MessageCategory_TRISAP2P, "transaction and identity payload constructed")
 
 	// Secure the envelope with the remote beneficiary's signing keys
 	msg, _, err := envelope.Seal(payload, envelope.WithEnvelopeID(xfer.Envelope), envelope.WithRSAPublicKey(signKey))
@@ -380,12 +584,20 @@ func (s *Server) sendTransfer(xfer *db.Transaction, beneficiary *db.Wallet, part
 		log.Warn().Err(err).Msg("TRISA protocol error while sealing envelope")
 		return status.Errorf(codes.FailedPrecondition, "TRISA protocol error: %s", err)
 	}
+	progress.update(pb.MessageCategory_TRISAP2P, "secure envelope %s sealed: encrypted with AES-GCM and RSA - sending ...", msg.Id)
+
+	// Record the exchange with the beneficiary if enabled
+	exchange := s.records.Exchange(peer.String())
+	defer func() { exchange.Save(xfer.State, err) }()
 
 	// Conduct the TRISA transaction, handle errors and send back to user
+	exchange.Outbound(msg)
 	if msg, err = peer.Transfer(msg); err != nil {
 		log.Warn().Err(err).Msg("could not perform TRISA exchange")
 		return status.Errorf(codes.FailedPrecondition, "could not perform TRISA exchange: %s", err)
 	}
+	exchange.Inbound(msg)
+	progress.update(pb.MessageCategory_TRISAP2P, "received %s information exchange reply from %s", msg.Id, peer.String())
 
 	// Check for TRISA rejection errors
 	reject, isErr := envelope.Check(msg)
@@ -411,6 +623,7 @@ func (s *Server) sendTransfer(xfer *db.Transaction, beneficiary *db.Wallet, part
 		log.Warn().Str("message", parseError.Message).Msg("TRISA protocol error while parsing payload")
 		return status.Errorf(codes.FailedPrecondition, "TRISA protocol error: %s", parseError.Message)
 	}
+	progress.update(pb.MessageCategory_TRISAP2P, "successfully decrypted and parsed secure envelope")
 
 	// Update the transaction record with the identity payload
 	var data []byte
@@ -432,6 +645,7 @@ func (s *Server) sendTransfer(xfer *db.Transaction, beneficiary *db.Wallet, part
 			log.Warn().Err(err).Msg("TRISA protocol error: could not parse ReplyNotAfter timestamp")
 			return status.Errorf(codes.FailedPrecondition, "TRISA protocol error: could not parse ReplyNotAfter timestamp in pending message: %s", err)
 		}
+		progress.update(pb.MessageCategory_BLOCKCHAIN, "transaction %04d pending: %s will reply between %s and %s", xfer.ID, peer.String(), pending.ReplyNotBefore, pending.ReplyNotAfter)
 	} else if transaction != nil {
 		if !partial {
 			// Validate that the beneficiary identity matches the original request
@@ -463,6 +677,17 @@ func (s *Server) sendTransfer(xfer *db.Transaction, beneficiary *db.Wallet, part
 			}
 		}
 
+		// Screen the beneficiary returned by the beneficiary VASP against the watchlist
+		// in case the beneficiary is not a local account that could be screened before
+		// the transfer was sent. The beneficiary VASP has already accepted the transfer
+		// so it is notified of the rejection.
+		if hit = s.screener.Screen(identity.GetBeneficiary().GetBeneficiaryPersons()); hit != nil {
+			progress.update(pb.MessageCategory_TRISAP2P, "beneficiary screening hit: %s", hit)
+			reject := hit.Reject()
+			s.notifyRejected(peer, xfer, reject, progress)
+			return &screeningRejection{reject: reject}
+		}
+
 		// Update the account information
 		xfer.Account.Pending--
 		xfer.Account.Completed++
@@ -473,21 +698,76 @@ func (s *Server) sendTransfer(xfer *db.Transaction, beneficiary *db.Wallet, part
 		}
 
 		// This transaction is now complete
-		xfer.SetState(pb.TransactionState_COMPLETED)
+		if err = xfer.SetState(pb.TransactionState_COMPLETED, db.ActorIntegration, "beneficiary returned the transaction"); err != nil {
+			log.Error().Err(err).Msg("could not complete transaction")
+			return status.Errorf(codes.FailedPrecondition, "could not complete transaction: %s", err)
+		}
 		xfer.Timestamp, _ = time.Parse(time.RFC3339, transaction.Timestamp)
+
+		progress.update(pb.MessageCategory_BLOCKCHAIN, "transaction %04d complete: %s transferred from %s to %s", xfer.ID, xfer.Amount, xfer.Originator.WalletAddress, xfer.Beneficiary.WalletAddress)
+		progress.update(pb.MessageCategory_LEDGER, "%04d new account balance: %s", xfer.Account.ID, xfer.Account.Balance)
 	}
 
 	return nil
 }
 
+// screenBeneficiary screens the identity of the beneficiary of a transfer against the
+// watchlist if the beneficiary wallet belongs to a known account. Beneficiaries that
+// are not known are screened when their identity is returned by the beneficiary VASP.
+func (s *Server) screenBeneficiary(beneficiary *db.Wallet) (_ *Hit, err error) {
+	if s.screener == nil {
+		return nil, nil
+	}
+
+	var account db.Account
+	if err = s.db.LookupAnyAccount(beneficiary.Address).First(&account).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		log.Error().Err(err).Msg("could not lookup beneficiary account")
+		return nil, status.Errorf(codes.Internal, "could not lookup beneficiary account: %s", err)
+	}
+
+	var person *ivms101.Person
+	if person, err = account.LoadIdentity(); err != nil {
+		log.Error().Err(err).Msg("could not load beneficiary identity")
+		return nil, status.Errorf(codes.Internal, "could not load beneficiary identity: %s", err)
+	}
+	return s.screener.Screen([]*ivms101.Person{person}), nil
+}
+
+// notifyRejected sends a TRISA error to the beneficiary VASP of a transfer that the
+// originator rejected after the beneficiary VASP replied. The notification is best
+// effort, failures are logged but do not change the outcome of the transfer.
+func (s *Server) notifyRejected(peer *peers.Peer, xfer *db.Transaction, reject *protocol.Error, progress *progress) {
+	msg, err := envelope.Reject(reject, envelope.WithEnvelopeID(xfer.Envelope))
+	if err != nil {
+		log.Error().Err(err).Msg("could not create TRISA error envelope")
+		return
+	}
+
+	progress.update(pb.MessageCategory_TRISAP2P, "sending %s error envelope %s to %s", reject.Code, msg.Id, peer.String())
+	if msg, err = peer.Transfer(msg); err != nil {
+		log.Warn().Err(err).Msg("could not notify beneficiary of rejected transfer")
+		return
+	}
+
+	if _, isErr := envelope.Check(msg); !isErr {
+		log.Warn().Str("state", envelope.Status(msg).String()).Msg("unexpected TRISA response, expected reject envelope")
+	}
+}
+
 // sendError sends a TRISA error to the beneficiary.
-func (s *Server) sendError(xfer *db.Transaction, beneficiary *db.Wallet) (err error) {
+func (s *Server) sendError(xfer *db.Transaction, beneficiary *db.Wallet, progress *progress) (err error) {
 	// Fetch the remote peer
 	var peer *peers.Peer
+	progress.update(pb.MessageCategory_TRISADS, "search for %s in directory service", beneficiary.Provider.Name)
 	if peer, err = s.fetchPeer(beneficiary.Provider.Name); err != nil {
 		log.Warn().Err(err).Msg("could not fetch beneficiary peer")
 		return status.Errorf(codes.FailedPrecondition, "could not fetch beneficiary peer: %s", err)
 	}
+	info := peer.Info()
+	progress.update(pb.MessageCategory_TRISADS, "identified TRISA remote peer %s at %s via directory service", info.ID, info.Endpoint)
 
 	reject := protocol.Errorf(protocol.ComplianceCheckFail, "rVASP mock compliance check failed")
 	var msg *protocol.SecureEnvelope
@@ -496,11 +776,18 @@ func (s *Server) sendError(xfer *db.Transaction, beneficiary *db.Wallet) (err er
 		return status.Errorf(codes.Internal, "could not create TRISA error envelope: %s", err)
 	}
 
+	// Record the exchange with the beneficiary if enabled
+	exchange := s.records.Exchange(peer.String())
+	defer func() { exchange.Save(xfer.State, err) }()
+
 	// Conduct the TRISA transaction, handle errors and send back to user
+	progress.update(pb.MessageCategory_TRISAP2P, "sending %s error envelope %s to %s", reject.Code, msg.Id, peer.String())
+	exchange.Outbound(msg)
 	if msg, err = peer.Transfer(msg); err != nil {
 		log.Warn().Err(err).Msg("could not perform TRISA exchange")
 		return status.Errorf(codes.FailedPrecondition, "could not perform TRISA exchange: %s", err)
 	}
+	exchange.Inbound(msg)
 
 	// Check for the TRISA rejection error
 	reject, isErr := envelope.Check(msg)
@@ -509,8 +796,8 @@ func (s *Server) sendError(xfer *db.Transaction, beneficiary *db.Wallet) (err er
 		log.Warn().Str("state", state.String()).Msg("unexpected TRISA response, expected reject envelope")
 		return fmt.Errorf("expected TRISA rejection error, received envelope in state %s", state.String())
 	}
-	xfer.SetState(pb.TransactionState_REJECTED)
 
+	// The caller rejects the transaction with the returned TRISA error
 	return reject
 }
 
@@ -526,6 +813,17 @@ func (s *Server) respondAsync(peer *peers.Peer, payload *protocol.Payload, ident
 		return nil, protocol.Errorf(protocol.ComplianceCheckFail, "received expired transaction")
 	}
 
+	// Screen the beneficiary returned by the beneficiary VASP against the watchlist
+	if hit := s.screener.Screen(identity.GetBeneficiary().GetBeneficiaryPersons()); hit != nil {
+		s.updates.Broadcast(0, NewTopic(xfer.Envelope), fmt.Sprintf("beneficiary screening hit: %s", hit), pb.MessageCategory_TRISAP2P)
+		transferError = hit.Reject()
+		if err := xfer.SetState(pb.TransactionState_REJECTED, db.ActorPeer, transferError.Error()); err != nil {
+			log.Error().Err(err).Msg("could not reject transaction")
+			return nil, protocol.Errorf(protocol.ComplianceCheckFail, "could not reject transaction: %s", err)
+		}
+		return nil, transferError
+	}
+
 	// Fetch the signing key from the remote peer
 	var signKey *rsa.PublicKey
 	var err error
@@ -569,7 +867,10 @@ func (s *Server) respondAsync(peer *peers.Peer, payload *protocol.Payload, ident
 			if out, err = envelope.Reject(reject, envelope.WithEnvelopeID(xfer.Envelope)); err != nil {
 				return nil, protocol.Errorf(protocol.EnvelopeDecodeFail, "TRISA protocol error: %s", err)
 			}
-			xfer.SetState(pb.TransactionState_REJECTED)
+			if err = xfer.SetState(pb.TransactionState_REJECTED, db.ActorPeer, reject.Error()); err != nil {
+				log.Error().Err(err).Msg("could not reject transaction")
+				return nil, protocol.Errorf(protocol.ComplianceCheckFail, "could not reject transaction: %s", err)
+			}
 			return out, nil
 		}
 		log.Warn().Err(err).Msg("TRISA protocol error while sealing envelope")
@@ -580,7 +881,10 @@ func (s *Server) respondAsync(peer *peers.Peer, payload *protocol.Payload, ident
 	switch xfer.State {
 	case pb.TransactionState_AWAITING_REPLY:
 		// Mark the transaction as pending for the async routine
-		xfer.SetState(pb.TransactionState_PENDING_RECEIVED)
+		if err = xfer.SetState(pb.TransactionState_PENDING_RECEIVED, db.ActorPeer, "beneficiary continued the pending transaction"); err != nil {
+			log.Error().Err(err).Msg("could not update transaction state")
+			return nil, protocol.Errorf(protocol.ComplianceCheckFail, "could not update transaction state: %s", err)
+		}
 	case pb.TransactionState_ACCEPTED:
 		// The handshake is complete, finalize the transaction
 		var account db.Account
@@ -598,7 +902,10 @@ func (s *Server) respondAsync(peer *peers.Peer, payload *protocol.Payload, ident
 			log.Error().Err(err).Msg("could not save originator account")
 			return nil, protocol.Errorf(protocol.InternalError, "could not save originator account: %s", err)
 		}
-		xfer.SetState(pb.TransactionState_COMPLETED)
+		if err = xfer.SetState(pb.TransactionState_COMPLETED, db.ActorPeer, "beneficiary completed the transaction"); err != nil {
+			log.Error().Err(err).Msg("could not complete transaction")
+			return nil, protocol.Errorf(protocol.ComplianceCheckFail, "could not complete transaction: %s", err)
+		}
 	default:
 		log.Error().Str("state", xfer.State.String()).Msg("unexpected transaction state")
 		return nil, protocol.Errorf(protocol.ComplianceCheckFail, "unexpected transaction state: %s", xfer.State.String())
@@ -660,11 +967,17 @@ func (s *Server) continueAsync(xfer *db.Transaction) (err error) {
 		return fmt.Errorf("TRISA protocol error: %s", err)
 	}
 
+	// Record the exchange with the beneficiary if enabled
+	exchange := s.records.Exchange(peer.String())
+	defer func() { exchange.Save(xfer.State, err) }()
+
 	// Conduct the TRISA transaction, handle errors and send back to user
+	exchange.Outbound(msg)
 	if msg, err = peer.Transfer(msg); err != nil {
 		log.Warn().Err(err).Msg("could not perform TRISA exchange")
 		return fmt.Errorf("could not perform TRISA exchange: %s", err)
 	}
+	exchange.Inbound(msg)
 
 	// Open the response envelope with local private keys
 	payload, _, err = envelope.Open(msg, envelope.WithRSAPrivateKey(s.trisa.sign))
@@ -712,7 +1025,10 @@ func (s *Server) continueAsync(xfer *db.Transaction) (err error) {
 		return fmt.Errorf("TRISA protocol error: could not parse ReplyNotAfter timestamp: %s", err)
 	}
 
-	xfer.SetState(pb.TransactionState_ACCEPTED)
+	if err = xfer.SetState(pb.TransactionState_ACCEPTED, db.ActorAsync, "beneficiary acknowledged the transaction"); err != nil {
+		log.Error().Err(err).Msg("could not accept transaction")
+		return fmt.Errorf("could not accept transaction: %s", err)
+	}
 	return nil
 }
 
@@ -731,6 +1047,10 @@ func (s *Server) AccountStatus(ctx context.Context, req *pb.AccountRequest) (rep
 		return nil, status.Errorf(codes.FailedPrecondition, "could not lookup account: %s", err)
 	}
 
+	if err = authorize(ctx, account); err != nil {
+		return nil, err
+	}
+
 	rep.Name = account.Name
 	rep.Email = account.Email
 	rep.WalletAddress = account.WalletAddress
@@ -747,7 +1067,17 @@ func (s *Server) AccountStatus(ctx context.Context, req *pb.AccountRequest) (rep
 
 		rep.Transactions = make([]*pb.Transaction, 0, len(transactions))
 		for _, transaction := range transactions {
-			rep.Transactions = append(rep.Transactions, transaction.Proto())
+			if !req.History {
+				rep.Transactions = append(rep.Transactions, transaction.Proto())
+				continue
+			}
+
+			var msg *pb.Transaction
+			if msg, err = s.transactionHistory(transaction); err != nil {
+				log.Error().Err(err).Msg("could not get transaction history")
+				return nil, status.Errorf(codes.FailedPrecondition, "could not get transaction history: %s", err)
+			}
+			rep.Transactions = append(rep.Transactions, msg)
 		}
 	}
 
@@ -758,28 +1088,290 @@ func (s *Server) AccountStatus(ctx context.Context, req *pb.AccountRequest) (rep
 	return rep, nil
 }
 
+// TransactionHistory is a debugging RPC that returns the state transitions of the
+// transaction with the envelope ID, e.g. to find out why a transaction failed or how
+// long it waited for an asynchronous reply.
+func (s *Server) TransactionHistory(ctx context.Context, req *pb.TransactionHistoryRequest) (rep *pb.TransactionHistoryReply, err error) {
+	if req.EnvelopeId == "" {
+		return nil, status.Error(codes.InvalidArgument, "specify the envelope id of the transaction")
+	}
+
+	var xfer db.Transaction
+	if err = s.db.LookupTransaction(req.EnvelopeId).Preload("Account").First(&xfer).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Info().Str("envelope", req.EnvelopeId).Msg("transaction not found")
+			return nil, status.Error(codes.NotFound, "transaction not found")
+		}
+		log.Warn().Err(err).Msg("could not lookup transaction")
+		return nil, status.Errorf(codes.FailedPrecondition, "could not lookup transaction: %s", err)
+	}
+
+	if err = authorize(ctx, xfer.Account); err != nil {
+		return nil, err
+	}
+
+	rep = &pb.TransactionHistoryReply{}
+	if rep.Transaction, err = s.transactionHistory(xfer); err != nil {
+		log.Error().Err(err).Msg("could not get transaction history")
+		return nil, status.Errorf(codes.FailedPrecondition, "could not get transaction history: %s", err)
+	}
+
+	log.Info().
+		Str("envelope", req.EnvelopeId).
+		Int("transitions", len(rep.Transaction.History)).
+		Msg("transaction history")
+	return rep, nil
+}
+
+// transactionHistory returns the transaction with its state transitions.
+func (s *Server) transactionHistory(xfer db.Transaction) (msg *pb.Transaction, err error) {
+	var history []db.StateTransition
+	if err = s.db.LookupHistory(&xfer).Find(&history).Error; err != nil {
+		return nil, err
+	}
+
+	msg = xfer.Proto()
+	msg.History = make([]*pb.StateTransition, 0, len(history))
+	for _, transition := range history {
+		msg.History = append(msg.History, transition.Proto())
+	}
+	return msg, nil
+}
+
+// RegisterWebhook registers a URL to receive an event whenever the state of a
+// transaction changes, either for every account of the rVASP or for a single account.
+// The secret used to sign the events is only returned by this RPC.
+func (s *Server) RegisterWebhook(ctx context.Context, req *pb.Webhook) (rep *pb.Webhook, err error) {
+	if req.Url == "" {
+		return nil, status.Error(codes.InvalidArgument, "specify the url of the webhook")
+	}
+
+	if err = s.webhooks.ValidateURL(req.Url); err != nil {
+		log.Info().Err(err).Str("url", req.Url).Msg("invalid webhook url")
+		return nil, status.Errorf(codes.InvalidArgument, "invalid webhook url %q: %s", req.Url, err)
+	}
+
+	// Webhooks for every account may only be registered by credentials for all accounts
+	if req.Account == "" && !ScopeFromContext(ctx).All() {
+		return nil, status.Error(codes.PermissionDenied, "credentials do not allow access to every account, specify the account of the webhook")
+	}
+
+	hook := &db.Webhook{
+		URL:    req.Url,
+		Secret: req.Secret,
+		VaspID: s.vasp.ID,
+	}
+
+	if req.Account != "" {
+		var account db.Account
+		if err = s.db.LookupAccount(req.Account).First(&account).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				log.Info().Str("account", req.Account).Msg("not found")
+				return nil, status.Error(codes.NotFound, "account not found")
+			}
+			log.Error().Err(err).Msg("could not lookup account")
+			return nil, status.Errorf(codes.FailedPrecondition, "could not lookup account: %s", err)
+		}
+
+		if err = authorize(ctx, account); err != nil {
+			return nil, err
+		}
+		hook.AccountID = &account.ID
+		hook.Account = &account
+	}
+
+	if hook.Secret == "" {
+		if hook.Secret, err = generateWebhookSecret(); err != nil {
+			log.Error().Err(err).Msg("could not generate webhook secret")
+			return nil, status.Errorf(codes.Internal, "could not generate webhook secret: %s", err)
+		}
+	}
+
+	if err = s.db.Create(hook).Error; err != nil {
+		log.Error().Err(err).Msg("could not create webhook")
+		return nil, status.Errorf(codes.Internal, "could not create webhook: %s", err)
+	}
+
+	rep = webhookProto(*hook)
+	rep.Secret = hook.Secret
+
+	log.Info().Uint("id", hook.ID).Str("url", hook.URL).Str("account", rep.Account).Msg("webhook registered")
+	return rep, nil
+}
+
+// ListWebhooks returns the webhooks registered with the rVASP without their secrets.
+func (s *Server) ListWebhooks(ctx context.Context, req *pb.Empty) (rep *pb.WebhookList, err error) {
+	var hooks []db.Webhook
+	if err = s.db.Query().Preload("Account").Order("id").Find(&hooks).Error; err != nil {
+		log.Error().Err(err).Msg("could not list webhooks")
+		return nil, status.Errorf(codes.FailedPrecondition, "could not list webhooks: %s", err)
+	}
+
+	rep = &pb.WebhookList{Webhooks: make([]*pb.Webhook, 0, len(hooks))}
+	for _, hook := range hooks {
+		if !webhookInScope(ctx, hook) {
+			continue
+		}
+		rep.Webhooks = append(rep.Webhooks, webhookProto(hook))
+	}
+	return rep, nil
+}
+
+// DeleteWebhook stops sending events to the webhook with the specified ID and drops its
+// pending retries. The delivery log of the webhook is kept.
+func (s *Server) DeleteWebhook(ctx context.Context, req *pb.Webhook) (rep *pb.Empty, err error) {
+	if req.Id == 0 {
+		return nil, status.Error(codes.InvalidArgument, "specify the id of the webhook")
+	}
+
+	var hook db.Webhook
+	if err = s.db.Query().Preload("Account").First(&hook, req.Id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Error(codes.NotFound, "webhook not found")
+		}
+		log.Error().Err(err).Msg("could not lookup webhook")
+		return nil, status.Errorf(codes.FailedPrecondition, "could not lookup webhook: %s", err)
+	}
+
+	if !webhookInScope(ctx, hook) {
+		return nil, status.Error(codes.PermissionDenied, "credentials do not allow access to the account of the webhook")
+	}
+
+	var tx *gorm.DB
+	if tx = s.db.Query().Delete(&db.Webhook{}, hook.ID); tx.Error != nil {
+		log.Error().Err(tx.Error).Msg("could not delete webhook")
+		return nil, status.Errorf(codes.FailedPrecondition, "could not delete webhook: %s", tx.Error)
+	}
+
+	if tx.RowsAffected == 0 {
+		return nil, status.Error(codes.NotFound, "webhook not found")
+	}
+	s.webhooks.Remove(hook.ID)
+
+	log.Info().Uint64("id", req.Id).Msg("webhook deleted")
+	return &pb.Empty{}, nil
+}
+
+// WebhookDeliveries returns the delivery log of the webhooks registered with the rVASP
+// to debug webhook receivers.
+func (s *Server) WebhookDeliveries(ctx context.Context, req *pb.WebhookDeliveriesRequest) (rep *pb.WebhookDeliveriesReply, err error) {
+	query := s.db.LookupDeliveries()
+	if req.WebhookId != 0 {
+		query = query.Where("webhook_id = ?", req.WebhookId)
+	}
+
+	if req.EnvelopeId != "" {
+		query = query.Where("envelope = ?", req.EnvelopeId)
+	}
+
+	var deliveries []db.WebhookDelivery
+	if err = query.Find(&deliveries).Error; err != nil {
+		log.Error().Err(err).Msg("could not lookup webhook deliveries")
+		return nil, status.Errorf(codes.FailedPrecondition, "could not lookup webhook deliveries: %s", err)
+	}
+
+	// Only return the deliveries of the webhooks in the scope of the credentials
+	var hooks map[uint]bool
+	if !ScopeFromContext(ctx).All() {
+		var registered []db.Webhook
+		if err = s.db.Query().Unscoped().Preload("Account").Find(&registered).Error; err != nil {
+			log.Error().Err(err).Msg("could not lookup webhooks")
+			return nil, status.Errorf(codes.FailedPrecondition, "could not lookup webhooks: %s", err)
+		}
+
+		hooks = make(map[uint]bool, len(registered))
+		for _, hook := range registered {
+			hooks[hook.ID] = webhookInScope(ctx, hook)
+		}
+	}
+
+	rep = &pb.WebhookDeliveriesReply{Deliveries: make([]*pb.WebhookDelivery, 0, len(deliveries))}
+	for _, delivery := range deliveries {
+		if hooks != nil && !hooks[delivery.WebhookID] {
+			continue
+		}
+
+		rep.Deliveries = append(rep.Deliveries, &pb.WebhookDelivery{
+			WebhookId:  uint64(delivery.WebhookID),
+			EventId:    delivery.Event,
+			EnvelopeId: delivery.Envelope,
+			State:      delivery.State,
+			Attempt:    uint32(delivery.Attempt),
+			StatusCode: int32(delivery.StatusCode),
+			Error:      delivery.Error,
+			Delivered:  delivery.Delivered,
+			Timestamp:  delivery.Timestamp.Format(time.RFC3339),
+		})
+	}
+	return rep, nil
+}
+
+// webhookInScope returns true if the credentials of the request may access the webhook;
+// webhooks for every account are only in the scope of credentials for all accounts.
+func webhookInScope(ctx context.Context, hook db.Webhook) bool {
+	scope := ScopeFromContext(ctx)
+	if hook.Account == nil {
+		return scope.All()
+	}
+	return scope.Allows(hook.Account.Email, hook.Account.WalletAddress)
+}
+
+// webhookProto converts the webhook into a protocol buffer message without its secret.
+func webhookProto(hook db.Webhook) *pb.Webhook {
+	msg := &pb.Webhook{
+		Id:      uint64(hook.ID),
+		Url:     hook.URL,
+		Created: hook.CreatedAt.Format(time.RFC3339),
+	}
+
+	if hook.Account != nil {
+		msg.Account = hook.Account.Email
+	}
+	return msg
+}
+
 // LiveUpdates is a demo bidirectional RPC that allows demo clients to explicitly show
 // the message interchange between VASPs during the InterVASP protocol. The demo client
 // connects to both sides of a transaction and can push commands to the stream; any
 // messages received by the VASP as they perform the protocol are sent down to the UI.
 func (s *Server) LiveUpdates(stream pb.TRISADemo_LiveUpdatesServer) (err error) {
 	var (
-		client   string
-		messages uint64
+		client       string
+		messages     uint64
+		disconnected <-chan error
 	)
 
 	ctx := stream.Context()
 
-	for {
+	// Receive commands in a separate go routine so that the stream can be closed when
+	// the client is disconnected by the update manager.
+	commands := make(chan *pb.Command)
+	recvErr := make(chan error, 1)
+	go func() {
+		for {
+			req, err := stream.Recv()
+			if err != nil {
+				recvErr <- err
+				return
+			}
 
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
+			select {
+			case commands <- req:
+			case <-ctx.Done():
+				return
+			}
 		}
+	}()
 
+	for {
 		var req *pb.Command
-		if req, err = stream.Recv(); err != nil {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err = <-disconnected:
+			log.Warn().Err(err).Str("client", client).Msg("live updates client disconnected")
+			return status.Error(codes.ResourceExhausted, err.Error())
+		case err = <-recvErr:
 			// The stream was closed on the client side
 			if err == io.EOF {
 				if client == "" {
@@ -792,16 +1384,18 @@ func (s *Server) LiveUpdates(stream pb.TRISADemo_LiveUpdatesServer) (err error)
 			// Some other error occurred
 			log.Error().Err(err).Str("client", client).Msg("connection dropped")
 			return nil
+		case req = <-commands:
 		}
 
 		// If this is the first time we've seen the client, log it
 		if client == "" {
 			client = req.Client
-			if err = s.updates.Add(client, stream); err != nil {
+			if err = s.updates.Add(client, stream, ScopeFromContext(ctx)); err != nil {
 				log.Error().Err(err).Msg("could not create client updater")
 				return err
 			}
 			log.Info().Str("client", client).Msg("connected to live updates")
+			disconnected = s.updates.Disconnected(client)
 			defer s.updates.Del(client)
 
 		} else if client != req.Client {
@@ -832,7 +1426,7 @@ func (s *Server) LiveUpdates(stream pb.TRISADemo_LiveUpdatesServer) (err error)
 			}
 		case pb.RPC_ACCOUNT:
 			var rep *pb.AccountReply
-			if rep, err = s.AccountStatus(context.Background(), req.GetAccount()); err != nil {
+			if rep, err = s.AccountStatus(ctx, req.GetAccount()); err != nil {
 				return err
 			}
 
@@ -848,291 +1442,155 @@ func (s *Server) LiveUpdates(stream pb.TRISADemo_LiveUpdatesServer) (err error)
 				return err
 			}
 		case pb.RPC_TRANSFER:
-			if err = s.handleTransaction(client, req); err != nil {
+			if err = s.handleTransaction(ctx, client, req); err != nil {
 				log.Error().Err(err).Msg("could not handle transaction")
 				return err
 			}
-		}
-	}
-}
-
-// NOTE: this adds in some purposeful latency to make the demo easier to see
-func (s *Server) handleTransaction(client string, req *pb.Command) (err error) {
-	// Get the transfer from the original command, will panic if nil
-	transfer := req.GetTransfer()
-	message := fmt.Sprintf("starting transaction of %0.2f from %s to %s", transfer.Amount, transfer.Account, transfer.Beneficiary)
-	s.updates.Broadcast(req.Id, message, pb.MessageCategory_LEDGER)
-	time.Sleep(time.Duration(rand.Int63n(1000)) * time.Millisecond)
-
-	// Handle Demo UI errors before the account lookup
-	if transfer.OriginatingVasp != "" && transfer.OriginatingVasp != s.vasp.Name {
-		log.Info().Str("requested", transfer.OriginatingVasp).Str("local", s.vasp.Name).Msg("requested originator does not match local VASP")
-		return s.updates.SendTransferError(client, req.Id,
-			pb.Errorf(pb.ErrWrongVASP, "message sent to the wrong originator VASP"),
-		)
-	}
-
-	// Lookup the account associated with the transfer originator
-	var account db.Account
-	if err = s.db.LookupAccount(transfer.Account).First(&account).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			log.Info().Str("account", transfer.Account).Msg("not found")
-			return s.updates.SendTransferError(client, req.Id,
-				pb.Errorf(pb.ErrNotFound, "account not found"),
-			)
-		}
-		return fmt.Errorf("could not fetch account: %s", err)
-	}
-	s.updates.Broadcast(req.Id, fmt.Sprintf("account %04d accessed successfully", account.ID), pb.MessageCategory_LEDGER)
-	time.Sleep(time.Duration(rand.Int63n(1000)) * time.Millisecond)
+		case pb.RPC_SUBSCRIBE:
+			// Replace the subscription and echo it back as an acknowledgement
+			subscription := req.GetSubscription()
+			if subscription == nil {
+				subscription = &pb.Subscription{}
+			}
 
-	// Lookup the wallet of the beneficiary
-	var beneficiary db.Wallet
-	if err = s.db.LookupBeneficiary(transfer.Beneficiary).First(&beneficiary).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			log.Info().Str("beneficiary", transfer.Beneficiary).Msg("not found")
-			return s.updates.SendTransferError(client, req.Id,
-				pb.Errorf(pb.ErrNotFound, "beneficiary wallet not found"),
-			)
-		}
-		return fmt.Errorf("could not fetch beneficiary wallet: %s", err)
-	}
+			if err = s.authorizeSubscription(ctx, subscription); err != nil {
+				return err
+			}
 
-	if transfer.CheckBeneficiary {
-		if transfer.BeneficiaryVasp != beneficiary.Provider.Name {
+			if err = s.updates.Subscribe(client, subscription); err != nil {
+				log.Error().Err(err).Str("client", client).Msg("could not update subscription")
+				return err
+			}
 			log.Info().
-				Str("expected", transfer.BeneficiaryVasp).
-				Str("actual", beneficiary.Provider.Name).
-				Msg("check beneficiary failed")
-			return s.updates.SendTransferError(client, req.Id,
-				pb.Errorf(pb.ErrWrongVASP, "beneficiary wallet does not match beneficiary vasp"),
-			)
-		}
-	}
-	s.updates.Broadcast(req.Id, fmt.Sprintf("wallet %s provided by %s", beneficiary.Address, beneficiary.Provider.Name), pb.MessageCategory_BLOCKCHAIN)
-	time.Sleep(time.Duration(rand.Int63n(1000)) * time.Millisecond)
-
-	// TODO: lookup peer from cache rather than always doing a directory service lookup
-	var peer *peers.Peer
-	s.updates.Broadcast(req.Id, fmt.Sprintf("search for %s in directory service", beneficiary.Provider.Name), pb.MessageCategory_TRISADS)
-	// send search request activity to network activity handler
-	activity.Search().Add()
-	if peer, err = s.peers.Search(beneficiary.Provider.Name); err != nil {
-		log.Error().Err(err).Msg("could not search peer from directory service")
-		return s.updates.SendTransferError(client, req.Id,
-			pb.Errorf(pb.ErrInternal, "could not search peer from directory service"),
-		)
-	}
-	info := peer.Info()
-	s.updates.Broadcast(req.Id, fmt.Sprintf("identified TRISA remote peer %s at %s via directory service", info.ID, info.Endpoint), pb.MessageCategory_TRISADS)
-	time.Sleep(time.Duration(rand.Int63n(1000)) * time.Millisecond)
-
-	var signKey *rsa.PublicKey
-	s.updates.Broadcast(req.Id, "exchanging peer signing keys", pb.MessageCategory_TRISAP2P)
-	time.Sleep(time.Duration(rand.Int63n(1000)) * time.Millisecond)
-	if signKey, err = peer.ExchangeKeys(true); err != nil {
-		log.Error().Err(err).Msg("could not exchange keys with remote peer")
-		return s.updates.SendTransferError(client, req.Id,
-			pb.Errorf(pb.ErrInternal, "could not exchange keys with remote peer"),
-		)
-	}
-
-	// Prepare the transaction
-	// Save the pending transaction and increment the accounts pending field
-	xfer := db.Transaction{
-		Envelope: uuid.New().String(),
-		Account:  account,
-		Amount:   decimal.NewFromFloat32(transfer.Amount),
-		Debit:    true,
-		State:    pb.TransactionState_AWAITING_REPLY,
-		Vasp:     s.vasp,
-	}
-
-	if err = s.db.Save(&xfer).Error; err != nil {
-		log.Error().Err(err).Msg("could not save pending transaction")
-		return s.updates.SendTransferError(client, req.Id,
-			pb.Errorf(pb.ErrInternal, "could not save pending transaction"),
-		)
-	}
-
-	// Save the pending transaction on the account
-	// TODO: remove pending transactions
-	account.Pending++
-	if err = s.db.Save(&account).Error; err != nil {
-		log.Error().Err(err).Msg("could not save originator account")
-		return s.updates.SendTransferError(client, req.Id,
-			pb.Errorf(pb.ErrInternal, "could not save originator account"),
-		)
-	}
+				Str("client", client).
+				Strs("accounts", subscription.Accounts).
+				Strs("envelopes", subscription.EnvelopeIds).
+				Int("categories", len(subscription.Categories)).
+				Msg("live updates subscription changed")
 
-	s.updates.Broadcast(req.Id, "ready to execute transaction", pb.MessageCategory_BLOCKCHAIN)
-	time.Sleep(time.Duration(rand.Int63n(1000)) * time.Millisecond)
-
-	// Create an identity and transaction payload for TRISA exchange
-	transaction := &generic.Transaction{
-		Txid:        fmt.Sprintf("%d", xfer.ID),
-		Originator:  account.WalletAddress,
-		Beneficiary: beneficiary.Address,
-		Amount:      float64(transfer.Amount),
-		Network:     "TestNet",
-		Timestamp:   xfer.Timestamp.Format(time.RFC3339),
-	}
-	identity := &ivms101.IdentityPayload{
-		Originator:      &ivms101.Originator{},
-		OriginatingVasp: &ivms101.OriginatingVasp{},
-	}
-	if identity.OriginatingVasp.OriginatingVasp, err = s.vasp.LoadIdentity(); err != nil {
-		log.Error().Err(err).Msg("could not load originator vasp")
-		return s.updates.SendTransferError(client, req.Id,
-			pb.Errorf(pb.ErrInternal, "could not load originator vasp"),
-		)
-	}
-
-	identity.Originator = &ivms101.Originator{
-		OriginatorPersons: make([]*ivms101.Person, 0, 1),
-		AccountNumbers:    []string{account.WalletAddress},
-	}
-	var originator *ivms101.Person
-	if originator, err = account.LoadIdentity(); err != nil {
-		log.Error().Err(err).Msg("could not load originator identity")
-		return s.updates.SendTransferError(client, req.Id,
-			pb.Errorf(pb.ErrInternal, "could not load originator identity"),
-		)
-	}
-	identity.Originator.OriginatorPersons = append(identity.Originator.OriginatorPersons, originator)
-
-	payload := &protocol.Payload{}
-	if payload.Transaction, err = anypb.New(transaction); err != nil {
-		log.Error().Err(err).Msg("could not serialize transaction payload")
-		return s.updates.SendTransferError(client, req.Id,
-			pb.Errorf(pb.ErrInternal, "could not serialize transaction payload"),
-		)
-	}
-	if payload.Identity, err = anypb.New(identity); err != nil {
-		log.Error().Err(err).Msg("could not serialize identity payload")
-		return s.updates.SendTransferError(client, req.Id,
-			pb.Errorf(pb.ErrInternal, "could not serialize identity payload"),
-		)
-	}
-
-	s.updates.Broadcast(req.Id, "transaction and identity payload constructed", pb.MessageCategory_TRISAP2P)
-	time.Sleep(time.Duration(rand.Int63n(1000)) * time.Millisecond)
-
-	// Secure the envelope with the remote beneficiary's signing keys
-	msg, _, err := envelope.Seal(payload, envelope.WithEnvelopeID(xfer.Envelope), envelope.WithRSAPublicKey(signKey))
-	if err != nil {
-		log.Error().Err(err).Msg("TRISA protocol error while sealing envelope")
-		return status.Errorf(codes.FailedPrecondition, "TRISA protocol error: %s", err)
-	}
-
-	s.updates.Broadcast(req.Id, fmt.Sprintf("secure envelope %s sealed: encrypted with AES-GCM and RSA - sending ...", msg.Id), pb.MessageCategory_TRISAP2P)
-	time.Sleep(time.Duration(rand.Int63n(1000)) * time.Millisecond)
+			ack := &pb.Message{
+				Type:      pb.RPC_SUBSCRIBE,
+				Id:        req.Id,
+				Timestamp: time.Now().Format(time.RFC3339),
+				Reply:     &pb.Message_Subscription{Subscription: subscription},
+			}
+			if err = s.updates.Send(client, ack); err != nil {
+				log.Error().Err(err).Str("client", client).Msg("could not send message")
+				return err
+			}
+		case pb.RPC_REPLAY:
+			// Replay the buffered updates then send back an acknowledgement message
+			ack := &pb.Message{
+				Type: pb.RPC_REPLAY,
+				Id:   req.Id,
+			}
 
-	// Conduct the TRISA transaction, handle errors and send back to user
-	if msg, err = peer.Transfer(msg); err != nil {
-		log.Error().Err(err).Msg("could not perform TRISA exchange")
-		return s.updates.SendTransferError(client, req.Id,
-			pb.Errorf(pb.ErrInternal, err.Error()),
-		)
-	}
+			replay := req.GetReplay()
+			var since time.Time
+			if replay.GetSinceTimestamp() != "" {
+				if since, err = time.Parse(time.RFC3339, replay.GetSinceTimestamp()); err != nil {
+					log.Warn().Err(err).Str("client", client).Msg("could not parse replay timestamp")
+					ack.Category = pb.MessageCategory_ERROR
+					ack.Update = fmt.Sprintf("could not parse since timestamp: %s", err)
+				}
+			}
 
-	s.updates.Broadcast(req.Id, fmt.Sprintf("received %s information exchange reply from %s", msg.Id, peer.String()), pb.MessageCategory_TRISAP2P)
-	time.Sleep(time.Duration(rand.Int63n(1000)) * time.Millisecond)
+			if ack.Category != pb.MessageCategory_ERROR {
+				var n int
+				if n, err = s.updates.Replay(client, replay.GetSinceSequence(), since); err != nil {
+					log.Error().Err(err).Str("client", client).Msg("could not replay updates")
+					return err
+				}
+				ack.Update = fmt.Sprintf("replayed %d updates", n)
+			}
 
-	// Open the response envelope with local private keys
-	payload, _, err = envelope.Open(msg, envelope.WithRSAPrivateKey(s.trisa.sign))
-	if err != nil {
-		log.Error().Err(err).Msg("TRISA protocol error while opening envelope")
-		return status.Errorf(codes.FailedPrecondition, "TRISA protocol error: %s", err)
+			ack.Timestamp = time.Now().Format(time.RFC3339)
+			if err = s.updates.Send(client, ack); err != nil {
+				log.Error().Err(err).Str("client", client).Msg("could not send message")
+				return err
+			}
+		}
 	}
+}
 
-	// Verify the contents of the response
-	if payload.Identity.TypeUrl != "type.googleapis.com/ivms101.IdentityPayload" {
-		log.Warn().Str("type", payload.Identity.TypeUrl).Msg("unsupported identity type")
-		return s.updates.SendTransferError(client, req.Id,
-			pb.Errorf(pb.ErrInternal, "unsupported identity type", payload.Identity.TypeUrl),
-		)
-	}
+// authorizeSubscription returns a PermissionDenied error if the subscription includes an
+// account that is not in the scope of the credentials of the stream. Accounts may be
+// subscribed to by email or wallet address, so accounts that are not in the scope as
+// specified are looked up to check their other identifier.
+func (s *Server) authorizeSubscription(ctx context.Context, subscription *pb.Subscription) (err error) {
+	scope := ScopeFromContext(ctx)
+	for _, id := range subscription.Accounts {
+		if scope.Allows(id) {
+			continue
+		}
 
-	if payload.Transaction.TypeUrl != "type.googleapis.com/trisa.data.generic.v1beta1.Transaction" {
-		log.Warn().Str("type", payload.Transaction.TypeUrl).Msg("unsupported transaction type")
-		return s.updates.SendTransferError(client, req.Id,
-			pb.Errorf(pb.ErrInternal, "unsupported transaction type", payload.Transaction.TypeUrl),
-		)
-	}
+		var account db.Account
+		if err = s.db.LookupAccount(id).First(&account).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				log.Info().Str("account", id).Msg("subscription to account outside of scope")
+				return status.Errorf(codes.PermissionDenied, "credentials do not allow access to account %s", id)
+			}
+			log.Error().Err(err).Msg("could not lookup account")
+			return status.Errorf(codes.FailedPrecondition, "could not lookup account: %s", err)
+		}
 
-	identity = &ivms101.IdentityPayload{}
-	transaction = &generic.Transaction{}
-	if err = payload.Identity.UnmarshalTo(identity); err != nil {
-		log.Error().Err(err).Msg("could not unmarshal identity")
-		return s.updates.SendTransferError(client, req.Id,
-			pb.Errorf(pb.ErrInternal, err.Error()),
-		)
-	}
-	if err = payload.Transaction.UnmarshalTo(transaction); err != nil {
-		log.Error().Err(err).Msg("could not unmarshal transaction")
-		return s.updates.SendTransferError(client, req.Id,
-			pb.Errorf(pb.ErrInternal, err.Error()),
-		)
+		if err = authorize(ctx, account); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
-	s.updates.Broadcast(req.Id, "successfully decrypted and parsed secure envelope", pb.MessageCategory_TRISAP2P)
-	time.Sleep(time.Duration(rand.Int63n(1000)) * time.Millisecond)
-
-	// Update the completed transaction and save to disk
-	xfer.Beneficiary = db.Identity{
-		WalletAddress: transaction.Beneficiary,
-		Vasp:          s.vasp,
-	}
-	xfer.SetState(pb.TransactionState_COMPLETED)
-	xfer.Timestamp, _ = time.Parse(time.RFC3339, transaction.Timestamp)
+// handleTransaction performs a transfer requested by the demo exactly as if it had been
+// requested from the integration API, broadcasting each step of the exchange with the
+// ID of the command and pausing between steps to make the demo easier to follow.
+func (s *Server) handleTransaction(ctx context.Context, client string, req *pb.Command) (err error) {
+	// Get the transfer from the original command, will panic if nil
+	transfer := req.GetTransfer()
 
-	// Serialize the identity information as JSON data
-	var data []byte
-	if data, err = json.Marshal(identity); err != nil {
-		log.Error().Err(err).Msg("could not save completed transaction")
+	// Handle Demo UI errors before performing the transfer
+	if transfer.OriginatingVasp != "" && transfer.OriginatingVasp != s.vasp.Name {
+		log.Info().Str("requested", transfer.OriginatingVasp).Str("local", s.vasp.Name).Msg("requested originator does not match local VASP")
 		return s.updates.SendTransferError(client, req.Id,
-			pb.Errorf(pb.ErrInternal, "could not marshal IVMS 101 identity"),
+			pb.Errorf(pb.ErrWrongVASP, "message sent to the wrong originator VASP"),
 		)
 	}
-	xfer.Identity = string(data)
 
-	if err = s.db.Save(&xfer).Error; err != nil {
-		log.Error().Err(err).Msg("could not save completed transaction")
-		return s.updates.SendTransferError(client, req.Id,
-			pb.Errorf(pb.ErrInternal, err.Error()),
-		)
-	}
+	var reply *pb.TransferReply
+	if reply, err = s.transfer(ctx, transfer, newProgress(s.updates, req.Id, s.conf.DemoPacing)); err != nil {
+		// Report the error to the demo client rather than closing the stream
+		serr := status.Convert(err)
+		log.Warn().Str("code", serr.Code().String()).Str("message", serr.Message()).Msg("could not perform demo transfer")
+
+		var code int32
+		switch serr.Code() {
+		case codes.NotFound:
+			code = pb.ErrNotFound
+		case codes.PermissionDenied:
+			code = pb.ErrForbidden
+		case codes.InvalidArgument:
+			code = pb.ErrWrongVASP
+		default:
+			code = pb.ErrInternal
+		}
 
-	// Save the pending transaction on the account
-	// TODO: remove pending transactions
-	account.Pending--
-	account.Completed++
-	account.Balance = account.Balance.Sub(xfer.Amount)
-	if err = s.db.Save(&account).Error; err != nil {
-		log.Error().Err(err).Msg("could not save transaction on account")
-		return s.updates.SendTransferError(client, req.Id,
-			pb.Errorf(pb.ErrInternal, err.Error()),
-		)
+		if reply == nil {
+			return s.updates.SendTransferError(client, req.Id, pb.Errorf(code, serr.Message()))
+		}
+		reply.Error = pb.Errorf(code, serr.Message())
 	}
 
-	message = fmt.Sprintf("transaction %04d complete: %s transferred from %s to %s", xfer.ID, xfer.Amount.String(), xfer.Originator.WalletAddress, xfer.Beneficiary.WalletAddress)
-	s.updates.Broadcast(req.Id, message, pb.MessageCategory_BLOCKCHAIN)
-	time.Sleep(time.Duration(rand.Int63n(1000)) * time.Millisecond)
-
-	s.updates.Broadcast(req.Id, fmt.Sprintf("%04d new account balance: %s", account.ID, account.Balance), pb.MessageCategory_LEDGER)
-	time.Sleep(time.Duration(rand.Int63n(1000)) * time.Millisecond)
-
 	rep := &pb.Message{
 		Type:      pb.RPC_TRANSFER,
 		Id:        req.Id,
 		Timestamp: time.Now().Format(time.RFC3339),
 		Category:  pb.MessageCategory_LEDGER,
-		Reply: &pb.Message_Transfer{Transfer: &pb.TransferReply{
-			Transaction: xfer.Proto(),
-		}},
+		Reply:     &pb.Message_Transfer{Transfer: reply},
 	}
 
+	if reply.Error != nil {
+		rep.Category = pb.MessageCategory_ERROR
+	}
 	return s.updates.Send(client, rep)
 }
 
@@ -1144,10 +1602,12 @@ func (s *Server) Status(context.Context, *pb.Empty) (_ *pb.ServerStatus, err err
 	}
 
 	return &pb.ServerStatus{
-		Status:     pb.ServerStatus_ONLINE,
-		Version:    pkg.Version(),
-		CommonName: cert.Subject.CommonName,
-		NotBefore:  cert.NotBefore.Format(time.RFC3339),
-		NotAfter:   cert.NotAfter.Format(time.RFC3339),
+		Status:      pb.ServerStatus_ONLINE,
+		Version:     pkg.Version(),
+		CommonName:  cert.Subject.CommonName,
+		NotBefore:   cert.NotBefore.Format(time.RFC3339),
+		NotAfter:    cert.NotAfter.Format(time.RFC3339),
+		LiveUpdates: s.updates.Stats(),
+		RateLimits:  s.trisa.limits.Stats(),
 	}, nil
 }
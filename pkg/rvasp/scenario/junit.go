@@ -0,0 +1,122 @@
+package scenario
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Result of running a scenario.
+type Result struct {
+	Scenario string
+	Steps    []*StepResult
+	Duration time.Duration
+}
+
+// StepResult is the outcome of a single step; the step passed if Failure is empty.
+type StepResult struct {
+	Name     string
+	Envelope string
+	Failure  string
+	Duration time.Duration
+}
+
+// Failures returns the number of steps that failed.
+func (r *Result) Failures() (n int) {
+	for _, step := range r.Steps {
+		if step.Failure != "" {
+			n++
+		}
+	}
+	return n
+}
+
+// Passed returns true if all of the steps passed.
+func (r *Result) Passed() bool {
+	return r.Failures() == 0
+}
+
+// JUnit XML elements, see https://github.com/testmoapp/junitxml
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Time     string       `xml:"time,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Time     string      `xml:"time,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnit writes the results as a JUnit XML report with a test suite per scenario.
+func WriteJUnit(w io.Writer, results ...*Result) (err error) {
+	report := junitSuites{Suites: make([]junitSuite, 0, len(results))}
+	var total time.Duration
+	for _, result := range results {
+		suite := junitSuite{
+			Name:     result.Scenario,
+			Tests:    len(result.Steps),
+			Failures: result.Failures(),
+			Time:     seconds(result.Duration),
+			Cases:    make([]junitCase, 0, len(result.Steps)),
+		}
+
+		for _, step := range result.Steps {
+			tc := junitCase{
+				Name:      step.Name,
+				ClassName: result.Scenario,
+				Time:      seconds(step.Duration),
+			}
+
+			if step.Envelope != "" {
+				tc.SystemOut = fmt.Sprintf("envelope: %s", step.Envelope)
+			}
+
+			if step.Failure != "" {
+				tc.Failure = &junitFailure{Message: step.Failure, Text: step.Failure}
+			}
+			suite.Cases = append(suite.Cases, tc)
+		}
+
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.Suites = append(report.Suites, suite)
+		total += result.Duration
+	}
+	report.Time = seconds(total)
+
+	if _, err = io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err = encoder.Encode(report); err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+func seconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
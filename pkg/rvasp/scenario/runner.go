@@ -0,0 +1,279 @@
+package scenario
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	pb "github.com/trisacrypto/testnet/pkg/rvasp/pb/v1"
+	"google.golang.org/grpc/status"
+)
+
+// Balances are reported by the rVASPs with a precision of two decimal places.
+const balanceTolerance = 0.005
+
+// DefaultPollInterval is the interval at which the runner checks transaction states
+// while waiting for asynchronous transfers.
+const DefaultPollInterval = 5 * time.Second
+
+// Runner executes scenarios against rVASPs using the clients of the named endpoints.
+type Runner struct {
+	clients      map[string]pb.TRISAIntegrationClient
+	PollInterval time.Duration
+}
+
+// NewRunner creates a runner with a client for each endpoint name in the scenarios.
+func NewRunner(clients map[string]pb.TRISAIntegrationClient) *Runner {
+	return &Runner{clients: clients, PollInterval: DefaultPollInterval}
+}
+
+// Run the steps of the scenario in order. Steps that fail do not stop the scenario.
+func (r *Runner) Run(ctx context.Context, s *Scenario) *Result {
+	result := &Result{Scenario: s.Name, Steps: make([]*StepResult, 0, len(s.Steps))}
+	start := time.Now()
+	for _, step := range s.Steps {
+		result.Steps = append(result.Steps, r.runStep(ctx, step))
+	}
+	result.Duration = time.Since(start)
+	return result
+}
+
+func (r *Runner) runStep(ctx context.Context, step *Step) (result *StepResult) {
+	result = &StepResult{Name: step.Name}
+	start := time.Now()
+	defer func() {
+		result.Duration = time.Since(start)
+	}()
+
+	if err := r.execute(ctx, step, result); err != nil {
+		result.Failure = err.Error()
+	}
+	return result
+}
+
+// execute the transfer of the step and check the expectations, returning an error
+// describing the first expectation that was not met.
+func (r *Runner) execute(ctx context.Context, step *Step, result *StepResult) (err error) {
+	var originator, beneficiary pb.TRISAIntegrationClient
+	if originator, err = r.client(step.VASP); err != nil {
+		return err
+	}
+
+	if step.Counterparty != "" {
+		if beneficiary, err = r.client(step.Counterparty); err != nil {
+			return err
+		}
+	}
+
+	// Fetch the balances before the transfer
+	var originatorBalance, beneficiaryBalance float32
+	if step.Expect.OriginatorBalanceChange != nil {
+		if originatorBalance, err = balance(ctx, originator, step.Account); err != nil {
+			return err
+		}
+	}
+
+	if step.Expect.BeneficiaryBalanceChange != nil {
+		if beneficiaryBalance, err = balance(ctx, beneficiary, step.Beneficiary); err != nil {
+			return err
+		}
+	}
+
+	// Execute the transfer
+	var reply *pb.TransferReply
+	reply, err = originator.Transfer(ctx, &pb.TransferRequest{
+		Account:         step.Account,
+		Beneficiary:     step.Beneficiary,
+		Amount:          step.Amount,
+		AssetType:       step.AssetType,
+		BeneficiaryVasp: step.BeneficiaryVASP,
+	})
+
+	if step.Expect.Status != "" {
+		code, _ := ParseCode(step.Expect.Status)
+		if actual := status.Code(err); actual != code {
+			return fmt.Errorf("expected status %s but transfer returned %s: %v", code, actual, err)
+		}
+		if err != nil {
+			return nil
+		}
+	} else if err != nil {
+		return fmt.Errorf("transfer failed: %s", err)
+	}
+
+	if reply.Transaction != nil {
+		result.Envelope = reply.Transaction.EnvelopeId
+	}
+
+	if err = checkError(step.Expect, reply.Error); err != nil {
+		return err
+	}
+
+	// The state of the transaction is taken from the reply unless waiting
+	var state pb.TransactionState
+	if reply.Transaction != nil {
+		state = reply.Transaction.State
+	}
+
+	if step.Wait != nil {
+		if result.Envelope == "" {
+			return fmt.Errorf("cannot wait for %s: transfer reply has no envelope id", step.Wait.State)
+		}
+
+		expected, _ := ParseState(step.Wait.State)
+		if state, err = r.wait(ctx, originator, step.Account, result.Envelope, expected, step.Wait.Timeout); err != nil {
+			return err
+		}
+	}
+
+	if step.Expect.State != "" {
+		if expected, _ := ParseState(step.Expect.State); state != expected {
+			return fmt.Errorf("expected originator transaction state %s but was %s", expected, state)
+		}
+	}
+
+	if step.Expect.BeneficiaryState != "" {
+		expected, _ := ParseState(step.Expect.BeneficiaryState)
+		if step.Wait != nil {
+			// The beneficiary may save its record after the originator's record has
+			// reached its state, so wait for the beneficiary as well.
+			if _, err = r.wait(ctx, beneficiary, step.Beneficiary, result.Envelope, expected, step.Wait.Timeout); err != nil {
+				return fmt.Errorf("beneficiary %s", err)
+			}
+		} else {
+			var txn *pb.Transaction
+			if txn, err = transaction(ctx, beneficiary, step.Beneficiary, result.Envelope); err != nil {
+				return err
+			}
+
+			if txn == nil || txn.State != expected {
+				actual := "not found"
+				if txn != nil {
+					actual = txn.State.String()
+				}
+				return fmt.Errorf("expected beneficiary transaction state %s but was %s", expected, actual)
+			}
+		}
+	}
+
+	if step.Expect.OriginatorBalanceChange != nil {
+		if err = checkBalance(ctx, originator, step.Account, originatorBalance, *step.Expect.OriginatorBalanceChange); err != nil {
+			return fmt.Errorf("originator %s", err)
+		}
+	}
+
+	if step.Expect.BeneficiaryBalanceChange != nil {
+		if err = checkBalance(ctx, beneficiary, step.Beneficiary, beneficiaryBalance, *step.Expect.BeneficiaryBalanceChange); err != nil {
+			return fmt.Errorf("beneficiary %s", err)
+		}
+	}
+
+	return nil
+}
+
+// wait polls the account until the transaction reaches the expected state, returning
+// early if the transaction reaches a different final state.
+func (r *Runner) wait(ctx context.Context, client pb.TRISAIntegrationClient, account, envelope string, expected pb.TransactionState, timeout time.Duration) (state pb.TransactionState, err error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(r.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		var txn *pb.Transaction
+		if txn, err = transaction(ctx, client, account, envelope); err != nil && ctx.Err() == nil {
+			return state, err
+		}
+
+		if txn != nil {
+			state = txn.State
+			if state == expected {
+				return state, nil
+			}
+
+			if isFinal(state) {
+				return state, fmt.Errorf("expected transaction to reach state %s but it was %s", expected, state)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return state, fmt.Errorf("timed out after %s waiting for state %s, transaction was %s", timeout, expected, state)
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Runner) client(name string) (pb.TRISAIntegrationClient, error) {
+	if client, ok := r.clients[name]; ok {
+		return client, nil
+	}
+	return nil, fmt.Errorf("no client for endpoint %q", name)
+}
+
+func checkError(expect Expect, reply *pb.Error) error {
+	if expect.ErrorCode == nil && expect.Error == "" {
+		return nil
+	}
+
+	if reply == nil {
+		return fmt.Errorf("expected transfer error but the transfer reply had no error")
+	}
+
+	if expect.ErrorCode != nil && reply.Code != *expect.ErrorCode {
+		return fmt.Errorf("expected error code %d but was %d: %s", *expect.ErrorCode, reply.Code, reply.Message)
+	}
+
+	if expect.Error != "" && !strings.Contains(reply.Message, expect.Error) {
+		return fmt.Errorf("expected error containing %q but was %q", expect.Error, reply.Message)
+	}
+	return nil
+}
+
+func checkBalance(ctx context.Context, client pb.TRISAIntegrationClient, account string, before float32, change float64) (err error) {
+	var after float32
+	if after, err = balance(ctx, client, account); err != nil {
+		return err
+	}
+
+	if actual := float64(after) - float64(before); math.Abs(actual-change) > balanceTolerance {
+		return fmt.Errorf("balance of %s expected to change by %.2f but changed by %.2f", account, change, actual)
+	}
+	return nil
+}
+
+func balance(ctx context.Context, client pb.TRISAIntegrationClient, account string) (_ float32, err error) {
+	var rep *pb.AccountReply
+	if rep, err = client.AccountStatus(ctx, &pb.AccountRequest{Account: account, NoTransactions: true}); err != nil {
+		return 0, fmt.Errorf("could not fetch account %s: %s", account, err)
+	}
+	return rep.Balance, nil
+}
+
+// transaction returns the transaction of the account with the envelope ID or nil if
+// the account has no such transaction.
+func transaction(ctx context.Context, client pb.TRISAIntegrationClient, account, envelope string) (_ *pb.Transaction, err error) {
+	var rep *pb.AccountReply
+	if rep, err = client.AccountStatus(ctx, &pb.AccountRequest{Account: account}); err != nil {
+		return nil, fmt.Errorf("could not fetch account %s: %s", account, err)
+	}
+
+	for _, txn := range rep.Transactions {
+		if txn.EnvelopeId == envelope {
+			return txn, nil
+		}
+	}
+	return nil, nil
+}
+
+func isFinal(state pb.TransactionState) bool {
+	switch state {
+	case pb.TransactionState_COMPLETED, pb.TransactionState_REJECTED, pb.TransactionState_FAILED, pb.TransactionState_EXPIRED:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,215 @@
+/*
+Package scenario executes declarative YAML scenarios against running rVASPs. A scenario
+lists transfers between rVASP accounts, optional waits for asynchronous transactions to
+reach a state, and assertions on the resulting transaction states, balances, and error
+codes. The results of a scenario can be reported as JUnit XML.
+*/
+package scenario
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	pb "github.com/trisacrypto/testnet/pkg/rvasp/pb/v1"
+	"google.golang.org/grpc/codes"
+	"gopkg.in/yaml.v3"
+)
+
+// Default settings for steps that do not specify them.
+const (
+	DefaultAssetType = "Bitcoin"
+	DefaultAmount    = 1.0
+	DefaultTimeout   = 2 * time.Minute
+)
+
+// Scenario is a named list of transfers between rVASPs and their expected results.
+type Scenario struct {
+	Name      string            `yaml:"name"`
+	Endpoints map[string]string `yaml:"endpoints"`
+	Defaults  Defaults          `yaml:"defaults"`
+	Steps     []*Step           `yaml:"steps"`
+}
+
+// Defaults are applied to steps that do not specify the field.
+type Defaults struct {
+	AssetType string        `yaml:"asset_type"`
+	Amount    float32       `yaml:"amount"`
+	Timeout   time.Duration `yaml:"timeout"`
+}
+
+// Step is a transfer initiated by the originator rVASP and the expected results.
+type Step struct {
+	Name string `yaml:"name"`
+
+	// The name of the endpoint of the originator rVASP
+	VASP string `yaml:"vasp"`
+
+	// The transfer request sent to the originator rVASP
+	Account         string  `yaml:"account"`
+	Beneficiary     string  `yaml:"beneficiary"`
+	BeneficiaryVASP string  `yaml:"beneficiary_vasp"`
+	Amount          float32 `yaml:"amount"`
+	AssetType       string  `yaml:"asset_type"`
+
+	// The name of the endpoint of the beneficiary rVASP, required to make assertions
+	// about the beneficiary account.
+	Counterparty string `yaml:"counterparty"`
+
+	Wait   *Wait  `yaml:"wait"`
+	Expect Expect `yaml:"expect"`
+}
+
+// Wait polls the originator rVASP until the transaction is in the specified state.
+type Wait struct {
+	State   string        `yaml:"state"`
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// Expect describes the assertions made after the transfer and any waits complete.
+// Unspecified fields are not checked.
+type Expect struct {
+	// The gRPC status code name of the transfer request, e.g. NotFound
+	Status string `yaml:"status"`
+
+	// The state of the originator's and beneficiary's transaction records
+	State            string `yaml:"state"`
+	BeneficiaryState string `yaml:"beneficiary_state"`
+
+	// The TRISA error code and a substring of the error message in the transfer reply
+	ErrorCode *int32 `yaml:"error_code"`
+	Error     string `yaml:"error"`
+
+	// The change in the account balances since before the transfer
+	OriginatorBalanceChange  *float64 `yaml:"originator_balance_change"`
+	BeneficiaryBalanceChange *float64 `yaml:"beneficiary_balance_change"`
+}
+
+// Load and validate a scenario from a YAML file.
+func Load(path string) (s *Scenario, err error) {
+	var data []byte
+	if data, err = os.ReadFile(path); err != nil {
+		return nil, fmt.Errorf("could not read scenario: %s", err)
+	}
+
+	s = &Scenario{}
+	if err = yaml.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("could not parse scenario %s: %s", path, err)
+	}
+
+	if s.Name == "" {
+		s.Name = path
+	}
+
+	if err = s.Validate(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Validate the scenario and apply the defaults to the steps.
+func (s *Scenario) Validate() (err error) {
+	if len(s.Steps) == 0 {
+		return fmt.Errorf("scenario %q has no steps", s.Name)
+	}
+
+	if s.Defaults.AssetType == "" {
+		s.Defaults.AssetType = DefaultAssetType
+	}
+
+	if s.Defaults.Amount == 0 {
+		s.Defaults.Amount = DefaultAmount
+	}
+
+	if s.Defaults.Timeout == 0 {
+		s.Defaults.Timeout = DefaultTimeout
+	}
+
+	for i, step := range s.Steps {
+		if step.Name == "" {
+			step.Name = fmt.Sprintf("step %d", i+1)
+		}
+
+		if err = step.validate(s); err != nil {
+			return fmt.Errorf("invalid scenario %q: %s: %s", s.Name, step.Name, err)
+		}
+	}
+	return nil
+}
+
+func (step *Step) validate(s *Scenario) (err error) {
+	if step.VASP == "" {
+		return errors.New("vasp is required")
+	}
+
+	if _, ok := s.Endpoints[step.VASP]; !ok {
+		return fmt.Errorf("unknown vasp endpoint %q", step.VASP)
+	}
+
+	if step.Counterparty != "" {
+		if _, ok := s.Endpoints[step.Counterparty]; !ok {
+			return fmt.Errorf("unknown counterparty endpoint %q", step.Counterparty)
+		}
+	}
+
+	if step.Account == "" || step.Beneficiary == "" {
+		return errors.New("account and beneficiary are required")
+	}
+
+	if step.Amount == 0 {
+		step.Amount = s.Defaults.Amount
+	}
+
+	if step.AssetType == "" {
+		step.AssetType = s.Defaults.AssetType
+	}
+
+	if step.Wait != nil {
+		if _, err = ParseState(step.Wait.State); err != nil {
+			return err
+		}
+
+		if step.Wait.Timeout == 0 {
+			step.Wait.Timeout = s.Defaults.Timeout
+		}
+	}
+
+	for _, state := range []string{step.Expect.State, step.Expect.BeneficiaryState} {
+		if state == "" {
+			continue
+		}
+		if _, err = ParseState(state); err != nil {
+			return err
+		}
+	}
+
+	if step.Expect.Status != "" {
+		if _, err = ParseCode(step.Expect.Status); err != nil {
+			return err
+		}
+	}
+
+	if step.Counterparty == "" && (step.Expect.BeneficiaryState != "" || step.Expect.BeneficiaryBalanceChange != nil) {
+		return errors.New("counterparty is required for beneficiary assertions")
+	}
+	return nil
+}
+
+// ParseState parses a transaction state name, e.g. COMPLETED.
+func ParseState(name string) (pb.TransactionState, error) {
+	if state, ok := pb.TransactionState_value[name]; ok {
+		return pb.TransactionState(state), nil
+	}
+	return 0, fmt.Errorf("unknown transaction state %q", name)
+}
+
+// ParseCode parses a gRPC status code name, e.g. NotFound.
+func ParseCode(name string) (codes.Code, error) {
+	for code := codes.OK; code <= codes.Unauthenticated; code++ {
+		if code.String() == name {
+			return code, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown status code %q", name)
+}
@@ -0,0 +1,148 @@
+package scenario_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trisacrypto/testnet/pkg/rvasp/harness"
+	pb "github.com/trisacrypto/testnet/pkg/rvasp/pb/v1"
+	"github.com/trisacrypto/testnet/pkg/rvasp/scenario"
+	"google.golang.org/grpc"
+)
+
+func TestPoliciesScenario(t *testing.T) {
+	s, err := scenario.Load(filepath.Join("..", "..", "..", "scenarios", "policies.yaml"))
+	require.NoError(t, err)
+	require.Equal(t, "policies", s.Name)
+
+	h := harness.New(t, "alice", "bob")
+
+	// Handle async transactions in the background as the rVASPs would; transfers hold
+	// the lock so that the originator does not save a stale copy of a transaction that
+	// the beneficiary has already advanced.
+	mu := &sync.Mutex{}
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		ticker := time.NewTicker(20 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				mu.Lock()
+				h.HandleAsync()
+				mu.Unlock()
+			}
+		}
+	}()
+
+	runner := scenario.NewRunner(map[string]pb.TRISAIntegrationClient{
+		"alice": &lockedClient{h.VASP("alice").Client, mu},
+		"bob":   &lockedClient{h.VASP("bob").Client, mu},
+	})
+	runner.PollInterval = 10 * time.Millisecond
+
+	result := runner.Run(context.Background(), s)
+	require.Len(t, result.Steps, len(s.Steps))
+	for _, step := range result.Steps {
+		require.Empty(t, step.Failure, "step %q failed", step.Name)
+	}
+	require.True(t, result.Passed())
+
+	// Steps with unmet expectations should fail without stopping the scenario
+	s.Steps[0].Expect.State = "REJECTED"
+	s.Steps[1].Wait = &scenario.Wait{State: "COMPLETED", Timeout: time.Second}
+	result = runner.Run(context.Background(), s)
+	require.Equal(t, 2, result.Failures())
+	require.Equal(t, "expected originator transaction state REJECTED but was COMPLETED", result.Steps[0].Failure)
+	require.Equal(t, "expected transaction to reach state COMPLETED but it was REJECTED", result.Steps[1].Failure)
+
+	// The results should be reported as JUnit XML
+	buf := &bytes.Buffer{}
+	require.NoError(t, scenario.WriteJUnit(buf, result))
+
+	var report struct {
+		Tests    int `xml:"tests,attr"`
+		Failures int `xml:"failures,attr"`
+		Suites   []struct {
+			Name  string `xml:"name,attr"`
+			Cases []struct {
+				Name    string `xml:"name,attr"`
+				Failure *struct {
+					Message string `xml:"message,attr"`
+				} `xml:"failure"`
+			} `xml:"testcase"`
+		} `xml:"testsuite"`
+	}
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &report))
+	require.Equal(t, len(s.Steps), report.Tests)
+	require.Equal(t, 2, report.Failures)
+	require.Len(t, report.Suites, 1)
+	require.Equal(t, "policies", report.Suites[0].Name)
+	require.Equal(t, "SendPartial to SyncRepair", report.Suites[0].Cases[0].Name)
+	require.NotNil(t, report.Suites[0].Cases[0].Failure)
+	require.Nil(t, report.Suites[0].Cases[2].Failure)
+}
+
+type lockedClient struct {
+	pb.TRISAIntegrationClient
+	mu *sync.Mutex
+}
+
+func (c *lockedClient) Transfer(ctx context.Context, in *pb.TransferRequest, opts ...grpc.CallOption) (*pb.TransferReply, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.TRISAIntegrationClient.Transfer(ctx, in, opts...)
+}
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		scenario *scenario.Scenario
+		err      string
+	}{
+		{
+			&scenario.Scenario{Name: "empty"},
+			`scenario "empty" has no steps`,
+		},
+		{
+			&scenario.Scenario{Name: "s", Steps: []*scenario.Step{{VASP: "alice", Account: "a", Beneficiary: "b"}}},
+			`invalid scenario "s": step 1: unknown vasp endpoint "alice"`,
+		},
+		{
+			&scenario.Scenario{Name: "s", Endpoints: map[string]string{"alice": ":1"}, Steps: []*scenario.Step{{VASP: "alice", Beneficiary: "b"}}},
+			`invalid scenario "s": step 1: account and beneficiary are required`,
+		},
+		{
+			&scenario.Scenario{Name: "s", Endpoints: map[string]string{"alice": ":1"}, Steps: []*scenario.Step{{Name: "x", VASP: "alice", Account: "a", Beneficiary: "b", Wait: &scenario.Wait{State: "DONE"}}}},
+			`invalid scenario "s": x: unknown transaction state "DONE"`,
+		},
+		{
+			&scenario.Scenario{Name: "s", Endpoints: map[string]string{"alice": ":1"}, Steps: []*scenario.Step{{VASP: "alice", Account: "a", Beneficiary: "b", Expect: scenario.Expect{Status: "Missing"}}}},
+			`invalid scenario "s": step 1: unknown status code "Missing"`,
+		},
+		{
+			&scenario.Scenario{Name: "s", Endpoints: map[string]string{"alice": ":1"}, Steps: []*scenario.Step{{VASP: "alice", Account: "a", Beneficiary: "b", Expect: scenario.Expect{BeneficiaryState: "COMPLETED"}}}},
+			`invalid scenario "s": step 1: counterparty is required for beneficiary assertions`,
+		},
+	}
+
+	for _, tc := range testCases {
+		require.EqualError(t, tc.scenario.Validate(), tc.err)
+	}
+
+	// Defaults should be applied to the steps
+	s := &scenario.Scenario{Endpoints: map[string]string{"alice": ":1"}, Steps: []*scenario.Step{{VASP: "alice", Account: "a", Beneficiary: "b", Wait: &scenario.Wait{State: "COMPLETED"}}}}
+	require.NoError(t, s.Validate())
+	require.Equal(t, "step 1", s.Steps[0].Name)
+	require.Equal(t, scenario.DefaultAssetType, s.Steps[0].AssetType)
+	require.Equal(t, float32(scenario.DefaultAmount), s.Steps[0].Amount)
+	require.Equal(t, scenario.DefaultTimeout, s.Steps[0].Wait.Timeout)
+}
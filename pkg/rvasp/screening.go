@@ -0,0 +1,236 @@
+package rvasp
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/rs/zerolog/log"
+	"github.com/trisacrypto/testnet/pkg/rvasp/config"
+	"github.com/trisacrypto/trisa/pkg/ivms101"
+	protocol "github.com/trisacrypto/trisa/pkg/trisa/api/v1beta1"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Screener simulates the sanctions screening of a real VASP by comparing the names of
+// IVMS101 natural and legal persons, including their local and phonetic names, with a
+// local watchlist. Names are compared with a fuzzy match that ignores case, accents,
+// punctuation, and the order of the words of the name, so that minor misspellings
+// still match. A nil Screener does not match any names.
+type Screener struct {
+	sync.RWMutex
+	path      string
+	threshold float64
+	action    config.ScreeningAction
+	entries   []config.WatchlistEntry
+	names     []watchlistName
+}
+
+// watchlistName is a normalized name or alias of a watchlist entry.
+type watchlistName struct {
+	name  string
+	entry int
+}
+
+// NewScreener loads the watchlist, returns nil if screening is not enabled.
+func NewScreener(conf config.ScreeningConfig) (s *Screener, err error) {
+	if !conf.Enabled() {
+		return nil, nil
+	}
+
+	s = &Screener{path: conf.WatchlistPath, threshold: conf.Threshold, action: conf.Action}
+	if err = s.Reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Reload reads the watchlist again so that hits of transfers held for review can be
+// cleared by removing the entry from the watchlist. The current watchlist is kept if
+// the watchlist cannot be read.
+func (s *Screener) Reload() (err error) {
+	if s == nil {
+		return nil
+	}
+
+	var entries []config.WatchlistEntry
+	if entries, err = config.LoadWatchlist(s.path); err != nil {
+		return err
+	}
+
+	var names []watchlistName
+	for i, entry := range entries {
+		for _, name := range entry.Names() {
+			if name = normalizeName(name); name != "" {
+				names = append(names, watchlistName{name: name, entry: i})
+			}
+		}
+	}
+
+	s.Lock()
+	s.entries, s.names = entries, names
+	s.Unlock()
+	return nil
+}
+
+// Review returns true if transfers received from peers whose originator matches the
+// watchlist are held for review rather than rejected.
+func (s *Screener) Review() bool {
+	return s != nil && s.action == config.ReviewHits
+}
+
+// Screen returns the closest match of the names of the persons with the watchlist, or
+// nil if no name is at least as similar to a watchlist entry as the threshold.
+func (s *Screener) Screen(persons []*ivms101.Person) (hit *Hit) {
+	if s == nil {
+		return nil
+	}
+
+	s.RLock()
+	defer s.RUnlock()
+	for _, person := range persons {
+		for _, name := range personNames(person) {
+			normalized := normalizeName(name)
+			if normalized == "" {
+				continue
+			}
+
+			for _, candidate := range s.names {
+				score := similarity(normalized, candidate.name)
+				if score >= s.threshold && (hit == nil || score > hit.Score) {
+					hit = &Hit{Name: name, Entry: s.entries[candidate.entry], Score: score}
+				}
+			}
+		}
+	}
+
+	if hit != nil {
+		log.Info().Str("name", hit.Name).Str("entry", hit.Entry.Name).Float64("score", hit.Score).Msg("sanctions screening hit")
+	}
+	return hit
+}
+
+// Hit is a name that matches an entry of the watchlist.
+type Hit struct {
+	Name  string
+	Entry config.WatchlistEntry
+	Score float64
+}
+
+func (h *Hit) String() string {
+	if h.Entry.Program != "" {
+		return fmt.Sprintf("%q matches watchlist entry %q of the %s program (score %.2f)", h.Name, h.Entry.Name, h.Entry.Program, h.Score)
+	}
+	return fmt.Sprintf("%q matches watchlist entry %q (score %.2f)", h.Name, h.Entry.Name, h.Score)
+}
+
+// Reject returns the TRISA error that rejects a transfer because of the hit.
+func (h *Hit) Reject() *protocol.Error {
+	return protocol.Errorf(protocol.ComplianceCheckFail, "sanctions screening failed: %s", h)
+}
+
+// screeningRejection is a TRISA error raised by the local sanctions screening rather
+// than returned by the remote peer.
+type screeningRejection struct {
+	reject *protocol.Error
+}
+
+func (e *screeningRejection) Error() string {
+	return e.reject.Error()
+}
+
+// personNames returns the legal, local, and phonetic names of a natural or legal person.
+func personNames(person *ivms101.Person) (names []string) {
+	if natural := person.GetNaturalPerson(); natural != nil && natural.Name != nil {
+		for _, id := range natural.Name.NameIdentifiers {
+			names = append(names, strings.TrimSpace(id.SecondaryIdentifier+" "+id.PrimaryIdentifier))
+		}
+		for _, ids := range [][]*ivms101.LocalNaturalPersonNameId{natural.Name.LocalNameIdentifiers, natural.Name.PhoneticNameIdentifiers} {
+			for _, id := range ids {
+				names = append(names, strings.TrimSpace(id.SecondaryIdentifier+" "+id.PrimaryIdentifier))
+			}
+		}
+	}
+
+	if legal := person.GetLegalPerson(); legal != nil && legal.Name != nil {
+		for _, id := range legal.Name.NameIdentifiers {
+			names = append(names, id.LegalPersonName)
+		}
+		for _, ids := range [][]*ivms101.LocalLegalPersonNameId{legal.Name.LocalNameIdentifiers, legal.Name.PhoneticNameIdentifiers} {
+			for _, id := range ids {
+				names = append(names, id.LegalPersonName)
+			}
+		}
+	}
+	return names
+}
+
+// normalizeName lowercases the name, removes accents and punctuation, and sorts the
+// words of the name so that "Riddle, Tom" and "Tom Riddle" are the same name.
+func normalizeName(name string) string {
+	var sb strings.Builder
+	for _, r := range norm.NFD.String(name) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// Drop the accents separated from their letters by the decomposition
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			sb.WriteRune(unicode.ToLower(r))
+		default:
+			sb.WriteRune(' ')
+		}
+	}
+
+	words := strings.Fields(sb.String())
+	sort.Strings(words)
+	return strings.Join(words, " ")
+}
+
+// similarity returns the Levenshtein similarity of two names, from 0 for names that
+// have nothing in common to 1 for identical names.
+func similarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := len(ra)
+	if len(rb) > longest {
+		longest = len(rb)
+	}
+
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+// levenshtein returns the number of single character insertions, deletions, and
+// substitutions required to change a into b.
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = minInt(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+func minInt(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
@@ -0,0 +1,249 @@
+package rvasp_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trisacrypto/testnet/pkg/rvasp"
+	"github.com/trisacrypto/testnet/pkg/rvasp/config"
+	"github.com/trisacrypto/testnet/pkg/rvasp/harness"
+	pb "github.com/trisacrypto/testnet/pkg/rvasp/pb/v1"
+	"github.com/trisacrypto/trisa/pkg/ivms101"
+	protocol "github.com/trisacrypto/trisa/pkg/trisa/api/v1beta1"
+	"gorm.io/gorm"
+)
+
+const watchlist = `name,aliases,type,program,country
+"JAMES, Mary",,individual,SDN,US
+Radomil Sokolov,Радомил Соколов;Ra-do-mil So-ko-lov,individual,SDN,GG
+Evil Holdings Ltd,Evil Corp,entity,SDGT,GG
+`
+
+func TestScreener(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "watchlist.csv")
+	require.NoError(t, os.WriteFile(path, []byte(watchlist), 0644))
+
+	screener, err := rvasp.NewScreener(config.ScreeningConfig{WatchlistPath: path, Threshold: 0.85})
+	require.NoError(t, err)
+	require.False(t, screener.Review())
+
+	natural := func(name *ivms101.NaturalPersonName) *ivms101.Person {
+		return &ivms101.Person{Person: &ivms101.Person_NaturalPerson{NaturalPerson: &ivms101.NaturalPerson{Name: name}}}
+	}
+
+	legal := func(name *ivms101.LegalPersonName) *ivms101.Person {
+		return &ivms101.Person{Person: &ivms101.Person_LegalPerson{LegalPerson: &ivms101.LegalPerson{Name: name}}}
+	}
+
+	testCases := []struct {
+		name   string
+		person *ivms101.Person
+		entry  string // the matched watchlist entry or empty if no match
+	}{
+		{
+			"exact name in any order",
+			natural(&ivms101.NaturalPersonName{NameIdentifiers: []*ivms101.NaturalPersonNameId{{PrimaryIdentifier: "James", SecondaryIdentifier: "Mary"}}}),
+			"JAMES, Mary",
+		},
+		{
+			"misspelled name",
+			natural(&ivms101.NaturalPersonName{NameIdentifiers: []*ivms101.NaturalPersonNameId{{PrimaryIdentifier: "Sokoloa", SecondaryIdentifier: "Radomil"}}}),
+			"Radomil Sokolov",
+		},
+		{
+			"local name",
+			natural(&ivms101.NaturalPersonName{
+				NameIdentifiers:      []*ivms101.NaturalPersonNameId{{PrimaryIdentifier: "Smith", SecondaryIdentifier: "John"}},
+				LocalNameIdentifiers: []*ivms101.LocalNaturalPersonNameId{{PrimaryIdentifier: "Соколов", SecondaryIdentifier: "Радомил"}},
+			}),
+			"Radomil Sokolov",
+		},
+		{
+			"phonetic name",
+			natural(&ivms101.NaturalPersonName{
+				NameIdentifiers:         []*ivms101.NaturalPersonNameId{{PrimaryIdentifier: "Smith", SecondaryIdentifier: "John"}},
+				PhoneticNameIdentifiers: []*ivms101.LocalNaturalPersonNameId{{PrimaryIdentifier: "So ko lov", SecondaryIdentifier: "Ra do mil"}},
+			}),
+			"Radomil Sokolov",
+		},
+		{
+			"legal person alias with accents",
+			legal(&ivms101.LegalPersonName{NameIdentifiers: []*ivms101.LegalPersonNameId{{LegalPersonName: "Évil Corp."}}}),
+			"Evil Holdings Ltd",
+		},
+		{
+			"legal person local name",
+			legal(&ivms101.LegalPersonName{
+				NameIdentifiers:      []*ivms101.LegalPersonNameId{{LegalPersonName: "Good Holdings"}},
+				LocalNameIdentifiers: []*ivms101.LocalLegalPersonNameId{{LegalPersonName: "EVIL HOLDINGS LTD"}},
+			}),
+			"Evil Holdings Ltd",
+		},
+		{
+			"different name",
+			natural(&ivms101.NaturalPersonName{NameIdentifiers: []*ivms101.NaturalPersonNameId{{PrimaryIdentifier: "Jameson", SecondaryIdentifier: "Marty"}}}),
+			"",
+		},
+		{
+			"no name",
+			&ivms101.Person{},
+			"",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			hit := screener.Screen([]*ivms101.Person{tc.person})
+			if tc.entry == "" {
+				require.Nil(t, hit)
+				return
+			}
+
+			require.NotNil(t, hit)
+			require.Equal(t, tc.entry, hit.Entry.Name)
+			require.GreaterOrEqual(t, hit.Score, 0.85)
+			require.Equal(t, protocol.ComplianceCheckFail, hit.Reject().Code)
+		})
+	}
+
+	// A nil screener does not match any names
+	screener, err = rvasp.NewScreener(config.ScreeningConfig{})
+	require.NoError(t, err)
+	require.Nil(t, screener)
+	require.Nil(t, screener.Screen([]*ivms101.Person{testCases[0].person}))
+}
+
+func TestScreening(t *testing.T) {
+	entries := `[
+		{"name": "Mary James", "program": "SDN"},
+		{"name": "Howard, Robert", "aliases": ["Bob Howard"]},
+		{"name": "Lawrence Clarke"}
+	]`
+
+	path := filepath.Join(t.TempDir(), "watchlist.json")
+	require.NoError(t, os.WriteFile(path, []byte(entries), 0644))
+
+	// screen returns a harness in which only the named rVASP screens names
+	screen := func(t *testing.T, name string, action config.ScreeningAction) *harness.Harness {
+		return harness.NewWithConfig(t, func(conf *config.Config) {
+			if conf.Name == name {
+				conf.Screening = config.ScreeningConfig{WatchlistPath: path, Threshold: 0.85, Action: action}
+			}
+		}, "alice", "bob")
+	}
+
+	transfer := func(t *testing.T, alice *harness.VASP, beneficiary string) *pb.TransferReply {
+		reply, err := alice.Client.Transfer(context.Background(), &pb.TransferRequest{
+			Account:     "mary@alicevasp.us",
+			Beneficiary: beneficiary,
+			Amount:      0.1,
+			AssetType:   "Bitcoin",
+		})
+		require.NoError(t, err)
+		return reply
+	}
+
+	t.Run("beneficiary rejects originator", func(t *testing.T) {
+		h := screen(t, "api.bob.vaspbot.com", config.RejectHits)
+		reply := transfer(t, h.VASP("alice"), "robert@bobvasp.co.uk")
+		require.NotNil(t, reply.Error)
+		require.Equal(t, int32(protocol.ComplianceCheckFail), reply.Error.Code)
+		require.Contains(t, reply.Error.Message, `"Mary James" matches watchlist entry "Mary James" of the SDN program`)
+		require.Equal(t, pb.TransactionState_REJECTED, reply.Transaction.State)
+
+		xfer, err := h.VASP("bob").Transaction(reply.Transaction.EnvelopeId)
+		require.NoError(t, err)
+		require.Equal(t, pb.TransactionState_REJECTED, xfer.State)
+	})
+
+	t.Run("beneficiary holds originator for review", func(t *testing.T) {
+		h := screen(t, "api.bob.vaspbot.com", config.ReviewHits)
+		alice, bob := h.VASP("alice"), h.VASP("bob")
+
+		reply := transfer(t, alice, "robert@bobvasp.co.uk")
+		require.Nil(t, reply.Error)
+		require.Equal(t, pb.TransactionState_AWAITING_REPLY, reply.Transaction.State)
+
+		xfer, err := bob.Transaction(reply.Transaction.EnvelopeId)
+		require.NoError(t, err)
+		require.Equal(t, pb.TransactionState_PENDING_SENT, xfer.State)
+
+		// The hit is not cleared so the review rejects the transfer
+		h.HandleAsync()
+		xfer, err = bob.Transaction(reply.Transaction.EnvelopeId)
+		require.NoError(t, err)
+		require.Equal(t, pb.TransactionState_REJECTED, xfer.State)
+
+		xfer, err = alice.Transaction(reply.Transaction.EnvelopeId)
+		require.NoError(t, err)
+		require.Equal(t, pb.TransactionState_REJECTED, xfer.State)
+	})
+
+	t.Run("beneficiary releases originator after review", func(t *testing.T) {
+		h := screen(t, "api.bob.vaspbot.com", config.ReviewHits)
+		alice, bob := h.VASP("alice"), h.VASP("bob")
+
+		reply := transfer(t, alice, "robert@bobvasp.co.uk")
+		require.Nil(t, reply.Error)
+		require.Equal(t, pb.TransactionState_AWAITING_REPLY, reply.Transaction.State)
+
+		// Clearing the hit from the watchlist releases the held transfer
+		require.NoError(t, os.WriteFile(path, []byte(`[{"name": "Lawrence Clarke"}]`), 0644))
+		defer os.WriteFile(path, []byte(entries), 0644)
+
+		h.HandleAsync()
+		h.HandleAsync()
+		xfer, err := bob.Transaction(reply.Transaction.EnvelopeId)
+		require.NoError(t, err)
+		require.Equal(t, pb.TransactionState_COMPLETED, xfer.State)
+
+		xfer, err = alice.Transaction(reply.Transaction.EnvelopeId)
+		require.NoError(t, err)
+		require.Equal(t, pb.TransactionState_COMPLETED, xfer.State)
+	})
+
+	t.Run("originator rejects beneficiary", func(t *testing.T) {
+		h := screen(t, "api.alice.vaspbot.com", config.ReviewHits)
+		alice, bob := h.VASP("alice"), h.VASP("bob")
+
+		account, err := bob.Account("robert@bobvasp.co.uk")
+		require.NoError(t, err)
+
+		reply := transfer(t, alice, "robert@bobvasp.co.uk")
+		require.NotNil(t, reply.Error)
+		require.Equal(t, int32(protocol.ComplianceCheckFail), reply.Error.Code)
+		require.Contains(t, reply.Error.Message, `"Robert Howard" matches watchlist entry "Howard, Robert"`)
+		require.Equal(t, pb.TransactionState_REJECTED, reply.Transaction.State)
+
+		xfer, err := alice.Transaction(reply.Transaction.EnvelopeId)
+		require.NoError(t, err)
+		require.Equal(t, pb.TransactionState_REJECTED, xfer.State)
+
+		// The beneficiary is screened before the transfer is sent to the beneficiary
+		_, err = bob.Transaction(reply.Transaction.EnvelopeId)
+		require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+		beneficiary, err := bob.Account("robert@bobvasp.co.uk")
+		require.NoError(t, err)
+		require.True(t, account.Balance.Equal(beneficiary.Balance))
+		require.Equal(t, account.Completed, beneficiary.Completed)
+	})
+
+	t.Run("originator rejects async beneficiary", func(t *testing.T) {
+		h := screen(t, "api.alice.vaspbot.com", config.RejectHits)
+		alice, bob := h.VASP("alice"), h.VASP("bob")
+
+		// Known beneficiaries are screened before the async handshake is started
+		reply := transfer(t, alice, "larry@bobvasp.co.uk")
+		require.NotNil(t, reply.Error)
+		require.Equal(t, int32(protocol.ComplianceCheckFail), reply.Error.Code)
+		require.Contains(t, reply.Error.Message, `"Lawrence Clark" matches watchlist entry "Lawrence Clarke"`)
+		require.Equal(t, pb.TransactionState_REJECTED, reply.Transaction.State)
+
+		_, err := bob.Transaction(reply.Transaction.EnvelopeId)
+		require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+}
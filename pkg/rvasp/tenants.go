@@ -0,0 +1,119 @@
+package rvasp
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+	activity "github.com/trisacrypto/directory/pkg/utils/activity"
+	"github.com/trisacrypto/testnet/pkg/rvasp/config"
+	"github.com/trisacrypto/testnet/pkg/rvasp/db"
+	"gorm.io/gorm"
+)
+
+// Tenants hosts multiple rVASPs in a single process. Each rVASP has its own TRISA
+// server, certificates, bind addresses, async handler, and update manager but all of
+// the rVASPs share a single database connection pool.
+type Tenants struct {
+	sync.Mutex
+	servers []*Server
+	started []*Server
+	echan   chan error
+}
+
+// NewTenants creates a rVASP server for each configuration. The database and the
+// other shared settings are taken from the first configuration.
+func NewTenants(confs []*config.Config) (t *Tenants, err error) {
+	if len(confs) == 0 {
+		return nil, errors.New("no rvasps configured")
+	}
+	setupLogging(confs[0])
+
+	var gdb *gorm.DB
+	if gdb, err = db.OpenDB(confs[0]); err != nil {
+		return nil, err
+	}
+
+	// Refuse to start if the schema has not been migrated to the expected version
+	if err = db.CheckSchema(gdb); err != nil {
+		return nil, err
+	}
+
+	t = &Tenants{
+		servers: make([]*Server, 0, len(confs)),
+		echan:   make(chan error, len(confs)),
+	}
+
+	for _, conf := range confs {
+		var vaspdb *db.DB
+		if vaspdb, err = db.NewVASPDB(gdb, conf.Name); err != nil {
+			return nil, fmt.Errorf("could not create %s: %s", conf.Name, err)
+		}
+
+		var s *Server
+		if s, err = newServer(conf, vaspdb, t.echan); err != nil {
+			return nil, fmt.Errorf("could not create %s: %s", conf.Name, err)
+		}
+		t.servers = append(t.servers, s)
+	}
+
+	// Start the activity publisher
+	if err = activity.Start(confs[0].Activity); err != nil {
+		return nil, fmt.Errorf("could not start the activity publisher: %s", err)
+	}
+
+	return t, nil
+}
+
+// Servers returns the rVASP servers hosted by the process.
+func (t *Tenants) Servers() []*Server {
+	return t.servers
+}
+
+// Serve all of the rVASPs and block until the process is interrupted or one of the
+// servers stops with an error, in which case all of the servers are shut down.
+func (t *Tenants) Serve() (err error) {
+	// Catch OS signals for graceful shutdowns
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, os.Interrupt)
+	go func() {
+		<-quit
+		t.echan <- t.Shutdown()
+	}()
+
+	for _, s := range t.servers {
+		if err = s.Start(); err != nil {
+			t.Shutdown()
+			return fmt.Errorf("could not start %s: %s", s.vasp.Name, err)
+		}
+
+		t.Lock()
+		t.started = append(t.started, s)
+		t.Unlock()
+	}
+
+	// Listen for any errors that might have occurred; a single failing rVASP stops
+	// the process so that the failure is not hidden by the other rVASPs.
+	if err = <-t.echan; err != nil {
+		t.Shutdown()
+		return err
+	}
+	return nil
+}
+
+// Shutdown all of the started rVASPs gracefully.
+func (t *Tenants) Shutdown() (err error) {
+	t.Lock()
+	defer t.Unlock()
+	for _, s := range t.started {
+		if serr := s.Shutdown(); serr != nil {
+			log.Error().Err(serr).Str("name", s.vasp.Name).Msg("could not shutdown rvasp")
+			err = serr
+		}
+	}
+	t.started = nil
+	return err
+}
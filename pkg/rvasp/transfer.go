@@ -2,10 +2,12 @@ package rvasp
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/rs/zerolog/log"
 	"github.com/trisacrypto/testnet/pkg/rvasp/db"
+	"github.com/trisacrypto/testnet/pkg/utils"
 	"github.com/trisacrypto/trisa/pkg/ivms101"
 	protocol "github.com/trisacrypto/trisa/pkg/trisa/api/v1beta1"
 	generic "github.com/trisacrypto/trisa/pkg/trisa/data/generic/v1beta1"
@@ -291,3 +293,22 @@ func ValidateIdentityPayload(identity *ivms101.IdentityPayload, requireBeneficia
 	}
 	return nil
 }
+
+// ValidateBeneficiaryAddress checks that the beneficiary wallet address is well formed
+// for the asset type of the transfer. Email addresses and asset types without an
+// address validator are not checked since there is no wallet address to validate.
+func ValidateBeneficiaryAddress(assetType, address string) *protocol.Error {
+	if address == "" || strings.Contains(address, "@") {
+		return nil
+	}
+
+	validate, err := utils.LookupValidator(assetType)
+	if err != nil {
+		return nil
+	}
+
+	if err = validate(address); err != nil {
+		return protocol.Errorf(protocol.UnkownWalletAddress, "invalid %s beneficiary wallet address %q: %s", assetType, address, err)
+	}
+	return nil
+}
@@ -113,3 +113,20 @@ func (s *rVASPTestSuite) TestValidateIdentityPayload() {
 	err = rvasp.ValidateIdentityPayload(req, true)
 	require.Nil(err)
 }
+
+func (s *rVASPTestSuite) TestValidateBeneficiaryAddress() {
+	require := s.Require()
+
+	// Should not validate email addresses or asset types without a validator
+	require.Nil(rvasp.ValidateBeneficiaryAddress("Bitcoin", "george@bobvasp.co.uk"))
+	require.Nil(rvasp.ValidateBeneficiaryAddress("", "not an address"))
+	require.Nil(rvasp.ValidateBeneficiaryAddress("Tezos", "not an address"))
+
+	// Should accept well formed addresses for the asset type
+	require.Nil(rvasp.ValidateBeneficiaryAddress("Bitcoin", "18nxAxBktHZDrMoJ3N2fk9imLX8xNnYbNh"))
+	require.Nil(rvasp.ValidateBeneficiaryAddress("ETH", "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"))
+
+	// Should return an unknown wallet address error for malformed addresses
+	err := rvasp.ValidateBeneficiaryAddress("Bitcoin", "1oJuU1GjhJzUdUGukw13a4w6CWjfFsJ92q")
+	require.EqualError(err, `trisa rejection [UNKNOWN_WALLET_ADDRESS]: invalid Bitcoin beneficiary wallet address "1oJuU1GjhJzUdUGukw13a4w6CWjfFsJ92q": invalid checksum`)
+}
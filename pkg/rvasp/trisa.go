@@ -39,6 +39,7 @@ type TRISA struct {
 	certs  *trust.Provider
 	chain  trust.ProviderPool
 	sign   *rsa.PrivateKey
+	limits *PeerLimiter
 }
 
 // NewTRISA from a parent server.
@@ -49,7 +50,7 @@ func NewTRISA(parent *Server) (svc *TRISA, err error) {
 	}
 
 	var sz *trust.Serializer
-	svc = &TRISA{parent: parent}
+	svc = &TRISA{parent: parent, limits: NewPeerLimiter(conf.RateLimit)}
 
 	// Load the TRISA certificates for server-side TLS
 	if sz, err = trust.NewSerializer(false); err != nil {
@@ -74,25 +75,25 @@ func NewTRISA(parent *Server) (svc *TRISA, err error) {
 	if svc.sign, err = svc.certs.GetRSAKeys(); err != nil {
 		return nil, err
 	}
-	return svc, nil
-}
 
-// Serve initializes the GRPC server and returns any errors during initialization, it
-// then kicks off a go routine to handle requests. Not thread safe, should not be called
-// multiple times.
-func (s *TRISA) Serve() (err error) {
 	// Create TLS Credentials for the server
 	// NOTE: the mtls package specifies TRISA-specific TLS configuration.
 	var creds grpc.ServerOption
-	if creds, err = mtls.ServerCreds(s.certs, s.chain); err != nil {
-		return err
+	if creds, err = mtls.ServerCreds(svc.certs, svc.chain); err != nil {
+		return nil, err
 	}
 
 	// Create a new gRPC server with panic recovery and tracing middleware
-	s.srv = grpc.NewServer(creds, grpc.UnaryInterceptor(UnaryTraceInterceptor), grpc.StreamInterceptor(StreamTraceInterceptor))
-	protocol.RegisterTRISANetworkServer(s.srv, s)
-	protocol.RegisterTRISAHealthServer(s.srv, s)
+	svc.srv = grpc.NewServer(creds, grpc.UnaryInterceptor(UnaryTraceInterceptor), grpc.StreamInterceptor(StreamTraceInterceptor))
+	protocol.RegisterTRISANetworkServer(svc.srv, svc)
+	protocol.RegisterTRISAHealthServer(svc.srv, svc)
+	return svc, nil
+}
 
+// Serve the GRPC server on the TRISA bind address and returns any errors during
+// initialization, it then kicks off a go routine to handle requests. Not thread safe,
+// should not be called multiple times.
+func (s *TRISA) Serve() (err error) {
 	var sock net.Listener
 	if sock, err = net.Listen("tcp", s.parent.conf.TRISABindAddr); err != nil {
 		return fmt.Errorf("trisa service could not listen on %q", s.parent.conf.TRISABindAddr)
@@ -142,7 +143,17 @@ func (s *TRISA) Transfer(ctx context.Context, in *protocol.SecureEnvelope) (out
 		return msg, nil
 	}
 	log.Info().Str("peer", peer.String()).Msg("unary transfer request received")
-	s.parent.updates.Broadcast(0, fmt.Sprintf("received secure exchange from %s", peer), pb.MessageCategory_TRISAP2P)
+
+	// Reject the request before doing any work if the peer is over its rate limit
+	if reject := s.rateLimit(peer); reject != nil {
+		var msg *protocol.SecureEnvelope
+		if msg, err = envelope.Reject(reject, envelope.WithEnvelopeID(in.Id)); err != nil {
+			log.Error().Err(err).Msg("could not create TRISA error envelope")
+			return nil, status.Errorf(codes.Internal, "could not create TRISA error envelope: %s", err)
+		}
+		return msg, nil
+	}
+	s.parent.updates.Broadcast(0, NewTopic(in.Id), fmt.Sprintf("received secure exchange from %s", peer), pb.MessageCategory_TRISAP2P)
 
 	// Fetch the signing key from the peer to ensure we can encrypt envelopes
 	if _, err = s.parent.fetchSigningKey(peer); err != nil {
@@ -156,6 +167,11 @@ func (s *TRISA) Transfer(ctx context.Context, in *protocol.SecureEnvelope) (out
 		return msg, nil
 	}
 
+	// Record the exchange with the originator if enabled
+	exchange := s.parent.records.Exchange(peer.String())
+	exchange.Inbound(in)
+	defer func() { exchange.Reply(out, err) }()
+
 	var transferError *protocol.Error
 	if out, transferError = s.handleTransaction(ctx, peer, in); transferError != nil {
 		log.Warn().Err(transferError).Msg("could not complete transfer")
@@ -171,6 +187,15 @@ func (s *TRISA) Transfer(ctx context.Context, in *protocol.SecureEnvelope) (out
 	return out, nil
 }
 
+// rateLimit returns a retryable Unavailable error if the peer has exceeded its rate
+// limit or quota, otherwise nil.
+func (s *TRISA) rateLimit(peer *peers.Peer) *protocol.Error {
+	if ok, retryAfter := s.limits.Allow(peer.String()); !ok {
+		return protocol.Errorf(protocol.Unavailable, "rate limit exceeded, retry after %s", retryAfter.Round(time.Millisecond)).WithRetry()
+	}
+	return nil
+}
+
 // TransferStream allows for high-throughput transactions.
 func (s *TRISA) TransferStream(stream protocol.TRISANetwork_TransferStreamServer) (err error) {
 	// Get the peer from the context
@@ -184,20 +209,20 @@ func (s *TRISA) TransferStream(stream protocol.TRISANetwork_TransferStreamServer
 		}
 	}
 	log.Info().Str("peer", peer.String()).Msg("transfer stream opened")
-	s.parent.updates.Broadcast(0, fmt.Sprintf("transfer stream opened from %s", peer), pb.MessageCategory_TRISAP2P)
+	s.parent.updates.Broadcast(0, Topic{}, fmt.Sprintf("transfer stream opened from %s", peer), pb.MessageCategory_TRISAP2P)
 
 	// Check signing key is available to send an encrypted response
-	if peer.SigningKey() == nil {
+	if s.parent.signingKey(peer) == nil {
 		log.Warn().Str("peer", peer.String()).Msg("no remote signing key available, attempting key exchange")
-		s.parent.updates.Broadcast(0, "no remote signing key available, attempting key exchange", pb.MessageCategory_TRISAP2P)
+		s.parent.updates.Broadcast(0, Topic{}, "no remote signing key available, attempting key exchange", pb.MessageCategory_TRISAP2P)
 
 		if _, err = peer.ExchangeKeys(false); err != nil {
 			log.Warn().Err(err).Str("peer", peer.String()).Msg("no remote signing key available, key exchange failed")
-			s.parent.updates.Broadcast(0, fmt.Sprintf("key exchange failed: %s", err), pb.MessageCategory_TRISAP2P)
+			s.parent.updates.Broadcast(0, Topic{}, fmt.Sprintf("key exchange failed: %s", err), pb.MessageCategory_TRISAP2P)
 		}
 
 		// Second check for signing keys, if they're not available then reject messages
-		if peer.SigningKey() == nil {
+		if s.parent.signingKey(peer) == nil {
 			return &protocol.Error{
 				Code:    protocol.NoSigningKey,
 				Message: "please retry transfer after key exchange",
@@ -218,12 +243,31 @@ func (s *TRISA) TransferStream(stream protocol.TRISANetwork_TransferStreamServer
 		var in *protocol.SecureEnvelope
 		if in, err = stream.Recv(); err == io.EOF {
 			log.Info().Str("peer", peer.String()).Msg("transfer stream closed")
+			return nil
 		} else if err != nil {
 			log.Warn().Err(err).Msg("recv stream error")
 			return protocol.Errorf(protocol.Unavailable, "stream closed prematurely: %s", err)
 		}
 
+		// Reject each envelope that exceeds the rate limit of the peer without closing
+		// the stream so that the peer can retry it.
+		if reject := s.rateLimit(peer); reject != nil {
+			var out *protocol.SecureEnvelope
+			if out, err = envelope.Reject(reject, envelope.WithEnvelopeID(in.Id)); err != nil {
+				log.Error().Err(err).Msg("could not create TRISA error envelope")
+				return status.Errorf(codes.Internal, "could not create TRISA error envelope: %s", err)
+			}
+
+			if err = stream.Send(out); err != nil {
+				log.Error().Err(err).Msg("send stream error")
+				return err
+			}
+			continue
+		}
+
 		// Handle the response
+		exchange := s.parent.records.Exchange(peer.String())
+		exchange.Inbound(in)
 		out, err := s.handleTransaction(ctx, peer, in)
 		if err != nil {
 			// Do not close the stream, send the error in the secure envelope if the
@@ -232,6 +276,7 @@ func (s *TRISA) TransferStream(stream protocol.TRISANetwork_TransferStreamServer
 				Error: err,
 			}
 		}
+		exchange.Reply(out, nil)
 
 		if err := stream.Send(out); err != nil {
 			log.Error().Err(err).Msg("send stream error")
@@ -268,7 +313,10 @@ func (s *TRISA) handleTransaction(ctx context.Context, peer *peers.Peer, in *pro
 			}
 
 			// Set the transaction state to rejected
-			xfer.SetState(pb.TransactionState_REJECTED)
+			if err = xfer.SetState(pb.TransactionState_REJECTED, db.ActorPeer, reject.Error()); err != nil {
+				log.Warn().Err(err).Str("id", in.Id).Msg("could not reject transaction")
+				return nil, protocol.Errorf(protocol.ComplianceCheckFail, "could not reject transaction: %s", err)
+			}
 			if err = s.parent.db.Save(xfer).Error; err != nil {
 				log.Error().Err(err).Msg("could not save transaction")
 				return nil, protocol.Errorf(protocol.InternalError, "could not save transaction: %s", err)
@@ -285,10 +333,10 @@ func (s *TRISA) handleTransaction(ctx context.Context, peer *peers.Peer, in *pro
 			Str("encryption", in.EncryptionAlgorithm).
 			Str("hmac", in.HmacAlgorithm).
 			Msg("unsupported cryptographic algorithms")
-		s.parent.updates.Broadcast(0, "server only supports AES256-GCM and HMAC-SHA256", pb.MessageCategory_TRISAP2P)
+		s.parent.updates.Broadcast(0, NewTopic(in.Id), "server only supports AES256-GCM and HMAC-SHA256", pb.MessageCategory_TRISAP2P)
 		return nil, protocol.Errorf(protocol.UnhandledAlgorithm, "server only supports AES256-GCM and HMAC-SHA256")
 	}
-	s.parent.updates.Broadcast(0, "decrypting with RSA and AES256-GCM; verifying with HMAC-SHA256", pb.MessageCategory_TRISAP2P)
+	s.parent.updates.Broadcast(0, NewTopic(in.Id), "decrypting with RSA and AES256-GCM; verifying with HMAC-SHA256", pb.MessageCategory_TRISAP2P)
 
 	// Decrypt the encryption key and HMAC secret with private signing keys (asymmetric phase)
 	payload, reject, err := envelope.Open(in, envelope.WithRSAPrivateKey(s.sign))
@@ -308,7 +356,7 @@ func (s *TRISA) handleTransaction(ctx context.Context, peer *peers.Peer, in *pro
 		return nil, transferError
 	}
 
-	s.parent.updates.Broadcast(0, fmt.Sprintf("secure envelope %s opened and payload decrypted and parsed", in.Id), pb.MessageCategory_TRISAP2P)
+	s.parent.updates.Broadcast(0, NewTopic(in.Id, transaction.GetOriginator(), transaction.GetBeneficiary()), fmt.Sprintf("secure envelope %s opened and payload decrypted and parsed", in.Id), pb.MessageCategory_TRISAP2P)
 
 	// Check if we are the originator of the transaction
 	var localIdentity *ivms101.Person
@@ -327,10 +375,18 @@ func (s *TRISA) handleTransaction(ctx context.Context, peer *peers.Peer, in *pro
 			return nil, protocol.Errorf(protocol.InternalError, "could not find pending transaction: %s", err)
 		}
 
+		// The async handshake cannot continue once the transaction has been finalized
+		if db.Final(xfer.State) {
+			log.Warn().Str("id", in.Id).Str("state", xfer.State.String()).Msg("transaction is already in a final state")
+			return nil, protocol.Errorf(protocol.ComplianceCheckFail, "transaction is already %s", xfer.State.String())
+		}
+
 		// Perform the transfer back to the originator
-		if out, transferError = s.parent.respondAsync(peer, payload, identity, transaction, xfer); transferError != nil {
+		if out, transferError = s.parent.respondAsync(peer, payload, identity, transaction, xfer); transferError != nil && xfer.State != pb.TransactionState_REJECTED {
 			log.Warn().Err(err).Msg("TRISA protocol error while responding to async transaction")
-			xfer.SetState(pb.TransactionState_FAILED)
+			if err = xfer.SetState(pb.TransactionState_FAILED, db.ActorPeer, transferError.Error()); err != nil {
+				log.Error().Err(err).Msg("could not fail transaction")
+			}
 		}
 
 		// Save the updated transaction
@@ -342,6 +398,12 @@ func (s *TRISA) handleTransaction(ctx context.Context, peer *peers.Peer, in *pro
 		return out, transferError
 	}
 
+	// Refuse transfers from originators that the rVASP does not do business with
+	if transferError = s.parent.counterparties.Check(peer.String(), identity.GetOriginatingVasp().GetOriginatingVasp()); transferError != nil {
+		s.parent.updates.Broadcast(0, NewTopic(in.Id), fmt.Sprintf("refusing transfer from %s: %s", peer, transferError.Message), pb.MessageCategory_TRISAP2P)
+		return nil, transferError
+	}
+
 	// Lookup the beneficiary in the local VASP database.
 	var accountAddress string
 	if transaction.Beneficiary == "" {
@@ -351,6 +413,11 @@ func (s *TRISA) handleTransaction(ctx context.Context, peer *peers.Peer, in *pro
 		accountAddress = transaction.Beneficiary
 	}
 
+	if transferError = ValidateBeneficiaryAddress(transaction.AssetType, transaction.Beneficiary); transferError != nil {
+		log.Warn().Str("message", transferError.Message).Msg("invalid beneficiary wallet address")
+		return nil, transferError
+	}
+
 	var account db.Account
 	if err = s.parent.db.LookupAccount(accountAddress).First(&account).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -377,7 +444,7 @@ func (s *TRISA) handleTransaction(ctx context.Context, peer *peers.Peer, in *pro
 	if err = s.parent.db.LookupTransaction(in.Id).First(xfer).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			// Create a new pending transaction in the database
-			if xfer, err = s.parent.db.MakeTransaction(transaction.Originator, transaction.Beneficiary); err != nil {
+			if xfer, err = s.parent.db.MakeTransaction(transaction.Originator, transaction.Beneficiary, db.ActorPeer); err != nil {
 				log.Error().Err(err).Msg("could not construct transaction")
 				return nil, protocol.Errorf(protocol.InternalError, "request could not be processed")
 			}
@@ -396,9 +463,50 @@ func (s *TRISA) handleTransaction(ctx context.Context, peer *peers.Peer, in *pro
 		}
 	}
 
+	// Transactions in a final state cannot be continued, e.g. if a completed
+	// transfer is resent by the counterparty
+	if db.Final(xfer.State) {
+		log.Warn().Str("id", in.Id).Str("state", xfer.State.String()).Msg("transaction is already in a final state")
+		return nil, protocol.Errorf(protocol.ComplianceCheckFail, "transaction is already %s", xfer.State.String())
+	}
+
+	// Screen the originator against the sanctions watchlist, hits are either rejected or
+	// held for review with a pending reply.
+	var held bool
+	if hit := s.parent.screener.Screen(identity.GetOriginator().GetOriginatorPersons()); hit != nil {
+		s.parent.updates.Broadcast(0, NewTopic(in.Id), fmt.Sprintf("originator screening hit: %s", hit), pb.MessageCategory_TRISAP2P)
+		if !s.parent.screener.Review() {
+			transferError = hit.Reject()
+			if err = xfer.SetState(pb.TransactionState_REJECTED, db.ActorPeer, transferError.Error()); err != nil {
+				log.Error().Err(err).Msg("could not reject transaction")
+			}
+
+			if err = s.parent.db.Save(xfer).Error; err != nil {
+				log.Error().Err(err).Msg("could not save transaction")
+				return nil, protocol.Errorf(protocol.InternalError, "could not save transaction: %s", err)
+			}
+			return nil, transferError
+		}
+		held = true
+	}
+
 	// Run the scenario for the wallet's configured policy
 	policy := wallet.BeneficiaryPolicy
-	log.Debug().Str("wallet", account.WalletAddress).Str("policy", string(policy)).Msg("received transfer request")
+	message := fmt.Sprintf("You have initiated an asynchronous transfer with a robot VASP using the %s policy", policy)
+
+	// Transfers held for review are continued asynchronously whatever the policy of the
+	// wallet; the originator is screened again before the rVASP replies.
+	if held || xfer.State == pb.TransactionState_AWAITING_FULL_TRANSFER {
+		if policy == db.SyncRepair || policy == db.SyncRequire {
+			policy = db.AsyncRepair
+		}
+	}
+
+	if held {
+		message = "Your transfer is held for compliance review by a robot VASP"
+	}
+
+	log.Debug().Str("wallet", account.WalletAddress).Str("policy", string(policy)).Bool("held", held).Msg("received transfer request")
 	switch policy {
 	case db.SyncRepair:
 		// Respond to the transfer request immediately, filling in the beneficiary
@@ -411,11 +519,11 @@ func (s *TRISA) handleTransaction(ctx context.Context, peer *peers.Peer, in *pro
 	case db.AsyncRepair:
 		// Respond to the transfer request with a pending message and mark the
 		// transaction for later service. The beneficiary information is filled in.
-		out, transferError = s.respondPending(in, peer, identity, transaction, xfer, account, policy)
+		out, transferError = s.respondPending(in, peer, identity, transaction, xfer, account, message)
 	case db.AsyncReject:
 		// Respond to the transfer request with a pending message that will be later
 		// rejected.
-		out, transferError = s.respondPending(in, peer, identity, transaction, xfer, account, policy)
+		out, transferError = s.respondPending(in, peer, identity, transaction, xfer, account, message)
 	default:
 		return nil, protocol.Errorf(protocol.InternalError, "unknown policy '%s' for wallet '%s'", policy, account.WalletAddress)
 	}
@@ -423,7 +531,9 @@ func (s *TRISA) handleTransaction(ctx context.Context, peer *peers.Peer, in *pro
 	// Mark transaction as failed if it was not rejected but an error occurred
 	if xfer.State != pb.TransactionState_REJECTED && transferError != nil {
 		log.Debug().Err(transferError).Msg("transfer failed")
-		xfer.SetState(pb.TransactionState_FAILED)
+		if err = xfer.SetState(pb.TransactionState_FAILED, db.ActorPeer, transferError.Error()); err != nil {
+			log.Error().Err(err).Msg("could not fail transaction")
+		}
 	}
 
 	// Save the updated transaction
@@ -481,7 +591,9 @@ func (s *TRISA) respondTransfer(in *protocol.SecureEnvelope, peer *peers.Peer, i
 
 	if transferError = ValidateIdentityPayload(identity, requireBeneficiary); transferError != nil {
 		log.Warn().Str("message", transferError.Message).Msg("could not validate identity payload")
-		xfer.SetState(pb.TransactionState_REJECTED)
+		if err = xfer.SetState(pb.TransactionState_REJECTED, db.ActorPeer, transferError.Error()); err != nil {
+			log.Error().Err(err).Msg("could not reject transaction")
+		}
 		return nil, transferError
 	}
 
@@ -512,8 +624,9 @@ func (s *TRISA) respondTransfer(in *protocol.SecureEnvelope, peer *peers.Peer, i
 		return nil, protocol.Errorf(protocol.InternalError, "request could not be processed")
 	}
 
+	topic := NewTopic(in.Id, transaction.Originator, account.Email, account.WalletAddress)
 	msg := fmt.Sprintf("ready for transaction %04d: %s transferring from %s to %s", xfer.ID, xfer.Amount, xfer.Originator.WalletAddress, xfer.Beneficiary.WalletAddress)
-	s.parent.updates.Broadcast(0, msg, pb.MessageCategory_BLOCKCHAIN)
+	s.parent.updates.Broadcast(0, topic, msg, pb.MessageCategory_BLOCKCHAIN)
 
 	// Encode and encrypt the payload information to return the secure envelope
 	payload := &protocol.Payload{
@@ -529,7 +642,7 @@ func (s *TRISA) respondTransfer(in *protocol.SecureEnvelope, peer *peers.Peer, i
 		return nil, protocol.Errorf(protocol.InternalError, "request could not be processed")
 	}
 
-	s.parent.updates.Broadcast(0, "sealing beneficiary information and returning", pb.MessageCategory_TRISAP2P)
+	s.parent.updates.Broadcast(0, topic, "sealing beneficiary information and returning", pb.MessageCategory_TRISAP2P)
 
 	out, reject, err := envelope.Seal(payload, envelope.WithRSAPublicKey(signKey), envelope.WithEnvelopeID(in.Id))
 	if err != nil {
@@ -537,24 +650,30 @@ func (s *TRISA) respondTransfer(in *protocol.SecureEnvelope, peer *peers.Peer, i
 			if out, err = envelope.Reject(reject, envelope.WithEnvelopeID(in.Id)); err != nil {
 				return nil, protocol.Errorf(protocol.EnvelopeDecodeFail, "TRISA protocol error: %s", err)
 			}
-			xfer.SetState(pb.TransactionState_REJECTED)
+			if err = xfer.SetState(pb.TransactionState_REJECTED, db.ActorPeer, reject.Error()); err != nil {
+				log.Error().Err(err).Msg("could not reject transaction")
+				return nil, protocol.Errorf(protocol.ComplianceCheckFail, "could not reject transaction: %s", err)
+			}
 			return out, nil
 		}
 		log.Warn().Err(err).Msg("TRISA protocol error while sealing envelope")
 		return nil, protocol.Errorf(protocol.EnvelopeDecodeFail, "TRISA protocol error: %s", err)
 	}
 
-	s.parent.updates.Broadcast(0, fmt.Sprintf("%04d new account balance: %s", account.ID, account.Balance), pb.MessageCategory_LEDGER)
+	s.parent.updates.Broadcast(0, topic, fmt.Sprintf("%04d new account balance: %s", account.ID, account.Balance), pb.MessageCategory_LEDGER)
 
 	// Mark transaction as completed
-	xfer.SetState(pb.TransactionState_COMPLETED)
+	if err = xfer.SetState(pb.TransactionState_COMPLETED, db.ActorPeer, "returned the transaction to the originator"); err != nil {
+		log.Error().Err(err).Msg("could not complete transaction")
+		return nil, protocol.Errorf(protocol.ComplianceCheckFail, "could not complete transaction: %s", err)
+	}
 
 	return out, nil
 }
 
 // respondPending responds to a transfer request from the originator by returning a
 // pending message and saving the pending transaction in the database.
-func (s *TRISA) respondPending(in *protocol.SecureEnvelope, peer *peers.Peer, identity *ivms101.IdentityPayload, transaction *generic.Transaction, xfer *db.Transaction, account db.Account, policy db.PolicyType) (out *protocol.SecureEnvelope, transferError *protocol.Error) {
+func (s *TRISA) respondPending(in *protocol.SecureEnvelope, peer *peers.Peer, identity *ivms101.IdentityPayload, transaction *generic.Transaction, xfer *db.Transaction, account db.Account, message string) (out *protocol.SecureEnvelope, transferError *protocol.Error) {
 	now := time.Now()
 
 	xfer.NotBefore = now.Add(s.parent.conf.AsyncNotBefore)
@@ -614,7 +733,7 @@ func (s *TRISA) respondPending(in *protocol.SecureEnvelope, peer *peers.Peer, id
 		EnvelopeId:     xfer.Envelope,
 		ReceivedBy:     s.parent.vasp.Name + " (Robot VASP)",
 		ReceivedAt:     time.Now().Format(time.RFC3339),
-		Message:        fmt.Sprintf("You have initiated an asynchronous transfer with a robot VASP using the %s policy", policy),
+		Message:        message,
 		ReplyNotBefore: xfer.NotBefore.Format(time.RFC3339),
 		ReplyNotAfter:  xfer.NotAfter.Format(time.RFC3339),
 		Transaction:    transaction,
@@ -632,7 +751,10 @@ func (s *TRISA) respondPending(in *protocol.SecureEnvelope, peer *peers.Peer, id
 			if out, err = envelope.Reject(reject, envelope.WithEnvelopeID(in.Id)); err != nil {
 				return nil, protocol.Errorf(protocol.EnvelopeDecodeFail, "TRISA protocol error: %s", err)
 			}
-			xfer.SetState(pb.TransactionState_REJECTED)
+			if err = xfer.SetState(pb.TransactionState_REJECTED, db.ActorPeer, reject.Error()); err != nil {
+				log.Error().Err(err).Msg("could not reject transaction")
+				return nil, protocol.Errorf(protocol.ComplianceCheckFail, "could not reject transaction: %s", err)
+			}
 			return out, nil
 		}
 		log.Warn().Err(err).Msg("TRISA protocol error while sealing envelope")
@@ -641,9 +763,14 @@ func (s *TRISA) respondPending(in *protocol.SecureEnvelope, peer *peers.Peer, id
 
 	// Mark the transaction as pending for the async routine
 	if xfer.State == pb.TransactionState_AWAITING_REPLY {
-		xfer.SetState(pb.TransactionState_PENDING_SENT)
+		err = xfer.SetState(pb.TransactionState_PENDING_SENT, db.ActorPeer, "sent a pending reply to the originator")
 	} else {
-		xfer.SetState(pb.TransactionState_PENDING_ACKNOWLEDGED)
+		err = xfer.SetState(pb.TransactionState_PENDING_ACKNOWLEDGED, db.ActorPeer, "sent a pending reply to the continued transaction")
+	}
+
+	if err != nil {
+		log.Warn().Err(err).Msg("could not mark transaction as pending")
+		return nil, protocol.Errorf(protocol.ComplianceCheckFail, "could not mark transaction as pending: %s", err)
 	}
 
 	return out, nil
@@ -666,6 +793,10 @@ func (s *TRISA) sendAsync(tx *db.Transaction) (err error) {
 		return fmt.Errorf("could not fetch originator peer: %s", err)
 	}
 
+	// Record the exchanges with the originator if enabled
+	exchange := s.parent.records.Exchange(peer.String())
+	defer func() { exchange.Save(tx.State, err) }()
+
 	// Create the identity for the payload
 	identity := &ivms101.IdentityPayload{}
 	if err = protojson.Unmarshal([]byte(tx.Identity), identity); err != nil {
@@ -685,10 +816,12 @@ func (s *TRISA) sendAsync(tx *db.Transaction) (err error) {
 			}
 
 			// Conduct the TRISA exchange, handle errors
+			exchange.Outbound(reject)
 			if reject, err = peer.Transfer(reject); err != nil {
 				log.Warn().Err(err).Msg("could not perform TRISA exchange")
 				return fmt.Errorf("could not perform TRISA exchange: %s", err)
 			}
+			exchange.Inbound(reject)
 
 			// Check for the TRISA rejection error
 			rejectErr, isErr := envelope.Check(reject)
@@ -697,7 +830,13 @@ func (s *TRISA) sendAsync(tx *db.Transaction) (err error) {
 				log.Warn().Str("state", state.String()).Msg("unexpected TRISA response, expected reject envelope")
 				return fmt.Errorf("expected TRISA rejection error, received envelope in state %s", state.String())
 			}
-			tx.SetState(pb.TransactionState_REJECTED)
+
+			// The transaction cannot be continued once the originator has been notified
+			if err = tx.SetState(pb.TransactionState_REJECTED, db.ActorAsync, validationError.Error()); err != nil {
+				log.Error().Err(err).Msg("could not reject transaction")
+				return fmt.Errorf("could not reject transaction: %s", err)
+			}
+			return nil
 		}
 
 		var account *db.Account
@@ -746,10 +885,23 @@ func (s *TRISA) sendAsync(tx *db.Transaction) (err error) {
 	}
 
 	// Conduct the TRISA exchange, handle errors
+	exchange.Outbound(msg)
 	if msg, err = peer.Transfer(msg); err != nil {
 		log.Warn().Err(err).Msg("could not perform TRISA exchange")
 		return fmt.Errorf("could not perform TRISA exchange: %s", err)
 	}
+	exchange.Inbound(msg)
+
+	// The originator may reject the transaction, e.g. if the repaired beneficiary fails
+	// the sanctions screening of the originator
+	if reject, isErr := envelope.Check(msg); isErr && reject != nil {
+		log.Info().Str("message", reject.Message).Msg("originator rejected the async transaction")
+		if err = tx.SetState(pb.TransactionState_REJECTED, db.ActorPeer, reject.Error()); err != nil {
+			log.Error().Err(err).Msg("could not reject transaction")
+			return fmt.Errorf("could not reject transaction: %s", err)
+		}
+		return nil
+	}
 
 	// Open the response envelope with local private keys
 	payload, _, err = envelope.Open(msg, envelope.WithRSAPrivateKey(s.sign))
@@ -776,7 +928,10 @@ func (s *TRISA) sendAsync(tx *db.Transaction) (err error) {
 	switch tx.State {
 	case pb.TransactionState_PENDING_SENT:
 		// The first handshake is complete so move the transaction to the next state
-		tx.SetState(pb.TransactionState_AWAITING_FULL_TRANSFER)
+		if err = tx.SetState(pb.TransactionState_AWAITING_FULL_TRANSFER, db.ActorAsync, "originator acknowledged the repaired transaction"); err != nil {
+			log.Error().Err(err).Msg("could not update transaction state")
+			return fmt.Errorf("could not update transaction state: %s", err)
+		}
 	case pb.TransactionState_PENDING_ACKNOWLEDGED:
 		// This is a complete transaction so update the database
 		var account *db.Account
@@ -794,8 +949,11 @@ func (s *TRISA) sendAsync(tx *db.Transaction) (err error) {
 		}
 
 		msg := fmt.Sprintf("ready for transaction %s: %.2f transferring from %s to %s", transaction.Txid, transaction.Amount, transaction.Originator, transaction.Beneficiary)
-		s.parent.updates.Broadcast(0, msg, pb.MessageCategory_BLOCKCHAIN)
-		tx.SetState(pb.TransactionState_COMPLETED)
+		s.parent.updates.Broadcast(0, NewTopic(tx.Envelope, transaction.Originator, transaction.Beneficiary, account.Email), msg, pb.MessageCategory_BLOCKCHAIN)
+		if err = tx.SetState(pb.TransactionState_COMPLETED, db.ActorAsync, "originator confirmed the transaction"); err != nil {
+			log.Error().Err(err).Msg("could not complete transaction")
+			return fmt.Errorf("could not complete transaction: %s", err)
+		}
 	default:
 		log.Error().Str("state", tx.State.String()).Msg("unexpected transaction state")
 		return fmt.Errorf("unexpected transaction state: %s", tx.State.String())
@@ -803,10 +961,10 @@ func (s *TRISA) sendAsync(tx *db.Transaction) (err error) {
 	return nil
 }
 
-// sendRejected sends a rejected TRISA error message to the originator.
-func (s *TRISA) sendRejected(tx *db.Transaction) (err error) {
+// sendRejected sends a TRISA error message to the originator and rejects the
+// transaction for the reason.
+func (s *TRISA) sendRejected(tx *db.Transaction, reject *protocol.Error, reason string) (err error) {
 	var (
-		reject     *protocol.Error
 		msg        *protocol.SecureEnvelope
 		originator *db.Identity
 	)
@@ -825,17 +983,22 @@ func (s *TRISA) sendRejected(tx *db.Transaction) (err error) {
 	}
 
 	// Create the rejection message
-	reject = protocol.Errorf(protocol.Rejected, "rejected by beneficiary")
 	if msg, err = envelope.Reject(reject, envelope.WithEnvelopeID(tx.Envelope)); err != nil {
 		log.Warn().Err(err).Msg("TRISA protocol error while creating reject envelope")
 		return fmt.Errorf("TRISA protocol error: %s", err)
 	}
 
+	// Record the exchange with the originator if enabled
+	exchange := s.parent.records.Exchange(peer.String())
+	defer func() { exchange.Save(tx.State, err) }()
+
 	// Conduct the TRISA exchange, handle errors
+	exchange.Outbound(msg)
 	if msg, err = peer.Transfer(msg); err != nil {
 		log.Warn().Err(err).Msg("could not perform TRISA exchange")
 		return fmt.Errorf("could not perform TRISA exchange: %s", err)
 	}
+	exchange.Inbound(msg)
 
 	// Check for the TRISA rejection error
 	if state := envelope.Status(msg); state != envelope.Error {
@@ -843,7 +1006,10 @@ func (s *TRISA) sendRejected(tx *db.Transaction) (err error) {
 		return fmt.Errorf("expected TRISA rejection error, received envelope in state %d", state)
 	}
 
-	tx.SetState(pb.TransactionState_REJECTED)
+	if err = tx.SetState(pb.TransactionState_REJECTED, db.ActorAsync, reason); err != nil {
+		log.Error().Err(err).Msg("could not reject transaction")
+		return fmt.Errorf("could not reject transaction: %s", err)
+	}
 
 	return nil
 }
@@ -868,7 +1034,11 @@ func (s *TRISA) KeyExchange(ctx context.Context, in *protocol.SigningKey) (out *
 		}
 	}
 	log.Info().Str("peer", peer.String()).Msg("key exchange request received")
-	s.parent.updates.Broadcast(0, fmt.Sprintf("key exchange request received from %s", peer), pb.MessageCategory_TRISAP2P)
+
+	if reject := s.rateLimit(peer); reject != nil {
+		return nil, reject
+	}
+	s.parent.updates.Broadcast(0, Topic{}, fmt.Sprintf("key exchange request received from %s", peer), pb.MessageCategory_TRISAP2P)
 
 	// Cache key inside of the in-memory Peer map
 	var pub interface{}
@@ -881,21 +1051,36 @@ func (s *TRISA) KeyExchange(ctx context.Context, in *protocol.SigningKey) (out *
 		return nil, protocol.Errorf(protocol.NoSigningKey, "could not parse signing key")
 	}
 
-	if err = peer.UpdateSigningKey(pub); err != nil {
-		log.Error().Err(err).Msg("could not update signing key")
+	// Store the key before replying so that transfers sent by the peer after the key
+	// exchange can be answered, without waiting for an outgoing key exchange with the
+	// peer that may be waiting for this reply.
+	key, ok := pub.(*rsa.PublicKey)
+	if !ok {
+		log.Error().Str("type", fmt.Sprintf("%T", pub)).Msg("could not update signing key")
 		return nil, protocol.Errorf(protocol.UnhandledAlgorithm, "unsupported signing algorithm")
 	}
+	s.parent.keys.Set(peer.String(), key)
 
 	// TODO: check not before and not after constraints
 
 	// TODO: Kick off a go routine to store the key in the database
 
 	// Return the public signing-key of the service
-	// TODO: use separate signing key instead of using public key of mTLS certs
+	if out, err = signingKey(s.certs); err != nil {
+		return nil, protocol.Errorf(protocol.InternalError, "could not return signing keys")
+	}
+	s.parent.updates.Broadcast(0, Topic{}, "keys marshaled, returning public keys for signing", pb.MessageCategory_TRISAP2P)
+
+	return out, nil
+}
+
+// signingKey returns the public signing key of the certificates for key exchange.
+// TODO: use separate signing key instead of using public key of mTLS certs
+func signingKey(certs *trust.Provider) (out *protocol.SigningKey, err error) {
 	var key *x509.Certificate
-	if key, err = s.certs.GetLeafCertificate(); err != nil {
+	if key, err = certs.GetLeafCertificate(); err != nil {
 		log.Warn().Err(err).Msg("could not extract leaf certificate")
-		return nil, protocol.Errorf(protocol.InternalError, "could not return signing keys")
+		return nil, err
 	}
 
 	out = &protocol.SigningKey{
@@ -909,10 +1094,8 @@ func (s *TRISA) KeyExchange(ctx context.Context, in *protocol.SigningKey) (out *
 
 	if out.Data, err = x509.MarshalPKIXPublicKey(key.PublicKey); err != nil {
 		log.Error().Err(err).Msg("could not marshal PKIX public key")
-		return nil, protocol.Errorf(protocol.InternalError, "could not marshal public key")
+		return nil, err
 	}
-	s.parent.updates.Broadcast(0, "keys marshaled, returning public keys for signing", pb.MessageCategory_TRISAP2P)
-
 	return out, nil
 }
 
@@ -12,6 +12,7 @@ import (
 	"github.com/trisacrypto/testnet/pkg/rvasp/bufconn"
 	"github.com/trisacrypto/testnet/pkg/rvasp/config"
 	"github.com/trisacrypto/testnet/pkg/rvasp/db"
+	pb "github.com/trisacrypto/testnet/pkg/rvasp/pb/v1"
 	"github.com/trisacrypto/trisa/pkg/ivms101"
 	protocol "github.com/trisacrypto/trisa/pkg/trisa/api/v1beta1"
 	generic "github.com/trisacrypto/trisa/pkg/trisa/data/generic/v1beta1"
@@ -149,8 +150,8 @@ func (s *rVASPTestSuite) TestValidTransfer() {
 	s.db.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"wallet_address"}).AddRow(beneficiaryAddress))
 	s.db.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"beneficiary_policy"}).AddRow("SyncRequire"))
 
-	// Preload the transaction lookup
-	expectStandardQuery(s.db, "SELECT")
+	// Preload the transaction lookup with a transaction that is awaiting a reply
+	s.db.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id", "state"}).AddRow(1, pb.TransactionState_AWAITING_REPLY))
 
 	// Preload the beneficiary account insert
 	s.db.ExpectBegin()
@@ -162,11 +163,15 @@ func (s *rVASPTestSuite) TestValidTransfer() {
 	s.db.ExpectExec("UPDATE").WillReturnResult(sqlmock.NewResult(1, 1))
 	s.db.ExpectCommit()
 
-	// Transaction record update
+	// Transaction record update with the state transition
 	s.db.ExpectBegin()
 	s.db.ExpectExec("UPDATE").WillReturnResult(sqlmock.NewResult(1, 1))
+	expectStandardQuery(s.db, `INSERT INTO "state_transitions"`)
 	s.db.ExpectCommit()
 
+	// Webhooks lookup for the state transition
+	s.db.ExpectQuery(`SELECT \* FROM "webhooks"`).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
 	// Seal the envelope using the public key
 	key, err := s.certs.GetRSAKeys()
 	require.NoError(err)
@@ -246,19 +251,23 @@ func (s *rVASPTestSuite) TestInvalidTransfer() {
 	s.db.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"wallet_address"}).AddRow(beneficiaryAddress))
 	s.db.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"beneficiary_policy"}).AddRow("SyncRequire"))
 
-	// Preload the transaction lookup
-	expectStandardQuery(s.db, "SELECT")
+	// Preload the transaction lookup with a transaction that is awaiting a reply
+	s.db.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id", "state"}).AddRow(1, pb.TransactionState_AWAITING_REPLY))
 
 	// Preload the beneficiary account insert
 	s.db.ExpectBegin()
 	expectStandardQuery(s.db, "INSERT")
 	s.db.ExpectCommit()
 
-	// Transaction record update
+	// Transaction record update with the state transition
 	s.db.ExpectBegin()
 	s.db.ExpectExec("UPDATE").WillReturnResult(sqlmock.NewResult(1, 1))
+	expectStandardQuery(s.db, `INSERT INTO "state_transitions"`)
 	s.db.ExpectCommit()
 
+	// Webhooks lookup for the state transition
+	s.db.ExpectQuery(`SELECT \* FROM "webhooks"`).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
 	// Seal the envelope using the public key
 	key, err := s.certs.GetRSAKeys()
 	require.NoError(err)
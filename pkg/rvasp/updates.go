@@ -3,71 +3,304 @@ package rvasp
 import (
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/rs/zerolog/log"
+	"github.com/trisacrypto/testnet/pkg/rvasp/config"
+	"github.com/trisacrypto/testnet/pkg/rvasp/db"
 	pb "github.com/trisacrypto/testnet/pkg/rvasp/pb/v1"
 )
 
-// UpdateManager sends update messages to all connected clients.
+// UpdateManager sends update messages to the connected clients whose subscriptions
+// match the update. Clients without a subscription receive every update. Broadcast
+// updates are numbered and the most recent updates are kept in a ring buffer so that
+// clients which connect late can replay the updates they missed.
+//
+// Messages are never sent to a client by the caller; they are added to the bounded
+// queue of the client and sent by a go routine for each client, so that a slow client
+// cannot block the TRISA protocol. When the queue of a client is full, the slow
+// consumer policy either drops the oldest message or disconnects the client.
 type UpdateManager struct {
 	sync.RWMutex
-	streams map[string]pb.TRISADemo_LiveUpdatesServer
+	streams       map[string]*outbox
+	subscriptions map[string]*subscription
+	scopes        map[string]*Scope
+	sequence      uint64
+	history       []update
+	next          int
+	queue         int
+	policy        config.SlowConsumerPolicy
+	queued        atomic.Uint64
+	sent          atomic.Uint64
+	dropped       atomic.Uint64
+	disconnected  atomic.Uint64
 }
 
-// NewUpdateManager creates a new update manager ready to work. For thread safety, this
-// is the only object that can send messages on update streams.
-func NewUpdateManager() *UpdateManager {
+// update is a broadcast message in the ring buffer with the topic it was sent about.
+type update struct {
+	msg   *pb.Message
+	topic Topic
+	sent  time.Time
+}
+
+// outbox is the queue of messages waiting to be sent to a client stream.
+type outbox struct {
+	sync.Mutex
+	client    string
+	stream    pb.TRISADemo_LiveUpdatesServer
+	queue     []queued
+	pending   int // the number of messages in the queue, excluding replayed updates
+	replaying int // the number of replayed updates in the queue
+	dropped   uint64
+	closed    bool
+	ready     chan struct{} // signals the send routine that messages were queued
+	room      chan struct{} // signals a replay that replayed updates were sent
+	done      chan struct{} // closed when the client is removed
+	errc      chan error    // receives the reason the client was disconnected
+}
+
+// queued is a message waiting to be sent or a marker to close once the messages
+// before it have been sent. Replayed updates count the updates of their replay that
+// were sent.
+type queued struct {
+	msg      *pb.Message
+	flushed  chan struct{}
+	replayed *atomic.Uint64
+}
+
+// Topic describes what a broadcast update is about: the transaction envelope and the
+// email or wallet addresses of the accounts involved, if known. Updates with an empty
+// topic, e.g. key exchanges, are only sent to clients that are not subscribed to
+// specific accounts or envelopes.
+type Topic struct {
+	Envelope string
+	Accounts []string
+}
+
+// NewTopic creates a topic for the envelope and accounts, ignoring empty accounts.
+func NewTopic(envelope string, accounts ...string) Topic {
+	topic := Topic{Envelope: envelope, Accounts: make([]string, 0, len(accounts))}
+	topic.Add(accounts...)
+	return topic
+}
+
+// Add accounts to the topic as they are looked up, ignoring empty accounts.
+func (t *Topic) Add(accounts ...string) {
+	for _, account := range accounts {
+		if account != "" {
+			t.Accounts = append(t.Accounts, account)
+		}
+	}
+}
+
+// subscription is the set of accounts, envelopes and categories a client receives
+// updates for; empty sets are not used to filter updates.
+type subscription struct {
+	accounts   map[string]struct{}
+	envelopes  map[string]struct{}
+	categories map[pb.MessageCategory]struct{}
+}
+
+func newSubscription(in *pb.Subscription) *subscription {
+	sub := &subscription{
+		accounts:   make(map[string]struct{}, len(in.Accounts)),
+		envelopes:  make(map[string]struct{}, len(in.EnvelopeIds)),
+		categories: make(map[pb.MessageCategory]struct{}, len(in.Categories)),
+	}
+	for _, account := range in.Accounts {
+		sub.accounts[account] = struct{}{}
+	}
+	for _, envelope := range in.EnvelopeIds {
+		sub.envelopes[envelope] = struct{}{}
+	}
+	for _, cat := range in.Categories {
+		sub.categories[cat] = struct{}{}
+	}
+	return sub
+}
+
+// matches returns true if an update about the topic in the category should be sent to
+// the subscribed client. A nil subscription matches all updates.
+func (s *subscription) matches(topic Topic, cat pb.MessageCategory) bool {
+	if s == nil {
+		return true
+	}
+
+	if len(s.categories) > 0 {
+		if _, ok := s.categories[cat]; !ok {
+			return false
+		}
+	}
+
+	if len(s.accounts) == 0 && len(s.envelopes) == 0 {
+		return true
+	}
+
+	if _, ok := s.envelopes[topic.Envelope]; ok && topic.Envelope != "" {
+		return true
+	}
+
+	for _, account := range topic.Accounts {
+		if _, ok := s.accounts[account]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// NewUpdateManager creates a new update manager ready to work that buffers up to
+// conf.Buffer updates for replay; if the buffer is zero, updates are not buffered.
+// Each client may have up to conf.Queue messages waiting to be sent. For thread
+// safety, this is the only object that can send messages on update streams.
+func NewUpdateManager(conf config.UpdatesConfig) *UpdateManager {
+	if conf.Buffer < 0 {
+		conf.Buffer = 0
+	}
+
+	if conf.Queue < 1 {
+		conf.Queue = 1
+	}
+
 	return &UpdateManager{
-		streams: make(map[string]pb.TRISADemo_LiveUpdatesServer),
+		streams:       make(map[string]*outbox),
+		subscriptions: make(map[string]*subscription),
+		scopes:        make(map[string]*Scope),
+		history:       make([]update, 0, conf.Buffer),
+		queue:         conf.Queue,
+		policy:        conf.SlowConsumer,
 	}
 }
 
-// Add a new client update stream.
-func (u *UpdateManager) Add(client string, stream pb.TRISADemo_LiveUpdatesServer) (err error) {
+// Add a new client update stream and start sending queued messages to it. The updates
+// sent to the client are restricted to the updates about the accounts in the scope of
+// its credentials, regardless of its subscription; updates that are not about any
+// account, e.g. key exchanges, are only sent to clients that may access every account.
+// A nil scope allows every update.
+func (u *UpdateManager) Add(client string, stream pb.TRISADemo_LiveUpdatesServer, scope *Scope) (err error) {
 	u.Lock()
 	defer u.Unlock()
 	if _, ok := u.streams[client]; ok {
 		return fmt.Errorf("stream for client %q already exists", client)
 	}
-	u.streams[client] = stream
+
+	if !scope.All() {
+		u.scopes[client] = scope
+	}
+
+	out := &outbox{
+		client: client,
+		stream: stream,
+		queue:  make([]queued, 0, u.queue),
+		ready:  make(chan struct{}, 1),
+		room:   make(chan struct{}, 1),
+		done:   make(chan struct{}),
+		errc:   make(chan error, 1),
+	}
+	u.streams[client] = out
+	go u.send(out)
 	return nil
 }
 
-// Del an old client update stream. No-op if client odesn't exist.
+// Del an old client update stream, discarding any messages that have not been sent.
+// No-op if client doesn't exist.
 func (u *UpdateManager) Del(client string) {
 	u.Lock()
+	out, ok := u.streams[client]
 	delete(u.streams, client)
+	delete(u.subscriptions, client)
+	delete(u.scopes, client)
 	u.Unlock()
+
+	if ok {
+		out.close(nil)
+	}
 }
 
-// Broadcast a message to all streams.
-func (u *UpdateManager) Broadcast(requestID uint64, update string, cat pb.MessageCategory) (err error) {
+// Disconnected returns a channel that receives an error if the client is disconnected
+// because it could not keep up with its messages or its stream could not be sent to.
+// Returns nil if the client does not exist.
+func (u *UpdateManager) Disconnected(client string) <-chan error {
+	u.RLock()
+	defer u.RUnlock()
+	if out, ok := u.streams[client]; ok {
+		return out.errc
+	}
+	return nil
+}
+
+// allowed returns true if an update about the topic in the category matches the
+// subscription of the client and is in the scope of the client; must hold the lock.
+func (u *UpdateManager) allowed(client string, topic Topic, cat pb.MessageCategory) bool {
+	if !u.subscriptions[client].matches(topic, cat) {
+		return false
+	}
+
+	if scope, ok := u.scopes[client]; ok {
+		return scope.Allows(topic.Accounts...)
+	}
+	return true
+}
+
+// Subscribe replaces the subscription of a client update stream. An empty subscription
+// removes any filters so that the client receives every update.
+func (u *UpdateManager) Subscribe(client string, in *pb.Subscription) (err error) {
+	u.Lock()
+	defer u.Unlock()
+	if _, ok := u.streams[client]; !ok {
+		return fmt.Errorf("no stream for client %q", client)
+	}
+
+	if len(in.Accounts) == 0 && len(in.EnvelopeIds) == 0 && len(in.Categories) == 0 {
+		delete(u.subscriptions, client)
+		return nil
+	}
+
+	u.subscriptions[client] = newSubscription(in)
+	return nil
+}
+
+// Broadcast a message about the topic to all streams subscribed to it. The message is
+// numbered and added to the ring buffer before it is queued; Broadcast never waits for
+// the message to be sent. Returns an error if a client was disconnected.
+func (u *UpdateManager) Broadcast(requestID uint64, topic Topic, text string, cat pb.MessageCategory) (err error) {
+	now := time.Now()
 	msg := &pb.Message{
 		Type:      pb.RPC_NORPC,
 		Id:        requestID,
-		Update:    update,
+		Update:    text,
 		Category:  cat,
-		Timestamp: time.Now().Format(time.RFC3339),
+		Timestamp: now.Format(time.RFC3339),
 	}
 
-	inactive := make([]string, 0, 1)
-	errs := make([]error, 0, 1)
+	u.Lock()
+	u.sequence++
+	msg.Sequence = u.sequence
+	if cap(u.history) > 0 {
+		entry := update{msg: msg, topic: topic, sent: now}
+		if len(u.history) < cap(u.history) {
+			u.history = append(u.history, entry)
+		} else {
+			u.history[u.next] = entry
+		}
+		u.next = (u.next + 1) % cap(u.history)
+	}
+	u.Unlock()
 
+	errs := make([]error, 0, 1)
 	u.RLock()
-	for client, stream := range u.streams {
-		if err = stream.Send(msg); err != nil {
-			errs = append(errs, fmt.Errorf("could not send message to %q: %s", client, err))
-			inactive = append(inactive, client)
+	targets := make([]*outbox, 0, len(u.streams))
+	for client, out := range u.streams {
+		if u.allowed(client, topic, cat) {
+			targets = append(targets, out)
 		}
 	}
 	u.RUnlock()
 
-	if len(inactive) > 0 {
-		u.Lock()
-		for _, client := range inactive {
-			delete(u.streams, client)
+	for _, out := range targets {
+		if err = u.enqueue(out, queued{msg: msg}); err != nil {
+			errs = append(errs, err)
 		}
-		u.Unlock()
 	}
 
 	if len(errs) == 1 {
@@ -81,24 +314,282 @@ func (u *UpdateManager) Broadcast(requestID uint64, update string, cat pb.Messag
 	return nil
 }
 
-// Send a message to a specific stream
+// Replay sends the buffered updates with a sequence number greater than since and
+// that were sent at or after the timestamp, if it is not zero, to the client in the
+// order they were broadcast. Only updates matching the subscription and the scope of the
+// client are replayed. Updates broadcast while replaying may be sent to the client before the
+// replayed updates, so clients should order updates by their sequence number.
+//
+// Replayed updates are not subject to the slow consumer policy; instead, Replay waits
+// for the client to keep up so that no more than a queue of replayed updates is waiting
+// to be sent. Replay blocks until the replayed updates have been sent and returns the
+// number of updates that were sent to the client.
+func (u *UpdateManager) Replay(client string, since uint64, timestamp time.Time) (n int, err error) {
+	u.RLock()
+	out, ok := u.streams[client]
+	if !ok {
+		u.RUnlock()
+		return 0, fmt.Errorf("no stream for client %q", client)
+	}
+
+	replay := make([]*pb.Message, 0, len(u.history))
+	for i := range u.history {
+		// The oldest update is at the next index once the buffer is full
+		entry := u.history[(u.next+i)%len(u.history)]
+		if entry.msg.Sequence <= since || entry.sent.Before(timestamp) {
+			continue
+		}
+
+		if u.allowed(client, entry.topic, entry.msg.Category) {
+			replay = append(replay, entry.msg)
+		}
+	}
+	u.RUnlock()
+
+	sent := &atomic.Uint64{}
+	for _, msg := range replay {
+		if err = u.enqueueReplay(out, queued{msg: msg, replayed: sent}); err != nil {
+			return int(sent.Load()), err
+		}
+	}
+
+	if len(replay) > 0 {
+		if err = u.Flush(client); err != nil {
+			return int(sent.Load()), err
+		}
+	}
+	return int(sent.Load()), nil
+}
+
+// Send a message to a specific stream. The message is queued after any messages that
+// have not been sent to the client yet.
 func (u *UpdateManager) Send(client string, msg *pb.Message) (err error) {
 	u.RLock()
-	stream, ok := u.streams[client]
+	out, ok := u.streams[client]
+	u.RUnlock()
+
 	if !ok {
 		return fmt.Errorf("no stream for client %q", client)
 	}
-	if err = stream.Send(msg); err != nil {
-		// Does not matter if another routine gets the lock before we do - the delete
-		// will be a no-op since the other routine will also error and delete.
-		u.RUnlock()
-		u.Del(client)
-		return fmt.Errorf("could not send %s to %q: %s", msg.Type.String(), client, err)
+	return u.enqueue(out, queued{msg: msg})
+}
+
+// Flush blocks until the messages queued for the client before Flush was called have
+// been sent, dropped or discarded because the client was removed. Flushing a client
+// never causes its messages to be dropped.
+func (u *UpdateManager) Flush(client string) (err error) {
+	u.RLock()
+	out, ok := u.streams[client]
+	u.RUnlock()
+
+	if !ok {
+		return fmt.Errorf("no stream for client %q", client)
 	}
+
+	flushed := make(chan struct{})
+	if err = u.enqueue(out, queued{flushed: flushed}); err != nil {
+		return err
+	}
+
+	select {
+	case <-flushed:
+	case <-out.done:
+	}
+	return nil
+}
+
+// Stats returns the delivery counters of the update manager.
+func (u *UpdateManager) Stats() *pb.LiveUpdatesStats {
+	u.RLock()
+	clients := len(u.streams)
 	u.RUnlock()
+
+	return &pb.LiveUpdatesStats{
+		Clients:      uint32(clients),
+		Queued:       u.queued.Load(),
+		Sent:         u.sent.Load(),
+		Dropped:      u.dropped.Load(),
+		Disconnected: u.disconnected.Load(),
+	}
+}
+
+// enqueue adds the item to the queue of the client, applying the slow consumer policy
+// if the queue is full, and signals the send routine of the client.
+func (u *UpdateManager) enqueue(out *outbox, item queued) error {
+	out.Lock()
+	if out.closed {
+		out.Unlock()
+		return fmt.Errorf("stream for client %q is closed", out.client)
+	}
+
+	// Flush markers and replayed updates do not count towards the size of the queue and
+	// are never dropped
+	if item.msg != nil && out.pending >= u.queue {
+		if u.policy == config.Disconnect {
+			out.Unlock()
+			err := fmt.Errorf("client %q disconnected: more than %d messages waiting to be sent", out.client, u.queue)
+			log.Warn().Str("client", out.client).Int("queue", u.queue).Msg("disconnecting slow live updates client")
+			u.disconnected.Add(1)
+			u.remove(out, err)
+			return err
+		}
+
+		// Drop the oldest message
+		for i := range out.queue {
+			if out.queue[i].msg != nil && out.queue[i].replayed == nil {
+				out.queue = append(out.queue[:i], out.queue[i+1:]...)
+				break
+			}
+		}
+		out.pending--
+
+		u.dropped.Add(1)
+		out.dropped++
+		if out.dropped == 1 {
+			log.Warn().Str("client", out.client).Int("queue", u.queue).Msg("dropping messages to slow live updates client")
+		}
+	}
+
+	if item.msg != nil {
+		out.pending++
+		u.queued.Add(1)
+	}
+	out.queue = append(out.queue, item)
+	out.Unlock()
+
+	// Wake up the send routine if it is waiting for messages
+	select {
+	case out.ready <- struct{}{}:
+	default:
+	}
 	return nil
 }
 
+// enqueueReplay adds a replayed update to the queue of the client, waiting until the
+// send routine has sent enough of the replayed updates if the queue is full of them.
+func (u *UpdateManager) enqueueReplay(out *outbox, item queued) error {
+	for {
+		out.Lock()
+		if out.closed {
+			out.Unlock()
+			return fmt.Errorf("stream for client %q is closed", out.client)
+		}
+
+		if out.replaying < u.queue {
+			out.replaying++
+			out.queue = append(out.queue, item)
+			u.queued.Add(1)
+			out.Unlock()
+			break
+		}
+		out.Unlock()
+
+		select {
+		case <-out.room:
+		case <-out.done:
+		}
+	}
+
+	select {
+	case out.ready <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// send sends the queued messages to the client stream until the client is removed.
+// If a message cannot be sent, the client is removed.
+func (u *UpdateManager) send(out *outbox) {
+	for {
+		select {
+		case <-out.done:
+			return
+		case <-out.ready:
+		}
+
+		for {
+			out.Lock()
+			if out.closed || len(out.queue) == 0 {
+				out.Unlock()
+				break
+			}
+			item := out.queue[0]
+			out.queue[0] = queued{}
+			out.queue = out.queue[1:]
+			switch {
+			case item.replayed != nil:
+				out.replaying--
+			case item.msg != nil:
+				out.pending--
+			}
+			out.Unlock()
+
+			// Wake up a replay that is waiting for room in the queue
+			if item.replayed != nil {
+				select {
+				case out.room <- struct{}{}:
+				default:
+				}
+			}
+
+			if item.flushed != nil {
+				close(item.flushed)
+				continue
+			}
+
+			if err := out.stream.Send(item.msg); err != nil {
+				err = fmt.Errorf("could not send %s to %q: %s", item.msg.Type.String(), out.client, err)
+				log.Warn().Err(err).Str("client", out.client).Msg("removing live updates client")
+				u.remove(out, err)
+				return
+			}
+
+			u.sent.Add(1)
+			if item.replayed != nil {
+				item.replayed.Add(1)
+			}
+		}
+	}
+}
+
+// remove the client if it is still registered with the outbox and close the outbox.
+func (u *UpdateManager) remove(out *outbox, err error) {
+	u.Lock()
+	if u.streams[out.client] == out {
+		delete(u.streams, out.client)
+		delete(u.subscriptions, out.client)
+		delete(u.scopes, out.client)
+	}
+	u.Unlock()
+	out.close(err)
+}
+
+// close the outbox, discarding the messages that have not been sent and reporting the
+// reason the client was disconnected, if any.
+func (o *outbox) close(err error) {
+	o.Lock()
+	defer o.Unlock()
+	if o.closed {
+		return
+	}
+
+	o.closed = true
+	o.queue, o.pending, o.replaying = nil, 0, 0
+	close(o.done)
+	if err != nil {
+		o.errc <- err
+	}
+}
+
+// Transition broadcasts the state transition of a transaction. It implements
+// db.StateObserver so that clients are also updated about the asynchronous steps of
+// transfers that happen after the transfer request has been replied to.
+func (u *UpdateManager) Transition(xfer *db.Transaction, transition db.StateTransition) {
+	topic := NewTopic(xfer.Envelope, xfer.Account.Email, xfer.Account.WalletAddress, xfer.Originator.WalletAddress, xfer.Beneficiary.WalletAddress)
+	text := fmt.Sprintf("transaction %04d %s by %s: %s", xfer.ID, transition.State, transition.Actor, transition.Reason)
+	u.Broadcast(0, topic, text, pb.MessageCategory_LEDGER)
+}
+
 // SendTransferError to client
 func (u *UpdateManager) SendTransferError(client string, id uint64, err *pb.Error) error {
 	return u.Send(client, &pb.Message{
@@ -111,3 +602,27 @@ func (u *UpdateManager) SendTransferError(client string, id uint64, err *pb.Erro
 		}},
 	})
 }
+
+// progress broadcasts the steps of a transfer about the topic of the transfer, which
+// grows as the accounts and envelope of the transfer are looked up. The updates are
+// sent with the ID of the demo command that requested the transfer, or 0 for transfers
+// requested from the integration API, and are followed by a pause so that the steps
+// are easier to follow in the demo.
+type progress struct {
+	Topic
+	updates *UpdateManager
+	id      uint64
+	pacing  time.Duration
+}
+
+func newProgress(updates *UpdateManager, requestID uint64, pacing time.Duration) *progress {
+	return &progress{Topic: NewTopic(""), updates: updates, id: requestID, pacing: pacing}
+}
+
+// update broadcasts the formatted message then pauses for the pacing of the progress.
+func (p *progress) update(cat pb.MessageCategory, format string, a ...interface{}) {
+	p.updates.Broadcast(p.id, p.Topic, fmt.Sprintf(format, a...), cat)
+	if p.pacing > 0 {
+		time.Sleep(p.pacing)
+	}
+}
@@ -0,0 +1,467 @@
+package rvasp_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trisacrypto/testnet/pkg/rvasp"
+	"github.com/trisacrypto/testnet/pkg/rvasp/config"
+	"github.com/trisacrypto/testnet/pkg/rvasp/harness"
+	pb "github.com/trisacrypto/testnet/pkg/rvasp/pb/v1"
+	"google.golang.org/grpc"
+)
+
+// stream is a live updates server stream that collects the updates sent to it.
+type stream struct {
+	grpc.ServerStream
+	sync.Mutex
+	updates   []string
+	sequences []uint64
+}
+
+func (s *stream) Send(msg *pb.Message) error {
+	s.Lock()
+	defer s.Unlock()
+	s.updates = append(s.updates, msg.Update)
+	s.sequences = append(s.sequences, msg.Sequence)
+	return nil
+}
+
+func (s *stream) Recv() (*pb.Command, error) {
+	return nil, nil
+}
+
+// flush waits until the messages queued for the clients have been sent.
+func flush(t *testing.T, updates *rvasp.UpdateManager, clients ...string) {
+	for _, client := range clients {
+		require.NoError(t, updates.Flush(client))
+	}
+}
+
+// received returns the updates sent to the stream and clears them.
+func (s *stream) received() []string {
+	s.Lock()
+	defer s.Unlock()
+	updates := s.updates
+	s.updates, s.sequences = nil, nil
+	return updates
+}
+
+func TestBroadcastSubscriptions(t *testing.T) {
+	updates := rvasp.NewUpdateManager(config.UpdatesConfig{Buffer: 10, Queue: 10})
+	all, mary, envelope, ledger := &stream{}, &stream{}, &stream{}, &stream{}
+	require.NoError(t, updates.Add("all", all, nil))
+	require.NoError(t, updates.Add("mary", mary, nil))
+	require.NoError(t, updates.Add("envelope", envelope, nil))
+	require.NoError(t, updates.Add("ledger", ledger, nil))
+
+	require.NoError(t, updates.Subscribe("mary", &pb.Subscription{Accounts: []string{"mary@alicevasp.us"}}))
+	require.NoError(t, updates.Subscribe("envelope", &pb.Subscription{EnvelopeIds: []string{"foo"}, Categories: []pb.MessageCategory{pb.MessageCategory_TRISAP2P}}))
+	require.NoError(t, updates.Subscribe("ledger", &pb.Subscription{Categories: []pb.MessageCategory{pb.MessageCategory_LEDGER}}))
+	require.Error(t, updates.Subscribe("unknown", &pb.Subscription{}), "cannot subscribe without a stream")
+
+	require.NoError(t, updates.Broadcast(0, rvasp.NewTopic("", "mary@alicevasp.us", ""), "mary ledger", pb.MessageCategory_LEDGER))
+	require.NoError(t, updates.Broadcast(0, rvasp.NewTopic("foo", "larry@bobvasp.co.uk"), "foo p2p", pb.MessageCategory_TRISAP2P))
+	require.NoError(t, updates.Broadcast(0, rvasp.NewTopic("foo", "mary@alicevasp.us"), "foo blockchain", pb.MessageCategory_BLOCKCHAIN))
+	require.NoError(t, updates.Broadcast(0, rvasp.Topic{}, "key exchange", pb.MessageCategory_TRISAP2P))
+
+	flush(t, updates, "all", "mary", "envelope", "ledger")
+	require.Equal(t, []string{"mary ledger", "foo p2p", "foo blockchain", "key exchange"}, all.received())
+	require.Equal(t, []string{"mary ledger", "foo blockchain"}, mary.received())
+	require.Equal(t, []string{"foo p2p"}, envelope.received())
+	require.Equal(t, []string{"mary ledger"}, ledger.received())
+
+	// An empty subscription removes the filters
+	require.NoError(t, updates.Subscribe("mary", &pb.Subscription{}))
+	require.NoError(t, updates.Broadcast(0, rvasp.Topic{}, "key exchange", pb.MessageCategory_TRISAP2P))
+	flush(t, updates, "mary")
+	require.Equal(t, []string{"key exchange"}, mary.received())
+
+	// Subscriptions are removed with the stream
+	updates.Del("ledger")
+	require.NoError(t, updates.Add("ledger", ledger, nil))
+	require.NoError(t, updates.Broadcast(0, rvasp.Topic{}, "key exchange", pb.MessageCategory_TRISAP2P))
+	flush(t, updates, "ledger")
+	require.Equal(t, []string{"key exchange"}, ledger.received())
+}
+
+func TestBroadcastScopes(t *testing.T) {
+	updates := rvasp.NewUpdateManager(config.UpdatesConfig{Buffer: 10, Queue: 10})
+	all, mary := &stream{}, &stream{}
+	require.NoError(t, updates.Add("all", all, &rvasp.Scope{Accounts: []string{rvasp.AllAccounts}}))
+	require.NoError(t, updates.Add("mary", mary, &rvasp.Scope{Subject: "mary", Accounts: []string{"mary@alicevasp.us"}}))
+
+	require.NoError(t, updates.Broadcast(0, rvasp.NewTopic("foo", "MARY@alicevasp.us", "robert@bobvasp.co.uk"), "mary to robert", pb.MessageCategory_LEDGER))
+	require.NoError(t, updates.Broadcast(0, rvasp.NewTopic("bar", "jane@alicevasp.us", "robert@bobvasp.co.uk"), "jane to robert", pb.MessageCategory_LEDGER))
+	require.NoError(t, updates.Broadcast(0, rvasp.NewTopic("bar"), "envelope bar", pb.MessageCategory_TRISAP2P))
+	require.NoError(t, updates.Broadcast(0, rvasp.Topic{}, "key exchange", pb.MessageCategory_TRISAP2P))
+
+	// Updates that are not about the accounts in the scope are never sent, even if the
+	// stream is subscribed to them
+	require.NoError(t, updates.Subscribe("mary", &pb.Subscription{EnvelopeIds: []string{"foo", "bar"}}))
+	require.NoError(t, updates.Broadcast(0, rvasp.NewTopic("bar", "jane@alicevasp.us"), "jane again", pb.MessageCategory_LEDGER))
+
+	flush(t, updates, "all", "mary")
+	require.Equal(t, []string{"mary to robert", "jane to robert", "envelope bar", "key exchange", "jane again"}, all.received())
+	require.Equal(t, []string{"mary to robert"}, mary.received())
+
+	// Replayed updates are also restricted to the scope
+	require.NoError(t, updates.Subscribe("mary", &pb.Subscription{}))
+	n, err := updates.Replay("mary", 0, time.Time{})
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.Equal(t, []string{"mary to robert"}, mary.received())
+}
+
+// slowStream is a live updates server stream that blocks sending until it is released.
+type slowStream struct {
+	stream
+	sending chan struct{}
+	release chan struct{}
+}
+
+func newSlowStream() *slowStream {
+	return &slowStream{sending: make(chan struct{}, 10), release: make(chan struct{})}
+}
+
+func (s *slowStream) Send(msg *pb.Message) error {
+	s.sending <- struct{}{}
+	<-s.release
+	return s.stream.Send(msg)
+}
+
+func TestSlowConsumer(t *testing.T) {
+	// broadcast sends the first update, waits until the stream is blocked sending it,
+	// then broadcasts the remaining updates which are queued for the stream
+	broadcast := func(updates *rvasp.UpdateManager, slow *slowStream) (errs int) {
+		require.NoError(t, updates.Broadcast(0, rvasp.Topic{}, "one", pb.MessageCategory_LEDGER))
+		<-slow.sending
+		for _, text := range []string{"two", "three", "four", "five"} {
+			if err := updates.Broadcast(0, rvasp.Topic{}, text, pb.MessageCategory_LEDGER); err != nil {
+				errs++
+			}
+		}
+		return errs
+	}
+
+	// Broadcasting does not block on a slow client; the oldest queued updates are dropped
+	updates := rvasp.NewUpdateManager(config.UpdatesConfig{Queue: 2, SlowConsumer: config.DropOldest})
+	slow := newSlowStream()
+	require.NoError(t, updates.Add("slow", slow, nil))
+	require.Zero(t, broadcast(updates, slow))
+
+	close(slow.release)
+	flush(t, updates, "slow")
+	require.Equal(t, []string{"one", "four", "five"}, slow.received())
+
+	stats := updates.Stats()
+	require.Equal(t, uint32(1), stats.Clients)
+	require.Equal(t, uint64(5), stats.Queued)
+	require.Equal(t, uint64(3), stats.Sent)
+	require.Equal(t, uint64(2), stats.Dropped)
+	require.Zero(t, stats.Disconnected)
+
+	// Slow clients are disconnected when their queue is full with the disconnect policy
+	updates = rvasp.NewUpdateManager(config.UpdatesConfig{Queue: 2, SlowConsumer: config.Disconnect})
+	slow = newSlowStream()
+	require.NoError(t, updates.Add("slow", slow, nil))
+	disconnected := updates.Disconnected("slow")
+	require.Equal(t, 1, broadcast(updates, slow), "the update that overflows the queue should error")
+
+	select {
+	case err := <-disconnected:
+		require.Error(t, err)
+	default:
+		require.Fail(t, "expected the slow client to be disconnected")
+	}
+	close(slow.release)
+
+	stats = updates.Stats()
+	require.Zero(t, stats.Clients)
+	require.Equal(t, uint64(1), stats.Disconnected)
+	require.Error(t, updates.Send("slow", &pb.Message{}), "the slow client should be removed")
+}
+
+func TestLiveUpdatesSubscribe(t *testing.T) {
+	h := harness.New(t, "alice")
+	alice := h.VASP("alice")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := alice.Demo.LiveUpdates(ctx)
+	require.NoError(t, err)
+
+	subscription := &pb.Subscription{
+		Accounts:    []string{"mary@alicevasp.us"},
+		EnvelopeIds: []string{"foo"},
+		Categories:  []pb.MessageCategory{pb.MessageCategory_LEDGER},
+	}
+	require.NoError(t, stream.Send(&pb.Command{
+		Type:   pb.RPC_SUBSCRIBE,
+		Id:     1,
+		Client: "tester",
+		Request: &pb.Command_Subscription{
+			Subscription: subscription,
+		},
+	}))
+
+	// The applied subscription is echoed back as an acknowledgement
+	msg, err := stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, pb.RPC_SUBSCRIBE, msg.Type)
+	require.Equal(t, uint64(1), msg.Id)
+	require.Equal(t, subscription.Accounts, msg.GetSubscription().Accounts)
+	require.Equal(t, subscription.EnvelopeIds, msg.GetSubscription().EnvelopeIds)
+	require.Equal(t, subscription.Categories, msg.GetSubscription().Categories)
+
+	// The subscription can be cleared mid-stream
+	require.NoError(t, stream.Send(&pb.Command{Type: pb.RPC_SUBSCRIBE, Id: 2, Client: "tester"}))
+	msg, err = stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), msg.Id)
+	require.Empty(t, msg.GetSubscription().Accounts)
+}
+
+func TestReplay(t *testing.T) {
+	updates := rvasp.NewUpdateManager(config.UpdatesConfig{Buffer: 3, Queue: 10})
+	late, mary := &stream{}, &stream{}
+
+	for _, text := range []string{"one", "two", "three", "four", "five"} {
+		require.NoError(t, updates.Broadcast(0, rvasp.NewTopic("", "larry@bobvasp.co.uk"), text, pb.MessageCategory_LEDGER))
+	}
+	require.NoError(t, updates.Broadcast(0, rvasp.NewTopic("", "mary@alicevasp.us"), "six", pb.MessageCategory_LEDGER))
+
+	require.NoError(t, updates.Add("late", late, nil))
+	require.NoError(t, updates.Add("mary", mary, nil))
+	require.NoError(t, updates.Subscribe("mary", &pb.Subscription{Accounts: []string{"mary@alicevasp.us"}}))
+
+	// Only the most recent updates are buffered, in the order they were broadcast
+	n, err := updates.Replay("late", 0, time.Time{})
+	require.NoError(t, err)
+	require.Equal(t, 3, n)
+	flush(t, updates, "late")
+	require.Equal(t, []uint64{4, 5, 6}, late.sequences)
+	require.Equal(t, []string{"four", "five", "six"}, late.received())
+
+	n, err = updates.Replay("late", 5, time.Time{})
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	flush(t, updates, "late")
+	require.Equal(t, []string{"six"}, late.received())
+
+	n, err = updates.Replay("late", 0, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	require.Zero(t, n)
+
+	// Replayed updates are filtered by the subscription of the stream
+	n, err = updates.Replay("mary", 0, time.Time{})
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	flush(t, updates, "mary")
+	require.Equal(t, []string{"six"}, mary.received())
+
+	_, err = updates.Replay("unknown", 0, time.Time{})
+	require.Error(t, err)
+
+	// Updates are not buffered if the buffer size is zero
+	updates = rvasp.NewUpdateManager(config.UpdatesConfig{Queue: 10})
+	require.NoError(t, updates.Broadcast(0, rvasp.Topic{}, "one", pb.MessageCategory_LEDGER))
+	require.NoError(t, updates.Add("late", late, nil))
+	n, err = updates.Replay("late", 0, time.Time{})
+	require.NoError(t, err)
+	require.Zero(t, n)
+}
+
+// pacedStream is a live updates server stream that takes a moment to send each message.
+type pacedStream struct {
+	stream
+}
+
+func (s *pacedStream) Send(msg *pb.Message) error {
+	time.Sleep(100 * time.Microsecond)
+	return s.stream.Send(msg)
+}
+
+func TestReplaySlowConsumer(t *testing.T) {
+	// Replaying more updates than fit in the queue of the client neither drops the
+	// replayed updates nor disconnects the client
+	for _, policy := range []config.SlowConsumerPolicy{config.DropOldest, config.Disconnect} {
+		updates := rvasp.NewUpdateManager(config.UpdatesConfig{Buffer: 1000, Queue: 4, SlowConsumer: policy})
+		for i := 0; i < 1000; i++ {
+			require.NoError(t, updates.Broadcast(0, rvasp.Topic{}, fmt.Sprintf("update %d", i), pb.MessageCategory_LEDGER))
+		}
+
+		late := &pacedStream{}
+		require.NoError(t, updates.Add("late", late, nil))
+
+		n, err := updates.Replay("late", 0, time.Time{})
+		require.NoError(t, err, policy)
+		require.Equal(t, 1000, n, policy)
+
+		late.Lock()
+		require.Len(t, late.sequences, 1000, policy)
+		for i, sequence := range late.sequences {
+			require.Equal(t, uint64(i+1), sequence, policy)
+		}
+		late.Unlock()
+
+		stats := updates.Stats()
+		require.Equal(t, uint32(1), stats.Clients, policy)
+		require.Zero(t, stats.Dropped, policy)
+		require.Zero(t, stats.Disconnected, policy)
+	}
+}
+
+func TestLiveUpdatesReplay(t *testing.T) {
+	h := harness.New(t, "alice", "bob")
+	alice, bob := h.VASP("alice"), h.VASP("bob")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Updates sent by bob before the client connects are buffered
+	reply, err := alice.Client.Transfer(ctx, &pb.TransferRequest{
+		Account:     "mary@alicevasp.us",
+		Beneficiary: "robert@bobvasp.co.uk",
+		Amount:      0.5,
+		AssetType:   "Bitcoin",
+	})
+	require.NoError(t, err)
+	require.Empty(t, reply.Error)
+
+	stream, err := bob.Demo.LiveUpdates(ctx)
+	require.NoError(t, err)
+
+	require.NoError(t, stream.Send(&pb.Command{
+		Type:   pb.RPC_REPLAY,
+		Id:     1,
+		Client: "late",
+		Request: &pb.Command_Replay{
+			Replay: &pb.Replay{SinceSequence: 1},
+		},
+	}))
+
+	var sequence uint64 = 1
+	for {
+		msg, err := stream.Recv()
+		require.NoError(t, err)
+		if msg.Type == pb.RPC_REPLAY {
+			require.Equal(t, uint64(1), msg.Id)
+			require.Equal(t, fmt.Sprintf("replayed %d updates", sequence-1), msg.Update)
+			break
+		}
+
+		sequence++
+		require.Equal(t, sequence, msg.Sequence, "replayed updates should be in order without gaps")
+		require.NotEmpty(t, msg.Update)
+	}
+	require.Greater(t, sequence, uint64(1), "expected updates to be replayed")
+
+	// Invalid timestamps are reported to the client
+	require.NoError(t, stream.Send(&pb.Command{
+		Type:   pb.RPC_REPLAY,
+		Id:     2,
+		Client: "late",
+		Request: &pb.Command_Replay{
+			Replay: &pb.Replay{SinceTimestamp: "yesterday"},
+		},
+	}))
+
+	msg, err := stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), msg.Id)
+	require.Equal(t, pb.MessageCategory_ERROR, msg.Category)
+}
+
+func TestLiveUpdatesTransfer(t *testing.T) {
+	h := harness.New(t, "alice", "bob")
+	alice := h.VASP("alice")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	stream, err := alice.Demo.LiveUpdates(ctx)
+	require.NoError(t, err)
+
+	// transfer sends the transfer command and collects the updates until the reply
+	transfer := func(id uint64, req *pb.TransferRequest) (updates []string, reply *pb.Message) {
+		require.NoError(t, stream.Send(&pb.Command{
+			Type:    pb.RPC_TRANSFER,
+			Id:      id,
+			Client:  "demo",
+			Request: &pb.Command_Transfer{Transfer: req},
+		}))
+
+		for {
+			msg, err := stream.Recv()
+			require.NoError(t, err)
+			if msg.Type == pb.RPC_TRANSFER {
+				return updates, msg
+			}
+			if msg.Id == id {
+				updates = append(updates, msg.Update)
+			}
+		}
+	}
+
+	// Demo transfers use the originator policy of the wallet and handle pending replies
+	updates, reply := transfer(1, &pb.TransferRequest{
+		Account:     "mary@alicevasp.us",
+		Beneficiary: "larry@bobvasp.co.uk",
+		Amount:      1.5,
+		AssetType:   "Bitcoin",
+	})
+	require.Equal(t, uint64(1), reply.Id)
+	require.Equal(t, pb.MessageCategory_LEDGER, reply.Category)
+	require.Nil(t, reply.GetTransfer().Error)
+	xfer := reply.GetTransfer().Transaction
+	require.Equal(t, pb.TransactionState_AWAITING_REPLY, xfer.State)
+	require.Contains(t, updates[0], "starting transaction of 1.50 from mary@alicevasp.us to larry@bobvasp.co.uk")
+	require.Contains(t, updates[len(updates)-1], "pending: api.bob.vaspbot.com will reply between")
+
+	// State transitions of the async exchange are broadcast once the reply is sent
+	h.HandleAsync()
+	h.HandleAsync()
+	for {
+		msg, err := stream.Recv()
+		require.NoError(t, err)
+		if strings.Contains(msg.Update, " COMPLETED by ") {
+			break
+		}
+	}
+
+	// Errors are sent to the demo client without closing the stream
+	_, reply = transfer(2, &pb.TransferRequest{
+		Account:         "mary@alicevasp.us",
+		Beneficiary:     "robert@bobvasp.co.uk",
+		Amount:          1.5,
+		AssetType:       "Bitcoin",
+		OriginatingVasp: "bob",
+	})
+	require.Equal(t, pb.MessageCategory_ERROR, reply.Category)
+	require.Equal(t, int32(pb.ErrWrongVASP), reply.GetTransfer().Error.Code)
+
+	_, reply = transfer(3, &pb.TransferRequest{
+		Account:     "nobody@alicevasp.us",
+		Beneficiary: "robert@bobvasp.co.uk",
+		Amount:      1.5,
+		AssetType:   "Bitcoin",
+	})
+	require.Equal(t, int32(pb.ErrNotFound), reply.GetTransfer().Error.Code)
+
+	_, reply = transfer(4, &pb.TransferRequest{
+		Account:          "mary@alicevasp.us",
+		Beneficiary:      "robert@bobvasp.co.uk",
+		Amount:           1.5,
+		AssetType:        "Bitcoin",
+		BeneficiaryVasp:  "alice",
+		CheckBeneficiary: true,
+	})
+	require.Equal(t, int32(pb.ErrWrongVASP), reply.GetTransfer().Error.Code)
+}
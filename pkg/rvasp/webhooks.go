@@ -0,0 +1,367 @@
+package rvasp
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"github.com/trisacrypto/testnet/pkg/rvasp/config"
+	"github.com/trisacrypto/testnet/pkg/rvasp/db"
+	pb "github.com/trisacrypto/testnet/pkg/rvasp/pb/v1"
+)
+
+// Headers set on the requests posted to webhooks. The signature is the hex encoded
+// HMAC-SHA256 of the request body keyed with the secret of the webhook.
+const (
+	WebhookEventHeader     = "X-Rvasp-Event"
+	WebhookSignatureHeader = "X-Rvasp-Signature"
+	webhookSignaturePrefix = "sha256="
+)
+
+// WebhookEvent is the JSON body posted to webhooks when the state of a transaction
+// changes. Events are delivered concurrently and retried on failure, so they may
+// arrive out of order; the timestamp is the time of the state transition.
+type WebhookEvent struct {
+	ID           string              `json:"id"`
+	VASP         string              `json:"vasp"`
+	EnvelopeID   string              `json:"envelope_id"`
+	Account      string              `json:"account"`
+	Previous     string              `json:"previous"`
+	State        string              `json:"state"`
+	Amount       float64             `json:"amount"`
+	AssetType    string              `json:"asset_type"`
+	Counterparty WebhookCounterparty `json:"counterparty"`
+	Actor        string              `json:"actor"`
+	Reason       string              `json:"reason"`
+	Timestamp    string              `json:"timestamp"`
+	state        pb.TransactionState
+}
+
+// WebhookCounterparty identifies the beneficiary of a transaction sent by the account
+// or the originator of a transaction received by the account.
+type WebhookCounterparty struct {
+	WalletAddress string `json:"wallet_address"`
+	Email         string `json:"email"`
+	Provider      string `json:"provider"`
+}
+
+// Webhooks posts signed events to the webhooks registered with the rVASP when the state
+// of a transaction changes. Failed deliveries are retried with exponential backoff and
+// every attempt is recorded in the delivery log.
+type Webhooks struct {
+	sync.Mutex
+	db      *db.DB
+	conf    config.WebhooksConfig
+	client  *http.Client
+	wg      sync.WaitGroup
+	done    chan struct{}
+	once    sync.Once
+	removed map[uint]chan struct{}
+}
+
+// ErrPrivateWebhook is returned when a webhook URL refers to a loopback, private, link
+// local or unspecified address and private addresses are not allowed.
+var ErrPrivateWebhook = errors.New("webhooks may not be delivered to private addresses")
+
+// NewWebhooks returns a webhook notifier for the VASP that the database is restricted
+// to. Register Notify as an observer of the database to send events.
+func NewWebhooks(vaspdb *db.DB, conf config.WebhooksConfig) *Webhooks {
+	if conf.MaxAttempts < 1 {
+		conf.MaxAttempts = 1
+	}
+
+	// Refuse to connect to private addresses after the host of the webhook is resolved,
+	// so that a public host name cannot be used to reach the network of the rVASP.
+	dialer := &net.Dialer{Timeout: conf.Timeout}
+	if !conf.AllowPrivate {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+
+			if ip := net.ParseIP(host); ip == nil || privateIP(ip) {
+				return ErrPrivateWebhook
+			}
+			return nil
+		}
+	}
+
+	return &Webhooks{
+		db:   vaspdb,
+		conf: conf,
+		client: &http.Client{
+			Timeout:   conf.Timeout,
+			Transport: &http.Transport{DialContext: dialer.DialContext},
+		},
+		done:    make(chan struct{}),
+		removed: make(map[uint]chan struct{}),
+	}
+}
+
+// ValidateURL returns an error if events cannot be delivered to the webhook URL. Only
+// http and https URLs are allowed and, unless private addresses are allowed, the host
+// may not be localhost or a private IP address. Host names are checked again when the
+// events are delivered since they may resolve to a different address.
+func (w *Webhooks) ValidateURL(raw string) (err error) {
+	var u *url.URL
+	if u, err = url.Parse(raw); err != nil {
+		return err
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("unsupported scheme %q", u.Scheme)
+	}
+
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if host == "" {
+		return errors.New("missing host")
+	}
+
+	if w.conf.AllowPrivate {
+		return nil
+	}
+
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrPrivateWebhook
+	}
+
+	if ip := net.ParseIP(host); ip != nil && privateIP(ip) {
+		return ErrPrivateWebhook
+	}
+	return nil
+}
+
+// privateIP returns true if the address is not a public unicast address.
+func privateIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast()
+}
+
+// Remove stops the pending deliveries to the webhook, which is called when the webhook
+// is deleted so that failed deliveries are not retried.
+func (w *Webhooks) Remove(id uint) {
+	w.Lock()
+	defer w.Unlock()
+	removed, ok := w.removed[id]
+	if !ok {
+		removed = make(chan struct{})
+		w.removed[id] = removed
+	}
+
+	select {
+	case <-removed:
+	default:
+		close(removed)
+	}
+}
+
+// removedChan returns the channel that is closed when the webhook is removed. Webhooks
+// are soft deleted so their IDs are never reused.
+func (w *Webhooks) removedChan(id uint) chan struct{} {
+	w.Lock()
+	defer w.Unlock()
+	if _, ok := w.removed[id]; !ok {
+		w.removed[id] = make(chan struct{})
+	}
+	return w.removed[id]
+}
+
+// Notify sends an event for the state transition to each of the webhooks registered
+// for the account of the transaction. It implements db.StateObserver; the event is
+// created before Notify returns and delivered in the background.
+func (w *Webhooks) Notify(xfer *db.Transaction, transition db.StateTransition) {
+	var hooks []db.Webhook
+	if err := w.db.LookupWebhooks(xfer.AccountID).Find(&hooks).Error; err != nil {
+		log.Warn().Err(err).Str("envelope", xfer.Envelope).Msg("could not lookup webhooks")
+		return
+	}
+
+	if len(hooks) == 0 {
+		return
+	}
+
+	event, err := w.event(xfer, transition)
+	if err != nil {
+		log.Warn().Err(err).Str("envelope", xfer.Envelope).Msg("could not create webhook event")
+		return
+	}
+
+	var body []byte
+	if body, err = json.Marshal(event); err != nil {
+		log.Warn().Err(err).Str("envelope", xfer.Envelope).Msg("could not marshal webhook event")
+		return
+	}
+
+	for _, hook := range hooks {
+		w.wg.Add(1)
+		go w.deliver(hook, event, body)
+	}
+}
+
+// event creates the webhook event for the state transition of the transaction.
+func (w *Webhooks) event(xfer *db.Transaction, transition db.StateTransition) (event *WebhookEvent, err error) {
+	account := xfer.Account
+	if account.ID == 0 {
+		if err = w.db.GetDB().First(&account, xfer.AccountID).Error; err != nil {
+			return nil, fmt.Errorf("could not fetch account: %s", err)
+		}
+	}
+
+	// The counterparty of a debit is the beneficiary, otherwise it is the originator
+	counterparty, counterpartyID := xfer.Beneficiary, xfer.BeneficiaryID
+	if !xfer.Debit {
+		counterparty, counterpartyID = xfer.Originator, xfer.OriginatorID
+	}
+
+	if counterparty.ID == 0 {
+		if err = w.db.GetDB().First(&counterparty, counterpartyID).Error; err != nil {
+			return nil, fmt.Errorf("could not fetch counterparty: %s", err)
+		}
+	}
+
+	amount, _ := xfer.Amount.Float64()
+	return &WebhookEvent{
+		ID:         uuid.New().String(),
+		VASP:       w.db.GetVASP().Name,
+		EnvelopeID: xfer.Envelope,
+		Account:    account.Email,
+		Previous:   transition.PreviousString,
+		State:      transition.StateString,
+		Amount:     amount,
+		AssetType:  xfer.AssetType,
+		Counterparty: WebhookCounterparty{
+			WalletAddress: counterparty.WalletAddress,
+			Email:         counterparty.Email,
+			Provider:      counterparty.Provider,
+		},
+		Actor:     string(transition.Actor),
+		Reason:    transition.Reason,
+		Timestamp: transition.Timestamp.Format(time.RFC3339Nano),
+		state:     transition.State,
+	}, nil
+}
+
+// deliver posts the event to the webhook until it is delivered, the maximum number of
+// attempts is reached, the webhook is removed or the notifier is closed.
+func (w *Webhooks) deliver(hook db.Webhook, event *WebhookEvent, body []byte) {
+	defer w.wg.Done()
+	backoff := w.conf.Backoff
+	removed := w.removedChan(hook.ID)
+
+	for attempt := 1; attempt <= w.conf.MaxAttempts; attempt++ {
+		delivery := &db.WebhookDelivery{
+			WebhookID:   hook.ID,
+			Event:       event.ID,
+			Envelope:    event.EnvelopeID,
+			State:       event.state,
+			StateString: event.State,
+			Attempt:     attempt,
+			Timestamp:   time.Now(),
+		}
+
+		if delivery.StatusCode, delivery.Error = w.post(hook, event, body); delivery.Error == "" {
+			delivery.Delivered = true
+		}
+
+		if err := w.db.Create(delivery).Error; err != nil {
+			log.Warn().Err(err).Uint("webhook", hook.ID).Msg("could not record webhook delivery")
+		}
+
+		if delivery.Delivered {
+			log.Debug().Uint("webhook", hook.ID).Str("event", event.ID).Int("attempt", attempt).Msg("webhook event delivered")
+			return
+		}
+		log.Warn().Uint("webhook", hook.ID).Str("event", event.ID).Int("attempt", attempt).Str("error", delivery.Error).Msg("could not deliver webhook event")
+
+		if attempt < w.conf.MaxAttempts {
+			select {
+			case <-time.After(backoff):
+				if backoff *= 2; w.conf.MaxBackoff > 0 && backoff > w.conf.MaxBackoff {
+					backoff = w.conf.MaxBackoff
+				}
+			case <-removed:
+				log.Debug().Uint("webhook", hook.ID).Str("event", event.ID).Msg("webhook removed, dropping pending delivery")
+				return
+			case <-w.done:
+				return
+			}
+		}
+	}
+}
+
+// post sends the event to the webhook, returning the HTTP status code, if any, and a
+// description of the error if the event was not delivered.
+func (w *Webhooks) post(hook db.Webhook, event *WebhookEvent, body []byte) (code int, errmsg string) {
+	req, err := http.NewRequest(http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err.Error()
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, event.ID)
+	req.Header.Set(WebhookSignatureHeader, SignWebhookEvent(hook.Secret, body))
+
+	var rep *http.Response
+	if rep, err = w.client.Do(req); err != nil {
+		return 0, err.Error()
+	}
+	rep.Body.Close()
+
+	if rep.StatusCode < 200 || rep.StatusCode >= 300 {
+		return rep.StatusCode, fmt.Sprintf("webhook returned %s", rep.Status)
+	}
+	return rep.StatusCode, ""
+}
+
+// Close stops retrying failed deliveries and waits for in-flight deliveries to finish.
+func (w *Webhooks) Close() {
+	w.once.Do(func() { close(w.done) })
+	w.wg.Wait()
+}
+
+// Wait blocks until the events that have been sent are delivered or have failed all of
+// their attempts.
+func (w *Webhooks) Wait() {
+	w.wg.Wait()
+}
+
+// SignWebhookEvent returns the signature header value of the event body for the secret.
+func SignWebhookEvent(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return webhookSignaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhookEvent returns true if the signature header value was created by signing
+// the event body with the secret. Webhook receivers should verify every event.
+func VerifyWebhookEvent(secret string, body []byte, signature string) bool {
+	if !strings.HasPrefix(signature, webhookSignaturePrefix) {
+		return false
+	}
+	return hmac.Equal([]byte(signature), []byte(SignWebhookEvent(secret, body)))
+}
+
+// generateWebhookSecret returns a random secret for signing webhook events.
+func generateWebhookSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(secret), nil
+}
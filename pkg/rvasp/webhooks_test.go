@@ -0,0 +1,276 @@
+package rvasp_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trisacrypto/testnet/pkg/rvasp"
+	"github.com/trisacrypto/testnet/pkg/rvasp/config"
+	"github.com/trisacrypto/testnet/pkg/rvasp/db"
+	"github.com/trisacrypto/testnet/pkg/rvasp/harness"
+	pb "github.com/trisacrypto/testnet/pkg/rvasp/pb/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// receiver is a local webhook that collects the events posted to it.
+type receiver struct {
+	sync.Mutex
+	secret string
+	code   int
+	events []rvasp.WebhookEvent
+	errs   []string
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.Lock()
+	defer r.Unlock()
+
+	body, _ := io.ReadAll(req.Body)
+	if !rvasp.VerifyWebhookEvent(r.secret, body, req.Header.Get(rvasp.WebhookSignatureHeader)) {
+		r.errs = append(r.errs, "invalid signature")
+	}
+
+	var event rvasp.WebhookEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		r.errs = append(r.errs, err.Error())
+	} else if event.ID != req.Header.Get(rvasp.WebhookEventHeader) {
+		r.errs = append(r.errs, "event header does not match event id")
+	}
+
+	r.events = append(r.events, event)
+	w.WriteHeader(r.code)
+}
+
+// received returns the events received for the transaction in the order of the
+// state transitions.
+func (r *receiver) received(envelope string) []rvasp.WebhookEvent {
+	r.Lock()
+	defer r.Unlock()
+
+	events := make([]rvasp.WebhookEvent, 0, len(r.events))
+	for _, event := range r.events {
+		if event.EnvelopeID == envelope {
+			events = append(events, event)
+		}
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].Timestamp < events[j].Timestamp })
+	return events
+}
+
+// states returns the states of the events received for the transaction in order.
+func (r *receiver) states(envelope string) []string {
+	events := r.received(envelope)
+	states := make([]string, 0, len(events))
+	for _, event := range events {
+		states = append(states, event.State)
+	}
+	return states
+}
+
+func TestWebhooks(t *testing.T) {
+	h := harness.New(t, "alice", "bob")
+	alice, bob := h.VASP("alice"), h.VASP("bob")
+	ctx := context.Background()
+
+	// Register a webhook for every alice account and a webhook for larry at bob
+	originator := &receiver{code: http.StatusOK}
+	originatorSrv := httptest.NewServer(originator)
+	defer originatorSrv.Close()
+
+	hook, err := alice.Client.RegisterWebhook(ctx, &pb.Webhook{Url: originatorSrv.URL})
+	require.NoError(t, err)
+	require.NotEmpty(t, hook.Secret, "a secret should be generated")
+	originator.secret = hook.Secret
+
+	beneficiary := &receiver{code: http.StatusOK, secret: "supersecret"}
+	beneficiarySrv := httptest.NewServer(beneficiary)
+	defer beneficiarySrv.Close()
+
+	_, err = bob.Client.RegisterWebhook(ctx, &pb.Webhook{Url: beneficiarySrv.URL, Account: "larry@bobvasp.co.uk", Secret: beneficiary.secret})
+	require.NoError(t, err)
+
+	// Failed deliveries are retried up to the maximum number of attempts
+	failing := &receiver{code: http.StatusInternalServerError}
+	failingSrv := httptest.NewServer(failing)
+	defer failingSrv.Close()
+
+	failingHook, err := alice.Client.RegisterWebhook(ctx, &pb.Webhook{Url: failingSrv.URL, Account: "mary@alicevasp.us"})
+	require.NoError(t, err)
+	failing.secret = failingHook.Secret
+
+	// SendPartial to AsyncRepair takes the async handshake through every pending state
+	reply, err := alice.Client.Transfer(ctx, &pb.TransferRequest{
+		Account:     "mary@alicevasp.us",
+		Beneficiary: "larry@bobvasp.co.uk",
+		Amount:      1.5,
+		AssetType:   "Bitcoin",
+	})
+	require.NoError(t, err)
+	h.HandleAsync()
+	h.HandleAsync()
+
+	// Pending fixture transactions also expire, so only consider the events of the transfer
+	id := reply.Transaction.EnvelopeId
+	require.Eventually(t, func() bool { return len(originator.states(id)) == 3 && len(beneficiary.states(id)) == 4 }, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, []string{"PENDING_RECEIVED", "ACCEPTED", "COMPLETED"}, originator.states(id))
+	require.Equal(t, []string{"PENDING_SENT", "AWAITING_FULL_TRANSFER", "PENDING_ACKNOWLEDGED", "COMPLETED"}, beneficiary.states(id))
+	require.Empty(t, originator.errs)
+	require.Empty(t, beneficiary.errs)
+
+	event := originator.received(id)[0]
+	require.Equal(t, alice.Name, event.VASP)
+	require.Equal(t, "mary@alicevasp.us", event.Account)
+	require.Equal(t, 1.5, event.Amount)
+	require.Equal(t, "Bitcoin", event.AssetType)
+	require.Equal(t, "larry@bobvasp.co.uk", event.Counterparty.Email)
+	require.Equal(t, bob.Name, event.Counterparty.Provider)
+
+	event = beneficiary.received(id)[0]
+	require.Equal(t, "larry@bobvasp.co.uk", event.Account)
+	require.Equal(t, "mary@alicevasp.us", event.Counterparty.Email)
+
+	// Every attempt is recorded in the delivery log
+	require.Eventually(t, func() bool {
+		rep, err := alice.Client.WebhookDeliveries(ctx, &pb.WebhookDeliveriesRequest{WebhookId: failingHook.Id, EnvelopeId: id})
+		return err == nil && len(rep.Deliveries) == 3*3
+	}, 5*time.Second, 10*time.Millisecond)
+
+	rep, err := alice.Client.WebhookDeliveries(ctx, &pb.WebhookDeliveriesRequest{WebhookId: failingHook.Id, EnvelopeId: id})
+	require.NoError(t, err)
+	attempts := make(map[string]uint32)
+	for _, delivery := range rep.Deliveries {
+		require.False(t, delivery.Delivered)
+		require.Equal(t, int32(http.StatusInternalServerError), delivery.StatusCode)
+		require.NotEmpty(t, delivery.Error)
+		require.Equal(t, id, delivery.EnvelopeId)
+		attempts[delivery.EventId]++
+	}
+	require.Len(t, attempts, 3)
+	for _, n := range attempts {
+		require.Equal(t, uint32(3), n)
+	}
+
+	rep, err = alice.Client.WebhookDeliveries(ctx, &pb.WebhookDeliveriesRequest{WebhookId: hook.Id, EnvelopeId: id})
+	require.NoError(t, err)
+	require.Len(t, rep.Deliveries, 3)
+	for _, delivery := range rep.Deliveries {
+		require.True(t, delivery.Delivered)
+		require.Equal(t, uint32(1), delivery.Attempt)
+	}
+
+	// Each rVASP only lists its own webhooks without their secrets
+	list, err := alice.Client.ListWebhooks(ctx, &pb.Empty{})
+	require.NoError(t, err)
+	require.Len(t, list.Webhooks, 2)
+	require.Equal(t, "", list.Webhooks[0].Account)
+	require.Equal(t, "mary@alicevasp.us", list.Webhooks[1].Account)
+	for _, webhook := range list.Webhooks {
+		require.Empty(t, webhook.Secret)
+	}
+
+	// Deleted webhooks are no longer listed
+	_, err = alice.Client.DeleteWebhook(ctx, &pb.Webhook{Id: failingHook.Id})
+	require.NoError(t, err)
+
+	list, err = alice.Client.ListWebhooks(ctx, &pb.Empty{})
+	require.NoError(t, err)
+	require.Len(t, list.Webhooks, 1)
+
+	_, err = alice.Client.DeleteWebhook(ctx, &pb.Webhook{Id: failingHook.Id})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	// Webhooks can only be registered for valid URLs and local accounts
+	_, err = alice.Client.RegisterWebhook(ctx, &pb.Webhook{Url: "ftp://example.com"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = alice.Client.RegisterWebhook(ctx, &pb.Webhook{Url: originatorSrv.URL, Account: "larry@bobvasp.co.uk"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestWebhookURLs(t *testing.T) {
+	h := harness.NewWithConfig(t, func(conf *config.Config) {
+		conf.Webhooks.AllowPrivate = false
+	}, "alice")
+	alice := h.VASP("alice")
+	ctx := context.Background()
+
+	testCases := []struct {
+		url   string
+		valid bool
+	}{
+		{"https://example.com/events", true},
+		{"http://93.184.216.34:8080/events", true},
+		{"ftp://example.com/events", false},
+		{"https:///events", false},
+		{"http://localhost:8080/events", false},
+		{"http://LOCALHOST./events", false},
+		{"http://api.localhost/events", false},
+		{"http://127.0.0.1:8080/events", false},
+		{"http://[::1]/events", false},
+		{"http://0.0.0.0/events", false},
+		{"http://10.0.0.1/events", false},
+		{"http://192.168.1.1/events", false},
+		{"http://169.254.169.254/latest/meta-data", false},
+	}
+
+	for _, tc := range testCases {
+		_, err := alice.Client.RegisterWebhook(ctx, &pb.Webhook{Url: tc.url})
+		if tc.valid {
+			require.NoError(t, err, tc.url)
+		} else {
+			require.Equal(t, codes.InvalidArgument, status.Code(err), tc.url)
+		}
+	}
+}
+
+func TestDeleteWebhook(t *testing.T) {
+	h := harness.NewWithConfig(t, func(conf *config.Config) {
+		conf.Webhooks.MaxAttempts = 100
+		conf.Webhooks.Backoff = 20 * time.Millisecond
+		conf.Webhooks.MaxBackoff = 20 * time.Millisecond
+	}, "alice", "bob")
+	alice := h.VASP("alice")
+	ctx := context.Background()
+
+	failing := &receiver{code: http.StatusServiceUnavailable}
+	srv := httptest.NewServer(failing)
+	defer srv.Close()
+
+	hook, err := alice.Client.RegisterWebhook(ctx, &pb.Webhook{Url: srv.URL, Account: "mary@alicevasp.us"})
+	require.NoError(t, err)
+
+	_, err = alice.Client.Transfer(ctx, &pb.TransferRequest{
+		Account:     "mary@alicevasp.us",
+		Beneficiary: "robert@bobvasp.co.uk",
+		Amount:      0.1,
+		AssetType:   "Bitcoin",
+	})
+	require.NoError(t, err)
+
+	deliveries := func() (n int64) {
+		require.NoError(t, alice.DB.GetDB().Model(&db.WebhookDelivery{}).Where("webhook_id = ?", hook.Id).Count(&n).Error)
+		return n
+	}
+
+	// The backoff is capped so the failed delivery keeps being retried quickly
+	require.Eventually(t, func() bool { return deliveries() >= 5 }, 5*time.Second, 10*time.Millisecond)
+
+	// Once the webhook is deleted its pending retries are dropped
+	_, err = alice.Client.DeleteWebhook(ctx, &pb.Webhook{Id: hook.Id})
+	require.NoError(t, err)
+
+	time.Sleep(100 * time.Millisecond)
+	n := deliveries()
+	time.Sleep(200 * time.Millisecond)
+	require.Equal(t, n, deliveries(), "deliveries should not be retried after the webhook is deleted")
+	require.Less(t, n, int64(100))
+}
@@ -0,0 +1,214 @@
+package rvasp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog/log"
+	"github.com/trisacrypto/testnet/pkg/rvasp/jsonpb"
+	pb "github.com/trisacrypto/testnet/pkg/rvasp/pb/v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+// LiveUpdatesHandler returns the WebSocket gateway to the LiveUpdates stream. Each text
+// frame received from the client is a JSON encoded Command and each text frame sent to
+// the client is a JSON encoded Message, so that browsers can connect to the rVASP
+// directly. Commands are dispatched exactly as if they had been sent on the gRPC
+// stream. The client ID is taken from the client query parameter, or generated if not
+// specified, and is used for commands that do not specify a client. If authentication
+// is enabled, the credentials are taken from the request headers or from the
+// access_token query parameter since browsers cannot set WebSocket headers.
+func (s *Server) LiveUpdatesHandler() http.Handler {
+	upgrader := &websocket.Upgrader{
+		HandshakeTimeout: 10 * time.Second,
+		CheckOrigin:      checkOrigin(s.conf.Gateway.Origins),
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		client := r.URL.Query().Get("client")
+		if client == "" {
+			client = uuid.New().String()
+		}
+
+		// Authenticate the request before upgrading the connection
+		ctx := context.Background()
+		if s.auth != nil {
+			scope, err := s.auth.Authenticate(tokenFromRequest(r))
+			if err != nil {
+				log.Info().Str("remote", r.RemoteAddr).Msg("unauthenticated websocket connection")
+				writeError(w, http.StatusUnauthorized, err)
+				return
+			}
+			ctx = WithScope(ctx, scope)
+		}
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			// The upgrader has already replied to the client with an HTTP error
+			log.Warn().Err(err).Str("origin", r.Header.Get("Origin")).Msg("could not upgrade websocket connection")
+			return
+		}
+
+		// Limit the size of commands like the bodies of REST API requests
+		conn.SetReadLimit(maxRequestBody)
+
+		stream := newWebSocketStream(ctx, conn, client)
+		defer stream.close()
+
+		log.Info().Str("client", client).Str("remote", r.RemoteAddr).Msg("websocket connection opened")
+		if err = s.LiveUpdates(stream); err != nil {
+			log.Warn().Err(err).Str("client", client).Msg("websocket live updates stream closed with error")
+			stream.closeWithError(err)
+		}
+	})
+}
+
+// checkOrigin returns a function that allows WebSocket connections from the origins.
+// Requests without an Origin header are not from browsers and are always allowed.
+func checkOrigin(origins []string) func(r *http.Request) bool {
+	allowed := make(map[string]struct{}, len(origins))
+	for _, origin := range origins {
+		allowed[strings.TrimSuffix(strings.TrimSpace(origin), "/")] = struct{}{}
+	}
+
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			return true
+		}
+
+		if len(allowed) == 0 {
+			// Only allow browsers to connect from the same host
+			u, err := url.Parse(origin)
+			return err == nil && strings.EqualFold(u.Host, r.Host)
+		}
+
+		if _, ok := allowed["*"]; ok {
+			return true
+		}
+		_, ok := allowed[origin]
+		return ok
+	}
+}
+
+// webSocketStream adapts a WebSocket connection to a LiveUpdates server stream so that
+// the UpdateManager can send messages to WebSocket clients like any other stream.
+type webSocketStream struct {
+	sync.Mutex
+	conn   *websocket.Conn
+	client string
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func newWebSocketStream(parent context.Context, conn *websocket.Conn, client string) *webSocketStream {
+	ctx, cancel := context.WithCancel(parent)
+	return &webSocketStream{conn: conn, client: client, ctx: ctx, cancel: cancel}
+}
+
+// Send a JSON encoded message to the client. The UpdateManager may send messages from
+// multiple go routines but WebSocket connections only support one concurrent writer.
+func (s *webSocketStream) Send(msg *pb.Message) (err error) {
+	var data []byte
+	if data, err = jsonpb.Marshal(msg); err != nil {
+		return fmt.Errorf("could not marshal message: %s", err)
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	return s.conn.WriteMessage(websocket.TextMessage, data)
+}
+
+// Recv the next JSON encoded command from the client. Returns io.EOF when the client
+// closes the connection.
+func (s *webSocketStream) Recv() (cmd *pb.Command, err error) {
+	var (
+		mt   int
+		data []byte
+	)
+
+	for {
+		if mt, data, err = s.conn.ReadMessage(); err != nil {
+			s.cancel()
+			if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				return nil, io.EOF
+			}
+			return nil, err
+		}
+
+		// Ignore binary frames, commands are only sent as JSON text
+		if mt == websocket.TextMessage {
+			break
+		}
+	}
+
+	cmd = &pb.Command{}
+	if err = jsonpb.Unmarshal(data, cmd); err != nil {
+		return nil, fmt.Errorf("could not unmarshal command: %s", err)
+	}
+
+	if cmd.Client == "" {
+		cmd.Client = s.client
+	}
+	return cmd, nil
+}
+
+// closeWithError sends a close frame with the reason the stream was closed.
+func (s *webSocketStream) closeWithError(err error) {
+	reason := err.Error()
+	if len(reason) > 120 {
+		// Close frame payloads are limited to 125 bytes including the close code
+		reason = reason[:120]
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	msg := websocket.FormatCloseMessage(websocket.CloseInternalServerErr, reason)
+	s.conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(time.Second))
+}
+
+func (s *webSocketStream) close() {
+	s.cancel()
+	s.conn.Close()
+}
+
+// Context of the stream, which is canceled when the connection is closed.
+func (s *webSocketStream) Context() context.Context {
+	return s.ctx
+}
+
+// Headers and trailers are not supported by the WebSocket gateway.
+func (s *webSocketStream) SetHeader(metadata.MD) error  { return nil }
+func (s *webSocketStream) SendHeader(metadata.MD) error { return nil }
+func (s *webSocketStream) SetTrailer(metadata.MD)       {}
+
+func (s *webSocketStream) SendMsg(m interface{}) error {
+	msg, ok := m.(*pb.Message)
+	if !ok {
+		return fmt.Errorf("cannot send %T on live updates stream", m)
+	}
+	return s.Send(msg)
+}
+
+func (s *webSocketStream) RecvMsg(m interface{}) (err error) {
+	cmd, ok := m.(*pb.Command)
+	if !ok {
+		return fmt.Errorf("cannot receive %T on live updates stream", m)
+	}
+
+	var in *pb.Command
+	if in, err = s.Recv(); err != nil {
+		return err
+	}
+	proto.Merge(cmd, in)
+	return nil
+}
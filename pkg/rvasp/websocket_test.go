@@ -0,0 +1,101 @@
+package rvasp_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+	"github.com/trisacrypto/testnet/pkg/rvasp/harness"
+	"github.com/trisacrypto/testnet/pkg/rvasp/jsonpb"
+	pb "github.com/trisacrypto/testnet/pkg/rvasp/pb/v1"
+)
+
+func TestWebSocketGateway(t *testing.T) {
+	h := harness.New(t, "alice")
+	alice := h.VASP("alice")
+
+	srv := httptest.NewServer(alice.Server.LiveUpdatesHandler())
+	defer srv.Close()
+	endpoint := "ws" + strings.TrimPrefix(srv.URL, "http") + "?client=browser"
+
+	conn, _, err := websocket.DefaultDialer.Dial(endpoint, nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	send := func(cmd *pb.Command) {
+		data, err := jsonpb.Marshal(cmd)
+		require.NoError(t, err)
+		require.NoError(t, conn.WriteMessage(websocket.TextMessage, data))
+	}
+
+	recv := func() *pb.Message {
+		mt, data, err := conn.ReadMessage()
+		require.NoError(t, err)
+		require.Equal(t, websocket.TextMessage, mt)
+
+		msg := &pb.Message{}
+		require.NoError(t, jsonpb.Unmarshal(data, msg))
+		return msg
+	}
+
+	// Commands without a client are sent as the client of the connection
+	send(&pb.Command{Type: pb.RPC_NORPC, Id: 1})
+	msg := recv()
+	require.Equal(t, uint64(1), msg.Id)
+	require.Equal(t, "command 1 acknowledged", msg.Update)
+
+	send(&pb.Command{
+		Type:    pb.RPC_ACCOUNT,
+		Id:      2,
+		Client:  "browser",
+		Request: &pb.Command_Account{Account: &pb.AccountRequest{Account: "mary@alicevasp.us"}},
+	})
+	msg = recv()
+	require.Equal(t, pb.RPC_ACCOUNT, msg.Type)
+	require.Equal(t, "mary@alicevasp.us", msg.GetAccount().Email)
+
+	// Commands from another client close the connection
+	send(&pb.Command{Type: pb.RPC_NORPC, Id: 3, Client: "other"})
+	_, _, err = conn.ReadMessage()
+	require.True(t, websocket.IsCloseError(err, websocket.CloseInternalServerErr), "expected close error, got %v", err)
+}
+
+func TestWebSocketReadLimit(t *testing.T) {
+	h := harness.New(t, "alice")
+	srv := httptest.NewServer(h.VASP("alice").Server.LiveUpdatesHandler())
+	defer srv.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// Commands larger than 1MiB close the connection
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(`{"client":"`+strings.Repeat("a", 1<<20)+`"}`)))
+	_, _, err = conn.ReadMessage()
+	require.True(t, websocket.IsCloseError(err, websocket.CloseMessageTooBig), "expected close error, got %v", err)
+}
+
+func TestWebSocketOrigins(t *testing.T) {
+	h := harness.New(t, "alice")
+	alice := h.VASP("alice")
+
+	srv := httptest.NewServer(alice.Server.LiveUpdatesHandler())
+	defer srv.Close()
+	endpoint := "ws" + strings.TrimPrefix(srv.URL, "http")
+
+	// No origins are configured so only browsers on the same host can connect
+	conn, _, err := websocket.DefaultDialer.Dial(endpoint, http.Header{"Origin": {srv.URL}})
+	require.NoError(t, err)
+	conn.Close()
+
+	conn, _, err = websocket.DefaultDialer.Dial(endpoint, nil)
+	require.NoError(t, err, "non-browser clients do not send an origin")
+	conn.Close()
+
+	_, rep, err := websocket.DefaultDialer.Dial(endpoint, http.Header{"Origin": {"https://evil.example.com"}})
+	require.ErrorIs(t, err, websocket.ErrBadHandshake)
+	require.Equal(t, http.StatusForbidden, rep.StatusCode)
+}
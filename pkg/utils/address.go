@@ -0,0 +1,117 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// All the valid characters for the XRP ledger's base58 encoding.
+var xrpTmpl = []byte("rpshnaf39wBUDNEGHJKLM4PQRST7VWXYZ2bcdeCg65jkm8oFqi1tuvAxyz")
+
+// AddressValidator returns an error if the wallet address is not well formed for the
+// chain that the validator was created for.
+type AddressValidator func(address string) error
+
+// Validators by normalized asset type; aliases refer to the same validator.
+var validators = map[string]AddressValidator{
+	"bitcoin":      ValidateBTCAddress,
+	"btc":          ValidateBTCAddress,
+	"ethereum":     ValidateETHAddress,
+	"eth":          ValidateETHAddress,
+	"litecoin":     ValidateLTCAddress,
+	"ltc":          ValidateLTCAddress,
+	"xrp":          ValidateXRPAddress,
+	"ripple":       ValidateXRPAddress,
+	"bitcoin cash": ValidateBCHAddress,
+	"bitcoincash":  ValidateBCHAddress,
+	"bch":          ValidateBCHAddress,
+}
+
+// LookupValidator returns the address validator for the specified asset type, e.g.
+// "Bitcoin" or "ETH". ErrUnknownAssetType is returned if the asset type is not
+// supported so that callers can decide whether or not to skip validation.
+func LookupValidator(assetType string) (AddressValidator, error) {
+	if validator, ok := validators[strings.ToLower(strings.TrimSpace(assetType))]; ok {
+		return validator, nil
+	}
+	return nil, fmt.Errorf("%w %q", ErrUnknownAssetType, assetType)
+}
+
+// ValidateBTCAddress accepts base58 P2PKH and P2SH addresses as well as bech32 and
+// bech32m segwit addresses on mainnet, testnet, and regtest.
+func ValidateBTCAddress(address string) (err error) {
+	if hasSegwitPrefix(address, "bc", "tb", "bcrt") {
+		_, err = ParseSegwitAddress(address, "bc", "tb", "bcrt")
+		return err
+	}
+	return validateBase58(address, tmpl, 0, 5, 111, 196)
+}
+
+// ValidateETHAddress accepts hex addresses with a valid EIP-55 checksum as well as
+// all lower or all upper case hex addresses.
+func ValidateETHAddress(address string) (err error) {
+	_, err = ParseETHAddress(address)
+	return err
+}
+
+// ValidateLTCAddress accepts base58 P2PKH and P2SH addresses (including the legacy
+// P2SH version shared with bitcoin) as well as segwit addresses.
+func ValidateLTCAddress(address string) (err error) {
+	if hasSegwitPrefix(address, "ltc", "tltc", "rltc") {
+		_, err = ParseSegwitAddress(address, "ltc", "tltc", "rltc")
+		return err
+	}
+	return validateBase58(address, tmpl, 48, 50, 5, 111, 58, 196)
+}
+
+// ValidateXRPAddress accepts classic XRP ledger account addresses.
+func ValidateXRPAddress(address string) (err error) {
+	return validateBase58(address, xrpTmpl, 0)
+}
+
+// ValidateBCHAddress accepts CashAddr addresses (with or without a prefix) as well
+// as legacy base58 addresses.
+func ValidateBCHAddress(address string) (err error) {
+	if err = validateBase58(address, tmpl, 0, 5, 111, 196); err == nil {
+		return nil
+	}
+	_, err = ParseCashAddress(address)
+	return err
+}
+
+// validateBase58 decodes the address using the specified alphabet, verifies its
+// checksum and checks that the version byte is one of the allowed versions.
+func validateBase58(address string, alphabet []byte, versions ...byte) (err error) {
+	// Addresses must be at least as long as the version, hash, and checksum
+	if len(address) < 25 {
+		return ErrInvalidLength
+	}
+
+	a := &A25{}
+	if err = a.decode([]byte(address), alphabet); err != nil {
+		return err
+	}
+
+	if !a.HasValidChecksum() {
+		return ErrInvalidChecksum
+	}
+
+	for _, version := range versions {
+		if a.Version() == version {
+			return nil
+		}
+	}
+	return ErrInvalidVersion
+}
+
+// hasSegwitPrefix returns true if the address starts with one of the hrps followed by
+// the bech32 separator.
+func hasSegwitPrefix(address string, hrps ...string) bool {
+	address = strings.ToLower(address)
+	for _, hrp := range hrps {
+		if strings.HasPrefix(address, hrp+"1") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trisacrypto/testnet/pkg/utils"
+)
+
+func TestValidateAddress(t *testing.T) {
+	testCases := []struct {
+		assetType string
+		address   string
+		err       string
+	}{
+		// Bitcoin base58 and segwit (BIP-173 and BIP-350 test vectors)
+		{"Bitcoin", "18nxAxBktHZDrMoJ3N2fk9imLX8xNnYbNh", ""},
+		{"BTC", "moJuU1GjhJzUdUGukw13a4w6CWjfFsJ92q", ""},
+		{"bitcoin", "1oJuU1GjhJzUdUGukw13a4w6CWjfFsJ92q", utils.ErrInvalidChecksum.Error()},
+		{"bitcoin", "LVuDpNCSSj6pQ7t9Pv6d6sUkLKoqDEVUnJ", utils.ErrInvalidVersion.Error()},
+		{"bitcoin", "BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4", ""},
+		{"bitcoin", "tb1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3q0sl5k7", ""},
+		{"bitcoin", "bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0", ""},
+		{"bitcoin", "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t5", utils.ErrInvalidChecksum.Error()},
+		{"bitcoin", "tb1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3q0sL5k7", utils.ErrMixedCase.Error()},
+		{"bitcoin", "bc1zw508d6qejxtdg4y5r3zarvaryvqyzf3du", utils.ErrInvalidPadding.Error()},
+
+		// Ethereum (EIP-55 test vectors)
+		{"Ethereum", "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", ""},
+		{"ETH", "0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359", ""},
+		{"eth", "0xdbf03b407c01e7cd3cbea99509d93f8dddc8c6fb", ""},
+		{"eth", "0x5AAEB6053F3E94C9B9A09F33669435E7EF1BEAED", ""},
+		{"eth", "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD", utils.ErrInvalidChecksum.Error()},
+		{"eth", "5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", utils.ErrInvalidPrefix.Error()},
+		{"eth", "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeA", utils.ErrInvalidLength.Error()},
+
+		// Litecoin
+		{"Litecoin", "LVuDpNCSSj6pQ7t9Pv6d6sUkLKoqDEVUnJ", ""},
+		{"LTC", "MJaRnao1s62a2zAKSkmG582KbLKianqb7v", ""},
+		{"ltc", "QXHFfTBKYXjaaTH1e7Rox8CcdNPGHVhM59", ""},
+		{"ltc", "LVuDpNCSSj6pQ7t9Pv6d6sUkLKoqDEVUnK", utils.ErrInvalidChecksum.Error()},
+		{"ltc", "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH", utils.ErrInvalidVersion.Error()},
+
+		// XRP
+		{"XRP", "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh", ""},
+		{"ripple", "rBgGZ9tc4him9KBzD8fKFiQz3fSZpaSwMH", ""},
+		{"xrp", "rBgGZ9tc4him9KBzD8fKFiQz3fSZpaSwMh", utils.ErrInvalidChecksum.Error()},
+		{"xrp", "rHb9CJAWyB4rj91VRWn96DkukG4bwdty0h", "invalid base58 character '0'"},
+
+		// Bitcoin Cash (CashAddr spec test vectors)
+		{"Bitcoin Cash", "bitcoincash:qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a", ""},
+		{"BCH", "qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a", ""},
+		{"bch", "bchtest:pr6m7j9njldwwzlg9v7v53unlr4jkmx6eyvwc0uz5t", ""},
+		{"bch", "1BpEi6DfDAUFd7GtittLSdBeYJvcoaVggu", ""},
+		{"bch", "bitcoincash:qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6c", utils.ErrInvalidChecksum.Error()},
+		{"bch", "bitcoincash:QPM2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a", utils.ErrMixedCase.Error()},
+	}
+
+	for _, tc := range testCases {
+		validate, err := utils.LookupValidator(tc.assetType)
+		require.NoError(t, err, "expected a validator for asset type %q", tc.assetType)
+
+		err = validate(tc.address)
+		if tc.err == "" {
+			require.NoError(t, err, "expected %s address %s to be valid", tc.assetType, tc.address)
+		} else {
+			require.ErrorContains(t, err, tc.err, "expected %s address %s to be invalid", tc.assetType, tc.address)
+		}
+	}
+}
+
+func TestLookupValidator(t *testing.T) {
+	_, err := utils.LookupValidator("Tezos")
+	require.ErrorIs(t, err, utils.ErrUnknownAssetType)
+
+	_, err = utils.LookupValidator("")
+	require.ErrorIs(t, err, utils.ErrUnknownAssetType)
+}
+
+func TestChecksumETHAddress(t *testing.T) {
+	eth, err := utils.ParseETHAddress("0xd1220a0cf47c7b9be7a2e6ba89f429762e7b9adb")
+	require.NoError(t, err)
+	require.Equal(t, "0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb", utils.ChecksumETHAddress(eth))
+}
@@ -0,0 +1,95 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CashAddress is a decoded Bitcoin Cash CashAddr address.
+// See https://github.com/bitcoincashorg/bitcoincash.org/blob/master/spec/cashaddr.md
+type CashAddress struct {
+	Prefix  string
+	Version byte
+	Hash    []byte
+}
+
+// ParseCashAddress decodes a Bitcoin Cash CashAddr address and returns an error if the
+// checksum is invalid or the hash length does not match the version byte. Addresses
+// without a prefix are assumed to be mainnet (bitcoincash) addresses.
+func ParseCashAddress(address string) (addr *CashAddress, err error) {
+	if strings.ToLower(address) != address && strings.ToUpper(address) != address {
+		return nil, ErrMixedCase
+	}
+	address = strings.ToLower(address)
+
+	addr = &CashAddress{Prefix: "bitcoincash"}
+	payload := address
+	if pos := strings.IndexByte(address, ':'); pos >= 0 {
+		addr.Prefix, payload = address[:pos], address[pos+1:]
+	}
+
+	switch addr.Prefix {
+	case "bitcoincash", "bchtest", "bchreg":
+	default:
+		return nil, fmt.Errorf("%w %q", ErrInvalidPrefix, addr.Prefix)
+	}
+
+	if len(payload) <= 8 {
+		return nil, ErrInvalidLength
+	}
+
+	values := make([]byte, 0, len(addr.Prefix)+1+len(payload))
+	for i := 0; i < len(addr.Prefix); i++ {
+		values = append(values, addr.Prefix[i]&31)
+	}
+	values = append(values, 0)
+
+	for _, c := range payload {
+		d := strings.IndexRune(charset, c)
+		if d < 0 {
+			return nil, fmt.Errorf("invalid cashaddr character %q", c)
+		}
+		values = append(values, byte(d))
+	}
+
+	if cashPolymod(values) != 0 {
+		return nil, ErrInvalidChecksum
+	}
+
+	// Strip the prefix and the 8 character checksum before converting to bytes
+	var data []byte
+	if data, err = convertBits(values[len(addr.Prefix)+1:len(values)-8], 5, 8, false); err != nil {
+		return nil, err
+	}
+
+	if len(data) == 0 {
+		return nil, ErrInvalidLength
+	}
+
+	addr.Version, addr.Hash = data[0], data[1:]
+	if addr.Version&0x80 != 0 {
+		return nil, ErrInvalidVersion
+	}
+
+	// The lower three bits of the version byte encode the size of the hash
+	sizes := [8]int{20, 24, 28, 32, 40, 48, 56, 64}
+	if len(addr.Hash) != sizes[addr.Version&0x07] {
+		return nil, ErrInvalidLength
+	}
+	return addr, nil
+}
+
+func cashPolymod(values []byte) uint64 {
+	gen := [5]uint64{0x98f2bc8e61, 0x79b76d99e2, 0xf33e5fb3c4, 0xae2eabe2a8, 0x1e4f43e470}
+	c := uint64(1)
+	for _, d := range values {
+		c0 := byte(c >> 35)
+		c = (c&0x07ffffffff)<<5 ^ uint64(d)
+		for i := 0; i < 5; i++ {
+			if (c0>>uint(i))&1 == 1 {
+				c ^= gen[i]
+			}
+		}
+	}
+	return c ^ 1
+}
@@ -0,0 +1,192 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// All the valid characters for bech32 encoding.
+const charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// Bech32 checksum constants; bech32m is used for segwit version 1+ addresses.
+// See https://github.com/bitcoin/bips/blob/master/bip-0350.mediawiki
+const (
+	bech32Const  = 1
+	bech32mConst = 0x2bc830a3
+)
+
+// Bech32Encoding is the checksum variant of a bech32 string.
+type Bech32Encoding uint8
+
+const (
+	Bech32 Bech32Encoding = iota + 1
+	Bech32m
+)
+
+// SegwitAddress is a decoded bech32 or bech32m segregated witness address.
+type SegwitAddress struct {
+	HRP      string
+	Version  byte
+	Program  []byte
+	Encoding Bech32Encoding
+}
+
+// ParseSegwitAddress decodes a bech32 or bech32m encoded segwit address and returns an
+// error if the checksum is invalid or the witness program is malformed. If hrps are
+// specified, the human readable part of the address must match one of them.
+// See https://github.com/bitcoin/bips/blob/master/bip-0173.mediawiki
+func ParseSegwitAddress(address string, hrps ...string) (addr *SegwitAddress, err error) {
+	var (
+		hrp  string
+		data []byte
+		enc  Bech32Encoding
+	)
+
+	if hrp, data, enc, err = decodeBech32(address); err != nil {
+		return nil, err
+	}
+
+	if len(hrps) > 0 {
+		var ok bool
+		for _, expected := range hrps {
+			if hrp == expected {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return nil, fmt.Errorf("%w %q", ErrInvalidHRP, hrp)
+		}
+	}
+
+	if len(data) == 0 {
+		return nil, ErrInvalidWitness
+	}
+
+	addr = &SegwitAddress{HRP: hrp, Version: data[0], Encoding: enc}
+	if addr.Program, err = convertBits(data[1:], 5, 8, false); err != nil {
+		return nil, err
+	}
+
+	if addr.Version > 16 || len(addr.Program) < 2 || len(addr.Program) > 40 {
+		return nil, ErrInvalidWitness
+	}
+
+	// Version 0 programs must be P2WPKH or P2WSH and use the original bech32 checksum,
+	// all later versions must use the bech32m checksum.
+	if addr.Version == 0 {
+		if len(addr.Program) != 20 && len(addr.Program) != 32 {
+			return nil, ErrInvalidWitness
+		}
+		if enc != Bech32 {
+			return nil, ErrInvalidChecksum
+		}
+	} else if enc != Bech32m {
+		return nil, ErrInvalidChecksum
+	}
+
+	return addr, nil
+}
+
+// decodeBech32 splits a bech32 string into its human readable part and 5-bit data
+// values (without the checksum), detecting which checksum variant was used.
+func decodeBech32(s string) (hrp string, data []byte, enc Bech32Encoding, err error) {
+	if len(s) > 90 {
+		return "", nil, 0, ErrTooLong
+	}
+
+	if strings.ToLower(s) != s && strings.ToUpper(s) != s {
+		return "", nil, 0, ErrMixedCase
+	}
+	s = strings.ToLower(s)
+
+	pos := strings.LastIndexByte(s, '1')
+	if pos < 1 || pos+7 > len(s) {
+		return "", nil, 0, ErrInvalidSeparator
+	}
+
+	hrp = s[:pos]
+	for _, c := range hrp {
+		if c < 33 || c > 126 {
+			return "", nil, 0, fmt.Errorf("invalid bech32 hrp character %q", c)
+		}
+	}
+
+	data = make([]byte, 0, len(s)-pos-1)
+	for _, c := range s[pos+1:] {
+		d := strings.IndexRune(charset, c)
+		if d < 0 {
+			return "", nil, 0, fmt.Errorf("invalid bech32 character %q", c)
+		}
+		data = append(data, byte(d))
+	}
+
+	switch bech32Polymod(append(hrpExpand(hrp), data...)) {
+	case bech32Const:
+		enc = Bech32
+	case bech32mConst:
+		enc = Bech32m
+	default:
+		return "", nil, 0, ErrInvalidChecksum
+	}
+
+	return hrp, data[:len(data)-6], enc, nil
+}
+
+func bech32Polymod(values []byte) uint32 {
+	gen := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		b := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (b>>uint(i))&1 == 1 {
+				chk ^= gen[i]
+			}
+		}
+	}
+	return chk
+}
+
+func hrpExpand(hrp string) []byte {
+	out := make([]byte, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]>>5)
+	}
+	out = append(out, 0)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]&31)
+	}
+	return out
+}
+
+// convertBits regroups a slice of fromBits-wide values into toBits-wide values.
+func convertBits(data []byte, fromBits, toBits uint, pad bool) ([]byte, error) {
+	var (
+		acc  uint32
+		bits uint
+		out  []byte
+	)
+
+	maxv := uint32(1)<<toBits - 1
+	for _, v := range data {
+		if uint32(v)>>fromBits != 0 {
+			return nil, ErrInvalidPadding
+		}
+		acc = acc<<fromBits | uint32(v)
+		bits += fromBits
+		for bits >= toBits {
+			bits -= toBits
+			out = append(out, byte(acc>>bits&maxv))
+		}
+	}
+
+	if pad {
+		if bits > 0 {
+			out = append(out, byte(acc<<(toBits-bits)&maxv))
+		}
+	} else if bits >= fromBits || (acc<<(toBits-bits))&maxv != 0 {
+		return nil, ErrInvalidPadding
+	}
+	return out, nil
+}
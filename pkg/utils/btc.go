@@ -25,6 +25,20 @@ func ParseBTCAddress(address string) (btc *A25, err error) {
 	return btc, nil
 }
 
+// NewA25 creates an address from a version byte and a 20 byte public key or script
+// hash, computing and embedding the checksum of the address.
+func NewA25(version byte, hash []byte) (a *A25, err error) {
+	if len(hash) != 20 {
+		return nil, fmt.Errorf("invalid hash length %d, expected 20 bytes", len(hash))
+	}
+
+	a = &A25{}
+	a[0] = version
+	copy(a[1:21], hash)
+	a.SetChecksum()
+	return a, nil
+}
+
 // A25 is a type for a 25 byte (not base58 encoded) bitcoin address.
 type A25 [25]byte
 
@@ -53,6 +67,13 @@ func (a *A25) EmbeddedChecksum() (c [4]byte) {
 	return
 }
 
+// SetChecksum computes the checksum of the first 21 bytes of the address and embeds
+// it into the last four bytes of the address.
+func (a *A25) SetChecksum() {
+	c := a.ComputeChecksum()
+	copy(a[21:], c[:])
+}
+
 // DoubleSHA256 computes a double sha256 hash of the first 21 bytes of the
 // address. Returned is the full 32 byte sha256 hash. The bitcoin checksum will be the
 // first four bytes of the slice.
@@ -77,8 +98,15 @@ func (a *A25) ComputeChecksum() (c [4]byte) {
 // value does not fit in the 25 byte address.  The address is not otherwise
 // checked for validity.
 func (a *A25) Decode(s []byte) error {
+	return a.decode(s, tmpl)
+}
+
+// decode a base58 encoded address into the receiver using the specified alphabet,
+// which allows chains such as XRP that use a different base58 alphabet to share the
+// same decoding and checksum logic as bitcoin.
+func (a *A25) decode(s, alphabet []byte) error {
 	for _, s1 := range s {
-		c := bytes.IndexByte(tmpl, s1)
+		c := bytes.IndexByte(alphabet, s1)
 		if c < 0 {
 			return fmt.Errorf("invalid base58 character %q", s1)
 		}
@@ -93,3 +121,47 @@ func (a *A25) Decode(s []byte) error {
 	}
 	return nil
 }
+
+// Encode returns the base58 encoding of the address, the inverse of Decode.
+func (a *A25) Encode() []byte {
+	return a.encode(tmpl)
+}
+
+// encode the address as base58 using the specified alphabet. Each leading zero byte
+// is encoded as the first character of the alphabet.
+func (a *A25) encode(alphabet []byte) []byte {
+	var (
+		n   = *a
+		out = make([]byte, 0, 35)
+	)
+
+	zeros := 0
+	for zeros < len(n) && n[zeros] == 0 {
+		zeros++
+	}
+
+	// Repeatedly divide the big-endian number by 58, collecting the remainders
+	for start := zeros; start < len(n); {
+		rem := 0
+		for j := start; j < len(n); j++ {
+			rem = rem*256 + int(n[j])
+			n[j] = byte(rem / 58)
+			rem %= 58
+		}
+		out = append(out, alphabet[rem])
+
+		for start < len(n) && n[start] == 0 {
+			start++
+		}
+	}
+
+	for i := 0; i < zeros; i++ {
+		out = append(out, alphabet[0])
+	}
+
+	// Remainders were collected least significant digit first
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return out
+}
@@ -37,3 +37,26 @@ func TestParseBTCAddress(t *testing.T) {
 		}
 	}
 }
+
+func TestEncodeBTCAddress(t *testing.T) {
+	addresses := []string{
+		"moJuU1GjhJzUdUGukw13a4w6CWjfFsJ92q",
+		"n1CqdbqoPZ8Y11UzQG6KyapWj4vcN3owcs",
+		"18nxAxBktHZDrMoJ3N2fk9imLX8xNnYbNh",
+		"1111111111111111111114oLvT2",
+	}
+
+	for _, address := range addresses {
+		btc, err := utils.ParseBTCAddress(address)
+		require.NoError(t, err, "expected address %s to be valid", address)
+		require.Equal(t, address, string(btc.Encode()), "expected encoded address to match original")
+
+		// Creating the address from its parts should produce the same address
+		actual, err := utils.NewA25(btc.Version(), btc[1:21])
+		require.NoError(t, err)
+		require.Equal(t, address, string(actual.Encode()))
+	}
+
+	_, err := utils.NewA25(111, []byte{0x01, 0x02})
+	require.EqualError(t, err, "invalid hash length 2, expected 20 bytes")
+}
@@ -3,6 +3,15 @@ package utils
 import "errors"
 
 var (
-	ErrTooLong         = errors.New("base58 address too long")
-	ErrInvalidChecksum = errors.New("invalid checksum")
+	ErrTooLong          = errors.New("base58 address too long")
+	ErrInvalidChecksum  = errors.New("invalid checksum")
+	ErrInvalidVersion   = errors.New("invalid address version")
+	ErrInvalidHRP       = errors.New("invalid human readable part")
+	ErrInvalidSeparator = errors.New("missing or misplaced bech32 separator")
+	ErrInvalidWitness   = errors.New("invalid witness program")
+	ErrInvalidPadding   = errors.New("invalid bit padding")
+	ErrInvalidLength    = errors.New("invalid address length")
+	ErrInvalidPrefix    = errors.New("invalid address prefix")
+	ErrMixedCase        = errors.New("address has mixed case")
+	ErrUnknownAssetType = errors.New("no address validator for asset type")
 )
@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"encoding/hex"
+	"strings"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// ParseETHAddress decodes a hex encoded ethereum address and returns an error if the
+// address is mixed case but does not have a valid EIP-55 checksum. All lower or all
+// upper case addresses do not carry a checksum and are only checked for length.
+// See https://eips.ethereum.org/EIPS/eip-55
+func ParseETHAddress(address string) (eth []byte, err error) {
+	if !strings.HasPrefix(address, "0x") && !strings.HasPrefix(address, "0X") {
+		return nil, ErrInvalidPrefix
+	}
+
+	digits := address[2:]
+	if len(digits) != 40 {
+		return nil, ErrInvalidLength
+	}
+
+	if eth, err = hex.DecodeString(digits); err != nil {
+		return nil, err
+	}
+
+	if strings.ToLower(digits) == digits || strings.ToUpper(digits) == digits {
+		return eth, nil
+	}
+
+	if digits != ChecksumETHAddress(eth)[2:] {
+		return nil, ErrInvalidChecksum
+	}
+	return eth, nil
+}
+
+// ChecksumETHAddress returns the EIP-55 mixed case encoding of a 20 byte address.
+func ChecksumETHAddress(eth []byte) string {
+	digits := []byte(hex.EncodeToString(eth))
+
+	h := sha3.NewLegacyKeccak256()
+	h.Write(digits)
+	hash := h.Sum(nil)
+
+	// Uppercase a letter if the corresponding nibble of the hash is 8 or greater
+	for i, c := range digits {
+		if c < 'a' {
+			continue
+		}
+
+		nibble := hash[i/2]
+		if i%2 == 0 {
+			nibble >>= 4
+		}
+		if nibble&0x0f >= 8 {
+			digits[i] = c - 32
+		}
+	}
+	return "0x" + string(digits)
+}